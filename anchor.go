@@ -0,0 +1,145 @@
+package difflib
+
+import "strings"
+
+// Anchor is a position in one version of a file, such as the line a review
+// comment is attached to.
+type Anchor struct {
+	// File is the path of the file, without any "a/" or "b/" prefix.
+	File string `json:"file"`
+	// Line is the 1-based line number.
+	Line int `json:"line"`
+	// Text is the anchored line, and Before and After are the lines just
+	// above and below it, as they read when the anchor was made. They are
+	// used to find the line again when a patch rewrites it.
+	Text   string   `json:"text,omitempty"`
+	Before []string `json:"before,omitempty"`
+	After  []string `json:"after,omitempty"`
+	// Orphaned reports that the anchored line no longer exists.
+	Orphaned bool `json:"orphaned,omitempty"`
+}
+
+// RelocateAnchor carries a, made on the version of a file that p applies to,
+// over to the version p produces. File names in p are compared with a.File
+// after removing strip leading path components, as ApplyFSOptions.Strip does,
+// and a renamed file gives the anchor its new name. Lines outside the hunks
+// move by the number of lines added or removed above them, and context lines
+// inside a hunk keep their anchors.
+//
+// When a hunk removes the anchored line, the line is looked for among the
+// lines that hunk adds: one that equals a.Text, ignoring leading and trailing
+// white space, and whose neighbours best match a.Before and a.After takes the
+// anchor, and its Text is updated. If there is none, or the file is deleted
+// or binary, the returned anchor is marked Orphaned and keeps its old Line.
+//
+// Example:
+//
+//	ps, _ := difflib.ParsePatchSet(nextRevision)
+//	moved := ps.RelocateAnchor(comment.Anchor, 1)
+//	if moved.Orphaned {
+//	    comment.Outdated = true
+//	}
+func (p *PatchSet) RelocateAnchor(a Anchor, strip int) Anchor {
+	if a.Orphaned {
+		return a
+	}
+	for _, f := range p.Files {
+		if stripPath(f.OldName, strip) != a.File || f.NewFile {
+			continue
+		}
+		if f.DeletedFile || f.Binary {
+			a.Orphaned = true
+			return a
+		}
+		a.File = stripPath(f.NewName, strip)
+		return relocateInHunks(a, f.Hunks)
+	}
+	return a
+}
+
+// relocateInHunks moves a across hunks, which apply to a's file.
+func relocateInHunks(a Anchor, hunks []Hunk) Anchor {
+	delta := 0
+	for _, h := range hunks {
+		start := h.OldStart
+		if h.OldLines == 0 {
+			start++
+		}
+		if a.Line < start {
+			break
+		}
+		if a.Line >= start+h.OldLines {
+			delta += h.NewLines - h.OldLines
+			continue
+		}
+		return relocateInHunk(a, h, a.Line-start, start+delta)
+	}
+	a.Line += delta
+	return a
+}
+
+// relocateInHunk moves a, which is the old line at offset off within h, to
+// the new side of h, whose first line is newStart.
+func relocateInHunk(a Anchor, h Hunk, off, newStart int) Anchor {
+	var newSide []string
+	var added []int
+	old, removedAt := 0, -1
+	for _, l := range h.Lines {
+		if l == "" {
+			continue
+		}
+		switch l[0] {
+		case ' ':
+			if old == off {
+				a.Line = newStart + len(newSide)
+				return a
+			}
+			old++
+			newSide = append(newSide, l[1:])
+		case '-':
+			if old == off {
+				removedAt = len(newSide)
+			}
+			old++
+		case '+':
+			added = append(added, len(newSide))
+			newSide = append(newSide, l[1:])
+		}
+	}
+
+	want := strings.TrimSpace(a.Text)
+	best, bestScore, bestDist := -1, 0, 0
+	for _, j := range added {
+		if a.Text == "" || strings.TrimSpace(newSide[j]) != want {
+			continue
+		}
+		score := 1
+		if newSide[j] == a.Text {
+			score++
+		}
+		for k := 1; k <= len(a.Before) && j-k >= 0; k++ {
+			if strings.TrimSpace(newSide[j-k]) == strings.TrimSpace(a.Before[len(a.Before)-k]) {
+				score++
+			}
+		}
+		for k := 1; k <= len(a.After) && j+k < len(newSide); k++ {
+			if strings.TrimSpace(newSide[j+k]) == strings.TrimSpace(a.After[k-1]) {
+				score++
+			}
+		}
+		dist := j - removedAt
+		if dist < 0 {
+			dist = -dist
+		}
+		if score > bestScore || score == bestScore && dist < bestDist {
+			best, bestScore, bestDist = j, score, dist
+		}
+	}
+	if best < 0 {
+		a.Orphaned = true
+		return a
+	}
+	a.Line = newStart + best
+	a.Text = newSide[best]
+	return a
+}
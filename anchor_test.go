@@ -0,0 +1,91 @@
+package difflib_test
+
+import (
+	"fmt"
+	"testing"
+
+	difflib "github.com/njchilds90/go-difflib"
+)
+
+func TestRelocateAnchor(t *testing.T) {
+	old := difflib.SplitLines("package p\n\nfunc a() {\n\tx := 1\n\treturn\n}\n\nfunc b() {}\n\nfunc c() {}\n")
+	next := difflib.SplitLines("package p\n\nimport \"os\"\n\nfunc a() {\n\t\tx := 1\n\treturn\n}\n\nfunc c() {}\n")
+	d := difflib.UnifiedDiff(difflib.DiffInput{A: old, B: next, FromFile: "a/p.go", ToFile: "b/p.go", Context: 1})
+	ps, err := difflib.ParsePatchSet(d.String())
+	if err != nil {
+		t.Fatalf("ParsePatchSet error: %v", err)
+	}
+	tests := []struct {
+		name     string
+		in       difflib.Anchor
+		line     int
+		orphaned bool
+	}{
+		{"before hunks", difflib.Anchor{File: "p.go", Line: 1, Text: "package p\n"}, 1, false},
+		{"context", difflib.Anchor{File: "p.go", Line: 3, Text: "func a() {\n"}, 5, false},
+		{"reindented", difflib.Anchor{File: "p.go", Line: 4, Text: "\tx := 1\n", Before: []string{"func a() {\n"}}, 6, false},
+		{"between hunks", difflib.Anchor{File: "p.go", Line: 6, Text: "}\n"}, 8, false},
+		{"deleted", difflib.Anchor{File: "p.go", Line: 8, Text: "func b() {}\n"}, 8, true},
+		{"after hunks", difflib.Anchor{File: "p.go", Line: 10, Text: "func c() {}\n"}, 10, false},
+		{"other file", difflib.Anchor{File: "q.go", Line: 8}, 8, false},
+	}
+	for _, tt := range tests {
+		got := ps.RelocateAnchor(tt.in, 1)
+		if got.Line != tt.line || got.Orphaned != tt.orphaned {
+			t.Errorf("%s: got line %d orphaned %v, want %d %v", tt.name, got.Line, got.Orphaned, tt.line, tt.orphaned)
+			continue
+		}
+		if !got.Orphaned && tt.in.File == "p.go" && got.Text != next[got.Line-1] {
+			t.Errorf("%s: Text = %q, want %q", tt.name, got.Text, next[got.Line-1])
+		}
+	}
+}
+
+func TestRelocateAnchorContext(t *testing.T) {
+	// Both "}" lines are rewritten; the neighbours pick the right one.
+	patch := "--- a/f\n+++ b/f\n@@ -1,6 +1,6 @@\n a\n-}\n+  }\n b\n-}\n+  }\n c\n x\n"
+	ps, err := difflib.ParsePatchSet(patch)
+	if err != nil {
+		t.Fatalf("ParsePatchSet error: %v", err)
+	}
+	got := ps.RelocateAnchor(difflib.Anchor{File: "f", Line: 4, Text: "}\n", Before: []string{"b\n"}, After: []string{"c\n"}}, 1)
+	if got.Line != 4 || got.Orphaned || got.Text != "  }\n" {
+		t.Errorf("got %+v, want line 4", got)
+	}
+}
+
+func TestRelocateAnchorFiles(t *testing.T) {
+	ps, err := difflib.ParsePatchSet(gitPatch)
+	if err != nil {
+		t.Fatalf("ParsePatchSet error: %v", err)
+	}
+	if got := ps.RelocateAnchor(difflib.Anchor{File: "old.txt", Line: 1}, 1); !got.Orphaned {
+		t.Errorf("deleted file: got %+v, want orphaned", got)
+	}
+	if got := ps.RelocateAnchor(difflib.Anchor{File: "from.txt", Line: 1}, 1); got.File != "to.txt" || got.Line != 1 || got.Orphaned {
+		t.Errorf("renamed file: got %+v", got)
+	}
+}
+
+func ExamplePatchSet_RelocateAnchor() {
+	patch := `--- a/notes.txt
++++ b/notes.txt
+@@ -1,3 +1,4 @@
++Intro
+ First
+-Second
++second
+ Third
+`
+	ps, _ := difflib.ParsePatchSet(patch)
+	for _, a := range []difflib.Anchor{
+		{File: "notes.txt", Line: 3, Text: "Third\n"},
+		{File: "notes.txt", Line: 2, Text: "Second\n"},
+	} {
+		moved := ps.RelocateAnchor(a, 1)
+		fmt.Printf("%s:%d -> line %d, orphaned %v\n", a.File, a.Line, moved.Line, moved.Orphaned)
+	}
+	// Output:
+	// notes.txt:3 -> line 4, orphaned false
+	// notes.txt:2 -> line 2, orphaned true
+}
@@ -0,0 +1,356 @@
+package difflib
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ApplyOptions controls how ApplyPatchWithOptions places hunks in the input.
+type ApplyOptions struct {
+	// Fuzz is the maximum number of leading and trailing context lines that
+	// may be ignored when a hunk's full context cannot be found, like the -F
+	// flag of GNU patch. Zero requires all context lines to match.
+	Fuzz int
+	// MaxOffset limits how many lines away from the position recorded in the
+	// hunk header a hunk may be placed. Zero means the whole input is searched.
+	MaxOffset int
+}
+
+// HunkResult reports where a single hunk of a patch was applied.
+type HunkResult struct {
+	// Hunk is the 1-based index of the hunk within the patch.
+	Hunk int
+	// Line is the 1-based line in the original input at which the hunk's
+	// first old line was found.
+	Line int
+	// Offset is the number of lines between Line and the position recorded
+	// in the hunk header. Positive values mean the hunk was found later.
+	Offset int
+	// Fuzz is the number of leading and trailing context lines that had to be
+	// ignored to place the hunk.
+	Fuzz int
+	// Err is non-nil if the hunk could not be placed. Line then holds the
+	// position recorded in the hunk header.
+	Err error
+}
+
+// String describes the result in the style of GNU patch, e.g.
+// "Hunk #1 succeeded at 42 (offset 3 lines)." or "Hunk #2 FAILED at 17.".
+func (r HunkResult) String() string {
+	if r.Err != nil {
+		return fmt.Sprintf("Hunk #%d FAILED at %d.", r.Hunk, r.Line)
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "Hunk #%d succeeded at %d", r.Hunk, r.Line)
+	if r.Fuzz > 0 {
+		fmt.Fprintf(&b, " with fuzz %d", r.Fuzz)
+	}
+	if r.Offset != 0 {
+		unit := "lines"
+		if r.Offset == 1 || r.Offset == -1 {
+			unit = "line"
+		}
+		fmt.Fprintf(&b, " (offset %d %s)", r.Offset, unit)
+	}
+	b.WriteString(".")
+	return b.String()
+}
+
+// ApplyPatchWithOptions applies a unified diff string to the original lines A.
+// Unlike a strict positional apply, each hunk is located by searching outward
+// from the line named in its header for the hunk's context and deleted lines,
+// and up to opts.Fuzz context lines may be ignored at either end of the hunk.
+// It returns the patched lines along with where each hunk was applied, or an
+// error naming the first hunk that could not be placed.
+//
+// Example:
+//
+//	patched, results, err := difflib.ApplyPatchWithOptions(original, patchString,
+//	    difflib.ApplyOptions{Fuzz: 2})
+//	for _, r := range results {
+//	    fmt.Println(r) // Hunk #1 succeeded at 42 (offset 3 lines).
+//	}
+func ApplyPatchWithOptions(a []string, patch string, opts ApplyOptions) ([]string, []HunkResult, error) {
+	d, err := parseDiff(patch)
+	if err != nil {
+		return nil, nil, err
+	}
+	return applyHunks(a, d.Hunks, opts)
+}
+
+// ApplyReport describes how each hunk of a patch would apply to an input.
+type ApplyReport struct {
+	// Hunks holds one result per hunk, in patch order.
+	Hunks []HunkResult
+	// Rejects holds the hunks that do not apply, with the patch's file labels,
+	// so that Rejects.String() is the content of a GNU patch .rej file. It is
+	// empty when every hunk applies.
+	Rejects DiffResult
+}
+
+// OK reports whether every hunk applies.
+func (r ApplyReport) OK() bool {
+	for _, h := range r.Hunks {
+		if h.Err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// Failed returns the results of the hunks that do not apply.
+func (r ApplyReport) Failed() []HunkResult {
+	var out []HunkResult
+	for _, h := range r.Hunks {
+		if h.Err != nil {
+			out = append(out, h)
+		}
+	}
+	return out
+}
+
+// String renders the report one hunk per line, as GNU patch does.
+func (r ApplyReport) String() string {
+	var b strings.Builder
+	for _, h := range r.Hunks {
+		b.WriteString(h.String())
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// CheckPatch performs a dry run of ApplyPatchWithOptions: it locates every
+// hunk of patch in A using the same offset and fuzz rules but does not build
+// the patched output. Unlike applying, it keeps going after a hunk fails, so
+// the report covers the whole patch. The returned error is non-nil only if
+// the patch itself is malformed.
+//
+// Example:
+//
+//	report, err := difflib.CheckPatch(original, patchString, difflib.ApplyOptions{Fuzz: 2})
+//	if err == nil && !report.OK() {
+//	    fmt.Print(report) // Hunk #2 FAILED at 17.
+//	}
+func CheckPatch(a []string, patch string, opts ApplyOptions) (ApplyReport, error) {
+	d, err := parseDiff(patch)
+	if err != nil {
+		return ApplyReport{}, err
+	}
+	return newApplyReport(d, placeHunks(a, d.Hunks, opts)), nil
+}
+
+// ApplyPatchPartial applies the hunks of patch that can be placed in A and
+// skips the rest instead of failing. The report lists every hunk's outcome
+// and carries the skipped hunks in report.Rejects as a well-formed unified
+// diff, ready to be written out as a reject file. The returned error is
+// non-nil only if the patch itself is malformed.
+//
+// Example:
+//
+//	patched, report, err := difflib.ApplyPatchPartial(original, patchString, difflib.ApplyOptions{})
+//	if err == nil && !report.OK() {
+//	    os.WriteFile("file.rej", []byte(report.Rejects.String()), 0o644)
+//	}
+func ApplyPatchPartial(a []string, patch string, opts ApplyOptions) ([]string, ApplyReport, error) {
+	d, err := parseDiff(patch)
+	if err != nil {
+		return nil, ApplyReport{}, err
+	}
+	placed := placeHunks(a, d.Hunks, opts)
+	return splicePlacements(a, placed), newApplyReport(d, placed), nil
+}
+
+func newApplyReport(d DiffResult, placed []hunkPlacement) ApplyReport {
+	report := ApplyReport{
+		Rejects: DiffResult{FromFile: d.FromFile, ToFile: d.ToFile},
+	}
+	for i, p := range placed {
+		report.Hunks = append(report.Hunks, p.result)
+		if p.result.Err != nil {
+			report.Rejects.Hunks = append(report.Rejects.Hunks, d.Hunks[i])
+		}
+	}
+	return report
+}
+
+// hunkPlacement records where a hunk matched in the original input.
+type hunkPlacement struct {
+	// pos and end delimit the matched old lines in the input.
+	pos, end int
+	// lines replaces a[pos:end].
+	lines []string
+	// top and bottom are the numbers of leading and trailing context lines
+	// of the hunk that were ignored because of fuzz.
+	top, bottom int
+	result      HunkResult
+}
+
+// placeHunks locates each hunk in order against the unmodified input. Hunks
+// may not overlap; a hunk that cannot be placed gets a result with Err set and
+// does not constrain the placement of later hunks.
+func placeHunks(a []string, hunks []Hunk, opts ApplyOptions) []hunkPlacement {
+	placed := make([]hunkPlacement, 0, len(hunks))
+	minPos, lastOffset := 0, 0
+	for n, h := range hunks {
+		p, ok := placeHunk(a, h, minPos, lastOffset, opts)
+		if !ok {
+			p = hunkPlacement{result: HunkResult{
+				Line: h.OldStart,
+				Err:  hunkError(a, h, n+1),
+			}}
+		} else {
+			minPos, lastOffset = p.end, p.result.Offset
+		}
+		p.result.Hunk = n + 1
+		placed = append(placed, p)
+	}
+	return placed
+}
+
+// applyHunks places each hunk and splices the replacements into a copy of a,
+// failing on the first hunk that cannot be placed.
+func applyHunks(a []string, hunks []Hunk, opts ApplyOptions) ([]string, []HunkResult, error) {
+	placed := placeHunks(a, hunks, opts)
+	results := make([]HunkResult, 0, len(placed))
+	for _, p := range placed {
+		if p.result.Err != nil {
+			return nil, nil, p.result.Err
+		}
+		results = append(results, p.result)
+	}
+	return splicePlacements(a, placed), results, nil
+}
+
+// splicePlacements builds the patched output from a and the placed hunks,
+// leaving the input untouched where a hunk failed.
+func splicePlacements(a []string, placed []hunkPlacement) []string {
+	result := make([]string, 0, len(a))
+	prev := 0
+	for _, p := range placed {
+		if p.result.Err != nil {
+			continue
+		}
+		result = append(result, a[prev:p.pos]...)
+		result = append(result, p.lines...)
+		prev = p.end
+	}
+	return append(result, a[prev:]...)
+}
+
+// hunkAnchor returns the 0-based index in the original input at which the
+// hunk header says its old lines begin.
+func hunkAnchor(h Hunk) int {
+	if h.OldLines == 0 {
+		// For empty ranges the start names the line after which to insert.
+		return h.OldStart
+	}
+	// max guards hunks built by hand with a start of 0 and lines.
+	return max(h.OldStart-1, 0)
+}
+
+// placeHunk searches for h in a at or after minPos, starting at the header
+// position shifted by the offset of the previous hunk and widening outward.
+// Fuzz levels are tried in increasing order so the closest exact match wins.
+func placeHunk(a []string, h Hunk, minPos, lastOffset int, opts ApplyOptions) (hunkPlacement, bool) {
+	from, to := hunkSides(h)
+	leading, trailing := hunkContext(h)
+	anchor := hunkAnchor(h)
+	for fuzz := 0; fuzz <= opts.Fuzz; fuzz++ {
+		if fuzz > max(leading, trailing) {
+			break
+		}
+		top, bottom := min(fuzz, leading), min(fuzz, trailing)
+		want := from[top : len(from)-bottom]
+		expected := anchor + top
+		hint := expected + lastOffset
+		pos, ok := searchLines(a, want, hint, minPos, expected, opts.MaxOffset)
+		if !ok {
+			continue
+		}
+		return hunkPlacement{
+			pos:    pos,
+			end:    pos + len(want),
+			lines:  to[top : len(to)-bottom],
+			top:    top,
+			bottom: bottom,
+			result: HunkResult{
+				Line:   pos - top + 1,
+				Offset: pos - expected,
+				Fuzz:   max(top, bottom),
+			},
+		}, true
+	}
+	return hunkPlacement{}, false
+}
+
+// searchLines finds want in a at a position >= minPos, trying hint first and
+// then alternating below and above it at increasing distance. When maxOffset
+// is positive, positions further than maxOffset from expected are skipped.
+func searchLines(a, want []string, hint, minPos, expected, maxOffset int) (int, bool) {
+	last := len(a) - len(want)
+	if last < minPos {
+		return 0, false
+	}
+	hint = max(minPos, min(hint, last))
+	inRange := func(pos int) bool {
+		if pos < minPos || pos > last {
+			return false
+		}
+		if maxOffset > 0 && (pos-expected > maxOffset || expected-pos > maxOffset) {
+			return false
+		}
+		return true
+	}
+	for d := 0; hint-d >= minPos || hint+d <= last; d++ {
+		if pos := hint - d; inRange(pos) && linesMatchAt(a, want, pos) {
+			return pos, true
+		}
+		if pos := hint + d; d > 0 && inRange(pos) && linesMatchAt(a, want, pos) {
+			return pos, true
+		}
+	}
+	return 0, false
+}
+
+func linesMatchAt(a, want []string, pos int) bool {
+	for i, l := range want {
+		if a[pos+i] != l {
+			return false
+		}
+	}
+	return true
+}
+
+// contextError is a difflib error with context, such as the file it
+// concerns, added after the "difflib: " prefix, which it does not repeat.
+type contextError struct {
+	context string
+	err     error
+}
+
+func (e *contextError) Error() string {
+	return "difflib: " + e.context + ": " + strings.TrimPrefix(e.err.Error(), "difflib: ")
+}
+
+func (e *contextError) Unwrap() error { return e.err }
+
+// hunkError describes why h does not apply at its recorded position.
+func hunkError(a []string, h Hunk, n int) error {
+	return fmt.Errorf("difflib: hunk #%d %s", n, hunkMismatch(a, h))
+}
+
+// hunkMismatch describes where the old lines of h first differ from a at
+// the position recorded in its header.
+func hunkMismatch(a []string, h Hunk) string {
+	from, _ := hunkSides(h)
+	pos := hunkAnchor(h)
+	for i, l := range from {
+		if pos+i >= len(a) {
+			return fmt.Sprintf("failed at line %d: expected %q, got end of input", pos+i+1, l)
+		}
+		if a[pos+i] != l {
+			return fmt.Sprintf("failed at line %d: expected %q, got %q", pos+i+1, l, a[pos+i])
+		}
+	}
+	return fmt.Sprintf("failed at line %d: overlaps a previous hunk", pos+1)
+}
@@ -0,0 +1,350 @@
+package difflib_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	difflib "github.com/njchilds90/go-difflib"
+)
+
+// numbered returns n lines "line 1\n" ... "line n\n".
+func numbered(n int) []string {
+	lines := make([]string, n)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("line %d\n", i+1)
+	}
+	return lines
+}
+
+func TestApplyPatchWithOptions(t *testing.T) {
+	patch := "--- a\n+++ b\n" +
+		"@@ -4,7 +4,7 @@\n" +
+		" line 4\n line 5\n line 6\n-line 7\n+LINE 7\n line 8\n line 9\n line 10\n"
+
+	shifted := append([]string{"extra 1\n", "extra 2\n", "extra 3\n"}, numbered(20)...)
+	drifted := numbered(20)
+	drifted[3] = "changed 4\n"
+
+	tests := []struct {
+		name     string
+		input    []string
+		opts     difflib.ApplyOptions
+		wantLine int
+		wantErr  bool
+		want     difflib.HunkResult
+	}{
+		{"exact", numbered(20), difflib.ApplyOptions{}, 7,
+			false, difflib.HunkResult{Hunk: 1, Line: 4}},
+		{"offset", shifted, difflib.ApplyOptions{}, 10,
+			false, difflib.HunkResult{Hunk: 1, Line: 7, Offset: 3}},
+		{"offset beyond max", shifted, difflib.ApplyOptions{MaxOffset: 2}, 0,
+			true, difflib.HunkResult{}},
+		{"context mismatch without fuzz", drifted, difflib.ApplyOptions{}, 0,
+			true, difflib.HunkResult{}},
+		{"context mismatch with fuzz", drifted, difflib.ApplyOptions{Fuzz: 1}, 7,
+			false, difflib.HunkResult{Hunk: 1, Line: 4, Fuzz: 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, results, err := difflib.ApplyPatchWithOptions(tt.input, patch, tt.opts)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got results %v", results)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ApplyPatchWithOptions error: %v", err)
+			}
+			if got[tt.wantLine-1] != "LINE 7\n" {
+				t.Errorf("line %d = %q, want %q", tt.wantLine, got[tt.wantLine-1], "LINE 7\n")
+			}
+			if len(got) != len(tt.input) {
+				t.Errorf("got %d lines, want %d", len(got), len(tt.input))
+			}
+			if len(results) != 1 || results[0] != tt.want {
+				t.Errorf("results = %+v, want [%+v]", results, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyPatchMultipleHunks(t *testing.T) {
+	a := numbered(30)
+	b := numbered(30)
+	b[1] = "two\n"
+	b = append(b[:15], b[16:]...)
+	b = append(b, "thirty-one\n")
+	patch := difflib.UnifiedDiff(difflib.DiffInput{A: a, B: b, FromFile: "a", ToFile: "b"}).String()
+
+	// Insert unrelated lines at the top so every hunk is found at an offset.
+	input := append([]string{"header\n", "header\n"}, a...)
+	got, results, err := difflib.ApplyPatchWithOptions(input, patch, difflib.ApplyOptions{})
+	if err != nil {
+		t.Fatalf("ApplyPatchWithOptions error: %v", err)
+	}
+	want := append([]string{"header\n", "header\n"}, b...)
+	if difflib.JoinLines(got) != difflib.JoinLines(want) {
+		t.Errorf("patched mismatch:\n%s", difflib.UnifiedDiff(difflib.DiffInput{A: want, B: got}).String())
+	}
+	if len(results) != 3 {
+		t.Fatalf("expected 3 hunk results, got %v", results)
+	}
+	for _, r := range results {
+		if r.Offset != 2 {
+			t.Errorf("hunk %d: offset = %d, want 2", r.Hunk, r.Offset)
+		}
+	}
+}
+
+func TestApplyPatchChecksContext(t *testing.T) {
+	// "}" occurs twice; only the context tells the two apart.
+	input := difflib.SplitLines("func a() {\n}\nfunc b() {\n}\n")
+	patch := "--- x\n+++ x\n@@ -1,2 +1,2 @@\n func b() {\n-}\n+} // b\n"
+	got, err := difflib.ApplyPatch(input, patch)
+	if err != nil {
+		t.Fatalf("ApplyPatch error: %v", err)
+	}
+	if want := "func a() {\n}\nfunc b() {\n} // b\n"; difflib.JoinLines(got) != want {
+		t.Errorf("ApplyPatch = %q, want %q", difflib.JoinLines(got), want)
+	}
+	// With only context lines that match nowhere, the hunk must fail
+	// even though its deletion matches.
+	patch = "--- x\n+++ x\n@@ -1,2 +1,2 @@\n func c() {\n-}\n+} // c\n"
+	if _, err := difflib.ApplyPatch(input, patch); err == nil {
+		t.Error("ApplyPatch succeeded with context matching nowhere")
+	}
+}
+
+func TestApplyPatchHeaderLikeLines(t *testing.T) {
+	// Deleting "-- drop" and adding "++ add" gives body lines that read
+	// like a file header; the hunk counts say they are not.
+	input := difflib.SplitLines("select 1;\n-- drop\n---\nkeep\n")
+	want := difflib.SplitLines("select 1;\n++ add\n+++\nkeep\n")
+	patch := difflib.UnifiedDiff(difflib.DiffInput{A: input, B: want, FromFile: "a.sql", ToFile: "b.sql"}).String()
+	if !strings.Contains(patch, "\n--- drop\n") || !strings.Contains(patch, "\n+++ add\n") {
+		t.Fatalf("patch does not exercise the case:\n%s", patch)
+	}
+	repaired, fixes, err := difflib.RepairPatch(patch)
+	if err != nil || repaired != patch {
+		t.Errorf("RepairPatch = %q, %v, %v; want the patch unchanged", repaired, fixes, err)
+	}
+	if errs := difflib.ValidatePatch(patch); len(errs) != 0 {
+		t.Errorf("ValidatePatch = %v", errs)
+	}
+	for name, apply := range map[string]func() ([]string, error){
+		"ApplyPatch": func() ([]string, error) { return difflib.ApplyPatch(input, patch) },
+		"ApplyPatchLenient": func() ([]string, error) {
+			got, _, err := difflib.ApplyPatchLenient(input, patch, difflib.LenientOptions{})
+			return got, err
+		},
+	} {
+		got, err := apply()
+		if err != nil {
+			t.Errorf("%s error: %v", name, err)
+			continue
+		}
+		if difflib.JoinLines(got) != difflib.JoinLines(want) {
+			t.Errorf("%s = %q, want %q", name, difflib.JoinLines(got), difflib.JoinLines(want))
+		}
+	}
+}
+
+func TestApplyPatchNewlineRoundTrip(t *testing.T) {
+	tests := []struct {
+		name, a, b string
+	}{
+		{"both missing", "a\nb", "a\nc"},
+		{"added", "a\nb", "a\nb\n"},
+		{"removed", "a\nb\n", "a\nb"},
+		{"single line", "x", "y"},
+		{"crlf", "a\r\nb\r\n", "a\r\nb"},
+		{"crlf insert", "a\r\nc\r\n", "a\r\nb\r\nc\r\n"},
+		{"from empty", "", "x"},
+		{"to empty", "x", ""},
+		{"context", "1\n2\n3\n4\n5", "0\n2\n3\n4\n5"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := difflib.SplitLines(tt.a), difflib.SplitLines(tt.b)
+			patch := difflib.UnifiedDiff(difflib.DiffInput{A: a, B: b, FromFile: "a", ToFile: "b"}).String()
+			got, err := difflib.ApplyPatch(a, patch)
+			if err != nil {
+				t.Fatalf("ApplyPatch error: %v\n%s", err, patch)
+			}
+			if difflib.JoinLines(got) != tt.b {
+				t.Errorf("ApplyPatch = %q, want %q\n%s", difflib.JoinLines(got), tt.b, patch)
+			}
+			lenient, _, err := difflib.ApplyPatchLenient(a, patch, difflib.LenientOptions{})
+			if err != nil || difflib.JoinLines(lenient) != tt.b {
+				t.Errorf("ApplyPatchLenient = %q, %v; want %q", difflib.JoinLines(lenient), err, tt.b)
+			}
+			ps, err := difflib.ParsePatchSet(patch)
+			if err != nil {
+				t.Fatalf("ParsePatchSet error: %v", err)
+			}
+			if ps.String() != patch {
+				t.Errorf("ParsePatchSet(patch).String() = %q, want %q", ps.String(), patch)
+			}
+		})
+	}
+}
+
+func TestApplyPatchMalformed(t *testing.T) {
+	tests := []struct {
+		name  string
+		patch string
+	}{
+		{"bad header", "@@ -x +1 @@\n-a\n+b\n"},
+		{"short body", "@@ -1,2 +1,2 @@\n-a\n+b\n"},
+		{"bad line", "@@ -1,1 +1,1 @@\n?a\n+b\n"},
+		{"zero start", "--- a\n+++ b\n@@ -0,1 +0,1 @@\n-q\n+z\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := difflib.ApplyPatch([]string{"a\n"}, tt.patch)
+			if err == nil {
+				t.Errorf("expected error for %q", tt.patch)
+			}
+		})
+	}
+}
+
+func TestApplyZeroStartHunk(t *testing.T) {
+	// A start of 0 is only valid for an empty range; such hunks are
+	// rejected by the parser and, built by hand, placed at the first line.
+	a := []string{"x\n"}
+	patch := "--- a\n+++ b\n@@ -0,1 +0,1 @@\n-q\n+z\n"
+	if _, _, err := difflib.ApplyPatchWithOptions(a, patch, difflib.ApplyOptions{}); err == nil {
+		t.Error("ApplyPatchWithOptions() error = nil")
+	}
+	if _, err := difflib.CheckPatch(a, patch, difflib.ApplyOptions{}); err == nil {
+		t.Error("CheckPatch() error = nil")
+	}
+	if _, _, err := difflib.ApplyPatchPartial(a, patch, difflib.ApplyOptions{}); err == nil {
+		t.Error("ApplyPatchPartial() error = nil")
+	}
+	h := difflib.Hunk{OldStart: 0, OldLines: 1, NewStart: 0, NewLines: 1, Lines: []string{"-q\n", "+z\n"}}
+	if _, err := h.Apply(a); err == nil {
+		t.Error("Hunk.Apply() error = nil")
+	}
+	got, err := difflib.Hunk{OldStart: 0, OldLines: 1, NewStart: 0, NewLines: 1, Lines: []string{"-x\n", "+z\n"}}.Apply(a)
+	if err != nil || strings.Join(got, "") != "z\n" {
+		t.Errorf("Hunk.Apply() = %q, %v, want [z]", got, err)
+	}
+}
+
+func TestHunkResultString(t *testing.T) {
+	tests := []struct {
+		r    difflib.HunkResult
+		want string
+	}{
+		{difflib.HunkResult{Hunk: 1, Line: 42}, "Hunk #1 succeeded at 42."},
+		{difflib.HunkResult{Hunk: 1, Line: 42, Offset: 3}, "Hunk #1 succeeded at 42 (offset 3 lines)."},
+		{difflib.HunkResult{Hunk: 2, Line: 7, Offset: -1}, "Hunk #2 succeeded at 7 (offset -1 line)."},
+		{difflib.HunkResult{Hunk: 3, Line: 9, Fuzz: 2}, "Hunk #3 succeeded at 9 with fuzz 2."},
+		{difflib.HunkResult{Hunk: 4, Line: 17, Err: errors.New("mismatch")}, "Hunk #4 FAILED at 17."},
+	}
+	for _, tt := range tests {
+		if got := tt.r.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestCheckPatch(t *testing.T) {
+	a := numbered(30)
+	b := numbered(30)
+	b[1] = "two\n"
+	b[14] = "fifteen\n"
+	b[27] = "twenty-eight\n"
+	patch := difflib.UnifiedDiff(difflib.DiffInput{A: a, B: b, FromFile: "a", ToFile: "b"}).String()
+
+	input := numbered(30)
+	input[14] = "already changed\n"
+	snapshot := difflib.JoinLines(input)
+
+	report, err := difflib.CheckPatch(input, patch, difflib.ApplyOptions{})
+	if err != nil {
+		t.Fatalf("CheckPatch error: %v", err)
+	}
+	if difflib.JoinLines(input) != snapshot {
+		t.Error("CheckPatch modified its input")
+	}
+	if report.OK() {
+		t.Fatalf("expected report to contain a failure:\n%s", report)
+	}
+	want := "Hunk #1 succeeded at 1.\nHunk #2 FAILED at 12.\nHunk #3 succeeded at 25.\n"
+	if got := report.String(); got != want {
+		t.Errorf("report =\n%s\nwant\n%s", got, want)
+	}
+	if failed := report.Failed(); len(failed) != 1 || failed[0].Hunk != 2 || failed[0].Err == nil {
+		t.Errorf("Failed() = %+v, want hunk 2 only", failed)
+	}
+
+	if _, err := difflib.CheckPatch(input, "@@ -1,2 +1,2 @@\n-x\n", difflib.ApplyOptions{}); err == nil {
+		t.Error("expected error for malformed patch")
+	}
+}
+
+func TestApplyPatchPartial(t *testing.T) {
+	a := numbered(30)
+	b := numbered(30)
+	b[1] = "two\n"
+	b[14] = "fifteen\n"
+	b[27] = "twenty-eight\n"
+	patch := difflib.UnifiedDiff(difflib.DiffInput{A: a, B: b, FromFile: "a/f", ToFile: "b/f"}).String()
+
+	input := numbered(30)
+	input[14] = "already changed\n"
+	got, report, err := difflib.ApplyPatchPartial(input, patch, difflib.ApplyOptions{})
+	if err != nil {
+		t.Fatalf("ApplyPatchPartial error: %v", err)
+	}
+	want := append([]string(nil), input...)
+	want[1] = "two\n"
+	want[27] = "twenty-eight\n"
+	if difflib.JoinLines(got) != difflib.JoinLines(want) {
+		t.Errorf("patched mismatch:\n%s", difflib.UnifiedDiff(difflib.DiffInput{A: want, B: got}).String())
+	}
+
+	wantRej := "--- a/f\n+++ b/f\n@@ -12,7 +12,7 @@\n" +
+		" line 12\n line 13\n line 14\n-line 15\n+fifteen\n line 16\n line 17\n line 18\n"
+	if rej := report.Rejects.String(); rej != wantRej {
+		t.Errorf("rejects =\n%s\nwant\n%s", rej, wantRej)
+	}
+
+	// The reject file must itself be a valid patch for the intended change.
+	fixed, err := difflib.ApplyPatch(numbered(30), report.Rejects.String())
+	if err != nil {
+		t.Fatalf("applying rejects: %v", err)
+	}
+	if fixed[14] != "fifteen\n" {
+		t.Errorf("rejects applied line 15 = %q", fixed[14])
+	}
+
+	_, report, err = difflib.ApplyPatchPartial(numbered(30), patch, difflib.ApplyOptions{})
+	if err != nil || !report.OK() || !report.Rejects.IsEmpty() {
+		t.Errorf("clean apply: err=%v ok=%v rejects=%q", err, report.OK(), report.Rejects.String())
+	}
+}
+
+func ExampleApplyPatchWithOptions() {
+	original := difflib.SplitLines("one\ntwo\nthree\n")
+	patch := "@@ -2,2 +2,2 @@\n-two\n+TWO\n three\n"
+	patched, results, err := difflib.ApplyPatchWithOptions(
+		append([]string{"zero\n"}, original...), patch, difflib.ApplyOptions{})
+	if err != nil {
+		panic(err)
+	}
+	fmt.Print(strings.Join(patched, ""))
+	fmt.Println(results[0])
+	// Output:
+	// zero
+	// one
+	// TWO
+	// three
+	// Hunk #1 succeeded at 3 (offset 1 line).
+}
@@ -0,0 +1,321 @@
+package difflib
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// PatchFS is a writable file system that PatchSet.ApplyFS modifies.
+// Names are slash-separated paths as accepted by fs.ValidPath.
+type PatchFS interface {
+	fs.FS
+	// WriteFile replaces the contents of the named file, creating it and any
+	// missing parent directories if necessary. Implementations should make
+	// the replacement atomic.
+	WriteFile(name string, data []byte, perm fs.FileMode) error
+	// Remove deletes the named file.
+	Remove(name string) error
+}
+
+// DirFS returns a PatchFS for the directory tree rooted at dir on the local
+// disk. WriteFile writes to a temporary file in the target's directory and
+// renames it into place, so readers never observe a partially written file.
+//
+// Example:
+//
+//	err := ps.ApplyFS(difflib.DirFS("."), difflib.ApplyFSOptions{Strip: 1})
+func DirFS(dir string) PatchFS {
+	return dirFS{FS: os.DirFS(dir), dir: dir}
+}
+
+type dirFS struct {
+	fs.FS
+	dir string
+}
+
+func (d dirFS) path(name string) (string, error) {
+	if !fs.ValidPath(name) {
+		return "", &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	return filepath.Join(d.dir, filepath.FromSlash(name)), nil
+}
+
+func (d dirFS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	p, err := d.path(name)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(p), "."+filepath.Base(p)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(perm); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), p)
+}
+
+func (d dirFS) Remove(name string) error {
+	p, err := d.path(name)
+	if err != nil {
+		return err
+	}
+	return os.Remove(p)
+}
+
+// ApplyFSOptions controls PatchSet.ApplyFS.
+type ApplyFSOptions struct {
+	// ApplyOptions controls how hunks are located within each file.
+	ApplyOptions
+	// Strip is the number of leading path components removed from file names
+	// in the patch, like the -p option of patch(1). Patches produced by git
+	// need Strip: 1 to remove the "a/" and "b/" prefixes.
+	Strip int
+	// BackupSuffix, if non-empty, makes ApplyFS save the original contents of
+	// every modified, renamed or deleted file under its name plus this suffix
+	// (e.g. ".orig") before changing it.
+	BackupSuffix string
+}
+
+// fileChange is the planned effect of one FileDiff on the file system.
+type fileChange struct {
+	oldPath, newPath string
+	original         []byte
+	oldPerm          fs.FileMode
+	result           []byte
+	perm             fs.FileMode
+	// remove is set when oldPath must disappear (deletions and renames).
+	remove bool
+	// write is set when newPath must be (re)written.
+	write bool
+	// exists is set when a rename or copy targets a path that exists.
+	exists bool
+}
+
+// ApplyFS applies every file diff in the patch set to fsys: it modifies
+// files, creates and deletes them as the headers indicate, and performs
+// renames and copies. All hunks of all files are applied in memory first, so
+// if any file fails to patch nothing is written. Each file is then replaced
+// through fsys.WriteFile, which is atomic for DirFS; if a write or removal
+// fails, the files already changed are restored as far as fsys allows and
+// the error reports any that could not be. Renames and copies onto a file
+// that exists, and is not itself renamed away by the patch, are rejected.
+//
+// Every file diff is applied to the files as they were before the patch, as
+// git apply does, so a patch set is rejected if two of its file diffs write
+// or remove the same path, or one modifies a file that another deletes or
+// renames. Renames and copies may still read a path another file diff
+// changes, as when two files swap names.
+//
+// Example:
+//
+//	ps, err := difflib.ParsePatchSet(patch)
+//	if err != nil {
+//	    return err
+//	}
+//	err = ps.ApplyFS(difflib.DirFS(repoRoot), difflib.ApplyFSOptions{Strip: 1, BackupSuffix: ".orig"})
+func (p *PatchSet) ApplyFS(fsys PatchFS, opts ApplyFSOptions) error {
+	changes := make([]fileChange, 0, len(p.Files))
+	for _, f := range p.Files {
+		c, err := planFileChange(fsys, f, opts)
+		if err != nil {
+			return err
+		}
+		changes = append(changes, c)
+	}
+	if err := checkTargets(changes); err != nil {
+		return err
+	}
+
+	// touched lists the paths changed so far, for rolling back on failure.
+	var touched []string
+	fail := func(err error) error {
+		return errors.Join(err, rollBack(fsys, changes, touched))
+	}
+	written := make(map[string]bool)
+	for _, c := range changes {
+		if opts.BackupSuffix != "" && c.original != nil && (c.remove || c.write && c.oldPath == c.newPath) {
+			if err := fsys.WriteFile(c.oldPath+opts.BackupSuffix, c.original, c.oldPerm); err != nil {
+				return fmt.Errorf("difflib: backing up %s: %w", c.oldPath, err)
+			}
+		}
+	}
+	for _, c := range changes {
+		if c.write {
+			touched = append(touched, c.newPath)
+			if err := fsys.WriteFile(c.newPath, c.result, c.perm); err != nil {
+				return fail(fmt.Errorf("difflib: writing %s: %w", c.newPath, err))
+			}
+			written[c.newPath] = true
+		}
+	}
+	for _, c := range changes {
+		if c.remove && !written[c.oldPath] {
+			if err := fsys.Remove(c.oldPath); err != nil {
+				return fail(fmt.Errorf("difflib: removing %s: %w", c.oldPath, err))
+			}
+			touched = append(touched, c.oldPath)
+		}
+	}
+	return nil
+}
+
+// rollBack restores the paths in touched to their contents before changes
+// were applied, removing those that did not exist. It returns an error
+// naming the paths it could not restore.
+func rollBack(fsys PatchFS, changes []fileChange, touched []string) error {
+	type orig struct {
+		data []byte
+		perm fs.FileMode
+	}
+	originals := make(map[string]orig)
+	for _, c := range changes {
+		if c.original != nil {
+			originals[c.oldPath] = orig{c.original, c.oldPerm}
+		}
+	}
+	var errs []error
+	for k := len(touched) - 1; k >= 0; k-- {
+		name := touched[k]
+		var err error
+		if o, ok := originals[name]; ok {
+			err = fsys.WriteFile(name, o.data, o.perm)
+		} else if err = fsys.Remove(name); errors.Is(err, fs.ErrNotExist) {
+			err = nil
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("difflib: restoring %s: %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// planFileChange reads the file targeted by f and computes its new contents.
+func planFileChange(fsys PatchFS, f FileDiff, opts ApplyFSOptions) (fileChange, error) {
+	c := fileChange{
+		oldPath: stripPath(f.OldName, opts.Strip),
+		newPath: stripPath(f.NewName, opts.Strip),
+	}
+	for _, name := range []string{c.oldPath, c.newPath} {
+		if !fs.ValidPath(name) || name == "." {
+			return c, fmt.Errorf("difflib: invalid path %q after stripping %d components from %q",
+				name, opts.Strip, f.NewName)
+		}
+	}
+	if f.Binary {
+		return c, fmt.Errorf("difflib: %s: binary patches are not supported", c.newPath)
+	}
+
+	var original []string
+	c.perm = 0o644
+	if !f.NewFile {
+		data, err := fs.ReadFile(fsys, c.oldPath)
+		if err != nil {
+			return c, fmt.Errorf("difflib: reading %s: %w", c.oldPath, err)
+		}
+		if info, err := fs.Stat(fsys, c.oldPath); err == nil {
+			c.oldPerm = info.Mode().Perm()
+			c.perm = c.oldPerm
+		}
+		c.original = data
+		original = SplitLines(string(data))
+	} else if _, err := fs.Stat(fsys, c.newPath); err == nil {
+		return c, fmt.Errorf("difflib: %s: cannot create file: already exists", c.newPath)
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return c, fmt.Errorf("difflib: %s: %w", c.newPath, err)
+	}
+	if perm, ok := parseGitMode(f.NewMode); ok {
+		c.perm = perm
+	}
+	if (f.Rename || f.Copy) && c.newPath != c.oldPath {
+		if _, err := fs.Stat(fsys, c.newPath); err == nil {
+			c.exists = true
+		} else if !errors.Is(err, fs.ErrNotExist) {
+			return c, fmt.Errorf("difflib: %s: %w", c.newPath, err)
+		}
+	}
+
+	result, _, err := applyHunks(original, f.Hunks, opts.ApplyOptions)
+	if err != nil {
+		return c, &contextError{context: c.oldPath, err: err}
+	}
+
+	switch {
+	case f.DeletedFile:
+		if len(result) != 0 {
+			return c, fmt.Errorf("difflib: %s: file is not empty after deletion patch", c.oldPath)
+		}
+		c.remove = true
+	case f.Rename:
+		c.remove, c.write = true, true
+	default:
+		c.write = true
+	}
+	c.result = []byte(JoinLines(result))
+	return c, nil
+}
+
+// checkTargets reports an error if changes, each planned against the
+// original files, would undo or overwrite one another on some path.
+func checkTargets(changes []fileChange) error {
+	type use struct {
+		writes, removes int
+		modified        bool // written in place, from its own contents
+	}
+	uses := make(map[string]*use)
+	get := func(name string) *use {
+		if uses[name] == nil {
+			uses[name] = &use{}
+		}
+		return uses[name]
+	}
+	for _, c := range changes {
+		if c.write {
+			u := get(c.newPath)
+			u.writes++
+			u.modified = u.modified || c.oldPath == c.newPath
+		}
+		if c.remove {
+			get(c.oldPath).removes++
+		}
+	}
+	for _, c := range changes {
+		if c.exists && uses[c.newPath].removes == 0 {
+			return fmt.Errorf("difflib: %s: cannot rename or copy onto existing file", c.newPath)
+		}
+		for _, name := range []string{c.oldPath, c.newPath} {
+			if u := uses[name]; u != nil && (u.writes > 1 || u.removes > 1 || u.removes > 0 && u.modified) {
+				return fmt.Errorf("difflib: %s: changed by more than one file diff", name)
+			}
+		}
+	}
+	return nil
+}
+
+// parseGitMode converts a git file mode such as "100755" to permission bits.
+func parseGitMode(mode string) (fs.FileMode, bool) {
+	if mode == "" {
+		return 0, false
+	}
+	n, err := strconv.ParseUint(mode, 8, 32)
+	if err != nil {
+		return 0, false
+	}
+	return fs.FileMode(n).Perm(), true
+}
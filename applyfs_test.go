@@ -0,0 +1,231 @@
+package difflib_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	difflib "github.com/njchilds90/go-difflib"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func readFile(t *testing.T, dir, name string) (string, bool) {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+	if os.IsNotExist(err) {
+		return "", false
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data), true
+}
+
+func TestPatchSetApplyFS(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"main.go":       "package main\nvar x = 1\nfunc main() {}\n",
+		"old.txt":       "bye\n",
+		"docs/a b.md":   "doc\n",
+		"from.txt":      "keep\nold\n",
+		"untouched.txt": "same\n",
+	})
+	ps, err := difflib.ParsePatchSet(gitPatch)
+	if err != nil {
+		t.Fatalf("ParsePatchSet error: %v", err)
+	}
+	if err := ps.ApplyFS(difflib.DirFS(dir), difflib.ApplyFSOptions{Strip: 1, BackupSuffix: ".orig"}); err != nil {
+		t.Fatalf("ApplyFS error: %v", err)
+	}
+
+	want := map[string]string{
+		"main.go":       "package main\nvar x = 2\nfunc main() {}\n",
+		"main.go.orig":  "package main\nvar x = 1\nfunc main() {}\n",
+		"new.txt":       "hello\n-- not a header\n",
+		"old.txt.orig":  "bye\n",
+		"docs/c d.md":   "doc\n",
+		"to.txt":        "keep\nnew\n",
+		"from.txt.orig": "keep\nold\n",
+		"untouched.txt": "same\n",
+	}
+	for name, content := range want {
+		got, ok := readFile(t, dir, name)
+		if !ok || got != content {
+			t.Errorf("%s = %q (exists=%v), want %q", name, got, ok, content)
+		}
+	}
+	for _, name := range []string{"old.txt", "from.txt", "docs/a b.md"} {
+		if _, ok := readFile(t, dir, name); ok {
+			t.Errorf("%s should have been removed", name)
+		}
+	}
+	if info, err := os.Stat(filepath.Join(dir, "new.txt")); err != nil || info.Mode().Perm() != 0o755 {
+		t.Errorf("new.txt mode = %v (err %v), want 0755", info.Mode().Perm(), err)
+	}
+}
+
+func TestPatchSetApplyFSAllOrNothing(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"main.go":  "package main\nvar x = 1\nfunc main() {}\n",
+		"old.txt":  "bye\n",
+		"from.txt": "something else\n",
+	})
+	ps, err := difflib.ParsePatchSet(gitPatch)
+	if err != nil {
+		t.Fatalf("ParsePatchSet error: %v", err)
+	}
+	if err := ps.ApplyFS(difflib.DirFS(dir), difflib.ApplyFSOptions{Strip: 1}); err == nil {
+		t.Fatal("expected error for non-applying patch")
+	}
+	if got, _ := readFile(t, dir, "main.go"); got != "package main\nvar x = 1\nfunc main() {}\n" {
+		t.Errorf("main.go was modified despite failure: %q", got)
+	}
+	if _, ok := readFile(t, dir, "new.txt"); ok {
+		t.Error("new.txt was created despite failure")
+	}
+}
+
+func TestPatchSetApplyFSRejectsEscapingPaths(t *testing.T) {
+	ps, err := difflib.ParsePatchSet("--- ../etc/passwd\n+++ ../etc/passwd\n@@ -1 +1 @@\n-a\n+b\n")
+	if err != nil {
+		t.Fatalf("ParsePatchSet error: %v", err)
+	}
+	if err := ps.ApplyFS(difflib.DirFS(t.TempDir()), difflib.ApplyFSOptions{}); err == nil {
+		t.Error("expected error for path outside the root")
+	}
+}
+
+func TestPatchSetApplyFSErrorPrefix(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"main.go": "package other\n"})
+	ps, err := difflib.ParsePatchSet("--- a/main.go\n+++ b/main.go\n@@ -1 +1 @@\n-package main\n+package app\n")
+	if err != nil {
+		t.Fatalf("ParsePatchSet error: %v", err)
+	}
+	err = ps.ApplyFS(difflib.DirFS(dir), difflib.ApplyFSOptions{Strip: 1})
+	if err == nil || !strings.HasPrefix(err.Error(), "difflib: main.go: hunk #1 failed") ||
+		strings.Count(err.Error(), "difflib: ") != 1 {
+		t.Errorf("ApplyFS error = %v, want one difflib prefix followed by the path", err)
+	}
+}
+
+func TestPatchSetApplyFSSamePath(t *testing.T) {
+	files := map[string]string{"a.txt": "one\ntwo\n", "b.txt": "three\n"}
+	tests := []struct {
+		name, patch string
+		ok          bool
+	}{
+		{
+			name:  "modified twice",
+			patch: "--- a/a.txt\n+++ b/a.txt\n@@ -1 +1 @@\n-one\n+1\n--- a/a.txt\n+++ b/a.txt\n@@ -2 +2 @@\n-two\n+2\n",
+		},
+		{
+			name: "modified and deleted",
+			patch: "--- a/a.txt\n+++ b/a.txt\n@@ -1 +1 @@\n-one\n+1\n" +
+				"diff --git a/a.txt b/a.txt\ndeleted file mode 100644\n--- a/a.txt\n+++ /dev/null\n@@ -1,2 +0,0 @@\n-one\n-two\n",
+		},
+		{
+			name: "swapped by renames",
+			patch: "diff --git a/a.txt b/b.txt\nsimilarity index 100%\nrename from a.txt\nrename to b.txt\n" +
+				"diff --git a/b.txt b/a.txt\nsimilarity index 100%\nrename from b.txt\nrename to a.txt\n",
+			ok: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeFiles(t, dir, files)
+			ps, err := difflib.ParsePatchSet(tt.patch)
+			if err != nil {
+				t.Fatalf("ParsePatchSet error: %v", err)
+			}
+			err = ps.ApplyFS(difflib.DirFS(dir), difflib.ApplyFSOptions{Strip: 1})
+			if !tt.ok {
+				if err == nil || !strings.Contains(err.Error(), "a.txt: changed by more than one file diff") {
+					t.Errorf("ApplyFS error = %v, want a.txt rejected", err)
+				}
+				if got, _ := readFile(t, dir, "a.txt"); got != files["a.txt"] {
+					t.Errorf("a.txt = %q after a rejected patch", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ApplyFS error: %v", err)
+			}
+			a, _ := readFile(t, dir, "a.txt")
+			b, _ := readFile(t, dir, "b.txt")
+			if a != files["b.txt"] || b != files["a.txt"] {
+				t.Errorf("a.txt = %q, b.txt = %q; want them swapped", a, b)
+			}
+		})
+	}
+}
+
+// failFS is a PatchFS whose WriteFile fails for one name.
+type failFS struct {
+	difflib.PatchFS
+	name string
+}
+
+func (f failFS) WriteFile(name string, data []byte, perm os.FileMode) error {
+	if name == f.name {
+		return errWriteFailed
+	}
+	return f.PatchFS.WriteFile(name, data, perm)
+}
+
+func TestPatchSetApplyFSRollsBack(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"a.txt": "one\n", "b.txt": "two\n"})
+	patch := "--- a/a.txt\n+++ b/a.txt\n@@ -1 +1 @@\n-one\n+1\n" +
+		"diff --git a/b.txt b/b.txt\ndeleted file mode 100644\n--- a/b.txt\n+++ /dev/null\n@@ -1 +0,0 @@\n-two\n" +
+		"diff --git a/new.txt b/new.txt\nnew file mode 100644\n--- /dev/null\n+++ b/new.txt\n@@ -0,0 +1 @@\n+new\n" +
+		"diff --git a/z.txt b/z.txt\nnew file mode 100644\n--- /dev/null\n+++ b/z.txt\n@@ -0,0 +1 @@\n+z\n"
+	ps, err := difflib.ParsePatchSet(patch)
+	if err != nil {
+		t.Fatalf("ParsePatchSet error: %v", err)
+	}
+	err = ps.ApplyFS(failFS{difflib.DirFS(dir), "z.txt"}, difflib.ApplyFSOptions{Strip: 1})
+	if !errors.Is(err, errWriteFailed) {
+		t.Fatalf("ApplyFS error = %v, want the write failure", err)
+	}
+	for name, want := range map[string]string{"a.txt": "one\n", "b.txt": "two\n"} {
+		if got, _ := readFile(t, dir, name); got != want {
+			t.Errorf("%s = %q after rollback, want %q", name, got, want)
+		}
+	}
+	if _, ok := readFile(t, dir, "new.txt"); ok {
+		t.Error("new.txt was left behind after rollback")
+	}
+}
+
+func TestPatchSetApplyFSRenameOntoExisting(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"a.txt": "one\n", "b.txt": "two\n"})
+	ps, err := difflib.ParsePatchSet("diff --git a/a.txt b/b.txt\nsimilarity index 100%\nrename from a.txt\nrename to b.txt\n")
+	if err != nil {
+		t.Fatalf("ParsePatchSet error: %v", err)
+	}
+	err = ps.ApplyFS(difflib.DirFS(dir), difflib.ApplyFSOptions{Strip: 1})
+	if err == nil || !strings.Contains(err.Error(), "b.txt: cannot rename or copy onto existing file") {
+		t.Errorf("ApplyFS error = %v, want b.txt rejected", err)
+	}
+	if got, _ := readFile(t, dir, "b.txt"); got != "two\n" {
+		t.Errorf("b.txt = %q, want it untouched", got)
+	}
+}
@@ -0,0 +1,244 @@
+package difflib
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// OpenArchive reads a tar, gzip-compressed tar or zip archive into memory and
+// returns its regular files as a read-only fs.FS, suitable for DiffTrees.
+// The format is detected from the content. Member names are cleaned, so
+// "./bin/tool" and "bin/tool" are the same file and "../x" cannot escape
+// the archive root.
+//
+// Example:
+//
+//	f, _ := os.Open("release.tar.gz")
+//	defer f.Close()
+//	fsys, err := difflib.OpenArchive(f)
+func OpenArchive(r io.Reader) (fs.FS, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("difflib: reading archive: %w", err)
+	}
+	m := memFS{".": {name: ".", mode: fs.ModeDir | 0o755}}
+	switch {
+	case bytes.HasPrefix(data, []byte("PK\x03\x04")) || bytes.HasPrefix(data, []byte("PK\x05\x06")):
+		err = m.addZip(data)
+	case bytes.HasPrefix(data, []byte{0x1f, 0x8b}):
+		var gz *gzip.Reader
+		if gz, err = gzip.NewReader(bytes.NewReader(data)); err == nil {
+			err = m.addTar(gz)
+		}
+	default:
+		err = m.addTar(bytes.NewReader(data))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("difflib: reading archive: %w", err)
+	}
+	return m, nil
+}
+
+// DiffArchives opens the archive files at oldPath and newPath (see
+// OpenArchive) and diffs their members with DiffTrees, reporting added,
+// removed and modified members as a patch set. It is handy for checking
+// that two builds are reproducible.
+//
+// Example:
+//
+//	ps, err := difflib.DiffArchives("build1.tar.gz", "build2.tar.gz", difflib.TreeDiffOptions{})
+//	if err == nil && len(ps.Files) > 0 {
+//	    fmt.Print(difflib.DiffStat(ps))
+//	}
+func DiffArchives(oldPath, newPath string, opts TreeDiffOptions) (*PatchSet, error) {
+	open := func(name string) (fs.FS, error) {
+		f, err := os.Open(name)
+		if err != nil {
+			return nil, fmt.Errorf("difflib: %w", err)
+		}
+		defer f.Close()
+		fsys, err := OpenArchive(f)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		return fsys, nil
+	}
+	oldFS, err := open(oldPath)
+	if err != nil {
+		return nil, err
+	}
+	newFS, err := open(newPath)
+	if err != nil {
+		return nil, err
+	}
+	return DiffTrees(oldFS, newFS, opts)
+}
+
+// memFS is an in-memory file system holding the contents of an archive,
+// keyed by cleaned path. Parent directories are created implicitly.
+type memFS map[string]*memFile
+
+func (m memFS) addTar(r io.Reader) error {
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if hdr.Typeflag != tar.TypeReg && hdr.Typeflag != tar.TypeRegA {
+			continue
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return err
+		}
+		if err := m.add(hdr.Name, data, fs.FileMode(hdr.Mode).Perm(), hdr.ModTime); err != nil {
+			return err
+		}
+	}
+}
+
+func (m memFS) addZip(data []byte) error {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return err
+	}
+	for _, f := range zr.File {
+		if !f.Mode().IsRegular() {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return err
+		}
+		content, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return err
+		}
+		if err := m.add(f.Name, content, f.Mode().Perm(), f.Modified); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// add stores a regular file and creates its parent directories.
+func (m memFS) add(name string, data []byte, perm fs.FileMode, modTime time.Time) error {
+	name = strings.TrimPrefix(path.Clean("/"+name), "/")
+	if name == "" || !fs.ValidPath(name) {
+		return fmt.Errorf("invalid member name %q", name)
+	}
+	if f, ok := m[name]; ok && f.IsDir() {
+		return fmt.Errorf("member %q is both a file and a directory", name)
+	}
+	m[name] = &memFile{name: name, data: data, mode: perm, modTime: modTime}
+	for dir := path.Dir(name); dir != "."; dir = path.Dir(dir) {
+		if f, ok := m[dir]; ok {
+			if !f.IsDir() {
+				return fmt.Errorf("member %q is both a file and a directory", dir)
+			}
+			break
+		}
+		m[dir] = &memFile{name: dir, mode: fs.ModeDir | 0o755}
+	}
+	return nil
+}
+
+func (m memFS) Open(name string) (fs.File, error) {
+	f, ok := m[name]
+	if !ok || !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	if f.IsDir() {
+		entries, err := m.ReadDir(name)
+		if err != nil {
+			return nil, err
+		}
+		return &openMemDir{memFile: f, entries: entries}, nil
+	}
+	return &openMemFile{memFile: f, Reader: bytes.NewReader(f.data)}, nil
+}
+
+func (m memFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	if f, ok := m[name]; !ok || !f.IsDir() {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+	var entries []fs.DirEntry
+	for p, f := range m {
+		if p != "." && path.Dir(p) == name {
+			entries = append(entries, f)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+	return entries, nil
+}
+
+// memFile is a file or directory of a memFS. It serves as its own
+// fs.FileInfo and fs.DirEntry.
+type memFile struct {
+	name    string
+	data    []byte
+	mode    fs.FileMode
+	modTime time.Time
+}
+
+func (f *memFile) Name() string               { return path.Base(f.name) }
+func (f *memFile) Size() int64                { return int64(len(f.data)) }
+func (f *memFile) Mode() fs.FileMode          { return f.mode }
+func (f *memFile) ModTime() time.Time         { return f.modTime }
+func (f *memFile) IsDir() bool                { return f.mode.IsDir() }
+func (f *memFile) Sys() any                   { return nil }
+func (f *memFile) Type() fs.FileMode          { return f.mode.Type() }
+func (f *memFile) Info() (fs.FileInfo, error) { return f, nil }
+
+// openMemFile is an open memFile.
+type openMemFile struct {
+	*memFile
+	*bytes.Reader
+}
+
+func (f *openMemFile) Stat() (fs.FileInfo, error) { return f.memFile, nil }
+func (f *openMemFile) Close() error               { return nil }
+
+// openMemDir is an open memFile directory.
+type openMemDir struct {
+	*memFile
+	entries []fs.DirEntry
+}
+
+func (d *openMemDir) Stat() (fs.FileInfo, error) { return d.memFile, nil }
+func (d *openMemDir) Close() error               { return nil }
+
+func (d *openMemDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.name, Err: errors.New("is a directory")}
+}
+
+func (d *openMemDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	if n <= 0 {
+		entries := d.entries
+		d.entries = nil
+		return entries, nil
+	}
+	if len(d.entries) == 0 {
+		return nil, io.EOF
+	}
+	n = min(n, len(d.entries))
+	entries := d.entries[:n]
+	d.entries = d.entries[n:]
+	return entries, nil
+}
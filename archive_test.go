@@ -0,0 +1,147 @@
+package difflib_test
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	difflib "github.com/njchilds90/go-difflib"
+)
+
+func makeTar(t *testing.T, files map[string]string, compress bool) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	var w io.Writer = &buf
+	var gz *gzip.Writer
+	if compress {
+		gz = gzip.NewWriter(&buf)
+		w = gz
+	}
+	tw := tar.NewWriter(w)
+	for _, name := range sortedKeys(files) {
+		hdr := &tar.Header{Name: name, Mode: 0o644, Size: int64(len(files[name])), Typeflag: tar.TypeReg}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(files[name])); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if gz != nil {
+		if err := gz.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return buf.Bytes()
+}
+
+func makeZip(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, name := range sortedKeys(files) {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(files[name])); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func sortedKeys(m map[string]string) []string {
+	var keys []string
+	for k := range m {
+		keys = append(keys, k)
+	}
+	for i := 1; i < len(keys); i++ {
+		for j := i; j > 0 && keys[j] < keys[j-1]; j-- {
+			keys[j], keys[j-1] = keys[j-1], keys[j]
+		}
+	}
+	return keys
+}
+
+func TestOpenArchive(t *testing.T) {
+	files := map[string]string{"./bin/tool": "#!/bin/sh\n", "README": "hi\n", "a/b/c.txt": "deep\n"}
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"tar", makeTar(t, files, false)},
+		{"tar.gz", makeTar(t, files, true)},
+		{"zip", makeZip(t, files)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fsys, err := difflib.OpenArchive(bytes.NewReader(tt.data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := fstest.TestFS(fsys, "bin/tool", "README", "a/b/c.txt"); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestOpenArchiveMemberNames(t *testing.T) {
+	data := makeZip(t, map[string]string{"../evil": "x\n"})
+	fsys, err := difflib.OpenArchive(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := fstest.TestFS(fsys, "evil"); err != nil {
+		t.Fatal(err)
+	}
+	data = makeTar(t, map[string]string{"a": "x\n", "a/b": "y\n"}, false)
+	if _, err := difflib.OpenArchive(bytes.NewReader(data)); err == nil {
+		t.Fatal("expected an error for a member that is both a file and a directory")
+	}
+}
+
+func TestDiffArchives(t *testing.T) {
+	dir := t.TempDir()
+	oldPath, newPath := filepath.Join(dir, "old.tar.gz"), filepath.Join(dir, "new.zip")
+	oldData := makeTar(t, map[string]string{"same.txt": "x\n", "changed.txt": "a\nb\n", "removed.txt": "bye\n"}, true)
+	newData := makeZip(t, map[string]string{"same.txt": "x\n", "changed.txt": "a\nB\n", "added.txt": "hi\n"})
+	if err := os.WriteFile(oldPath, oldData, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(newPath, newData, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	ps, err := difflib.DiffArchives(oldPath, newPath, difflib.TreeDiffOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, f := range ps.Files {
+		got = append(got, describeFileDiff(f))
+	}
+	if want := "N added.txt|M changed.txt|D removed.txt"; strings.Join(got, "|") != want {
+		t.Errorf("files = %q, want %q", strings.Join(got, "|"), want)
+	}
+	if !strings.Contains(ps.String(), "-b\n+B\n") {
+		t.Errorf("missing member diff:\n%s", ps)
+	}
+
+	if _, err := difflib.DiffArchives(filepath.Join(dir, "missing.tar"), newPath, difflib.TreeDiffOptions{}); err == nil {
+		t.Error("expected an error for a missing archive")
+	}
+}
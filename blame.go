@@ -0,0 +1,81 @@
+package difflib
+
+import "fmt"
+
+// BlameLine is a line of the document produced by Blame.
+type BlameLine struct {
+	// Text is the line, with its line ending.
+	Text string `json:"text"`
+	// Patch is the index of the patch that introduced the line, or -1 if it
+	// comes from the base document.
+	Patch int `json:"patch"`
+}
+
+// Blame applies the unified diffs patches to base in order, as
+// ApplyPatchWithOptions does with opts, and returns the final document with
+// the patch that introduced each line. A line keeps its origin for as long
+// as later patches carry it as context, so a line that is deleted and added
+// back is attributed to the patch that added it back. An error names the
+// first patch that does not apply.
+//
+// Example:
+//
+//	lines, err := difflib.Blame(v1, []string{patch1, patch2}, difflib.ApplyOptions{})
+//	for _, l := range lines {
+//	    fmt.Printf("%3d %s", l.Patch+1, l.Text) // 0 for base lines
+//	}
+func Blame(base []string, patches []string, opts ApplyOptions) ([]BlameLine, error) {
+	text := base
+	origin := make([]int, len(base))
+	for i := range origin {
+		origin[i] = -1
+	}
+	for n, patch := range patches {
+		d, err := parseDiff(patch)
+		if err != nil {
+			return nil, &contextError{context: fmt.Sprintf("patch %d", n), err: err}
+		}
+		placed := placeHunks(text, d.Hunks, opts)
+		for _, p := range placed {
+			if p.result.Err != nil {
+				return nil, &contextError{context: fmt.Sprintf("patch %d", n), err: p.result.Err}
+			}
+		}
+		next := make([]int, 0, len(origin))
+		prev := 0
+		for k, p := range placed {
+			next = append(next, origin[prev:p.pos]...)
+			next = append(next, blameHunk(d.Hunks[k], p, origin, n)...)
+			prev = p.end
+		}
+		origin = append(next, origin[prev:]...)
+		text = splicePlacements(text, placed)
+	}
+	out := make([]BlameLine, len(text))
+	for i, l := range text {
+		out[i] = BlameLine{Text: l, Patch: origin[i]}
+	}
+	return out, nil
+}
+
+// blameHunk returns the origins of the lines that the placed hunk h writes:
+// those of the old lines for context and n for insertions.
+func blameHunk(h Hunk, p hunkPlacement, origin []int, n int) []int {
+	var out []int
+	k := p.pos
+	for _, l := range h.Lines[p.top : len(h.Lines)-p.bottom] {
+		if l == "" {
+			continue
+		}
+		switch l[0] {
+		case ' ':
+			out = append(out, origin[k])
+			k++
+		case '-':
+			k++
+		case '+':
+			out = append(out, n)
+		}
+	}
+	return out
+}
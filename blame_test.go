@@ -0,0 +1,72 @@
+package difflib_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	difflib "github.com/njchilds90/go-difflib"
+)
+
+func TestBlame(t *testing.T) {
+	v0 := difflib.SplitLines("a\nb\nc\nd\ne\nf\ng\nh\n")
+	v1 := difflib.SplitLines("a\nB\nc\nd\ne\nf\ng\nh\ni\n")
+	v2 := difflib.SplitLines("a\nB\nc\nd\ne\nF\ng\nh\ni\n")
+	v3 := difflib.SplitLines("a\nb\nc\nd\ne\nF\ng\nh\ni\n")
+	diff := func(a, b []string) string {
+		return difflib.UnifiedDiff(difflib.DiffInput{A: a, B: b, Context: 1}).String()
+	}
+	got, err := difflib.Blame(v0, []string{diff(v0, v1), diff(v1, v2), diff(v2, v3)}, difflib.ApplyOptions{})
+	if err != nil {
+		t.Fatalf("Blame error: %v", err)
+	}
+	want := []int{-1, 2, -1, -1, -1, 1, -1, -1, 0}
+	var text strings.Builder
+	for i, l := range got {
+		text.WriteString(l.Text)
+		if l.Patch != want[i] {
+			t.Errorf("line %d (%q): Patch = %d, want %d", i+1, l.Text, l.Patch, want[i])
+		}
+	}
+	if text.String() != difflib.JoinLines(v3) {
+		t.Errorf("text = %q, want %q", text.String(), difflib.JoinLines(v3))
+	}
+}
+
+func TestBlameFuzzAndErrors(t *testing.T) {
+	base := difflib.SplitLines("1\n2\n3\n4\n5\n")
+	// The first context line does not match; fuzz 1 ignores it.
+	patch := "@@ -1,3 +1,3 @@\n X\n-2\n+two\n 3\n"
+	got, err := difflib.Blame(base, []string{patch}, difflib.ApplyOptions{Fuzz: 1})
+	if err != nil {
+		t.Fatalf("Blame error: %v", err)
+	}
+	if len(got) != 5 || got[1].Text != "two\n" || got[1].Patch != 0 || got[0].Patch != -1 || got[2].Patch != -1 {
+		t.Errorf("Blame = %+v", got)
+	}
+	if _, err := difflib.Blame(base, []string{patch}, difflib.ApplyOptions{}); err == nil || !strings.HasPrefix(err.Error(), "difflib: patch 0: hunk #1") {
+		t.Errorf("error = %v, want one naming patch 0 after a single prefix", err)
+	}
+}
+
+func ExampleBlame() {
+	v0 := difflib.SplitLines("title\nbody\n")
+	v1 := difflib.SplitLines("title\nbody\nfooter\n")
+	v2 := difflib.SplitLines("Title\nbody\nfooter\n")
+	patches := []string{
+		difflib.UnifiedDiff(difflib.DiffInput{A: v0, B: v1}).String(),
+		difflib.UnifiedDiff(difflib.DiffInput{A: v1, B: v2}).String(),
+	}
+	lines, err := difflib.Blame(v0, patches, difflib.ApplyOptions{})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	for _, l := range lines {
+		fmt.Printf("%2d %s", l.Patch, l.Text)
+	}
+	// Output:
+	//  1 Title
+	// -1 body
+	//  0 footer
+}
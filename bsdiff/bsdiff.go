@@ -0,0 +1,276 @@
+// Package bsdiff creates and applies binary patches in the BSDIFF40 format
+// of Colin Percival's bsdiff 4, as read and written by bsdiff/bspatch and
+// the Python bsdiff4 module. Patches are compact for executables and other
+// binaries where small source changes shift much of the file.
+//
+// Basic usage:
+//
+//	patch := bsdiff.Diff(oldBinary, newBinary)
+//	restored, err := bsdiff.Patch(oldBinary, patch)
+package bsdiff
+
+import (
+	"bytes"
+	"compress/bzip2"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// magic starts every BSDIFF40 patch.
+const magic = "BSDIFF40"
+
+// headerSize is the length of the patch header: the magic followed by the
+// compressed control and diff block lengths and the new file size.
+const headerSize = 32
+
+// maxPrealloc caps the output buffer Patch allocates up front from the size
+// in the patch header.
+const maxPrealloc = 1 << 20
+
+// ErrCorrupt is returned (wrapped) by Patch when the patch is malformed or
+// does not fit the old data.
+var ErrCorrupt = errors.New("bsdiff: corrupt patch")
+
+// Diff returns a BSDIFF40 patch that turns old into new.
+//
+// Example:
+//
+//	patch := bsdiff.Diff(oldBinary, newBinary)
+//	os.WriteFile("update.bsdiff", patch, 0o644)
+func Diff(old, new []byte) []byte {
+	sa := suffixArray(old)
+	var ctrl, diff, extra []byte
+	var scan, pos, length int
+	var lastScan, lastPos, lastOffset int
+	for scan < len(new) {
+		oldScore := 0
+		scan += length
+		for scsc := scan; scan < len(new); scan++ {
+			pos, length = search(sa, old, new[scan:], 0, len(old))
+			for ; scsc < scan+length; scsc++ {
+				if scsc+lastOffset < len(old) && old[scsc+lastOffset] == new[scsc] {
+					oldScore++
+				}
+			}
+			if (length == oldScore && length != 0) || length > oldScore+8 {
+				break
+			}
+			if scan+lastOffset < len(old) && old[scan+lastOffset] == new[scan] {
+				oldScore--
+			}
+		}
+		if length == oldScore && scan != len(new) {
+			continue
+		}
+
+		// Extend the previous match forwards and the new one backwards
+		// as far as that pays off, then split any overlap between them.
+		lenF := 0
+		for i, s, best := 0, 0, 0; lastScan+i < scan && lastPos+i < len(old); {
+			if old[lastPos+i] == new[lastScan+i] {
+				s++
+			}
+			i++
+			if s*2-i > best*2-lenF {
+				best, lenF = s, i
+			}
+		}
+		lenB := 0
+		if scan < len(new) {
+			for i, s, best := 1, 0, 0; scan >= lastScan+i && pos >= i; i++ {
+				if old[pos-i] == new[scan-i] {
+					s++
+				}
+				if s*2-i > best*2-lenB {
+					best, lenB = s, i
+				}
+			}
+		}
+		if overlap := lastScan + lenF - (scan - lenB); overlap > 0 {
+			s, best, lenS := 0, 0, 0
+			for i := 0; i < overlap; i++ {
+				if new[lastScan+lenF-overlap+i] == old[lastPos+lenF-overlap+i] {
+					s++
+				}
+				if new[scan-lenB+i] == old[pos-lenB+i] {
+					s--
+				}
+				if s > best {
+					best, lenS = s, i+1
+				}
+			}
+			lenF += lenS - overlap
+			lenB -= lenS
+		}
+
+		for i := 0; i < lenF; i++ {
+			diff = append(diff, new[lastScan+i]-old[lastPos+i])
+		}
+		extra = append(extra, new[lastScan+lenF:scan-lenB]...)
+		ctrl = appendOff(ctrl, lenF)
+		ctrl = appendOff(ctrl, scan-lenB-(lastScan+lenF))
+		ctrl = appendOff(ctrl, pos-lenB-(lastPos+lenF))
+
+		lastScan, lastPos, lastOffset = scan-lenB, pos-lenB, pos-scan
+	}
+
+	ctrlZ, diffZ, extraZ := compressBzip2(ctrl), compressBzip2(diff), compressBzip2(extra)
+	out := make([]byte, 0, headerSize+len(ctrlZ)+len(diffZ)+len(extraZ))
+	out = append(out, magic...)
+	out = appendOff(out, len(ctrlZ))
+	out = appendOff(out, len(diffZ))
+	out = appendOff(out, len(new))
+	out = append(out, ctrlZ...)
+	out = append(out, diffZ...)
+	return append(out, extraZ...)
+}
+
+// Patch applies a BSDIFF40 patch to old and returns the new data. An error
+// wrapping ErrCorrupt is returned for a malformed patch.
+//
+// Example:
+//
+//	newBinary, err := bsdiff.Patch(oldBinary, patch)
+func Patch(old, patch []byte) ([]byte, error) {
+	if len(patch) < headerSize || string(patch[:len(magic)]) != magic {
+		return nil, fmt.Errorf("bsdiff: not a BSDIFF40 patch")
+	}
+	ctrlLen, diffLen, newSize := readOff(patch[8:]), readOff(patch[16:]), readOff(patch[24:])
+	body := patch[headerSize:]
+	if ctrlLen < 0 || diffLen < 0 || newSize < 0 || ctrlLen > len(body) || diffLen > len(body)-ctrlLen {
+		return nil, fmt.Errorf("%w: bad header", ErrCorrupt)
+	}
+	ctrl := bzip2.NewReader(bytes.NewReader(body[:ctrlLen]))
+	diff := bzip2.NewReader(bytes.NewReader(body[ctrlLen : ctrlLen+diffLen]))
+	extra := bzip2.NewReader(bytes.NewReader(body[ctrlLen+diffLen:]))
+
+	// The header's size is not trusted for allocation: out grows as the
+	// diff and extra blocks are decoded, so a patch claiming a huge size
+	// fails when its blocks run out rather than exhausting memory.
+	var out bytes.Buffer
+	out.Grow(min(newSize, maxPrealloc))
+	var buf [24]byte
+	oldPos := 0
+	for out.Len() < newSize {
+		if _, err := io.ReadFull(ctrl, buf[:]); err != nil {
+			return nil, fmt.Errorf("%w: reading control block: %v", ErrCorrupt, err)
+		}
+		add, copyLen, seek := readOff(buf[0:]), readOff(buf[8:]), readOff(buf[16:])
+		newPos := out.Len()
+		if add < 0 || add > newSize-newPos {
+			return nil, fmt.Errorf("%w: diff length %d out of range", ErrCorrupt, add)
+		}
+		if _, err := io.CopyN(&out, diff, int64(add)); err != nil {
+			return nil, fmt.Errorf("%w: reading diff block: %v", ErrCorrupt, err)
+		}
+		added := out.Bytes()[newPos:]
+		for i := range added {
+			if p := oldPos + i; p >= 0 && p < len(old) {
+				added[i] += old[p]
+			}
+		}
+		newPos += add
+		oldPos += add
+		if copyLen < 0 || copyLen > newSize-newPos {
+			return nil, fmt.Errorf("%w: extra length %d out of range", ErrCorrupt, copyLen)
+		}
+		if _, err := io.CopyN(&out, extra, int64(copyLen)); err != nil {
+			return nil, fmt.Errorf("%w: reading extra block: %v", ErrCorrupt, err)
+		}
+		oldPos += seek
+	}
+	return out.Bytes(), nil
+}
+
+// appendOff appends v as bsdiff's 8-byte little-endian sign-magnitude integer.
+func appendOff(b []byte, v int) []byte {
+	u := uint64(v)
+	if v < 0 {
+		u = uint64(-v) | 1<<63
+	}
+	return binary.LittleEndian.AppendUint64(b, u)
+}
+
+// readOff decodes an 8-byte sign-magnitude integer written by appendOff.
+func readOff(b []byte) int {
+	u := binary.LittleEndian.Uint64(b)
+	v := int(u &^ (1 << 63))
+	if u&(1<<63) != 0 {
+		return -v
+	}
+	return v
+}
+
+// suffixArray returns the sorted suffixes of b, including the empty suffix
+// first, by prefix doubling.
+func suffixArray(b []byte) []int {
+	n := len(b) + 1
+	sa := make([]int, n)
+	rank := make([]int, n)
+	tmp := make([]int, n)
+	for i := range sa {
+		sa[i] = i
+		rank[i] = -1 // the empty suffix sorts first
+		if i < len(b) {
+			rank[i] = int(b[i])
+		}
+	}
+	key := func(i, k int) int {
+		if i+k < n {
+			return rank[i+k]
+		}
+		return -2
+	}
+	for k := 1; ; k <<= 1 {
+		less := func(x, y int) bool {
+			if rank[x] != rank[y] {
+				return rank[x] < rank[y]
+			}
+			return key(x, k) < key(y, k)
+		}
+		sort.Slice(sa, func(i, j int) bool { return less(sa[i], sa[j]) })
+		tmp[sa[0]] = 0
+		for i := 1; i < n; i++ {
+			tmp[sa[i]] = tmp[sa[i-1]]
+			if less(sa[i-1], sa[i]) {
+				tmp[sa[i]]++
+			}
+		}
+		copy(rank, tmp)
+		if rank[sa[n-1]] == n-1 {
+			return sa
+		}
+	}
+}
+
+// search finds the suffix of old in sa[st:en+1] with the longest common
+// prefix with target, returning its position and the prefix length.
+func search(sa []int, old, target []byte, st, en int) (int, int) {
+	for en-st >= 2 {
+		x := st + (en-st)/2
+		if bytes.Compare(old[sa[x]:sa[x]+min(len(old)-sa[x], len(target))], target[:min(len(old)-sa[x], len(target))]) < 0 {
+			st = x
+		} else {
+			en = x
+		}
+	}
+	x, y := matchLen(old[sa[st]:], target), matchLen(old[sa[en]:], target)
+	if x > y {
+		return sa[st], x
+	}
+	return sa[en], y
+}
+
+// matchLen returns the length of the common prefix of a and b.
+func matchLen(a, b []byte) int {
+	n := min(len(a), len(b))
+	for i := 0; i < n; i++ {
+		if a[i] != b[i] {
+			return i
+		}
+	}
+	return n
+}
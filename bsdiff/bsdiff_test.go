@@ -0,0 +1,104 @@
+package bsdiff_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/njchilds90/go-difflib/bsdiff"
+)
+
+func TestDiffPatchRoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	random := func(n int) []byte {
+		b := make([]byte, n)
+		rng.Read(b)
+		return b
+	}
+	base := random(20000)
+	// Simulate a recompiled binary: small edits plus shifted offsets.
+	edited := append([]byte{}, base...)
+	for i := 0; i < len(edited); i += 500 {
+		edited[i]++
+	}
+	edited = append(append(append([]byte{}, edited[:8000]...), random(100)...), edited[8000:]...)
+	tests := []struct {
+		name     string
+		old, new []byte
+		maxSize  int
+	}{
+		{"empty", nil, nil, 200},
+		{"from empty", nil, []byte("hello, world\n"), 250},
+		{"to empty", base, nil, 200},
+		{"equal", base, base, 300},
+		{"edited", base, edited, 1500},
+		{"text", []byte(strings.Repeat("line of text\n", 500)), []byte(strings.Repeat("line of text!\n", 500)), 500},
+		{"runs", bytes.Repeat([]byte{0}, 5000), bytes.Repeat([]byte{0, 1}, 5000), 400},
+		{"unrelated", random(2000), random(2000), 3000},
+		// More than one bzip2 block of extra data.
+		{"large", nil, bytes.Repeat(base[:1000], 1000), 1 << 20},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			patch := bsdiff.Diff(tt.old, tt.new)
+			if len(patch) > tt.maxSize {
+				t.Errorf("len(patch) = %d, want at most %d", len(patch), tt.maxSize)
+			}
+			got, err := bsdiff.Patch(tt.old, patch)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, tt.new) {
+				t.Error("Patch(Diff()) differs from new")
+			}
+		})
+	}
+}
+
+func TestPatchErrors(t *testing.T) {
+	good := bsdiff.Diff([]byte("old data here"), []byte("new data here"))
+	damaged := append([]byte{}, good...)
+	damaged[40] ^= 0xFF // inside the compressed control block
+	huge := append([]byte{}, good...)
+	binary.LittleEndian.PutUint64(huge[24:], 1<<62) // new size in the header
+	tests := []struct {
+		name  string
+		patch []byte
+		want  string
+	}{
+		{"short", []byte("BSDIFF40"), "not a BSDIFF40 patch"},
+		{"magic", append([]byte("BSDIFF41"), good[8:]...), "not a BSDIFF40 patch"},
+		{"header", append(append([]byte{}, good[:8]...), bytes.Repeat([]byte{0xFF}, 24)...), "bad header"},
+		{"damaged", damaged, "corrupt patch"},
+		{"size", huge, "corrupt patch"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := bsdiff.Patch([]byte("old data here"), tt.patch)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Patch() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+	for _, patch := range [][]byte{damaged, huge} {
+		if _, err := bsdiff.Patch(nil, patch); !errors.Is(err, bsdiff.ErrCorrupt) {
+			t.Errorf("Patch() error = %v, want ErrCorrupt", err)
+		}
+	}
+}
+
+func ExampleDiff() {
+	old := []byte("version = 1.0.0\nbuild = 41\n")
+	new := []byte("version = 1.0.1\nbuild = 42\n")
+	patch := bsdiff.Diff(old, new)
+	restored, err := bsdiff.Patch(old, patch)
+	fmt.Printf("%s%v %s\n", restored, err, patch[:8])
+	// Output:
+	// version = 1.0.1
+	// build = 42
+	// <nil> BSDIFF40
+}
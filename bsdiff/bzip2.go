@@ -0,0 +1,377 @@
+package bsdiff
+
+import "bytes"
+
+// The standard library only decompresses bzip2, so this file implements the
+// small compressor bsdiff needs: run-length encoding, the Burrows-Wheeler
+// transform, move-to-front coding and Huffman coding with two identical
+// tables. It trades compression ratio for simplicity; the output is a
+// standard bzip2 stream readable by compress/bzip2 and bunzip2.
+
+// maxBlock is the largest block, after the initial run-length encoding,
+// written with the 900k block size of a "BZh9" stream.
+const maxBlock = 900000 - 19
+
+// maxCodeLen is the longest Huffman code the compressor emits.
+const maxCodeLen = 17
+
+// groupSize is the number of symbols coded with one selector.
+const groupSize = 50
+
+var crcTable = func() (t [256]uint32) {
+	for i := range t {
+		c := uint32(i) << 24
+		for k := 0; k < 8; k++ {
+			if c&0x80000000 != 0 {
+				c = c<<1 ^ 0x04C11DB7
+			} else {
+				c <<= 1
+			}
+		}
+		t[i] = c
+	}
+	return t
+}()
+
+// blockCRC is the non-reflected CRC-32 bzip2 uses for block checksums.
+func blockCRC(data []byte) uint32 {
+	crc := ^uint32(0)
+	for _, b := range data {
+		crc = crc<<8 ^ crcTable[byte(crc>>24)^b]
+	}
+	return ^crc
+}
+
+// bitWriter writes bits most significant first.
+type bitWriter struct {
+	buf   bytes.Buffer
+	acc   uint64
+	nbits uint
+}
+
+func (w *bitWriter) write(n uint, v uint64) {
+	w.acc = w.acc<<n | v&(1<<n-1)
+	w.nbits += n
+	for w.nbits >= 8 {
+		w.nbits -= 8
+		w.buf.WriteByte(byte(w.acc >> w.nbits))
+	}
+}
+
+func (w *bitWriter) flush() []byte {
+	if w.nbits > 0 {
+		w.write(8-w.nbits, 0)
+	}
+	return w.buf.Bytes()
+}
+
+// compressBzip2 returns data compressed as a bzip2 stream.
+func compressBzip2(data []byte) []byte {
+	w := &bitWriter{}
+	w.buf.WriteString("BZh9")
+	var combined uint32
+	block := make([]byte, 0, maxBlock)
+	start := 0
+	for i := 0; i <= len(data); {
+		run := 0
+		if i < len(data) {
+			run = 1
+			for run < 255 && i+run < len(data) && data[i+run] == data[i] {
+				run++
+			}
+		}
+		// Flush the block at the end of the input or when the next run
+		// might not fit.
+		if i == len(data) || len(block)+5 > maxBlock {
+			if len(block) > 0 {
+				crc := blockCRC(data[start:i])
+				combined = (combined<<1 | combined>>31) ^ crc
+				writeBlock(w, block, crc)
+			}
+			if i == len(data) {
+				break
+			}
+			block, start = block[:0], i
+		}
+		if run >= 4 {
+			block = append(block, data[i], data[i], data[i], data[i], byte(run-4))
+		} else {
+			block = append(block, data[i:i+run]...)
+		}
+		i += run
+	}
+	w.write(24, 0x177245)
+	w.write(24, 0x385090)
+	w.write(32, uint64(combined))
+	return w.flush()
+}
+
+// writeBlock writes one compressed block of run-length encoded data.
+func writeBlock(w *bitWriter, block []byte, crc uint32) {
+	bwt, origPtr := burrowsWheeler(block)
+
+	var inUse [256]bool
+	for _, b := range block {
+		inUse[b] = true
+	}
+	var seqToByte []byte
+	var byteToSeq [256]byte
+	for b := 0; b < 256; b++ {
+		if inUse[b] {
+			byteToSeq[b] = byte(len(seqToByte))
+			seqToByte = append(seqToByte, byte(b))
+		}
+	}
+	syms := moveToFront(bwt, byteToSeq, len(seqToByte))
+	alphaSize := len(seqToByte) + 2
+
+	freq := make([]int, alphaSize)
+	for _, s := range syms {
+		freq[s]++
+	}
+	lengths := huffmanLengths(freq)
+	codes := canonicalCodes(lengths)
+
+	w.write(24, 0x314159)
+	w.write(24, 0x265359)
+	w.write(32, uint64(crc))
+	w.write(1, 0) // not randomised
+	w.write(24, uint64(origPtr))
+
+	var used16 uint64
+	for i := 0; i < 16; i++ {
+		for _, u := range inUse[i*16 : i*16+16] {
+			if u {
+				used16 |= 1 << (15 - i)
+				break
+			}
+		}
+	}
+	w.write(16, used16)
+	for i := 0; i < 16; i++ {
+		if used16&(1<<(15-i)) == 0 {
+			continue
+		}
+		var bits uint64
+		for j, u := range inUse[i*16 : i*16+16] {
+			if u {
+				bits |= 1 << (15 - j)
+			}
+		}
+		w.write(16, bits)
+	}
+
+	// Two identical tables, the minimum the format allows, all selecting
+	// the first table.
+	const groups = 2
+	selectors := (len(syms) + groupSize - 1) / groupSize
+	w.write(3, groups)
+	w.write(15, uint64(selectors))
+	for i := 0; i < selectors; i++ {
+		w.write(1, 0)
+	}
+	for g := 0; g < groups; g++ {
+		cur := lengths[0]
+		w.write(5, uint64(cur))
+		for _, l := range lengths {
+			for ; cur < l; cur++ {
+				w.write(2, 2)
+			}
+			for ; cur > l; cur-- {
+				w.write(2, 3)
+			}
+			w.write(1, 0)
+		}
+	}
+	for _, s := range syms {
+		w.write(uint(lengths[s]), uint64(codes[s]))
+	}
+}
+
+// burrowsWheeler returns the last column of the sorted rotations of s and
+// the row of the unrotated string.
+func burrowsWheeler(s []byte) ([]byte, int) {
+	n := len(s)
+	p := make([]int, n)
+	c := make([]int, n)
+	cnt := make([]int, max(n, 256))
+	for _, b := range s {
+		cnt[b]++
+	}
+	for i := 1; i < 256; i++ {
+		cnt[i] += cnt[i-1]
+	}
+	for i := n - 1; i >= 0; i-- {
+		cnt[s[i]]--
+		p[cnt[s[i]]] = i
+	}
+	classes := 1
+	for i := 1; i < n; i++ {
+		if s[p[i]] != s[p[i-1]] {
+			classes++
+		}
+		c[p[i]] = classes - 1
+	}
+	pn := make([]int, n)
+	cn := make([]int, n)
+	for k := 1; k < n && classes < n; k <<= 1 {
+		// Sort by the second half first (already ordered by p), then
+		// stably by the class of the first half.
+		for i := range p {
+			pn[i] = (p[i] - k + n) % n
+		}
+		for i := 0; i < classes; i++ {
+			cnt[i] = 0
+		}
+		for _, v := range pn {
+			cnt[c[v]]++
+		}
+		for i := 1; i < classes; i++ {
+			cnt[i] += cnt[i-1]
+		}
+		for i := n - 1; i >= 0; i-- {
+			cnt[c[pn[i]]]--
+			p[cnt[c[pn[i]]]] = pn[i]
+		}
+		cn[p[0]] = 0
+		classes = 1
+		for i := 1; i < n; i++ {
+			cur, prev := p[i], p[i-1]
+			if c[cur] != c[prev] || c[(cur+k)%n] != c[(prev+k)%n] {
+				classes++
+			}
+			cn[cur] = classes - 1
+		}
+		c, cn = cn, c
+	}
+	out := make([]byte, n)
+	origPtr := 0
+	for i, r := range p {
+		if r == 0 {
+			origPtr = i
+		}
+		out[i] = s[(r+n-1)%n]
+	}
+	return out, origPtr
+}
+
+// moveToFront converts the transformed block into bzip2 symbols: zero runs
+// become RUNA (0) and RUNB (1) digits, other move-to-front positions v
+// become v+1, and the block ends with EOB.
+func moveToFront(bwt []byte, byteToSeq [256]byte, nInUse int) []uint16 {
+	list := make([]byte, nInUse)
+	for i := range list {
+		list[i] = byte(i)
+	}
+	var syms []uint16
+	zeros := 0
+	flushZeros := func() {
+		for zeros--; zeros >= 0; zeros = (zeros - 2) / 2 {
+			syms = append(syms, uint16(zeros&1))
+			if zeros < 2 {
+				break
+			}
+		}
+		zeros = 0
+	}
+	for _, b := range bwt {
+		seq := byteToSeq[b]
+		if list[0] == seq {
+			zeros++
+			continue
+		}
+		flushZeros()
+		j := 1
+		for list[j] != seq {
+			j++
+		}
+		copy(list[1:j+1], list[:j])
+		list[0] = seq
+		syms = append(syms, uint16(j+1))
+	}
+	flushZeros()
+	return append(syms, uint16(nInUse+1))
+}
+
+// huffmanLengths returns code lengths of at most maxCodeLen bits for the
+// given symbol frequencies. Every symbol gets a code, even unused ones.
+func huffmanLengths(freq []int) []int {
+	weights := make([]int, len(freq))
+	for i, f := range freq {
+		weights[i] = f + 1
+	}
+	for {
+		lengths := huffmanTree(weights)
+		if maxInts(lengths) <= maxCodeLen {
+			return lengths
+		}
+		for i := range weights {
+			weights[i] = weights[i]/2 + 1
+		}
+	}
+}
+
+// huffmanTree returns unlimited Huffman code lengths for weights.
+func huffmanTree(weights []int) []int {
+	n := len(weights)
+	weight := append([]int(nil), weights...)
+	parent := make([]int, n, 2*n)
+	alive := make([]int, n)
+	for i := range alive {
+		alive[i] = i
+		parent[i] = -1
+	}
+	for len(alive) > 1 {
+		// Take the two lightest nodes.
+		a, b := 0, 1
+		if weight[alive[b]] < weight[alive[a]] {
+			a, b = b, a
+		}
+		for i := 2; i < len(alive); i++ {
+			switch w := weight[alive[i]]; {
+			case w < weight[alive[a]]:
+				a, b = i, a
+			case w < weight[alive[b]]:
+				b = i
+			}
+		}
+		node := len(weight)
+		weight = append(weight, weight[alive[a]]+weight[alive[b]])
+		parent = append(parent, -1)
+		parent[alive[a]], parent[alive[b]] = node, node
+		alive[a] = node
+		alive = append(alive[:b], alive[b+1:]...)
+	}
+	lengths := make([]int, n)
+	for i := range lengths {
+		for p := parent[i]; p >= 0; p = parent[p] {
+			lengths[i]++
+		}
+	}
+	return lengths
+}
+
+func maxInts(v []int) int {
+	m := 0
+	for _, x := range v {
+		m = max(m, x)
+	}
+	return m
+}
+
+// canonicalCodes assigns bzip2's canonical codes: shorter codes first and,
+// within one length, in symbol order.
+func canonicalCodes(lengths []int) []uint32 {
+	codes := make([]uint32, len(lengths))
+	code := uint32(0)
+	for l := 1; l <= maxCodeLen; l++ {
+		for s, sl := range lengths {
+			if sl == l {
+				codes[s] = code
+				code++
+			}
+		}
+		code <<= 1
+	}
+	return codes
+}
@@ -0,0 +1,129 @@
+package difflib
+
+import (
+	"fmt"
+	"strings"
+)
+
+// WhitespaceKind names a problem found by CheckWhitespace. The values are
+// the messages git diff --check prints.
+type WhitespaceKind string
+
+const (
+	// TrailingWhitespace is a space or tab before the end of the line.
+	TrailingWhitespace WhitespaceKind = "trailing whitespace"
+	// SpaceBeforeTab is a space followed by a tab in the line's indent.
+	SpaceBeforeTab WhitespaceKind = "space before tab in indent"
+	// ConflictMarker is a line starting with a merge conflict marker such
+	// as "<<<<<<<" or "=======".
+	ConflictMarker WhitespaceKind = "leftover conflict marker"
+)
+
+// WhitespaceError is a problem in a line added by a diff.
+type WhitespaceError struct {
+	// File is the new file's name as it appears in the diff.
+	File string `json:"file"`
+	// Line is the 1-based line number in the new file.
+	Line int `json:"line"`
+	// Kind is the problem found.
+	Kind WhitespaceKind `json:"kind"`
+	// Text is the added line, without its '+' prefix.
+	Text string `json:"text"`
+}
+
+// Error formats the problem as git diff --check does, as
+// "file:line: message.".
+func (e WhitespaceError) Error() string {
+	return fmt.Sprintf("%s:%d: %s.", e.File, e.Line, e.Kind)
+}
+
+// CheckWhitespace reports the lines d adds that have trailing whitespace,
+// a space before a tab in their indent, or a leftover conflict marker, like
+// git diff --check. Removed and context lines are not checked, and a
+// "\r\n" line ending does not count as trailing whitespace. Problems are
+// returned in line order, with at most one of each kind per line.
+//
+// Example:
+//
+//	for _, e := range difflib.CheckWhitespace(d) {
+//	    fmt.Println(e) // b/main.go:12: trailing whitespace.
+//	}
+func CheckWhitespace(d DiffResult) []WhitespaceError {
+	return checkHunks(d.ToFile, d.Hunks)
+}
+
+// CheckWhitespace is CheckWhitespace for every file of p. File names are
+// reported with strip leading path components removed, as
+// ApplyFSOptions.Strip does. Deleted and binary files are skipped.
+//
+// Example:
+//
+//	ps, _ := difflib.ParsePatchSet(staged)
+//	if errs := ps.CheckWhitespace(1); len(errs) > 0 {
+//	    for _, e := range errs {
+//	        fmt.Fprintln(os.Stderr, e)
+//	    }
+//	    os.Exit(1)
+//	}
+func (p *PatchSet) CheckWhitespace(strip int) []WhitespaceError {
+	var errs []WhitespaceError
+	for _, f := range p.Files {
+		if f.DeletedFile || f.Binary {
+			continue
+		}
+		errs = append(errs, checkHunks(stripPath(f.NewName, strip), f.Hunks)...)
+	}
+	return errs
+}
+
+// checkHunks checks the lines added by hunks to the file name.
+func checkHunks(name string, hunks []Hunk) []WhitespaceError {
+	var errs []WhitespaceError
+	for _, h := range hunks {
+		line := h.NewStart
+		for _, l := range h.Lines {
+			if l == "" {
+				continue
+			}
+			switch l[0] {
+			case ' ':
+				line++
+			case '+':
+				for _, kind := range whitespaceProblems(l[1:]) {
+					errs = append(errs, WhitespaceError{File: name, Line: line, Kind: kind, Text: l[1:]})
+				}
+				line++
+			}
+		}
+	}
+	return errs
+}
+
+// whitespaceProblems returns the problems of the added line l.
+func whitespaceProblems(l string) []WhitespaceKind {
+	var kinds []WhitespaceKind
+	text := strings.TrimSuffix(strings.TrimSuffix(l, "\n"), "\r")
+	if text != strings.TrimRight(text, " \t") {
+		kinds = append(kinds, TrailingWhitespace)
+	}
+	indent := text[:len(text)-len(strings.TrimLeft(text, " \t"))]
+	if strings.Contains(indent, " \t") {
+		kinds = append(kinds, SpaceBeforeTab)
+	}
+	if isConflictMarker(text) {
+		kinds = append(kinds, ConflictMarker)
+	}
+	return kinds
+}
+
+// isConflictMarker reports whether text starts with seven '<', '=', '>' or
+// '|' characters followed by a space or the end of the line.
+func isConflictMarker(text string) bool {
+	if len(text) < 7 || !strings.ContainsRune("<=>|", rune(text[0])) {
+		return false
+	}
+	if strings.Count(text[:7], text[:1]) != 7 {
+		return false
+	}
+	return len(text) == 7 || text[7] == ' '
+}
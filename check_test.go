@@ -0,0 +1,60 @@
+package difflib_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	difflib "github.com/njchilds90/go-difflib"
+)
+
+func TestCheckWhitespace(t *testing.T) {
+	a := difflib.SplitLines("keep \nfunc f() {\n}\n")
+	b := difflib.SplitLines("keep \nfunc f() {\n\tx := 1 \n \tif x {}\n<<<<<<< HEAD\n=======\n>>>>>>> topic\n=======x\ny\r\n\t\n}\n")
+	d := difflib.UnifiedDiff(difflib.DiffInput{A: a, B: b, FromFile: "a/f.go", ToFile: "b/f.go"})
+	var got []string
+	for _, e := range difflib.CheckWhitespace(d) {
+		got = append(got, e.Error())
+	}
+	want := []string{
+		"b/f.go:3: trailing whitespace.",
+		"b/f.go:4: space before tab in indent.",
+		"b/f.go:5: leftover conflict marker.",
+		"b/f.go:6: leftover conflict marker.",
+		"b/f.go:7: leftover conflict marker.",
+		"b/f.go:10: trailing whitespace.",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("CheckWhitespace =\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestPatchSetCheckWhitespace(t *testing.T) {
+	patch := "diff --git a/x b/x\n--- a/x\n+++ b/x\n@@ -5,3 +5,4 @@\n ok\n-gone \n+added \n ok \n+tail\t\n" +
+		"diff --git a/y b/y\ndeleted file mode 100644\n--- a/y\n+++ /dev/null\n@@ -1 +0,0 @@\n-bad \n"
+	ps, err := difflib.ParsePatchSet(patch)
+	if err != nil {
+		t.Fatalf("ParsePatchSet error: %v", err)
+	}
+	got := ps.CheckWhitespace(1)
+	want := []difflib.WhitespaceError{
+		{File: "x", Line: 6, Kind: difflib.TrailingWhitespace, Text: "added \n"},
+		{File: "x", Line: 8, Kind: difflib.TrailingWhitespace, Text: "tail\t\n"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("CheckWhitespace = %+v, want %+v", got, want)
+	}
+}
+
+func ExampleCheckWhitespace() {
+	d := difflib.UnifiedDiff(difflib.DiffInput{
+		A:      difflib.SplitLines("a\n"),
+		B:      difflib.SplitLines("a\nb \n"),
+		ToFile: "notes.txt",
+	})
+	for _, e := range difflib.CheckWhitespace(d) {
+		fmt.Println(e)
+	}
+	// Output:
+	// notes.txt:2: trailing whitespace.
+}
@@ -0,0 +1,545 @@
+package difflib
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// CleanupSemantic rewrites the opcodes of a and b so they read more
+// naturally, following diff-match-patch's diff_cleanupSemantic: short
+// equalities that merely split a larger edit are folded into it, edits are
+// slid along equal text to word, line and blank-line boundaries, and a
+// deletion and insertion that overlap are split around the overlap. The
+// result still transforms a into b but is no longer minimal.
+//
+// The elements of a and b may be characters, words or lines; boundaries are
+// judged by the characters where elements meet.
+//
+// Example:
+//
+//	a, b := difflib.SplitLines(old), difflib.SplitLines(new)
+//	codes := difflib.CleanupSemantic(a, b, difflib.GetOpCodes(a, b))
+func CleanupSemantic(a, b []string, codes []OpCode) []OpCode {
+	return segsToOpCodes(cleanupSemantic(opCodesToSegs(a, b, codes)))
+}
+
+// CleanupSemanticText is CleanupSemantic for a character-level diff such as
+// one returned by DiffText.
+//
+// Example:
+//
+//	diffs := difflib.CleanupSemanticText(difflib.DiffText("mouse", "sofas"))
+//	// [{delete "mouse"} {insert "sofas"}]
+func CleanupSemanticText(diffs []TextDiff) []TextDiff {
+	return segsToTextDiffs(cleanupSemantic(textDiffsToSegs(diffs)))
+}
+
+// CleanupEfficiency folds short equalities into the edits around them when
+// that makes the diff cheaper to apply, following diff-match-patch's
+// diff_cleanupEfficiency. editCost is the cost of one edit operation in
+// elements: an equality shorter than editCost that sits between edits on
+// both sides, or shorter than editCost/2 with edits on three sides, is
+// turned into a replacement and merged with its neighbours. A non-positive
+// editCost selects the diff-match-patch default of 4. The result still
+// transforms a into b.
+//
+// Example:
+//
+//	codes := difflib.CleanupEfficiency(difflib.GetOpCodes(a, b), 4)
+func CleanupEfficiency(codes []OpCode, editCost int) []OpCode {
+	if editCost <= 0 {
+		editCost = 4
+	}
+	codes = append([]OpCode(nil), codes...)
+	var equalities []int // indexes of candidate equalities
+	lastEquality := -1
+	// Whether there is a deletion or insertion before or after the last
+	// candidate equality.
+	var preIns, preDel, postIns, postDel bool
+	changed := false
+	for p := 0; p < len(codes); p++ {
+		c := codes[p]
+		if c.Tag == OpEqual {
+			if c.I2-c.I1 < editCost && (postIns || postDel) {
+				equalities = append(equalities, p)
+				preIns, preDel = postIns, postDel
+				lastEquality = p
+			} else {
+				equalities = equalities[:0]
+				lastEquality = -1
+			}
+			postIns, postDel = false, false
+			continue
+		}
+		if c.I2 > c.I1 {
+			postDel = true
+		}
+		if c.J2 > c.J1 {
+			postIns = true
+		}
+		if lastEquality < 0 {
+			continue
+		}
+		n := codes[lastEquality].I2 - codes[lastEquality].I1
+		if !(preIns && preDel && postIns && postDel) &&
+			!(2*n < editCost && countTrue(preIns, preDel, postIns, postDel) == 3) {
+			continue
+		}
+		codes[lastEquality].Tag = OpReplace
+		equalities = equalities[:len(equalities)-1]
+		lastEquality = -1
+		changed = true
+		if preIns && preDel {
+			// No changes made that could affect earlier entries.
+			postIns, postDel = true, true
+			equalities = equalities[:0]
+			continue
+		}
+		// Rescan from the previous candidate equality.
+		if len(equalities) > 0 {
+			equalities = equalities[:len(equalities)-1]
+		}
+		p = -1
+		if len(equalities) > 0 {
+			p = equalities[len(equalities)-1]
+		}
+		postIns, postDel = false, false
+	}
+	if !changed {
+		return codes
+	}
+	return mergeEdits(codes)
+}
+
+// countTrue returns how many of bs are true.
+func countTrue(bs ...bool) int {
+	n := 0
+	for _, b := range bs {
+		if b {
+			n++
+		}
+	}
+	return n
+}
+
+// mergeEdits joins runs of adjacent non-equal opcodes into single deletes,
+// inserts or replacements.
+func mergeEdits(codes []OpCode) []OpCode {
+	var out []OpCode
+	for _, c := range codes {
+		if k := len(out) - 1; k >= 0 && c.Tag != OpEqual && out[k].Tag != OpEqual {
+			out[k].I2, out[k].J2 = c.I2, c.J2
+			out[k].Tag = editTag(out[k])
+			continue
+		}
+		out = append(out, c)
+	}
+	return out
+}
+
+// editTag returns the tag of an edit covering the ranges of c: insert if
+// its a range is empty, delete if its b range is, and replace otherwise.
+func editTag(c OpCode) Op {
+	switch {
+	case c.I1 == c.I2:
+		return OpInsert
+	case c.J1 == c.J2:
+		return OpDelete
+	}
+	return OpReplace
+}
+
+// diffSeg is one run of a diff as a slice of elements. Replacements are
+// represented as a deletion followed by an insertion.
+type diffSeg struct {
+	op    Op
+	items []string
+}
+
+func opCodesToSegs(a, b []string, codes []OpCode) []diffSeg {
+	var segs []diffSeg
+	for _, c := range codes {
+		switch c.Tag {
+		case OpEqual:
+			segs = append(segs, diffSeg{OpEqual, a[c.I1:c.I2]})
+		case OpDelete:
+			segs = append(segs, diffSeg{OpDelete, a[c.I1:c.I2]})
+		case OpInsert:
+			segs = append(segs, diffSeg{OpInsert, b[c.J1:c.J2]})
+		case OpReplace:
+			segs = append(segs, diffSeg{OpDelete, a[c.I1:c.I2]}, diffSeg{OpInsert, b[c.J1:c.J2]})
+		}
+	}
+	return segs
+}
+
+func segsToOpCodes(segs []diffSeg) []OpCode {
+	var codes []OpCode
+	i, j := 0, 0
+	for _, s := range segs {
+		n := len(s.items)
+		if n == 0 {
+			continue
+		}
+		var c OpCode
+		switch s.op {
+		case OpEqual:
+			c = OpCode{OpEqual, i, i + n, j, j + n}
+		case OpDelete:
+			c = OpCode{OpDelete, i, i + n, j, j}
+		case OpInsert:
+			c = OpCode{OpInsert, i, i, j, j + n}
+		}
+		i, j = c.I2, c.J2
+		if k := len(codes) - 1; k >= 0 && c.Tag != OpEqual && codes[k].Tag != OpEqual {
+			codes[k].I2, codes[k].J2 = c.I2, c.J2
+			codes[k].Tag = OpReplace
+			continue
+		}
+		codes = append(codes, c)
+	}
+	return codes
+}
+
+func textDiffsToSegs(diffs []TextDiff) []diffSeg {
+	segs := make([]diffSeg, 0, len(diffs))
+	for _, d := range diffs {
+		segs = append(segs, diffSeg{d.Op, splitChars(d.Text)})
+	}
+	return segs
+}
+
+func segsToTextDiffs(segs []diffSeg) []TextDiff {
+	var diffs []TextDiff
+	for _, s := range segs {
+		if len(s.items) > 0 {
+			diffs = append(diffs, TextDiff{s.op, strings.Join(s.items, "")})
+		}
+	}
+	return diffs
+}
+
+// segWeight is the length of a run in characters.
+func segWeight(items []string) int {
+	n := 0
+	for _, s := range items {
+		n += utf8.RuneCountInString(s)
+	}
+	return n
+}
+
+// cat returns the concatenation of element slices as a new slice.
+func cat(parts ...[]string) []string {
+	var out []string
+	for _, p := range parts {
+		out = append(out, p...)
+	}
+	return out
+}
+
+func itemsEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func itemsPrefix(a, b []string) int {
+	n := 0
+	for n < len(a) && n < len(b) && a[n] == b[n] {
+		n++
+	}
+	return n
+}
+
+func itemsSuffix(a, b []string) int {
+	n := 0
+	for n < len(a) && n < len(b) && a[len(a)-1-n] == b[len(b)-1-n] {
+		n++
+	}
+	return n
+}
+
+// itemsOverlap returns the length of the longest suffix of a that is a
+// prefix of b.
+func itemsOverlap(a, b []string) int {
+	for n := min(len(a), len(b)); n > 0; n-- {
+		if itemsEqual(a[len(a)-n:], b[:n]) {
+			return n
+		}
+	}
+	return 0
+}
+
+func insertSeg(segs []diffSeg, at int, s diffSeg) []diffSeg {
+	segs = append(segs, diffSeg{})
+	copy(segs[at+1:], segs[at:])
+	segs[at] = s
+	return segs
+}
+
+func removeSeg(segs []diffSeg, at int) []diffSeg {
+	return append(segs[:at], segs[at+1:]...)
+}
+
+// cleanupSemantic is diff-match-patch's diff_cleanupSemantic over segments.
+func cleanupSemantic(segs []diffSeg) []diffSeg {
+	changes := false
+	var equalities []int // indexes of candidate equalities
+	var lastEquality []string
+	haveEquality := false
+	// Characters inserted and deleted before and after the last equality.
+	ins1, del1, ins2, del2 := 0, 0, 0, 0
+	for p := 0; p < len(segs); p++ {
+		if segs[p].op == OpEqual {
+			equalities = append(equalities, p)
+			ins1, del1, ins2, del2 = ins2, del2, 0, 0
+			lastEquality, haveEquality = segs[p].items, true
+			continue
+		}
+		if segs[p].op == OpInsert {
+			ins2 += segWeight(segs[p].items)
+		} else {
+			del2 += segWeight(segs[p].items)
+		}
+		w := segWeight(lastEquality)
+		if haveEquality && w <= max(ins1, del1) && w <= max(ins2, del2) {
+			// Replace the equality with a deletion and an insertion.
+			e := equalities[len(equalities)-1]
+			segs = insertSeg(segs, e, diffSeg{OpDelete, lastEquality})
+			segs[e+1].op = OpInsert
+			equalities = equalities[:len(equalities)-1]
+			// Re-examine the previous equality, which may now be short
+			// relative to the grown edit.
+			if len(equalities) > 0 {
+				equalities = equalities[:len(equalities)-1]
+			}
+			p = -1
+			if len(equalities) > 0 {
+				p = equalities[len(equalities)-1]
+			}
+			ins1, del1, ins2, del2 = 0, 0, 0, 0
+			lastEquality, haveEquality = nil, false
+			changes = true
+		}
+	}
+	if changes {
+		segs = cleanupMerge(segs)
+	}
+	segs = cleanupSemanticLossless(segs)
+
+	// Split overlapping deletions and insertions around the overlap, if it
+	// is at least half of either, e.g. <del>abcxxx</del><ins>xxxdef</ins>
+	// becomes <del>abc</del>xxx<ins>def</ins>.
+	for p := 1; p < len(segs); p++ {
+		if segs[p-1].op != OpDelete || segs[p].op != OpInsert {
+			continue
+		}
+		del, ins := segs[p-1].items, segs[p].items
+		o1, o2 := itemsOverlap(del, ins), itemsOverlap(ins, del)
+		halfDel, halfIns := float64(segWeight(del))/2, float64(segWeight(ins))/2
+		if o1 >= o2 {
+			if w := float64(segWeight(ins[:o1])); o1 > 0 && (w >= halfDel || w >= halfIns) {
+				segs = insertSeg(segs, p, diffSeg{OpEqual, ins[:o1]})
+				segs[p-1].items = del[:len(del)-o1]
+				segs[p+1].items = ins[o1:]
+				p++
+			}
+		} else if w := float64(segWeight(del[:o2])); w >= halfDel || w >= halfIns {
+			segs = insertSeg(segs, p, diffSeg{OpEqual, del[:o2]})
+			segs[p-1] = diffSeg{OpInsert, ins[:len(ins)-o2]}
+			segs[p+1] = diffSeg{OpDelete, del[o2:]}
+			p++
+		}
+		p++
+	}
+	return segs
+}
+
+// cleanupSemanticLossless slides single edits surrounded by equalities to
+// the most natural boundary, e.g. "The c<ins>at c</ins>ame." becomes
+// "The <ins>cat </ins>came.".
+func cleanupSemanticLossless(segs []diffSeg) []diffSeg {
+	for p := 1; p < len(segs)-1; p++ {
+		if segs[p-1].op != OpEqual || segs[p+1].op != OpEqual {
+			continue
+		}
+		eq1, edit, eq2 := segs[p-1].items, segs[p].items, segs[p+1].items
+
+		// Shift the edit as far left as possible.
+		if n := itemsSuffix(eq1, edit); n > 0 {
+			common := edit[len(edit)-n:]
+			eq1 = eq1[:len(eq1)-n]
+			edit = cat(common, edit[:len(edit)-n])
+			eq2 = cat(common, eq2)
+		}
+
+		// Then step right, keeping the best scoring position.
+		bestEq1, bestEdit, bestEq2 := eq1, edit, eq2
+		bestScore := boundaryScore(eq1, edit) + boundaryScore(edit, eq2)
+		for len(edit) > 0 && len(eq2) > 0 && edit[0] == eq2[0] {
+			eq1 = cat(eq1, edit[:1])
+			edit = cat(edit[1:], eq2[:1])
+			eq2 = eq2[1:]
+			// The >= favours later positions, so edits end at a boundary.
+			if score := boundaryScore(eq1, edit) + boundaryScore(edit, eq2); score >= bestScore {
+				bestScore = score
+				bestEq1, bestEdit, bestEq2 = eq1, edit, eq2
+			}
+		}
+
+		if itemsEqual(segs[p-1].items, bestEq1) {
+			continue
+		}
+		segs[p].items = bestEdit
+		segs[p+1].items = bestEq2
+		if len(bestEq2) == 0 {
+			segs = removeSeg(segs, p+1)
+		}
+		segs[p-1].items = bestEq1
+		if len(bestEq1) == 0 {
+			segs = removeSeg(segs, p-1)
+			p--
+		}
+	}
+	return segs
+}
+
+// boundaryScore rates the boundary between one and two from 6 (best, an
+// end of text) down to 0 (inside a word).
+func boundaryScore(one, two []string) int {
+	if segWeight(one) == 0 || segWeight(two) == 0 {
+		return 6
+	}
+	// A blank line is at most three characters either side.
+	tail := strings.Join(one[max(0, len(one)-3):], "")
+	head := strings.Join(two[:min(3, len(two))], "")
+	c1, _ := utf8.DecodeLastRuneInString(tail)
+	c2, _ := utf8.DecodeRuneInString(head)
+	nonAlnum1 := !unicode.IsLetter(c1) && !unicode.IsDigit(c1)
+	nonAlnum2 := !unicode.IsLetter(c2) && !unicode.IsDigit(c2)
+	space1 := nonAlnum1 && unicode.IsSpace(c1)
+	space2 := nonAlnum2 && unicode.IsSpace(c2)
+	break1 := space1 && (c1 == '\n' || c1 == '\r')
+	break2 := space2 && (c2 == '\n' || c2 == '\r')
+	blank1 := break1 && (strings.HasSuffix(tail, "\n\n") || strings.HasSuffix(tail, "\n\r\n"))
+	blank2 := break2 && (strings.HasPrefix(head, "\n\n") || strings.HasPrefix(head, "\r\n\n") ||
+		strings.HasPrefix(head, "\n\r\n") || strings.HasPrefix(head, "\r\n\r\n"))
+	switch {
+	case blank1 || blank2:
+		return 5
+	case break1 || break2:
+		return 4
+	case nonAlnum1 && !space1 && space2:
+		// End of a sentence.
+		return 3
+	case space1 || space2:
+		return 2
+	case nonAlnum1 || nonAlnum2:
+		return 1
+	}
+	return 0
+}
+
+// cleanupMerge is diff-match-patch's diff_cleanupMerge: it joins adjacent
+// runs of the same kind, factors common prefixes and suffixes of deletions
+// and insertions out into the neighbouring equalities, and slides single
+// edits to absorb an equal neighbour, e.g. A<ins>BA</ins>C becomes
+// <ins>AB</ins>AC.
+func cleanupMerge(segs []diffSeg) []diffSeg {
+	var kept []diffSeg
+	for _, s := range segs {
+		if len(s.items) > 0 {
+			kept = append(kept, s)
+		}
+	}
+	segs = append(kept, diffSeg{OpEqual, nil}) // sentinel
+	var del, ins []string
+	nDel, nIns := 0, 0
+	for p := 0; p < len(segs); {
+		switch segs[p].op {
+		case OpInsert:
+			nIns++
+			ins = cat(ins, segs[p].items)
+			p++
+			continue
+		case OpDelete:
+			nDel++
+			del = cat(del, segs[p].items)
+			p++
+			continue
+		}
+		if nDel+nIns > 1 {
+			if nDel != 0 && nIns != 0 {
+				if n := itemsPrefix(ins, del); n > 0 {
+					if at := p - nDel - nIns - 1; at >= 0 && segs[at].op == OpEqual {
+						segs[at].items = cat(segs[at].items, ins[:n])
+					} else {
+						segs = insertSeg(segs, 0, diffSeg{OpEqual, ins[:n]})
+						p++
+					}
+					ins, del = ins[n:], del[n:]
+				}
+				if n := itemsSuffix(ins, del); n > 0 {
+					segs[p].items = cat(ins[len(ins)-n:], segs[p].items)
+					ins, del = ins[:len(ins)-n], del[:len(del)-n]
+				}
+			}
+			// Replace the edits with at most one deletion and one insertion.
+			p -= nDel + nIns
+			segs = append(segs[:p], segs[p+nDel+nIns:]...)
+			if len(del) > 0 {
+				segs = insertSeg(segs, p, diffSeg{OpDelete, del})
+				p++
+			}
+			if len(ins) > 0 {
+				segs = insertSeg(segs, p, diffSeg{OpInsert, ins})
+				p++
+			}
+			p++
+		} else if p > 0 && segs[p-1].op == OpEqual {
+			segs[p-1].items = cat(segs[p-1].items, segs[p].items)
+			segs = removeSeg(segs, p)
+		} else {
+			p++
+		}
+		nDel, nIns = 0, 0
+		del, ins = nil, nil
+	}
+	if last := len(segs) - 1; last >= 0 && len(segs[last].items) == 0 {
+		segs = segs[:last]
+	}
+
+	// Slide single edits surrounded by equalities.
+	changes := false
+	for p := 1; p < len(segs)-1; p++ {
+		prev, cur, next := segs[p-1], segs[p], segs[p+1]
+		if prev.op != OpEqual || next.op != OpEqual {
+			continue
+		}
+		switch {
+		case len(prev.items) > 0 && itemsSuffix(cur.items, prev.items) == len(prev.items):
+			// Shift the edit over the previous equality.
+			segs[p].items = cat(prev.items, cur.items[:len(cur.items)-len(prev.items)])
+			segs[p+1].items = cat(prev.items, next.items)
+			segs = removeSeg(segs, p-1)
+			changes = true
+		case len(next.items) > 0 && itemsPrefix(cur.items, next.items) == len(next.items):
+			// Shift the edit over the next equality.
+			segs[p-1].items = cat(prev.items, next.items)
+			segs[p].items = cat(cur.items[len(next.items):], next.items)
+			segs = removeSeg(segs, p+1)
+			changes = true
+		}
+	}
+	if changes {
+		return cleanupMerge(segs)
+	}
+	return segs
+}
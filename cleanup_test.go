@@ -0,0 +1,164 @@
+package difflib_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	difflib "github.com/njchilds90/go-difflib"
+)
+
+// td builds a []TextDiff from alternating ops and texts.
+func td(args ...any) []difflib.TextDiff {
+	var out []difflib.TextDiff
+	for i := 0; i < len(args); i += 2 {
+		out = append(out, difflib.TextDiff{Op: args[i].(difflib.Op), Text: args[i+1].(string)})
+	}
+	return out
+}
+
+const (
+	eq  = difflib.OpEqual
+	del = difflib.OpDelete
+	ins = difflib.OpInsert
+)
+
+func TestCleanupSemanticText(t *testing.T) {
+	// Cases from the diff-match-patch test suite.
+	tests := []struct {
+		name     string
+		in, want []difflib.TextDiff
+	}{
+		{"null", nil, nil},
+		{"no elimination 1", td(del, "ab", ins, "cd", eq, "12", del, "e"), td(del, "ab", ins, "cd", eq, "12", del, "e")},
+		{"no elimination 2", td(del, "abc", ins, "ABC", eq, "1234", del, "wxyz"), td(del, "abc", ins, "ABC", eq, "1234", del, "wxyz")},
+		{"simple elimination", td(del, "a", eq, "b", del, "c"), td(del, "abc", ins, "b")},
+		{"backpass elimination", td(del, "ab", eq, "cd", del, "e", eq, "f", ins, "g"), td(del, "abcdef", ins, "cdfg")},
+		{"multiple eliminations",
+			td(ins, "1", eq, "A", del, "B", ins, "2", eq, "_", ins, "1", eq, "A", del, "B", ins, "2"),
+			td(del, "AB_AB", ins, "1A2_1A2")},
+		{"word boundaries", td(eq, "The c", del, "ow and the c", eq, "at."), td(eq, "The ", del, "cow and the ", eq, "cat.")},
+		{"no overlap elimination", td(del, "abcxx", ins, "xxdef"), td(del, "abcxx", ins, "xxdef")},
+		{"overlap elimination", td(del, "abcxxx", ins, "xxxdef"), td(del, "abc", eq, "xxx", ins, "def")},
+		{"reverse overlap elimination", td(del, "xxxabc", ins, "defxxx"), td(ins, "def", eq, "xxx", del, "abc")},
+		{"two overlap eliminations",
+			td(del, "abcd1212", ins, "1212efghi", eq, "----", del, "A3", ins, "3BC"),
+			td(del, "abcd", eq, "1212", ins, "efghi", eq, "----", del, "A", eq, "3", ins, "BC")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := difflib.CleanupSemanticText(tt.in); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CleanupSemanticText() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCleanupSemantic(t *testing.T) {
+	a := difflib.SplitLines("func a() {\n\treturn 1\n}\n\nfunc b() {\n\treturn 2\n}\n")
+	b := difflib.SplitLines("func a() {\n\treturn 1\n}\n\nfunc c() {\n\treturn 3\n}\n\nfunc b() {\n\treturn 2\n}\n")
+	codes := difflib.GetOpCodes(a, b)
+	got := difflib.CleanupSemantic(a, b, codes)
+	// The inserted function is aligned to start after the blank line.
+	want := []difflib.OpCode{
+		{Tag: eq, I1: 0, I2: 4, J1: 0, J2: 4},
+		{Tag: ins, I1: 4, I2: 4, J1: 4, J2: 8},
+		{Tag: eq, I1: 4, I2: 7, J1: 8, J2: 11},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("CleanupSemantic() = %+v\nwant %+v\n(input %+v)", got, want, codes)
+	}
+	var rebuilt []string
+	for _, c := range got {
+		if c.Tag == eq {
+			rebuilt = append(rebuilt, a[c.I1:c.I2]...)
+		} else {
+			rebuilt = append(rebuilt, b[c.J1:c.J2]...)
+		}
+	}
+	if !reflect.DeepEqual(rebuilt, b) {
+		t.Errorf("opcodes no longer produce b: %q", rebuilt)
+	}
+}
+
+func ExampleCleanupSemanticText() {
+	diffs := difflib.DiffText("The cat sat on the mat.", "The dog sat on the log.")
+	fmt.Println(len(diffs), "segments before")
+	for _, d := range difflib.CleanupSemanticText(diffs) {
+		fmt.Printf("%s %q\n", d.Op, d.Text)
+	}
+	// Output:
+	// 7 segments before
+	// equal "The "
+	// delete "cat"
+	// insert "dog"
+	// equal " sat on the "
+	// delete "mat"
+	// insert "log"
+	// equal "."
+}
+
+// opCodesOf converts a text diff into character-level opcodes, joining a
+// deletion and insertion into a replacement.
+func opCodesOf(diffs []difflib.TextDiff) []difflib.OpCode {
+	var codes []difflib.OpCode
+	i, j := 0, 0
+	for _, d := range diffs {
+		n := len(d.Text)
+		c := difflib.OpCode{Tag: d.Op, I1: i, I2: i, J1: j, J2: j}
+		switch d.Op {
+		case eq:
+			c.I2, c.J2 = i+n, j+n
+		case del:
+			c.I2 = i + n
+		case ins:
+			c.J2 = j + n
+		}
+		i, j = c.I2, c.J2
+		if k := len(codes) - 1; k >= 0 && d.Op == ins && codes[k].Tag == del {
+			codes[k].Tag, codes[k].J2 = difflib.OpReplace, c.J2
+			continue
+		}
+		codes = append(codes, c)
+	}
+	return codes
+}
+
+func TestCleanupEfficiency(t *testing.T) {
+	// Cases from the diff-match-patch test suite.
+	tests := []struct {
+		name     string
+		cost     int
+		in, want []difflib.TextDiff
+	}{
+		{"no elimination", 4,
+			td(del, "ab", ins, "12", eq, "wxyz", del, "cd", ins, "34"),
+			td(del, "ab", ins, "12", eq, "wxyz", del, "cd", ins, "34")},
+		{"four-edit elimination", 4,
+			td(del, "ab", ins, "12", eq, "xyz", del, "cd", ins, "34"),
+			td(del, "abxyzcd", ins, "12xyz34")},
+		{"three-edit elimination", 4,
+			td(ins, "12", eq, "x", del, "cd", ins, "34"),
+			td(del, "xcd", ins, "12x34")},
+		{"backpass elimination", 4,
+			td(del, "ab", ins, "12", eq, "xy", ins, "34", eq, "z", del, "cd", ins, "56"),
+			td(del, "abxyzcd", ins, "12xy34z56")},
+		{"high cost elimination", 5,
+			td(del, "ab", ins, "12", eq, "wxyz", del, "cd", ins, "34"),
+			td(del, "abwxyzcd", ins, "12wxyz34")},
+		{"default cost", 0,
+			td(del, "ab", ins, "12", eq, "xyz", del, "cd", ins, "34"),
+			td(del, "abxyzcd", ins, "12xyz34")},
+		{"insertion only", 4,
+			td(eq, "a", ins, "1", eq, "b", ins, "2"),
+			td(eq, "a", ins, "1", eq, "b", ins, "2")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := difflib.CleanupEfficiency(opCodesOf(tt.in), tt.cost)
+			if want := opCodesOf(tt.want); !reflect.DeepEqual(got, want) {
+				t.Errorf("CleanupEfficiency() = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package difflib
+
+// DefaultCutoff is the similarity below which ClosestMatchesWithScores drops
+// candidates when no cutoff is given, as in Python's get_close_matches.
+const DefaultCutoff = 0.6
+
+// ScoredMatch is a candidate returned by ClosestMatchesWithScores.
+type ScoredMatch struct {
+	// Text is the candidate string.
+	Text string `json:"text"`
+	// Index is the position of the candidate in the input slice.
+	Index int `json:"index"`
+	// Score is the similarity ratio of the candidate to the target.
+	Score float64 `json:"score"`
+}
+
+// ClosestMatchesWithScores returns up to n candidates whose similarity to
+// target is at least cutoff, best first, with their scores and indices in
+// candidates. It follows Python's get_close_matches: each candidate is
+// compared character by character against target, and the cheap
+// RealQuickRatio and QuickRatio bounds rule candidates out before the full
+// ratio is computed. Candidates with equal scores keep their input order.
+//
+// A zero cutoff selects DefaultCutoff; pass a negative cutoff to accept
+// every candidate. n <= 0 returns nil.
+//
+// Example:
+//
+//	matches := difflib.ClosestMatchesWithScores("appel", []string{"ape", "apple", "peach", "puppy"}, 3, 0)
+//	// [{apple 1 0.8} {ape 0 0.75}]
+func ClosestMatchesWithScores(target string, candidates []string, n int, cutoff float64) []ScoredMatch {
+	if n <= 0 {
+		return nil
+	}
+	if cutoff == 0 {
+		cutoff = DefaultCutoff
+	}
+	var top []ScoredMatch
+	// below reports whether an upper bound rules a candidate out.
+	below := func(r float64) bool {
+		return r < cutoff || len(top) == n && r <= top[n-1].Score
+	}
+	m := NewMatcher(nil, splitChars(target))
+	for i, c := range candidates {
+		m.SetSeq1(splitChars(c))
+		if below(m.RealQuickRatio()) || below(m.QuickRatio()) {
+			continue
+		}
+		r := m.Ratio()
+		if below(r) {
+			continue
+		}
+		k := len(top)
+		for k > 0 && r > top[k-1].Score {
+			k--
+		}
+		top = append(top, ScoredMatch{})
+		copy(top[k+1:], top[k:])
+		top[k] = ScoredMatch{c, i, r}
+		if len(top) > n {
+			top = top[:n]
+		}
+	}
+	return top
+}
+
+// ClosestMatchFunc is ClosestMatch over arbitrary items: it returns the
+// item whose key is most similar to target, and its similarity ratio. The
+// first of equally similar items wins. ok is false if items is empty.
+//
+// Example:
+//
+//	type command struct{ Name, Help string }
+//	cmd, ratio, ok := difflib.ClosestMatchFunc("stauts", commands,
+//	    func(c command) string { return c.Name })
+func ClosestMatchFunc[T any](target string, items []T, key func(T) string) (best T, ratio float64, ok bool) {
+	ratio = -1
+	for _, item := range items {
+		if r := StringRatio(target, key(item)); r > ratio {
+			best, ratio = item, r
+		}
+	}
+	if len(items) == 0 {
+		return best, 0, false
+	}
+	return best, ratio, true
+}
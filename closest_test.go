@@ -0,0 +1,98 @@
+package difflib_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	difflib "github.com/njchilds90/go-difflib"
+)
+
+func TestClosestMatchesWithScores(t *testing.T) {
+	words := []string{"ape", "apple", "peach", "puppy"}
+	tests := []struct {
+		name   string
+		target string
+		cands  []string
+		n      int
+		cutoff float64
+		want   []difflib.ScoredMatch
+	}{
+		{"python docs example", "appel", words, 3, 0, []difflib.ScoredMatch{
+			{Text: "apple", Index: 1, Score: 0.8},
+			{Text: "ape", Index: 0, Score: 0.75},
+		}},
+		{"limit n", "appel", words, 1, 0, []difflib.ScoredMatch{
+			{Text: "apple", Index: 1, Score: 0.8},
+		}},
+		{"strict cutoff", "appel", words, 3, 0.78, []difflib.ScoredMatch{
+			{Text: "apple", Index: 1, Score: 0.8},
+		}},
+		{"negative cutoff keeps all", "ab", []string{"xy", "ab", "a"}, 5, -1, []difflib.ScoredMatch{
+			{Text: "ab", Index: 1, Score: 1},
+			{Text: "a", Index: 2, Score: 2.0 / 3},
+			{Text: "xy", Index: 0, Score: 0},
+		}},
+		{"ties keep input order", "abc", []string{"abd", "abx", "zbc"}, 2, 0, []difflib.ScoredMatch{
+			{Text: "abd", Index: 0, Score: 2.0 / 3},
+			{Text: "abx", Index: 1, Score: 2.0 / 3},
+		}},
+		{"no candidates", "abc", nil, 3, 0, nil},
+		{"zero n", "appel", words, 0, 0, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := difflib.ClosestMatchesWithScores(tt.target, tt.cands, tt.n, tt.cutoff)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ClosestMatchesWithScores() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func ExampleClosestMatchesWithScores() {
+	keywords := []string{"while", "for", "if", "switch", "return"}
+	for _, m := range difflib.ClosestMatchesWithScores("wheel", keywords, 3, 0) {
+		fmt.Printf("%s (#%d) %.2f\n", m.Text, m.Index, m.Score)
+	}
+	// Output:
+	// while (#0) 0.60
+}
+
+func TestClosestMatchFunc(t *testing.T) {
+	type command struct {
+		Name string
+		ID   int
+	}
+	cmds := []command{{"status", 1}, {"stash", 2}, {"commit", 3}}
+	key := func(c command) string { return c.Name }
+
+	best, ratio, ok := difflib.ClosestMatchFunc("stauts", cmds, key)
+	if !ok || best.ID != 1 || ratio != difflib.StringRatio("stauts", "status") {
+		t.Errorf("ClosestMatchFunc() = %+v, %v, %v; want status", best, ratio, ok)
+	}
+	// Agrees with ClosestMatch on plain strings.
+	names := []string{"status", "stash", "commit"}
+	want, wantRatio := difflib.ClosestMatch("stsh", names)
+	got, gotRatio, _ := difflib.ClosestMatchFunc("stsh", names, func(s string) string { return s })
+	if got != want || gotRatio != wantRatio {
+		t.Errorf("ClosestMatchFunc() = %q, %v; ClosestMatch() = %q, %v", got, gotRatio, want, wantRatio)
+	}
+	if best, ratio, ok := difflib.ClosestMatchFunc("x", []command(nil), key); ok || ratio != 0 || best != (command{}) {
+		t.Errorf("ClosestMatchFunc(empty) = %+v, %v, %v", best, ratio, ok)
+	}
+}
+
+func ExampleClosestMatchFunc() {
+	type command struct {
+		Name, Help string
+	}
+	commands := []command{
+		{"status", "show the working tree status"},
+		{"commit", "record changes"},
+	}
+	cmd, _, _ := difflib.ClosestMatchFunc("comit", commands, func(c command) string { return c.Name })
+	fmt.Printf("did you mean %q (%s)?\n", cmd.Name, cmd.Help)
+	// Output:
+	// did you mean "commit" (record changes)?
+}
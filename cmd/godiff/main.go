@@ -0,0 +1,358 @@
+// Command godiff compares, patches and merges text files using the
+// go-difflib library.
+//
+// Usage:
+//
+//	godiff [flags] OLD NEW
+//	godiff apply [flags] PATCH FILE
+//	godiff merge [flags] BASE OURS THEIRS
+//
+// Any file argument may be "-" to read standard input. Like diff(1), godiff
+// exits with status 0 if the inputs are identical, 1 if they differ and 2 on
+// error; apply exits 0 or 2, and merge exits 1 when conflicts remain.
+//
+// Run a subcommand with -h for its flags.
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	difflib "github.com/njchilds90/go-difflib"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// Exit statuses, as used by diff(1).
+const (
+	exitSame    = 0
+	exitDiffer  = 1
+	exitTrouble = 2
+)
+
+var (
+	// errUsage reports bad arguments; the flag package has already printed why.
+	errUsage = errors.New("usage")
+	// errDiffer is returned by a subcommand that succeeded but found
+	// differences or conflicts, which exit with status 1.
+	errDiffer = errors.New("inputs differ")
+)
+
+// command holds the standard streams of one invocation.
+type command struct {
+	stdin          io.Reader
+	stdout, stderr io.Writer
+
+	// stdinData caches standard input so "-" may be named more than once.
+	stdinData []byte
+	stdinRead bool
+}
+
+// run executes the command line args and returns the exit status.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	c := &command{stdin: stdin, stdout: stdout, stderr: stderr}
+	var err error
+	switch {
+	case len(args) > 0 && args[0] == "apply":
+		err = c.apply(args[1:])
+	case len(args) > 0 && args[0] == "merge":
+		err = c.merge(args[1:])
+	default:
+		err = c.diff(args)
+	}
+	switch {
+	case err == nil:
+		return exitSame
+	case errors.Is(err, errDiffer):
+		return exitDiffer
+	case !errors.Is(err, errUsage):
+		fmt.Fprintln(stderr, "godiff:", err)
+	}
+	return exitTrouble
+}
+
+// newFlagSet returns a FlagSet for a subcommand that writes to stderr.
+func newFlagSet(name, usage string, stderr io.Writer) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "usage: %s\n", usage)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parse parses args and checks the number of positional arguments.
+func parse(fs *flag.FlagSet, args []string, n int) error {
+	if err := fs.Parse(args); err != nil {
+		return errUsage
+	}
+	if fs.NArg() != n {
+		fs.Usage()
+		return errUsage
+	}
+	return nil
+}
+
+func (c *command) diff(args []string) error {
+	fs := newFlagSet("godiff", "godiff [flags] OLD NEW", c.stderr)
+	context := fs.Int("U", 3, "number of context `lines`")
+	color := fs.String("color", "auto", "colorize output: auto, always or never")
+	colorMoved := fs.Bool("color-moved", false, "color moved blocks of lines differently from other changes")
+	algorithm := fs.String("algorithm", "difflib", "diff algorithm: difflib (sequence matcher) or stream (line hashes, low memory)")
+	format := fs.String("format", "unified", "output format: unified, context, normal, ndiff, rcs, side-by-side, html or json")
+	width := fs.Int("width", 130, "total `columns` for side-by-side output")
+	fold := fs.Int("fold", 0, "in ndiff and side-by-side output, fold runs of more than `n` identical lines")
+	indent := fs.Bool("indent-heuristic", false, "slide unified and context hunks to natural boundaries, as git does")
+	showFunc := fs.Bool("p", false, "show the enclosing function in unified hunk headers")
+	funcRE := fs.String("F", "", "show the last line matching `regexp` in unified hunk headers")
+	if err := parse(fs, args, 2); err != nil {
+		return err
+	}
+	oldPath, newPath := fs.Arg(0), fs.Arg(1)
+	colored, err := useColor(*color, c.stdout)
+	if err != nil {
+		return err
+	}
+
+	if *context == 0 {
+		*context = difflib.NoContext
+	}
+	switch *algorithm {
+	case "difflib":
+	case "stream":
+		if *format != "unified" {
+			return fmt.Errorf("the stream algorithm only supports unified output")
+		}
+		return c.streamDiff(oldPath, newPath, *context, colored)
+	default:
+		return fmt.Errorf("unknown algorithm %q", *algorithm)
+	}
+
+	a, err := c.readLines(oldPath)
+	if err != nil {
+		return err
+	}
+	b, err := c.readLines(newPath)
+	if err != nil {
+		return err
+	}
+	if slices.Equal(a, b) {
+		return nil
+	}
+	input := difflib.DiffInput{A: a, B: b, FromFile: oldPath, ToFile: newPath, Context: *context, FoldEqual: *fold,
+		IndentHeuristic: *indent}
+	switch {
+	case *funcRE != "":
+		if input.FuncPattern, err = regexp.Compile(*funcRE); err != nil {
+			return fmt.Errorf("bad -F pattern: %v", err)
+		}
+	case *showFunc:
+		input.FuncPattern = difflib.FuncPatternFor(newPath)
+	}
+	var out string
+	switch *format {
+	case "unified":
+		out = difflib.UnifiedDiff(input).String()
+	case "context":
+		out = strings.Join(difflib.ContextDiff(input), "")
+	case "normal":
+		out = difflib.NormalDiff(a, b)
+	case "ndiff":
+		out = strings.Join(difflib.NDiffInput(input), "")
+	case "rcs":
+		out = difflib.RCSDiff(a, b)
+	case "side-by-side":
+		out = difflib.SideBySide(input, *width)
+	case "html":
+		out = difflib.MakeHTMLFile(a, b, difflib.HTMLDiffOptions{FromDesc: oldPath, ToDesc: newPath})
+	case "json":
+		ps, err := difflib.ParsePatchSet(difflib.UnifiedDiff(input).String())
+		if err != nil {
+			return err
+		}
+		if err := difflib.EncodeStructured(c.stdout, ps); err != nil {
+			return err
+		}
+		return errDiffer
+	default:
+		return fmt.Errorf("unknown format %q", *format)
+	}
+	if colored && (*format == "unified" || *format == "context") {
+		if *colorMoved {
+			out = difflib.ColorizeMoved(out, difflib.DefaultTheme(), difflib.MoveOptions{}, difflib.HighlightTrailingWhitespace)
+		} else {
+			out = difflib.ColorizeFunc(out, difflib.DefaultTheme(), difflib.HighlightTrailingWhitespace)
+		}
+	}
+	if _, err := io.WriteString(c.stdout, out); err != nil {
+		return err
+	}
+	return errDiffer
+}
+
+// streamDiff writes the unified diff of two files with UnifiedDiffReaders.
+func (c *command) streamDiff(oldPath, newPath string, context int, colored bool) error {
+	a, err := c.open(oldPath)
+	if err != nil {
+		return err
+	}
+	defer a.Close()
+	b, err := c.open(newPath)
+	if err != nil {
+		return err
+	}
+	defer b.Close()
+	var buf strings.Builder
+	w := c.stdout
+	if colored {
+		w = &buf
+	}
+	opts := difflib.StreamDiffOptions{FromFile: oldPath, ToFile: newPath, Context: context}
+	differ, err := difflib.UnifiedDiffReaders(w, a, b, opts)
+	if err != nil {
+		return err
+	}
+	if colored {
+		if _, err := io.WriteString(c.stdout, difflib.ColorizeFunc(buf.String(), difflib.DefaultTheme(), difflib.HighlightTrailingWhitespace)); err != nil {
+			return err
+		}
+	}
+	if differ {
+		return errDiffer
+	}
+	return nil
+}
+
+func (c *command) apply(args []string) error {
+	fs := newFlagSet("godiff apply", "godiff apply [flags] PATCH FILE", c.stderr)
+	output := fs.String("o", "", "write the result to `file` instead of standard output")
+	if err := parse(fs, args, 2); err != nil {
+		return err
+	}
+	patch, err := c.readFile(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	lines, err := c.readLines(fs.Arg(1))
+	if err != nil {
+		return err
+	}
+	patched, err := difflib.ApplyPatch(lines, string(patch))
+	if err != nil {
+		return err
+	}
+	return c.writeOutput(*output, difflib.JoinLines(patched))
+}
+
+func (c *command) merge(args []string) error {
+	fs := newFlagSet("godiff merge", "godiff merge [flags] BASE OURS THEIRS", c.stderr)
+	style := fs.String("style", "merge", "conflict style: merge, diff3 or zdiff3")
+	output := fs.String("o", "", "write the result to `file` instead of standard output")
+	if err := parse(fs, args, 3); err != nil {
+		return err
+	}
+	opts := difflib.MergeOptions{BaseLabel: fs.Arg(0), OursLabel: fs.Arg(1), TheirsLabel: fs.Arg(2)}
+	switch *style {
+	case "merge":
+		opts.Style = difflib.ConflictMerge
+	case "diff3":
+		opts.Style = difflib.ConflictDiff3
+	case "zdiff3":
+		opts.Style = difflib.ConflictZdiff3
+	default:
+		return fmt.Errorf("unknown conflict style %q", *style)
+	}
+	var inputs [3][]string
+	for i := range inputs {
+		lines, err := c.readLines(fs.Arg(i))
+		if err != nil {
+			return err
+		}
+		inputs[i] = lines
+	}
+	result := difflib.Merge(inputs[0], inputs[1], inputs[2], opts)
+	if err := c.writeOutput(*output, difflib.JoinLines(result.Lines)); err != nil {
+		return err
+	}
+	if result.HasConflicts() {
+		fmt.Fprintf(c.stderr, "godiff: %d conflict(s)\n", result.Conflicts)
+		return errDiffer
+	}
+	return nil
+}
+
+// useColor resolves the -color flag.
+func useColor(mode string, w io.Writer) (bool, error) {
+	switch mode {
+	case "auto":
+		return difflib.ColorEnabled(w), nil
+	case "always":
+		return true, nil
+	case "never":
+		return false, nil
+	}
+	return false, fmt.Errorf("invalid -color value %q", mode)
+}
+
+// readFile reads the named file, or standard input if path is "-".
+func (c *command) readFile(path string) ([]byte, error) {
+	if path != "-" {
+		return os.ReadFile(path)
+	}
+	if !c.stdinRead {
+		data, err := io.ReadAll(c.stdin)
+		if err != nil {
+			return nil, fmt.Errorf("reading standard input: %w", err)
+		}
+		c.stdinData, c.stdinRead = data, true
+	}
+	return c.stdinData, nil
+}
+
+// readLines reads a file as lines.
+func (c *command) readLines(path string) ([]string, error) {
+	data, err := c.readFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return difflib.SplitLines(string(data)), nil
+}
+
+// open opens the named file for streaming. Standard input is buffered in
+// memory so it can be re-read in place when the diff is written.
+func (c *command) open(path string) (io.ReadCloser, error) {
+	if path != "-" {
+		return os.Open(path)
+	}
+	data, err := c.readFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return memFile{bytes.NewReader(data)}, nil
+}
+
+// memFile is an in-memory io.ReadCloser that keeps bytes.Reader's ReadAt and
+// Seek methods, which io.NopCloser would hide.
+type memFile struct{ *bytes.Reader }
+
+func (memFile) Close() error { return nil }
+
+// writeOutput writes s to the named file, or to standard output if name is
+// empty.
+func (c *command) writeOutput(name, s string) error {
+	if name == "" {
+		_, err := io.WriteString(c.stdout, s)
+		return err
+	}
+	return os.WriteFile(name, []byte(s), 0o644)
+}
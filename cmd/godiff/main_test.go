@@ -0,0 +1,211 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeFiles writes each content to a file in a temp dir and returns the paths.
+func writeFiles(t *testing.T, contents ...string) []string {
+	t.Helper()
+	dir := t.TempDir()
+	paths := make([]string, len(contents))
+	for i, c := range contents {
+		paths[i] = filepath.Join(dir, string(rune('a'+i))+".txt")
+		if err := os.WriteFile(paths[i], []byte(c), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return paths
+}
+
+func runCmd(args ...string) (code int, stdout, stderr string) {
+	return runStdin("", args...)
+}
+
+func runStdin(stdin string, args ...string) (code int, stdout, stderr string) {
+	var out, errOut bytes.Buffer
+	code = run(args, strings.NewReader(stdin), &out, &errOut)
+	return code, out.String(), errOut.String()
+}
+
+func TestDiffFormats(t *testing.T) {
+	p := writeFiles(t, "one\ntwo\nthree\n", "one\nTWO\nthree\n")
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{nil, "--- " + p[0] + "\n+++ " + p[1] + "\n@@ -1,3 +1,3 @@\n one\n-two\n+TWO\n three\n"},
+		{[]string{"-U", "0"}, "\n@@ -2,1 +2,1 @@\n-two\n+TWO\n"},
+		{[]string{"-algorithm", "stream", "-U", "0"}, "\n@@ -2,1 +2,1 @@\n-two\n+TWO\n"},
+		{[]string{"-algorithm", "stream"}, "@@ -1,3 +1,3 @@\n one\n-two\n+TWO\n three\n"},
+		{[]string{"-format", "context"}, "*** " + p[0]},
+		{[]string{"-format", "normal"}, "2c2\n< two\n---\n> TWO\n"},
+		{[]string{"-format", "ndiff"}, "- two\n+ TWO\n"},
+		{[]string{"-format", "rcs"}, "d2 1\na2 1\nTWO\n"},
+		{[]string{"-format", "side-by-side", "-width", "40"}, "two                | TWO\n"},
+		{[]string{"-format", "html"}, "<html"},
+		{[]string{"-format", "json"}, `"old_path": "` + p[0] + `"`},
+		{[]string{"-color", "always"}, "\x1b["},
+	}
+	for _, tt := range tests {
+		code, out, errOut := runCmd(append(tt.args, p...)...)
+		if code != 1 {
+			t.Errorf("%v: exit %d, want 1; stderr %q", tt.args, code, errOut)
+			continue
+		}
+		if !strings.Contains(out, tt.want) {
+			t.Errorf("%v: output %q does not contain %q", tt.args, out, tt.want)
+		}
+	}
+}
+
+func TestDiffFuncContext(t *testing.T) {
+	p := writeFiles(t, "func f() {\n\t1\n\t2\n\t3\n\t4\n}\n", "func f() {\n\t1\n\t2\n\t3\n\t4\n\t5\n}\n")
+	code, out, _ := runCmd("-F", "^func (.*) {", p[0], p[1])
+	if want := "@@ -3,4 +3,5 @@ f()\n"; code != 1 || !strings.Contains(out, want) {
+		t.Errorf("exit %d, output %q does not contain %q", code, out, want)
+	}
+}
+
+func TestDiffIndentHeuristic(t *testing.T) {
+	p := writeFiles(t, "f {\n\tx\n}\n\ng {\n\ty\n}\n", "f {\n\tx\n}\n\nh {\n\tx\n}\n\ng {\n\ty\n}\n")
+	code, out, _ := runCmd("-U", "0", "-indent-heuristic", p[0], p[1])
+	if want := "@@ -4,0 +5,4 @@\n+h {\n+\tx\n+}\n+\n"; code != 1 || !strings.HasSuffix(out, want) {
+		t.Errorf("exit %d, output %q does not end with %q", code, out, want)
+	}
+}
+
+func TestDiffFold(t *testing.T) {
+	p := writeFiles(t, "1\n2\n3\n4\n5\n", "1\n2\n3\n4\nfive\n")
+	code, out, _ := runCmd("-format", "ndiff", "-fold", "2", p[0], p[1])
+	if want := "⋮ 4 identical lines (old 1-4, new 1-4)\n- 5\n+ five\n"; code != 1 || out != want {
+		t.Errorf("exit %d, output %q, want %q", code, out, want)
+	}
+}
+
+func TestDiffColorMoved(t *testing.T) {
+	p := writeFiles(t, "a\nb\nc\nx\ny\nz\nw\n", "x\ny\nz\nw\na\nb\nc\n")
+	code, out, _ := runCmd("-color", "always", "-color-moved", p[0], p[1])
+	if want := "\x1b[1;36m+a\x1b[0m\n"; code != 1 || !strings.Contains(out, want) {
+		t.Errorf("exit %d, output %q does not contain %q", code, out, want)
+	}
+}
+
+func TestDiffErrors(t *testing.T) {
+	p := writeFiles(t, "a\n", "b\n")
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{p[0]}, "usage: godiff"},
+		{[]string{"-format", "bogus", p[0], p[1]}, `unknown format "bogus"`},
+		{[]string{"-algorithm", "bogus", p[0], p[1]}, `unknown algorithm "bogus"`},
+		{[]string{"-algorithm", "stream", "-format", "ndiff", p[0], p[1]}, "only supports unified"},
+		{[]string{"-color", "sometimes", p[0], p[1]}, "invalid -color"},
+		{[]string{"-F", "(", p[0], p[1]}, "bad -F pattern"},
+		{[]string{p[0], p[0] + ".missing"}, "no such file"},
+	}
+	for _, tt := range tests {
+		code, _, errOut := runCmd(tt.args...)
+		if code != 2 {
+			t.Errorf("%v: exit %d, want 2", tt.args, code)
+		}
+		if !strings.Contains(errOut, tt.want) {
+			t.Errorf("%v: stderr %q does not contain %q", tt.args, errOut, tt.want)
+		}
+	}
+}
+
+func TestApply(t *testing.T) {
+	p := writeFiles(t, "one\ntwo\nthree\n", "one\nTWO\nthree\n", "")
+	code, patch, _ := runCmd(p[0], p[1])
+	if code != 1 {
+		t.Fatalf("diff exit %d", code)
+	}
+	if err := os.WriteFile(p[2], []byte(patch), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	code, out, errOut := runCmd("apply", p[2], p[0])
+	if code != 0 || out != "one\nTWO\nthree\n" {
+		t.Errorf("apply = %d %q (stderr %q)", code, out, errOut)
+	}
+
+	dest := filepath.Join(t.TempDir(), "out.txt")
+	if code, _, errOut := runCmd("apply", "-o", dest, p[2], p[0]); code != 0 {
+		t.Fatalf("apply -o exit %d: %s", code, errOut)
+	}
+	if got, _ := os.ReadFile(dest); string(got) != "one\nTWO\nthree\n" {
+		t.Errorf("apply -o wrote %q", got)
+	}
+
+	if code, _, _ := runCmd("apply", p[2], p[1]); code != 2 {
+		t.Errorf("applying to the wrong file: exit %d, want 2", code)
+	}
+
+	code, out, errOut = runStdin("one\ntwo\nthree\n", "apply", p[2], "-")
+	if code != 0 || out != "one\nTWO\nthree\n" {
+		t.Errorf("apply to stdin = %d %q (stderr %q)", code, out, errOut)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	clean := writeFiles(t, "a\nb\nc\n", "A\nb\nc\n", "a\nb\nC\n")
+	code, out, errOut := runCmd(append([]string{"merge"}, clean...)...)
+	if code != 0 || out != "A\nb\nC\n" {
+		t.Errorf("clean merge = %d %q (stderr %q)", code, out, errOut)
+	}
+
+	conflict := writeFiles(t, "a\nb\n", "a\nours\n", "a\ntheirs\n")
+	code, out, errOut = runCmd(append([]string{"merge", "-style", "diff3"}, conflict...)...)
+	if code != 1 {
+		t.Errorf("conflicting merge exit %d, want 1", code)
+	}
+	want := "a\n<<<<<<< " + conflict[1] + "\nours\n||||||| " + conflict[0] + "\nb\n=======\ntheirs\n>>>>>>> " + conflict[2] + "\n"
+	if out != want {
+		t.Errorf("conflicting merge output:\n%s\nwant:\n%s", out, want)
+	}
+	if !strings.Contains(errOut, "1 conflict(s)") {
+		t.Errorf("stderr %q does not report the conflict", errOut)
+	}
+
+	if code, _, errOut := runCmd(append([]string{"merge", "-style", "bogus"}, clean...)...); code != 2 || !strings.Contains(errOut, "unknown conflict style") {
+		t.Errorf("bad style = %d %q", code, errOut)
+	}
+}
+
+func TestExitStatus(t *testing.T) {
+	p := writeFiles(t, "one\ntwo\n", "one\ntwo\n", "one\n2\n")
+	tests := []struct {
+		name  string
+		stdin string
+		args  []string
+		code  int
+		out   string
+	}{
+		{"identical", "", []string{p[0], p[1]}, 0, ""},
+		{"identical stream", "", []string{"-algorithm", "stream", p[0], p[1]}, 0, ""},
+		{"identical ndiff", "", []string{"-format", "ndiff", p[0], p[1]}, 0, ""},
+		{"different", "", []string{"-U", "0", p[0], p[2]}, 1, "-two\n+2\n"},
+		{"different stream", "", []string{"-algorithm", "stream", "-U", "0", p[0], p[2]}, 1, "-two\n+2\n"},
+		{"stdin old", "one\nthree\n", []string{"-U", "0", "-", p[0]}, 1, "-three\n+two\n"},
+		{"stdin new", "one\nthree\n", []string{"-U", "0", p[0], "-"}, 1, "-two\n+three\n"},
+		{"stdin stream", "one\nthree\n", []string{"-algorithm", "stream", "-U", "0", p[0], "-"}, 1, "-two\n+three\n"},
+		{"stdin both", "x\n", []string{"-", "-"}, 0, ""},
+		{"missing file", "", []string{p[0], p[0] + ".missing"}, 2, ""},
+		{"usage", "", []string{p[0]}, 2, ""},
+	}
+	for _, tt := range tests {
+		code, out, errOut := runStdin(tt.stdin, tt.args...)
+		if code != tt.code {
+			t.Errorf("%s: exit %d, want %d (stderr %q)", tt.name, code, tt.code, errOut)
+		}
+		if !strings.HasSuffix(out, tt.out) || (tt.out == "" && out != "") {
+			t.Errorf("%s: output %q, want suffix %q", tt.name, out, tt.out)
+		}
+	}
+}
@@ -0,0 +1,233 @@
+package difflib
+
+import (
+	"io"
+	"os"
+	"strings"
+)
+
+// Theme holds the ANSI SGR escape sequences used to color each element of a
+// unified diff. An empty sequence leaves that element uncolored.
+type Theme struct {
+	// Header colors file header lines: "diff --git", "index", "---", "+++"
+	// and git's other extended header lines.
+	Header string
+	// HunkHeader colors the "@@ -a,b +c,d @@" part of hunk headers.
+	HunkHeader string
+	// Added and Removed color '+' and '-' lines.
+	Added, Removed string
+	// Context colors unchanged lines and "\ No newline at end of file" markers.
+	Context string
+	// Highlight colors the parts of hunk lines picked out by the
+	// LineClassifier passed to ColorizeFunc.
+	Highlight string
+	// MovedFrom and MovedTo color the removed and added lines of moved
+	// blocks in ColorizeMoved; MovedFromAlt and MovedToAlt color every
+	// other block, like git's --color-moved=zebra. Empty values fall back to
+	// Removed and Added.
+	MovedFrom, MovedFromAlt string
+	MovedTo, MovedToAlt     string
+}
+
+// ansiReset ends a colored span.
+const ansiReset = "\x1b[0m"
+
+// DefaultTheme returns git's default colors: bold file headers, cyan hunk
+// headers, red deletions and green additions, a red background for
+// highlights, and bold magenta and blue, and cyan and yellow, for moved
+// blocks.
+func DefaultTheme() Theme {
+	return Theme{
+		Header:     "\x1b[1m",
+		HunkHeader: "\x1b[36m",
+		Added:      "\x1b[32m",
+		Removed:    "\x1b[31m",
+		Highlight:  "\x1b[41m",
+
+		MovedFrom:    "\x1b[1;35m",
+		MovedFromAlt: "\x1b[1;34m",
+		MovedTo:      "\x1b[1;36m",
+		MovedToAlt:   "\x1b[1;33m",
+	}
+}
+
+// Colorize adds ANSI colors to a unified diff, such as the output of
+// DiffResult.String or PatchSet.String, according to theme. Hunk bodies
+// are recognized by the counts in their headers, so a removed line that
+// starts with "--" is not mistaken for a file header. Lines outside any file
+// diff, such as a commit message, are left as they are.
+//
+// Example:
+//
+//	fmt.Print(difflib.Colorize(d.String(), difflib.DefaultTheme()))
+func Colorize(diff string, theme Theme) string {
+	return ColorizeFunc(diff, theme, nil)
+}
+
+// ColorizeFunc is Colorize that also passes each hunk line to classify,
+// if it is not nil, and colors the parts it returns with theme.Highlight.
+//
+// Example:
+//
+//	secrets := func(kind byte, text string) []difflib.Highlight {
+//	    if i := strings.Index(text, "AKIA"); kind == '+' && i >= 0 {
+//	        return []difflib.Highlight{{Start: i, End: min(i+20, len(text))}}
+//	    }
+//	    return nil
+//	}
+//	fmt.Print(difflib.ColorizeFunc(d.String(), difflib.DefaultTheme(), secrets))
+func ColorizeFunc(diff string, theme Theme, classify LineClassifier) string {
+	return colorize(diff, theme, classify, nil)
+}
+
+// ColorizeMoved is ColorizeFunc that also finds blocks of lines moved within
+// each file (see DiffResult.MovedLines) and colors them with the Moved
+// colors of theme, alternating between blocks. If diff cannot be parsed as
+// a patch, no lines are colored as moved.
+//
+// Example:
+//
+//	fmt.Print(difflib.ColorizeMoved(d.String(), difflib.DefaultTheme(), difflib.MoveOptions{}, nil))
+func ColorizeMoved(diff string, theme Theme, opts MoveOptions, classify LineClassifier) string {
+	var moved []int
+	if ps, err := ParsePatchSet(diff); err == nil {
+		for _, f := range ps.Files {
+			for _, blocks := range f.movedBlocks(opts) {
+				moved = append(moved, blocks...)
+			}
+		}
+	}
+	return colorize(diff, theme, classify, moved)
+}
+
+// colorize implements ColorizeMoved. moved holds the moved block number of
+// each ' ', '+' and '-' hunk line of diff, in order.
+func colorize(diff string, theme Theme, classify LineClassifier, moved []int) string {
+	var b strings.Builder
+	oldLeft, newLeft := 0, 0
+	body := 0
+	for _, line := range SplitLines(diff) {
+		text, eol := line, ""
+		if strings.HasSuffix(text, "\n") {
+			text, eol = text[:len(text)-1], "\n"
+		}
+		color, rest := "", ""
+		var hs []Highlight
+		switch {
+		case oldLeft > 0 || newLeft > 0 || strings.HasPrefix(line, `\`):
+			block := 0
+			if strings.ContainsAny(line[:1], " +-") {
+				if theme.Highlight != "" {
+					hs = classifyLine(classify, line[0], strings.TrimSuffix(text[1:], "\r"))
+				}
+				if body < len(moved) {
+					block = moved[body]
+				}
+				body++
+			}
+			switch {
+			case strings.HasPrefix(line, "+"):
+				color = movedColor(block, theme.Added, theme.MovedTo, theme.MovedToAlt)
+				newLeft--
+			case strings.HasPrefix(line, "-"):
+				color = movedColor(block, theme.Removed, theme.MovedFrom, theme.MovedFromAlt)
+				oldLeft--
+			default:
+				color = theme.Context
+				if !strings.HasPrefix(line, `\`) {
+					oldLeft--
+					newLeft--
+				}
+			}
+		case strings.HasPrefix(line, "@@"):
+			if h, err := parseHunkHeader(line); err == nil {
+				oldLeft, newLeft = h.OldLines, h.NewLines
+			}
+			color = theme.HunkHeader
+			if end := strings.Index(text[2:], "@@"); end >= 0 {
+				text, rest = text[:end+4], text[end+4:]
+			}
+		case isHeaderLine(line):
+			color = theme.Header
+		}
+		if len(hs) > 0 {
+			b.WriteString(color + text[:1])
+			prev := 1
+			for _, h := range hs {
+				b.WriteString(text[prev:h.Start+1] + theme.Highlight + text[h.Start+1:h.End+1] + ansiReset + color)
+				prev = h.End + 1
+			}
+			b.WriteString(text[prev:] + ansiReset + eol)
+			continue
+		}
+		if color == "" || text == "" {
+			b.WriteString(line)
+			continue
+		}
+		b.WriteString(color + text + ansiReset + rest + eol)
+	}
+	return b.String()
+}
+
+// movedColor returns the color of a line in moved block number block, or
+// plain if block is 0 or the block's color is empty.
+func movedColor(block int, plain, moved, alt string) string {
+	if block%2 == 0 {
+		moved = alt
+	}
+	if block == 0 || moved == "" {
+		return plain
+	}
+	return moved
+}
+
+// isHeaderLine reports whether line is a file header line of a unified or
+// git diff.
+func isHeaderLine(line string) bool {
+	for _, prefix := range []string{
+		"diff ", "index ", "--- ", "+++ ", "old mode ", "new mode ", "new file mode ",
+		"deleted file mode ", "similarity index ", "rename from ", "rename to ",
+		"copy from ", "copy to ", "Binary files ",
+	} {
+		if strings.HasPrefix(line, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// ColorEnabled reports whether colored output should be written to w: w
+// must be a terminal, the NO_COLOR environment variable must be unset or
+// empty (see https://no-color.org), and TERM must not be "dumb".
+//
+// Example:
+//
+//	theme := difflib.Theme{}
+//	if difflib.ColorEnabled(os.Stdout) {
+//	    theme = difflib.DefaultTheme()
+//	}
+func ColorEnabled(w io.Writer) bool {
+	if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// WriteColored writes diff to w, colored with theme if ColorEnabled(w)
+// reports true and unchanged otherwise.
+//
+// Example:
+//
+//	difflib.WriteColored(os.Stdout, d.String(), difflib.DefaultTheme())
+func WriteColored(w io.Writer, diff string, theme Theme) (int64, error) {
+	if ColorEnabled(w) {
+		diff = Colorize(diff, theme)
+	}
+	n, err := io.WriteString(w, diff)
+	return int64(n), err
+}
@@ -0,0 +1,138 @@
+package difflib_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	difflib "github.com/njchilds90/go-difflib"
+)
+
+func TestColorize(t *testing.T) {
+	theme := difflib.Theme{Header: "<H>", HunkHeader: "<@>", Added: "<+>", Removed: "<->", Context: "<=>"}
+	tests := []struct {
+		name string
+		diff string
+		want string
+	}{
+		{
+			name: "unified",
+			diff: "--- a\n+++ b\n@@ -1,2 +1,2 @@ func main()\n keep\n-old\n+new\n",
+			want: "<H>--- a\x1b[0m\n<H>+++ b\x1b[0m\n<@>@@ -1,2 +1,2 @@\x1b[0m func main()\n<=> keep\x1b[0m\n<->-old\x1b[0m\n<+>+new\x1b[0m\n",
+		},
+		{
+			name: "removed line looking like a header",
+			diff: "--- a\n+++ b\n@@ -1 +1 @@\n--- x\n+++ y\n",
+			want: "<H>--- a\x1b[0m\n<H>+++ b\x1b[0m\n<@>@@ -1 +1 @@\x1b[0m\n<->--- x\x1b[0m\n<+>+++ y\x1b[0m\n",
+		},
+		{
+			name: "git headers and message",
+			diff: "Fix bug\ndiff --git a/x b/x\nindex 1..2 100644\n--- a/x\n+++ b/x\n@@ -1 +1 @@\n-a\n+b\n\\ No newline at end of file\n",
+			want: "Fix bug\n<H>diff --git a/x b/x\x1b[0m\n<H>index 1..2 100644\x1b[0m\n<H>--- a/x\x1b[0m\n<H>+++ b/x\x1b[0m\n" +
+				"<@>@@ -1 +1 @@\x1b[0m\n<->-a\x1b[0m\n<+>+b\x1b[0m\n<=>\\ No newline at end of file\x1b[0m\n",
+		},
+		{
+			name: "no trailing newline",
+			diff: "--- a\n+++ b\n@@ -1 +1 @@\n-a\n+b",
+			want: "<H>--- a\x1b[0m\n<H>+++ b\x1b[0m\n<@>@@ -1 +1 @@\x1b[0m\n<->-a\x1b[0m\n<+>+b\x1b[0m",
+		},
+		{
+			name: "empty theme",
+			diff: "--- a\n+++ b\n@@ -1 +1 @@\n-a\n+b\n",
+			want: "--- a\n+++ b\n@@ -1 +1 @@\n-a\n+b\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			th := theme
+			if tt.name == "empty theme" {
+				th = difflib.Theme{}
+			}
+			if got := difflib.Colorize(tt.diff, th); got != tt.want {
+				t.Errorf("Colorize() =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestColorizeFunc(t *testing.T) {
+	theme := difflib.Theme{Added: "<+>", Removed: "<->", Highlight: "<!>"}
+	todo := func(kind byte, text string) []difflib.Highlight {
+		if i := strings.Index(text, "TODO"); i >= 0 {
+			return []difflib.Highlight{{Start: i, End: i + 4}}
+		}
+		return nil
+	}
+	diff := "--- a\n+++ b\n@@ -1,2 +1,2 @@\n TODO keep\n-x \n+y TODO  \r\n"
+	got := difflib.ColorizeFunc(diff, theme, func(kind byte, text string) []difflib.Highlight {
+		return append(todo(kind, text), difflib.HighlightTrailingWhitespace(kind, text)...)
+	})
+	want := "--- a\n+++ b\n@@ -1,2 +1,2 @@\n <!>TODO\x1b[0m keep\x1b[0m\n<->-x \x1b[0m\n" +
+		"<+>+y <!>TODO\x1b[0m<+><!>  \x1b[0m<+>\r\x1b[0m\n"
+	if got != want {
+		t.Errorf("ColorizeFunc() =\n%q\nwant\n%q", got, want)
+	}
+	if got := difflib.ColorizeFunc(diff, difflib.Theme{}, todo); got != diff {
+		t.Errorf("ColorizeFunc with empty theme = %q, want the diff unchanged", got)
+	}
+}
+
+func TestColorizeMoved(t *testing.T) {
+	a := difflib.SplitLines("one\ntwo\nthree\nx\nfour\nfive\nsix\ny\nz\n")
+	b := difflib.SplitLines("x\nfour\nfive\nsix\ny\none\ntwo\nthree\nz\n")
+	diff := difflib.UnifiedDiff(difflib.DiffInput{A: a, B: b, Context: 1}).String()
+	theme := difflib.Theme{Added: "<+>", Removed: "<->", MovedFrom: "<m->", MovedTo: "<m+>", MovedToAlt: "<a+>"}
+	got := difflib.ColorizeMoved(diff, theme, difflib.MoveOptions{}, nil)
+	for _, want := range []string{"<m->-one\x1b[0m\n", "<m+>+one\x1b[0m\n"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("ColorizeMoved() = %q, does not contain %q", got, want)
+		}
+	}
+	if plain := difflib.Colorize(diff, theme); strings.Contains(plain, "<m") {
+		t.Errorf("Colorize() colored moved lines: %q", plain)
+	}
+	if got := difflib.ColorizeMoved("not a patch\n@@ -1 +1 @@\n", theme, difflib.MoveOptions{}, nil); got != "not a patch\n@@ -1 +1 @@\n" {
+		t.Errorf("ColorizeMoved() of malformed diff = %q", got)
+	}
+}
+
+func TestColorizeMovedZebra(t *testing.T) {
+	// Two blocks moved to adjacent places alternate colors.
+	diff := "--- a\n+++ b\n@@ -1,9 +1,9 @@\n-a1\n-a2\n-a3\n x\n-b1\n-b2\n-b3\n y\n z\n+a1\n+a2\n+a3\n+b1\n+b2\n+b3\n"
+	theme := difflib.Theme{MovedFrom: "<m->", MovedFromAlt: "<a->", MovedTo: "<m+>", MovedToAlt: "<a+>"}
+	got := difflib.ColorizeMoved(diff, theme, difflib.MoveOptions{}, nil)
+	for _, want := range []string{"<m->-a1", "<a->-b1", "<m+>+a3", "<a+>+b1"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("ColorizeMoved() = %q, does not contain %q", got, want)
+		}
+	}
+}
+
+func TestColorEnabled(t *testing.T) {
+	if difflib.ColorEnabled(&bytes.Buffer{}) {
+		t.Error("ColorEnabled(buffer) = true")
+	}
+	f, err := os.Create(filepath.Join(t.TempDir(), "out"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if difflib.ColorEnabled(f) {
+		t.Error("ColorEnabled(regular file) = true")
+	}
+	t.Setenv("NO_COLOR", "1")
+	if difflib.ColorEnabled(os.Stdout) {
+		t.Error("ColorEnabled with NO_COLOR set = true")
+	}
+}
+
+func TestWriteColoredPlainForNonTerminal(t *testing.T) {
+	var b bytes.Buffer
+	diff := "--- a\n+++ b\n@@ -1 +1 @@\n-a\n+b\n"
+	n, err := difflib.WriteColored(&b, diff, difflib.DefaultTheme())
+	if err != nil || n != int64(len(diff)) || b.String() != diff {
+		t.Errorf("WriteColored() = %d, %v, %q; want plain output", n, err, b.String())
+	}
+}
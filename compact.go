@@ -0,0 +1,141 @@
+package difflib
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CompactOptions controls CompactDiff.
+type CompactOptions struct {
+	// FromFile and ToFile label the two sides in the header.
+	FromFile, ToFile string
+	// Context is the number of context lines around each change. Defaults
+	// to 3 if zero; use NoContext for none.
+	Context int
+	// MaxLines, if positive, limits the number of output lines.
+	MaxLines int
+	// MaxChars, if positive, limits the length of the output in bytes.
+	MaxChars int
+}
+
+// CompactDiff renders a unified diff of a and b for reading rather than
+// applying, such as in a prompt for a language model. Unchanged stretches
+// between, before and after hunks are marked with a line like
+// "... 120 unchanged lines ...", so the reader knows how far apart the
+// changes are.
+//
+// If MaxLines or MaxChars is set and the diff is too long, the middles of
+// the largest hunks are cut, all hunks to the same number of lines, and
+// replaced by "... N lines omitted ..."; hunk headers are kept. If even the
+// headers do not fit, trailing hunks are dropped and summarized as
+// "... N more hunks omitted ...". The result is deterministic, and exceeds
+// the budget only when the file header and that summary alone do not fit.
+// The result is "" if a and b are equal.
+//
+// Example:
+//
+//	prompt += difflib.CompactDiff(a, b, difflib.CompactOptions{
+//	    FromFile: "a/main.go",
+//	    ToFile:   "b/main.go",
+//	    MaxLines: 200,
+//	})
+func CompactDiff(a, b []string, opts CompactOptions) string {
+	d := UnifiedDiff(DiffInput{A: a, B: b, FromFile: opts.FromFile, ToFile: opts.ToFile, Context: opts.Context})
+	if len(d.Hunks) == 0 {
+		return ""
+	}
+	c := compactRenderer{d: d, lenA: len(a)}
+	longest := 0
+	for _, h := range d.Hunks {
+		longest = max(longest, len(h.Lines))
+	}
+	fits := func(s string) bool {
+		return (opts.MaxLines <= 0 || strings.Count(s, "\n") <= opts.MaxLines) &&
+			(opts.MaxChars <= 0 || len(s) <= opts.MaxChars)
+	}
+	if s := c.render(len(d.Hunks), longest); fits(s) {
+		return s
+	}
+	// The most hunks that fit with their bodies cut to nothing, then the
+	// longest bodies that still fit with that many hunks.
+	n := largest(0, len(d.Hunks), func(n int) bool { return fits(c.render(n, 0)) })
+	k := largest(0, longest, func(k int) bool { return fits(c.render(n, k)) })
+	return c.render(n, k)
+}
+
+// largest returns the largest x in [lo, hi] for which ok(x) holds, given
+// that ok is monotonically decreasing, or lo if there is none.
+func largest(lo, hi int, ok func(int) bool) int {
+	for lo < hi {
+		mid := (lo + hi + 1) / 2
+		if ok(mid) {
+			lo = mid
+		} else {
+			hi = mid - 1
+		}
+	}
+	return lo
+}
+
+// compactRenderer renders CompactDiff output for a diff.
+type compactRenderer struct {
+	d    DiffResult
+	lenA int
+}
+
+// render writes the first n hunks with each hunk body cut to at most k
+// lines.
+func (c compactRenderer) render(n, k int) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- %s\n+++ %s\n", fileLabel(c.d.FromFile, c.d.FromDate), fileLabel(c.d.ToFile, c.d.ToDate))
+	prevEnd := 0
+	for _, h := range c.d.Hunks[:n] {
+		start := h.OldStart - 1
+		if h.OldLines == 0 {
+			start = h.OldStart
+		}
+		writeUnchanged(&b, start-prevEnd)
+		prevEnd = start + h.OldLines
+		b.WriteString(h.header() + "\n")
+		lines := h.Lines
+		if len(lines) > k {
+			head, tail := (k+1)/2, k/2
+			writeCompactLines(&b, lines[:head])
+			fmt.Fprintf(&b, "... %d lines omitted ...\n", len(lines)-k)
+			writeCompactLines(&b, lines[len(lines)-tail:])
+		} else {
+			writeCompactLines(&b, lines)
+		}
+	}
+	if rest := len(c.d.Hunks) - n; rest > 0 {
+		fmt.Fprintf(&b, "... %d more %s omitted ...\n", rest, plural(rest, "hunk", "hunks"))
+	} else {
+		writeUnchanged(&b, c.lenA-prevEnd)
+	}
+	return b.String()
+}
+
+// writeUnchanged writes the marker for n unchanged lines, if n > 0.
+func writeUnchanged(b *strings.Builder, n int) {
+	if n > 0 {
+		fmt.Fprintf(b, "... %d unchanged %s ...\n", n, plural(n, "line", "lines"))
+	}
+}
+
+// writeCompactLines writes diff lines, ending each with a newline.
+func writeCompactLines(b *strings.Builder, lines []string) {
+	for _, l := range lines {
+		b.WriteString(l)
+		if !strings.HasSuffix(l, "\n") {
+			b.WriteString("\n" + noNewlineMarker)
+		}
+	}
+}
+
+// plural returns one if n is 1 and many otherwise.
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
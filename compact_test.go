@@ -0,0 +1,117 @@
+package difflib_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	difflib "github.com/njchilds90/go-difflib"
+)
+
+// countLines returns lines "1\n" through "n\n", with the given 1-based
+// lines replaced by "changed N\n".
+func countLines(n int, changed ...int) []string {
+	lines := make([]string, n)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("%d\n", i+1)
+	}
+	for _, c := range changed {
+		lines[c-1] = fmt.Sprintf("changed %d\n", c)
+	}
+	return lines
+}
+
+func TestCompactDiff(t *testing.T) {
+	a := countLines(200)
+	tests := []struct {
+		name string
+		b    []string
+		opts difflib.CompactOptions
+		want string
+	}{
+		{"equal", countLines(200), difflib.CompactOptions{}, ""},
+		{
+			"unchanged runs", countLines(200, 50, 150), difflib.CompactOptions{Context: 1},
+			"--- \n+++ \n" +
+				"... 48 unchanged lines ...\n@@ -49,3 +49,3 @@\n 49\n-50\n+changed 50\n 51\n" +
+				"... 97 unchanged lines ...\n@@ -149,3 +149,3 @@\n 149\n-150\n+changed 150\n 151\n" +
+				"... 49 unchanged lines ...\n",
+		},
+		{
+			"change at start and end", countLines(200, 1, 200), difflib.CompactOptions{Context: 1, FromFile: "a", ToFile: "b"},
+			"--- a\n+++ b\n@@ -1,2 +1,2 @@\n-1\n+changed 1\n 2\n" +
+				"... 196 unchanged lines ...\n@@ -199,2 +199,2 @@\n 199\n-200\n+changed 200\n",
+		},
+		{
+			"fits budget exactly", countLines(200, 50), difflib.CompactOptions{Context: 1, MaxLines: 9},
+			"--- \n+++ \n... 48 unchanged lines ...\n@@ -49,3 +49,3 @@\n 49\n-50\n+changed 50\n 51\n... 149 unchanged lines ...\n",
+		},
+		{
+			"hunk trimmed", countLines(200, 50), difflib.CompactOptions{Context: 1, MaxLines: 8},
+			"--- \n+++ \n... 48 unchanged lines ...\n@@ -49,3 +49,3 @@\n 49\n... 2 lines omitted ...\n 51\n... 149 unchanged lines ...\n",
+		},
+		{
+			"hunks dropped", countLines(200, 50, 150), difflib.CompactOptions{Context: 1, MaxLines: 6},
+			"--- \n+++ \n... 48 unchanged lines ...\n@@ -49,3 +49,3 @@\n... 4 lines omitted ...\n... 1 more hunk omitted ...\n",
+		},
+	}
+	for _, tt := range tests {
+		if got := difflib.CompactDiff(a, tt.b, tt.opts); got != tt.want {
+			t.Errorf("%s: CompactDiff =\n%s\nwant\n%s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCompactDiffBudgets(t *testing.T) {
+	a := countLines(2000)
+	var changed []int
+	for i := 100; i <= 1900; i += 7 {
+		changed = append(changed, i)
+	}
+	b := countLines(2000, changed...)
+	full := difflib.CompactDiff(a, b, difflib.CompactOptions{})
+	for _, opts := range []difflib.CompactOptions{
+		{MaxLines: 10},
+		{MaxLines: 100},
+		{MaxLines: 1000},
+		{MaxChars: 200},
+		{MaxChars: 5000},
+		{MaxLines: 50, MaxChars: 800},
+	} {
+		got := difflib.CompactDiff(a, b, opts)
+		if opts.MaxLines > 0 && strings.Count(got, "\n") > opts.MaxLines {
+			t.Errorf("%+v: %d lines", opts, strings.Count(got, "\n"))
+		}
+		if opts.MaxChars > 0 && len(got) > opts.MaxChars {
+			t.Errorf("%+v: %d bytes", opts, len(got))
+		}
+		if !strings.HasPrefix(got, "--- \n+++ \n") || !strings.Contains(got, "@@ -") {
+			t.Errorf("%+v: headers missing:\n%s", opts, got)
+		}
+		if got == full {
+			t.Errorf("%+v: not truncated", opts)
+		}
+		if again := difflib.CompactDiff(a, b, opts); again != got {
+			t.Errorf("%+v: output not deterministic", opts)
+		}
+	}
+	if got := difflib.CompactDiff(a, b, difflib.CompactOptions{MaxLines: 1 << 20}); got != full {
+		t.Error("a budget larger than the diff changed the output")
+	}
+}
+
+func ExampleCompactDiff() {
+	a := difflib.SplitLines(strings.Repeat("same\n", 50) + "old\n" + strings.Repeat("same\n", 50))
+	b := difflib.SplitLines(strings.Repeat("same\n", 50) + "new\n" + strings.Repeat("same\n", 50))
+	fmt.Print(difflib.CompactDiff(a, b, difflib.CompactOptions{FromFile: "a.txt", ToFile: "b.txt", Context: 1}))
+	// Output:
+	// --- a.txt
+	// +++ b.txt
+	// ... 49 unchanged lines ...
+	// @@ -50,3 +50,3 @@
+	//  same
+	// -old
+	// +new
+	//  same
+	// ... 49 unchanged lines ...
+}
@@ -0,0 +1,261 @@
+package difflib
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ComposePatches combines two sequential patches, p1 transforming A into B
+// and p2 transforming B into C, into a single patch transforming A into C.
+// Files are paired by p1's new name and p2's old name (ignoring git's "a/"
+// and "b/" prefixes); files touched by only one patch are carried over.
+// Hunks that overlap or touch in B are merged and their headers recomputed.
+// An error is returned if p2's view of B contradicts p1's, i.e. the patches
+// were not made against the same intermediate content.
+//
+// Example:
+//
+//	first, _ := difflib.ParsePatchSet(edit1)
+//	second, _ := difflib.ParsePatchSet(edit2)
+//	squashed, err := difflib.ComposePatches(first, second)
+func ComposePatches(p1, p2 *PatchSet) (*PatchSet, error) {
+	out := &PatchSet{}
+	used := make([]bool, len(p2.Files))
+	for _, f1 := range p1.Files {
+		j := -1
+		for k, f2 := range p2.Files {
+			if !used[k] && patchPathKey(f2.OldName) == patchPathKey(f1.NewName) && !f1.DeletedFile {
+				j = k
+				break
+			}
+		}
+		if j < 0 {
+			out.Files = append(out.Files, f1)
+			continue
+		}
+		used[j] = true
+		f, keep, err := composeFiles(f1, p2.Files[j])
+		if err != nil {
+			return nil, err
+		}
+		if keep {
+			out.Files = append(out.Files, f)
+		}
+	}
+	for k, f2 := range p2.Files {
+		if !used[k] {
+			out.Files = append(out.Files, f2)
+		}
+	}
+	return out, nil
+}
+
+// patchPathKey strips a git "a/" or "b/" prefix so names from the old and
+// new side of different patches can be compared.
+func patchPathKey(name string) string {
+	if rest, ok := strings.CutPrefix(name, "a/"); ok {
+		return rest
+	}
+	if rest, ok := strings.CutPrefix(name, "b/"); ok {
+		return rest
+	}
+	return name
+}
+
+// composeFiles composes two diffs of the same file. keep is false when the
+// composition is a no-op, such as a file created by f1 and deleted by f2.
+func composeFiles(f1, f2 FileDiff) (f FileDiff, keep bool, err error) {
+	if f1.Binary || f2.Binary {
+		return FileDiff{}, false, fmt.Errorf("difflib: %s: cannot compose binary patches", f1.NewName)
+	}
+	if f1.NewFile && f2.DeletedFile {
+		return FileDiff{}, false, nil
+	}
+	hunks, err := composeHunks(f1.Hunks, f2.Hunks)
+	if err != nil {
+		return FileDiff{}, false, fmt.Errorf("%s: %w", f1.NewName, err)
+	}
+	f = FileDiff{
+		DiffResult:  DiffResult{FromFile: f1.FromFile, ToFile: f2.ToFile, Hunks: hunks},
+		OldName:     f1.OldName,
+		NewName:     f2.NewName,
+		Git:         f1.Git || f2.Git,
+		NewFile:     f1.NewFile,
+		DeletedFile: f2.DeletedFile,
+		OldMode:     f1.OldMode,
+		NewMode:     f2.NewMode,
+	}
+	if f.OldMode == "" {
+		f.OldMode = f2.OldMode
+	}
+	if f.NewMode == "" {
+		f.NewMode = f1.NewMode
+	}
+	if patchPathKey(f.OldName) != patchPathKey(f.NewName) {
+		f.Rename = true
+		f.Similarity = max(f1.Similarity, f2.Similarity)
+	}
+	if from, _, ok := strings.Cut(f1.Index, ".."); ok {
+		if _, to, ok := strings.Cut(f2.Index, ".."); ok {
+			f.Index = from + ".." + to
+		}
+	}
+	keep = len(hunks) > 0 || f.Rename || f.NewFile || f.DeletedFile || f.OldMode != f.NewMode
+	return f, keep, nil
+}
+
+// composeSpan is the range of intermediate (B) lines touched by a hunk.
+type composeSpan struct {
+	lo, hi int
+	hunk   Hunk
+	second bool
+}
+
+// composeSlot describes one intermediate line in a merged region together
+// with the lines that only exist in A or C immediately before it.
+type composeSlot struct {
+	line     string
+	known    bool
+	inA, inC bool
+	onlyA    []string
+	onlyC    []string
+}
+
+// composeHunks composes hunks h1 (A→B) with hunks h2 (B→C).
+func composeHunks(h1, h2 []Hunk) ([]Hunk, error) {
+	spans := make([]composeSpan, 0, len(h1)+len(h2))
+	i, j := 0, 0
+	for i < len(h1) || j < len(h2) {
+		if j == len(h2) || i < len(h1) && newAnchor(h1[i]) <= hunkAnchor(h2[j]) {
+			lo := newAnchor(h1[i])
+			spans = append(spans, composeSpan{lo, lo + h1[i].NewLines, h1[i], false})
+			i++
+		} else {
+			lo := hunkAnchor(h2[j])
+			spans = append(spans, composeSpan{lo, lo + h2[j].OldLines, h2[j], true})
+			j++
+		}
+	}
+
+	var out []Hunk
+	// delta1 is A-B and delta2 is C-B, accumulated over earlier regions.
+	delta1, delta2 := 0, 0
+	for k := 0; k < len(spans); {
+		lo, hi := spans[k].lo, spans[k].hi
+		end := k + 1
+		for end < len(spans) && spans[end].lo <= hi {
+			hi = max(hi, spans[end].hi)
+			end++
+		}
+		h, d1, d2, err := composeRegion(spans[k:end], lo, hi)
+		if err != nil {
+			return nil, err
+		}
+		h.OldStart = lo + delta1 + 1
+		if h.OldLines == 0 {
+			h.OldStart--
+		}
+		h.NewStart = lo + delta2 + 1
+		if h.NewLines == 0 {
+			h.NewStart--
+		}
+		if linesHaveChanges(h.Lines) {
+			out = append(out, h)
+		}
+		delta1 += d1
+		delta2 += d2
+		k = end
+	}
+	return out, nil
+}
+
+// newAnchor returns the 0-based index in the new file at which the hunk's new
+// lines begin; it is hunkAnchor for the new side.
+func newAnchor(h Hunk) int {
+	if h.NewLines == 0 {
+		return h.NewStart
+	}
+	return h.NewStart - 1
+}
+
+// composeRegion merges the spans covering intermediate lines [lo, hi) into a
+// single A→C hunk with unset start positions. It also returns how much the
+// region changes the A-B and C-B line offsets.
+func composeRegion(spans []composeSpan, lo, hi int) (Hunk, int, int, error) {
+	slots := make([]composeSlot, hi-lo+1)
+	for k := range slots {
+		slots[k].inA, slots[k].inC = true, true
+	}
+	setLine := func(b int, line string) error {
+		s := &slots[b-lo]
+		if s.known && s.line != line {
+			return fmt.Errorf("difflib: patches do not compose: intermediate line %d is %q in the first patch but %q in the second",
+				b+1, s.line, line)
+		}
+		s.line, s.known = line, true
+		return nil
+	}
+	d1, d2 := 0, 0
+	for _, sp := range spans {
+		b := sp.lo
+		for _, l := range sp.hunk.Lines {
+			if l == "" {
+				continue
+			}
+			tag, text := l[0], l[1:]
+			switch {
+			case !sp.second && tag == '-':
+				slots[b-lo].onlyA = append(slots[b-lo].onlyA, text)
+			case sp.second && tag == '+':
+				slots[b-lo].onlyC = append(slots[b-lo].onlyC, text)
+			case tag == ' ' || tag == '+' || tag == '-':
+				if err := setLine(b, text); err != nil {
+					return Hunk{}, 0, 0, err
+				}
+				s := &slots[b-lo]
+				if sp.second {
+					s.inC = tag == ' '
+				} else {
+					s.inA = tag == ' '
+				}
+				b++
+			}
+		}
+		if sp.second {
+			d2 += sp.hunk.NewLines - sp.hunk.OldLines
+		} else {
+			d1 += sp.hunk.OldLines - sp.hunk.NewLines
+		}
+	}
+
+	var h Hunk
+	var dels, adds []string
+	flush := func() {
+		h.Lines = append(h.Lines, dels...)
+		h.Lines = append(h.Lines, adds...)
+		dels, adds = dels[:0], adds[:0]
+	}
+	for k, s := range slots {
+		for _, l := range s.onlyA {
+			dels = append(dels, "-"+l)
+		}
+		for _, l := range s.onlyC {
+			adds = append(adds, "+"+l)
+		}
+		if k == len(slots)-1 {
+			break
+		}
+		switch {
+		case s.inA && s.inC:
+			flush()
+			h.Lines = append(h.Lines, " "+s.line)
+		case s.inA:
+			dels = append(dels, "-"+s.line)
+		case s.inC:
+			adds = append(adds, "+"+s.line)
+		}
+	}
+	flush()
+	h.OldLines, h.NewLines = countHunkLines(h.Lines)
+	return h, d1, d2, nil
+}
@@ -0,0 +1,121 @@
+package difflib_test
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	difflib "github.com/njchilds90/go-difflib"
+)
+
+func filePatch(name string, a, b []string) *difflib.PatchSet {
+	d := difflib.UnifiedDiff(difflib.DiffInput{A: a, B: b, FromFile: "a/" + name, ToFile: "b/" + name})
+	return &difflib.PatchSet{Files: []difflib.FileDiff{{
+		DiffResult: d, OldName: "a/" + name, NewName: "b/" + name,
+	}}}
+}
+
+// mutate returns a copy of lines with a few random edits applied.
+func mutate(r *rand.Rand, lines []string, tag string) []string {
+	out := append([]string(nil), lines...)
+	for n := r.Intn(4) + 1; n > 0; n-- {
+		i := r.Intn(len(out) + 1)
+		switch r.Intn(3) {
+		case 0:
+			out = append(out[:i], append([]string{tag + "\n"}, out[i:]...)...)
+		case 1:
+			if i < len(out) {
+				out = append(out[:i], out[i+1:]...)
+			}
+		case 2:
+			if i < len(out) {
+				out[i] = tag + " " + strings.TrimSpace(out[i]) + "\n"
+			}
+		}
+	}
+	return out
+}
+
+func TestComposePatches(t *testing.T) {
+	tests := []struct {
+		name    string
+		a, b, c string
+	}{
+		{"disjoint", "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n", "one\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n",
+			"one\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\ntwelve\n"},
+		{"overlapping", "1\n2\n3\n4\n5\n", "1\nTWO\n3\n4\n5\n", "1\nTWO\nTHREE\n4\n5\n"},
+		{"revert", "1\n2\n3\n", "1\nX\n3\n", "1\n2\n3\n"},
+		{"insert then delete", "1\n2\n", "1\nnew\n2\n", "1\n"},
+		{"from empty", "", "a\nb\n", "a\nc\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b, c := difflib.SplitLines(tt.a), difflib.SplitLines(tt.b), difflib.SplitLines(tt.c)
+			composed, err := difflib.ComposePatches(filePatch("f", a, b), filePatch("f", b, c))
+			if err != nil {
+				t.Fatalf("ComposePatches error: %v", err)
+			}
+			if len(composed.Files) != 1 {
+				t.Fatalf("expected 1 file, got %d", len(composed.Files))
+			}
+			got, err := difflib.ApplyPatch(a, composed.Files[0].DiffResult.String())
+			if err != nil {
+				t.Fatalf("applying composed patch: %v\n%s", err, composed.String())
+			}
+			if difflib.JoinLines(got) != tt.c {
+				t.Errorf("composed patch produced %q, want %q\n%s", difflib.JoinLines(got), tt.c, composed.String())
+			}
+		})
+	}
+}
+
+func TestComposePatchesRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for iter := 0; iter < 200; iter++ {
+		a := numbered(r.Intn(25) + 1)
+		b := mutate(r, a, "b")
+		c := mutate(r, b, "c")
+		composed, err := difflib.ComposePatches(filePatch("f", a, b), filePatch("f", b, c))
+		if err != nil {
+			t.Fatalf("iteration %d: ComposePatches error: %v", iter, err)
+		}
+		var patch string
+		if len(composed.Files) > 0 {
+			patch = composed.Files[0].DiffResult.String()
+		}
+		got, err := difflib.ApplyPatch(a, patch)
+		if err != nil {
+			t.Fatalf("iteration %d: applying composed patch: %v\n%s", iter, err, patch)
+		}
+		if difflib.JoinLines(got) != difflib.JoinLines(c) {
+			t.Fatalf("iteration %d: composed patch produced\n%q\nwant\n%q\n%s",
+				iter, difflib.JoinLines(got), difflib.JoinLines(c), patch)
+		}
+	}
+}
+
+func TestComposePatchesFiles(t *testing.T) {
+	x1 := difflib.SplitLines("x\n")
+	x2 := difflib.SplitLines("X\n")
+	y1 := difflib.SplitLines("y\n")
+	y2 := difflib.SplitLines("Y\n")
+	p1 := filePatch("x", x1, x2)
+	p2 := filePatch("y", y1, y2)
+	composed, err := difflib.ComposePatches(p1, p2)
+	if err != nil {
+		t.Fatalf("ComposePatches error: %v", err)
+	}
+	if len(composed.Files) != 2 || composed.Files[0].NewName != "b/x" || composed.Files[1].NewName != "b/y" {
+		t.Errorf("unrelated files should be carried over: %+v", composed.Files)
+	}
+}
+
+func TestComposePatchesMismatch(t *testing.T) {
+	a := difflib.SplitLines("1\n2\n3\n")
+	b := difflib.SplitLines("1\nB\n3\n")
+	other := difflib.SplitLines("1\nZ\n3\n")
+	_, err := difflib.ComposePatches(filePatch("f", a, b), filePatch("f", other, a))
+	if err == nil {
+		t.Error("expected error composing patches with different intermediates")
+	}
+}
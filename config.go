@@ -0,0 +1,145 @@
+package difflib
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ConfigChange is one key that differs between two key=value config files.
+type ConfigChange struct {
+	// Key is the setting's name, qualified by its section as "section.key".
+	// Entries of TOML arrays of tables are numbered: "servers[1].host".
+	Key string `json:"key"`
+	// Op is OpInsert for an added key, OpDelete for a removed key and
+	// OpReplace for a changed value.
+	Op Op `json:"op"`
+	// Old and New are the values, with surrounding whitespace removed.
+	// Old is empty for added keys and New for removed ones.
+	Old string `json:"old,omitempty"`
+	New string `json:"new,omitempty"`
+}
+
+// String formats the change on one line, prefixed with "+", "-" or "~".
+func (c ConfigChange) String() string {
+	switch c.Op {
+	case OpInsert:
+		return fmt.Sprintf("+ %s = %s", c.Key, c.New)
+	case OpDelete:
+		return fmt.Sprintf("- %s = %s", c.Key, c.Old)
+	}
+	return fmt.Sprintf("~ %s = %s -> %s", c.Key, c.Old, c.New)
+}
+
+// ConfigDiff is the list of changed keys returned by DiffConfig.
+type ConfigDiff []ConfigChange
+
+// String renders the changes one per line, as ConfigChange.String does.
+func (d ConfigDiff) String() string {
+	var b strings.Builder
+	for _, c := range d {
+		b.WriteString(c.String())
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// DiffConfig compares two key=value config files, such as INI, TOML or
+// .env files, by key rather than by line, so reordering settings or
+// sections is not a change. The result is sorted by key.
+//
+// Lines are parsed leniently:
+//   - "key = value" and "key: value" set a key; an "export " prefix, as in
+//     shell .env files, is ignored.
+//   - "[section]" qualifies the following keys as "section.key", and each
+//     TOML "[[table]]" header starts a new numbered entry.
+//   - Blank lines and lines starting with "#" or ";" are ignored.
+//   - A value with an unclosed "[", """ or ”' continues on the following
+//     lines until it is closed, as TOML arrays and multi-line strings do.
+//     Other lines without a separator are appended to the previous value.
+//
+// When a key is set more than once, the last value wins.
+//
+// Example:
+//
+//	d := difflib.DiffConfig("host=a\nport=80\n", "port=8080\nhost=a\ndebug=1\n")
+//	fmt.Print(d)
+//	// + debug = 1
+//	// ~ port = 80 -> 8080
+func DiffConfig(a, b string) ConfigDiff {
+	ka, kb := parseConfig(a), parseConfig(b)
+	keys := make([]string, 0, len(ka)+len(kb))
+	for k := range ka {
+		keys = append(keys, k)
+	}
+	for k := range kb {
+		if _, ok := ka[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	var d ConfigDiff
+	for _, k := range keys {
+		va, inA := ka[k]
+		vb, inB := kb[k]
+		switch {
+		case !inB:
+			d = append(d, ConfigChange{Key: k, Op: OpDelete, Old: va})
+		case !inA:
+			d = append(d, ConfigChange{Key: k, Op: OpInsert, New: vb})
+		case va != vb:
+			d = append(d, ConfigChange{Key: k, Op: OpReplace, Old: va, New: vb})
+		}
+	}
+	return d
+}
+
+// parseConfig returns the settings of a key=value config file by qualified
+// key.
+func parseConfig(s string) map[string]string {
+	values := map[string]string{}
+	tables := map[string]int{}
+	section, last := "", ""
+	for _, line := range SplitLines(s) {
+		line = strings.TrimSpace(line)
+		if last != "" && unclosedValue(values[last]) {
+			values[last] += "\n" + line
+			continue
+		}
+		switch {
+		case line == "" || line[0] == '#' || line[0] == ';':
+			continue
+		case strings.HasPrefix(line, "[[") && strings.HasSuffix(line, "]]"):
+			name := strings.TrimSpace(line[2 : len(line)-2])
+			section = fmt.Sprintf("%s[%d]", name, tables[name])
+			tables[name]++
+			last = ""
+			continue
+		case line[0] == '[' && line[len(line)-1] == ']':
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			last = ""
+			continue
+		}
+		i := strings.IndexAny(line, "=:")
+		if i <= 0 {
+			if last != "" {
+				values[last] += "\n" + line
+			}
+			continue
+		}
+		key := strings.TrimSpace(strings.TrimPrefix(line[:i], "export "))
+		if section != "" {
+			key = section + "." + key
+		}
+		values[key] = strings.TrimSpace(line[i+1:])
+		last = key
+	}
+	return values
+}
+
+// unclosedValue reports whether a TOML value continues on the next line: it
+// has an unclosed multi-line string or more "[" than "]".
+func unclosedValue(v string) bool {
+	return strings.Count(v, `"""`)%2 == 1 || strings.Count(v, "'''")%2 == 1 ||
+		strings.Count(v, "[") > strings.Count(v, "]")
+}
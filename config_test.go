@@ -0,0 +1,69 @@
+package difflib_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	difflib "github.com/njchilds90/go-difflib"
+)
+
+func TestDiffConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want string
+	}{
+		{"reordered", "a=1\nb=2\nc=3\n", "c=3\na=1\nb=2\n", ""},
+		{"whitespace and comments", "a=1\n# note\nb = 2\n", "; other\na = 1\n\nb=2\n", ""},
+		{"added removed changed", "a=1\nb=2\n", "b=3\nc=4\n", "- a = 1\n~ b = 2 -> 3\n+ c = 4\n"},
+		{"env export", "export PATH=/bin\nHOME=/root\n", "HOME=/home/me\nexport PATH=/bin\n", "~ HOME = /root -> /home/me\n"},
+		{"colon separator", "name: web\nurl=http://x\n", "url = http://y\nname : web\n", "~ url = http://x -> http://y\n"},
+		{
+			"reordered sections",
+			"[db]\nhost=a\n[web]\nhost=b\n",
+			"[web]\nhost=b\n[db]\nhost=c\n",
+			"~ db.host = a -> c\n",
+		},
+		{
+			"toml array of tables",
+			"[[servers]]\nname = \"a\"\n[[servers]]\nname = \"b\"\n",
+			"[[servers]]\nname = \"a\"\n[[servers]]\nname = \"c\"\n",
+			"~ servers[1].name = \"b\" -> \"c\"\n",
+		},
+		{
+			"toml multi-line array",
+			"hosts = [\n  \"http://a\",\n  \"http://b\",\n]\nport = 1\n",
+			"port = 1\nhosts = [\n  \"http://a\",\n  \"http://c\",\n]\n",
+			"~ hosts = [\n\"http://a\",\n\"http://b\",\n] -> [\n\"http://a\",\n\"http://c\",\n]\n",
+		},
+		{"last value wins", "a=1\na=2\n", "a=2\n", ""},
+		{"empty value", "a=\n", "a=x\n", "~ a =  -> x\n"},
+	}
+	for _, tt := range tests {
+		if got := difflib.DiffConfig(tt.a, tt.b).String(); got != tt.want {
+			t.Errorf("%s: DiffConfig =\n%q\nwant\n%q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDiffConfigJSON(t *testing.T) {
+	out, err := json.Marshal(difflib.DiffConfig("a=1\nb=2\n", "b=3\nc=4\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `[{"key":"a","op":"delete","old":"1"},{"key":"b","op":"replace","old":"2","new":"3"},{"key":"c","op":"insert","new":"4"}]`
+	if string(out) != want {
+		t.Errorf("JSON = %s\nwant %s", out, want)
+	}
+}
+
+func ExampleDiffConfig() {
+	old := "[server]\nhost = localhost\nport = 80\n\n[log]\nlevel = info\n"
+	new := "[log]\nlevel = debug\n\n[server]\nport = 8080\nhost = localhost\ntls = true\n"
+	fmt.Print(difflib.DiffConfig(old, new))
+	// Output:
+	// ~ log.level = info -> debug
+	// ~ server.port = 80 -> 8080
+	// + server.tls = true
+}
@@ -0,0 +1,88 @@
+package difflib_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing"
+	"time"
+
+	difflib "github.com/njchilds90/go-difflib"
+)
+
+func TestUnifiedDiffContext(t *testing.T) {
+	input := difflib.DiffInput{
+		A:        difflib.SplitLines("head\none\ntwo\nthree\nfour\ntail\n"),
+		B:        difflib.SplitLines("head\none\nTWO\nthree\nFOUR\ntail\n"),
+		FromFile: "a",
+		ToFile:   "b",
+		Context:  1,
+	}
+	got, err := difflib.UnifiedDiffContext(context.Background(), input)
+	if err != nil || !reflect.DeepEqual(got, difflib.UnifiedDiff(input)) {
+		t.Errorf("UnifiedDiffContext() = %v, %v; want UnifiedDiff result", got, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	got, err = difflib.UnifiedDiffContext(ctx, input)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("canceled: err = %v, want context.Canceled", err)
+	}
+	// The coarse diff replaces everything between the common prefix and
+	// suffix in one hunk, and still applies.
+	want := "--- a\n+++ b\n@@ -2,5 +2,5 @@\n one\n-two\n-three\n-four\n+TWO\n+three\n+FOUR\n tail\n"
+	if got.String() != want {
+		t.Errorf("coarse diff:\n%s\nwant:\n%s", got, want)
+	}
+	applied, err := difflib.ApplyPatch(input.A, got.String())
+	if err != nil || !reflect.DeepEqual(applied, input.B) {
+		t.Errorf("coarse diff does not apply: %v, %q", err, applied)
+	}
+}
+
+func TestUnifiedDiffContextDeadline(t *testing.T) {
+	// Many short, repetitive lines make matching slow.
+	rng := rand.New(rand.NewSource(1))
+	var a, b []string
+	for i := 0; i < 40000; i++ {
+		a = append(a, fmt.Sprintf("%d\n", rng.Intn(50)))
+		b = append(b, fmt.Sprintf("%d\n", rng.Intn(50)))
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := difflib.UnifiedDiffContext(ctx, difflib.DiffInput{A: a, B: b})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want context.DeadlineExceeded", err)
+	}
+	if d := time.Since(start); d > 2*time.Second {
+		t.Errorf("UnifiedDiffContext took %v after its deadline", d)
+	}
+}
+
+func TestMatcherContext(t *testing.T) {
+	a := difflib.SplitLines("a\nb\nc\n")
+	b := difflib.SplitLines("a\nx\nc\n")
+	m := difflib.NewMatcher(a, b)
+	blocks, err := m.GetMatchingBlocksContext(context.Background())
+	if err != nil || !reflect.DeepEqual(blocks, m.GetMatchingBlocks()) {
+		t.Errorf("GetMatchingBlocksContext() = %v, %v", blocks, err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if blocks, err := m.GetMatchingBlocksContext(ctx); blocks != nil || !errors.Is(err, context.Canceled) {
+		t.Errorf("canceled: GetMatchingBlocksContext() = %v, %v", blocks, err)
+	}
+	codes, err := m.GetOpCodesContext(ctx)
+	want := []difflib.OpCode{
+		{Tag: difflib.OpEqual, I1: 0, I2: 1, J1: 0, J2: 1},
+		{Tag: difflib.OpReplace, I1: 1, I2: 2, J1: 1, J2: 2},
+		{Tag: difflib.OpEqual, I1: 2, I2: 3, J1: 2, J2: 3},
+	}
+	if !errors.Is(err, context.Canceled) || !reflect.DeepEqual(codes, want) {
+		t.Errorf("canceled: GetOpCodesContext() = %v, %v; want %v", codes, err, want)
+	}
+}
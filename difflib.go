@@ -19,9 +19,13 @@
 package difflib
 
 import (
+	"context"
 	"fmt"
-	"math"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 )
 
 // Op represents a single diff operation kind.
@@ -49,62 +53,94 @@ func (o Op) String() string {
 		return "delete"
 	case OpReplace:
 		return "replace"
+	case OpMoveFrom:
+		return "move-from"
+	case OpMoveTo:
+		return "move-to"
 	default:
 		return "unknown"
 	}
 }
 
+// MarshalText encodes the operation as its String form, so that Op values
+// appear as "equal", "insert", etc. in JSON and other text encodings.
+func (o Op) MarshalText() ([]byte, error) {
+	if o < OpEqual || o > OpMoveTo {
+		return nil, fmt.Errorf("difflib: invalid Op %d", int(o))
+	}
+	return []byte(o.String()), nil
+}
+
+// UnmarshalText decodes an operation name produced by MarshalText.
+func (o *Op) UnmarshalText(text []byte) error {
+	for op := OpEqual; op <= OpMoveTo; op++ {
+		if op.String() == string(text) {
+			*o = op
+			return nil
+		}
+	}
+	return fmt.Errorf("difflib: unknown Op %q", text)
+}
+
 // OpCode describes a contiguous block of changes between two sequences.
 // It mirrors Python's difflib SequenceMatcher opcode format.
 type OpCode struct {
 	// Tag is the operation kind.
-	Tag Op
+	Tag Op `json:"tag"`
 	// I1, I2 are the start and end indices in sequence A (exclusive end).
-	I1, I2 int
+	I1 int `json:"i1"`
+	I2 int `json:"i2"`
 	// J1, J2 are the start and end indices in sequence B (exclusive end).
-	J1, J2 int
+	J1 int `json:"j1"`
+	J2 int `json:"j2"`
 }
 
 // Hunk represents a contiguous group of changed lines in a unified diff,
 // along with surrounding context lines.
 type Hunk struct {
 	// OldStart is the 1-based start line in the original file.
-	OldStart int
+	OldStart int `json:"old_start"`
 	// OldLines is the number of lines from the original file in this hunk.
-	OldLines int
+	OldLines int `json:"old_lines"`
 	// NewStart is the 1-based start line in the new file.
-	NewStart int
+	NewStart int `json:"new_start"`
 	// NewLines is the number of lines from the new file in this hunk.
-	NewLines int
+	NewLines int `json:"new_lines"`
 	// Lines contains the raw diff lines prefixed with ' ', '+', or '-'.
-	Lines []string
+	Lines []string `json:"lines"`
+	// Section is the heading shown after the header's closing "@@", such as
+	// the enclosing function, or "" for none.
+	Section string `json:"section,omitempty"`
+}
+
+// header returns the hunk's "@@ -l,s +l,s @@" line without a line ending.
+func (h Hunk) header() string {
+	s := fmt.Sprintf("@@ -%d,%d +%d,%d @@", h.OldStart, h.OldLines, h.NewStart, h.NewLines)
+	if h.Section != "" {
+		s += " " + h.Section
+	}
+	return s
 }
 
 // DiffResult holds a complete unified diff result.
 type DiffResult struct {
 	// FromFile is the label for the original file.
-	FromFile string
+	FromFile string `json:"from_file"`
 	// ToFile is the label for the modified file.
-	ToFile string
+	ToFile string `json:"to_file"`
+	// FromDate and ToDate, if set, follow the labels in the file headers
+	// after a tab, as in "--- a.txt\t2024-05-01 10:00:00.000000000 +0000".
+	// See FormatFileDate.
+	FromDate string `json:"from_date,omitempty"`
+	ToDate   string `json:"to_date,omitempty"`
 	// Hunks contains the diff hunks.
-	Hunks []Hunk
+	Hunks []Hunk `json:"hunks"`
 }
 
 // String renders the DiffResult as a standard unified diff string.
 func (d DiffResult) String() string {
-	if len(d.Hunks) == 0 {
-		return ""
-	}
 	var b strings.Builder
-	b.WriteString(fmt.Sprintf("--- %s\n", d.FromFile))
-	b.WriteString(fmt.Sprintf("+++ %s\n", d.ToFile))
-	for _, h := range d.Hunks {
-		b.WriteString(fmt.Sprintf("@@ -%d,%d +%d,%d @@\n",
-			h.OldStart, h.OldLines, h.NewStart, h.NewLines))
-		for _, l := range h.Lines {
-			b.WriteString(l)
-		}
-	}
+	d.WriteTo(&b)
 	return b.String()
 }
 
@@ -123,9 +159,45 @@ type DiffInput struct {
 	FromFile string
 	// ToFile is the label for the modified content (e.g., "b/file.go").
 	ToFile string
+	// FromDate and ToDate, if set, are written after the labels in the file
+	// headers, separated by a tab. Some tools require them; FormatFileDate
+	// formats a modification time the way GNU diff does.
+	FromDate, ToDate string
 	// Context is the number of unchanged lines to include around each change.
-	// Defaults to 3 if zero.
+	// Defaults to 3 if zero; use NoContext for none.
 	Context int
+	// MaxComparisons bounds the matching effort; past it the diff degrades
+	// to a single change between the common prefix and suffix. See
+	// Matcher.SetMaxComparisons. Zero means no bound.
+	MaxComparisons int
+	// TieBreak chooses between equally long matches; see Matcher.SetTieBreak.
+	TieBreak TieBreak
+	// IndentHeuristic slides changes in UnifiedDiff and ContextDiff output
+	// to natural boundaries, as git does by default; see IndentHeuristic.
+	IndentHeuristic bool
+	// FuncPattern, if set, gives each hunk a Section: the nearest line of A
+	// before the hunk that matches it, like the function names of
+	// "diff -p" and git. If the pattern has a group, the first group's text
+	// is used instead of the whole match. See FuncPatternFor.
+	FuncPattern *regexp.Regexp
+	// HunkLabel, if set, gives each hunk its Section instead of FuncPattern.
+	// It is called with the 0-based index in A of the hunk's first line, and
+	// may return "" for no label. Only the first line of the label is used.
+	HunkLabel func(aStart int) string
+	// FoldEqual, if positive, makes the full views SideBySide and
+	// NDiffInput replace each run of more than FoldEqual equal lines with
+	// a single marker line, such as "⋮ 240 identical lines (old 12-251,
+	// new 14-253)", that names the lines it stands for.
+	FoldEqual int
+}
+
+// matcher returns a Matcher for the input's sequences, effort bound and
+// tie-breaking rule.
+func (input DiffInput) matcher() *Matcher {
+	m := NewMatcher(input.A, input.B)
+	m.SetMaxComparisons(input.MaxComparisons)
+	m.SetTieBreak(input.TieBreak)
+	return m
 }
 
 // SplitLines splits a string into lines preserving line endings.
@@ -166,23 +238,67 @@ func JoinLines(lines []string) string {
 //	})
 //	fmt.Print(result.String())
 func UnifiedDiff(input DiffInput) DiffResult {
-	ctx := input.Context
-	if ctx == 0 {
-		ctx = 3
+	return unifiedFromOpCodes(input, input.matcher().GetOpCodes())
+}
+
+// UnifiedDiffContext is UnifiedDiff that gives up when ctx is canceled or
+// its deadline passes. It then returns ctx.Err() together with a coarse but
+// correct diff that only trims the common prefix and suffix of the inputs
+// and replaces everything between them, so services diffing untrusted
+// input can bound the time spent.
+//
+// Example:
+//
+//	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+//	defer cancel()
+//	result, err := difflib.UnifiedDiffContext(ctx, input)
+//	if err != nil {
+//	    log.Printf("diff timed out, showing coarse diff: %v", err)
+//	}
+func UnifiedDiffContext(ctx context.Context, input DiffInput) (DiffResult, error) {
+	codes, err := input.matcher().GetOpCodesContext(ctx)
+	return unifiedFromOpCodes(input, codes), err
+}
+
+// NoContext, as a Context option, asks for diffs without unchanged lines
+// around the changes, like diff -U0. Any negative Context does the same;
+// zero selects the default of three lines.
+const NoContext = -1
+
+// contextLines returns the number of context lines the Context option n
+// asks for.
+func contextLines(n int) int {
+	if n == 0 {
+		return 3
 	}
+	return max(n, 0)
+}
 
-	matcher := newMatcher(input.A, input.B)
-	opcodes := matcher.GetOpCodes()
+// unifiedFromOpCodes groups opcodes into the hunks of a unified diff.
+func unifiedFromOpCodes(input DiffInput, opcodes []OpCode) DiffResult {
+	ctx := contextLines(input.Context)
+	if input.IndentHeuristic {
+		opcodes = IndentHeuristic(input.A, input.B, opcodes)
+	}
 
 	result := DiffResult{
 		FromFile: input.FromFile,
 		ToFile:   input.ToFile,
+		FromDate: input.FromDate,
+		ToDate:   input.ToDate,
 	}
 
 	// Group opcodes into hunks separated by context
 	groups := groupOpcodes(opcodes, ctx)
 	for _, group := range groups {
 		hunk := buildHunk(input.A, input.B, group)
+		switch {
+		case input.HunkLabel != nil:
+			label, _, _ := strings.Cut(input.HunkLabel(group[0].I1), "\n")
+			hunk.Section = strings.TrimRight(label, " \t\r")
+		case input.FuncPattern != nil:
+			hunk.Section = hunkSection(input.A, group[0].I1, input.FuncPattern)
+		}
 		result.Hunks = append(result.Hunks, hunk)
 	}
 	return result
@@ -191,11 +307,11 @@ func UnifiedDiff(input DiffInput) DiffResult {
 // SequenceMatch holds information about a matching block between two sequences.
 type SequenceMatch struct {
 	// A is the start index in sequence A.
-	A int
+	A int `json:"a"`
 	// B is the start index in sequence B.
-	B int
+	B int `json:"b"`
 	// Size is the length of the matching block.
-	Size int
+	Size int `json:"size"`
 }
 
 // GetMatchingBlocks returns a list of matching blocks between two line sequences.
@@ -208,7 +324,7 @@ type SequenceMatch struct {
 //	    difflib.SplitLines("a\nX\nc\n"),
 //	)
 func GetMatchingBlocks(a, b []string) []SequenceMatch {
-	m := newMatcher(a, b)
+	m := NewMatcher(a, b)
 	return m.GetMatchingBlocks()
 }
 
@@ -221,10 +337,29 @@ func GetMatchingBlocks(a, b []string) []SequenceMatch {
 //	    difflib.SplitLines("foo\nbaz\n"),
 //	)
 func GetOpCodes(a, b []string) []OpCode {
-	m := newMatcher(a, b)
+	m := NewMatcher(a, b)
 	return m.GetOpCodes()
 }
 
+// WalkOpCodes diffs a and b and calls fn with each opcode in order, together
+// with the lines it covers in a and in b, without building the opcode list.
+// The line slices share storage with a and b and must not be modified. If fn
+// returns an error, WalkOpCodes stops and returns it.
+//
+// Example:
+//
+//	err := difflib.WalkOpCodes(a, b, func(op difflib.OpCode, aLines, bLines []string) error {
+//	    if op.Tag == difflib.OpDelete {
+//	        removed += len(aLines)
+//	    }
+//	    return nil
+//	})
+func WalkOpCodes(a, b []string, fn func(op OpCode, aLines, bLines []string) error) error {
+	return walkBlocks(NewMatcher(a, b).GetMatchingBlocks(), func(c OpCode) error {
+		return fn(c, a[c.I1:c.I2:c.I2], b[c.J1:c.J2:c.J2])
+	})
+}
+
 // SequenceRatio returns a similarity ratio in [0.0, 1.0] between two line sequences.
 // 1.0 means identical; 0.0 means completely different.
 //
@@ -235,32 +370,47 @@ func GetOpCodes(a, b []string) []OpCode {
 //	    difflib.SplitLines("foo\nbaz\n"),
 //	)
 func SequenceRatio(a, b []string) float64 {
-	m := newMatcher(a, b)
+	m := NewMatcher(a, b)
 	return m.Ratio()
 }
 
 // StringRatio returns a similarity ratio in [0.0, 1.0] between two raw strings
-// compared character by character.
+// compared character by character. The result is the same as SequenceRatio
+// over the strings' characters, computed directly on runes.
 //
 // Example:
 //
 //	ratio := difflib.StringRatio("kitten", "sitting") // ~0.615
 func StringRatio(a, b string) float64 {
-	ar := []rune(a)
-	br := []rune(b)
-	as := make([]string, len(ar))
-	bs := make([]string, len(br))
-	for i, r := range ar {
-		as[i] = string(r)
-	}
-	for i, r := range br {
-		bs[i] = string(r)
+	return runeRatio([]rune(a), []rune(b))
+}
+
+// LongestCommonSubstring returns the longest string that occurs in both a
+// and b, together with its byte offsets in a and in b. Ties go to the match
+// that starts earliest in a, then in b, as with SequenceMatcher's
+// find_longest_match. If a and b share no character the result is "", 0, 0.
+//
+// Example:
+//
+//	s, i, j := difflib.LongestCommonSubstring("the quick fox", "a quick dog")
+//	// s == " quick ", i == 3, j == 1
+func LongestCommonSubstring(a, b string) (string, int, int) {
+	as, bs := splitChars(a), splitChars(b)
+	m := NewMatcher(as, bs)
+	match := m.findLongestMatch(0, len(as), 0, len(bs))
+	if match.Size == 0 {
+		return "", 0, 0
 	}
-	return SequenceRatio(as, bs)
+	i := len(strings.Join(as[:match.A], ""))
+	j := len(strings.Join(bs[:match.B], ""))
+	return strings.Join(as[match.A:match.A+match.Size], ""), i, j
 }
 
 // ContextDiff generates a context diff (like `diff -c`) between A and B.
-// Returns lines suitable for display, each prefixed with '  ', '+ ', '- ', or '! '.
+// Returns lines suitable for display, each prefixed with '  ' (unchanged),
+// '- ' (deleted), '+ ' (inserted) or '! ' (changed). As in diff -c, the old
+// or new section of a hunk lists no lines if it has no changes of its own,
+// so the output can be applied with patch(1).
 //
 // Example:
 //
@@ -273,57 +423,107 @@ func StringRatio(a, b string) float64 {
 //	})
 //	fmt.Println(strings.Join(lines, ""))
 func ContextDiff(input DiffInput) []string {
-	ctx := input.Context
-	if ctx == 0 {
-		ctx = 3
+	var out []string
+	emitContextDiff(input, func(l string) { out = append(out, l) })
+	return out
+}
+
+// emitContextDiff passes each line of the context diff of input to emit.
+func emitContextDiff(input DiffInput, emit func(string)) {
+	ctx := contextLines(input.Context)
+	opcodes := input.matcher().GetOpCodes()
+	if input.IndentHeuristic {
+		opcodes = IndentHeuristic(input.A, input.B, opcodes)
 	}
-	matcher := newMatcher(input.A, input.B)
-	opcodes := matcher.GetOpCodes()
 	groups := groupOpcodes(opcodes, ctx)
 
 	if len(groups) == 0 {
-		return nil
+		return
 	}
 
-	var out []string
-	out = append(out, fmt.Sprintf("*** %s\n", input.FromFile))
-	out = append(out, fmt.Sprintf("--- %s\n", input.ToFile))
+	emitLine := func(tag Op, l string) {
+		if strings.HasSuffix(l, "\n") {
+			emit(contextMarker(tag) + l)
+			return
+		}
+		emit(contextMarker(tag) + l + "\n")
+		emit(noNewlineMarker)
+	}
+
+	emit("*** " + fileLabel(input.FromFile, input.FromDate) + "\n")
+	emit("--- " + fileLabel(input.ToFile, input.ToDate) + "\n")
 
 	for _, group := range groups {
 		first, last := group[0], group[len(group)-1]
-		out = append(out, "***************\n")
-		out = append(out, fmt.Sprintf("*** %d,%d ****\n", first.I1+1, last.I2))
-		for _, op := range group {
-			switch op.Tag {
-			case OpEqual:
-				for _, l := range input.A[op.I1:op.I2] {
-					out = append(out, "  "+l)
-				}
-			case OpReplace, OpDelete:
-				for _, l := range input.A[op.I1:op.I2] {
-					out = append(out, "! "+l)
+		emit("***************\n")
+		emit("*** " + contextRange(first.I1, last.I2) + " ****\n")
+		if hasTag(group, OpDelete, OpReplace) {
+			for _, op := range group {
+				if op.Tag != OpInsert {
+					for _, l := range input.A[op.I1:op.I2] {
+						emitLine(op.Tag, l)
+					}
 				}
 			}
 		}
-		out = append(out, fmt.Sprintf("--- %d,%d ----\n", first.J1+1, last.J2))
-		for _, op := range group {
-			switch op.Tag {
-			case OpEqual:
-				for _, l := range input.B[op.J1:op.J2] {
-					out = append(out, "  "+l)
-				}
-			case OpReplace, OpInsert:
-				for _, l := range input.B[op.J1:op.J2] {
-					out = append(out, "! "+l)
+		emit("--- " + contextRange(first.J1, last.J2) + " ----\n")
+		if hasTag(group, OpInsert, OpReplace) {
+			for _, op := range group {
+				if op.Tag != OpDelete {
+					for _, l := range input.B[op.J1:op.J2] {
+						emitLine(op.Tag, l)
+					}
 				}
 			}
 		}
 	}
-	return out
+}
+
+// contextRange formats the 0-based half-open range [start, stop) for a
+// context diff section header as diff -c does: "first,last", a single line
+// number for one line, and the line before the range for an empty one.
+func contextRange(start, stop int) string {
+	switch stop - start {
+	case 0:
+		return strconv.Itoa(start)
+	case 1:
+		return strconv.Itoa(start + 1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, stop)
+}
+
+// contextMarker returns the context diff prefix of lines of an opcode with
+// tag: "  " for equal, "- " for delete, "+ " for insert and "! " for
+// replace.
+func contextMarker(tag Op) string {
+	switch tag {
+	case OpDelete:
+		return "- "
+	case OpInsert:
+		return "+ "
+	case OpReplace:
+		return "! "
+	}
+	return "  "
+}
+
+// hasTag reports whether any opcode of group has one of tags.
+func hasTag(group []OpCode, tags ...Op) bool {
+	for _, op := range group {
+		for _, t := range tags {
+			if op.Tag == t {
+				return true
+			}
+		}
+	}
+	return false
 }
 
 // NDiff generates a delta-format diff similar to Python's ndiff,
 // showing every line with a prefix: '  ' (equal), '+ ' (insert), '- ' (delete).
+// Within a replaced block, each deleted line is shown next to the inserted
+// line most similar to it, followed by '? ' lines that mark the changed
+// characters, as Python's Differ does.
 //
 // Example:
 //
@@ -332,114 +532,72 @@ func ContextDiff(input DiffInput) []string {
 //	    difflib.SplitLines("one\nTWO\nthree\n"),
 //	)
 func NDiff(a, b []string) []string {
-	matcher := newMatcher(a, b)
-	opcodes := matcher.GetOpCodes()
 	var out []string
-	for _, op := range opcodes {
+	emitNDiff(a, b, func(l string) { out = append(out, l) })
+	return out
+}
+
+// NDiffInput is NDiff for input.A and input.B that honours the
+// MaxComparisons and FoldEqual fields of input. Fold markers have no prefix,
+// so Restore skips them and cannot rebuild a folded diff's sequences.
+//
+// Example:
+//
+//	lines := difflib.NDiffInput(difflib.DiffInput{A: a, B: b, FoldEqual: 10})
+func NDiffInput(input DiffInput) []string {
+	var out []string
+	emitNDiffInput(input, func(l string) { out = append(out, l) })
+	return out
+}
+
+// emitNDiff passes each line of the ndiff of a and b to emit.
+func emitNDiff(a, b []string, emit func(string)) {
+	emitNDiffInput(DiffInput{A: a, B: b}, emit)
+}
+
+// emitNDiffInput passes each line of the ndiff of input to emit.
+func emitNDiffInput(input DiffInput, emit func(string)) {
+	a, b := input.A, input.B
+	for _, op := range input.matcher().GetOpCodes() {
 		switch op.Tag {
 		case OpEqual:
+			if input.folds(op) {
+				emit(foldMarker(op))
+				continue
+			}
 			for _, l := range a[op.I1:op.I2] {
-				out = append(out, "  "+l)
+				emit("  " + l)
 			}
 		case OpInsert:
 			for _, l := range b[op.J1:op.J2] {
-				out = append(out, "+ "+l)
+				emit("+ " + l)
 			}
 		case OpDelete:
 			for _, l := range a[op.I1:op.I2] {
-				out = append(out, "- "+l)
+				emit("- " + l)
 			}
 		case OpReplace:
-			for _, l := range a[op.I1:op.I2] {
-				out = append(out, "- "+l)
-			}
-			for _, l := range b[op.J1:op.J2] {
-				out = append(out, "+ "+l)
-			}
+			emitNDiffReplace(a, b, op, input.MaxComparisons, emit)
 		}
 	}
-	return out
 }
 
 // ApplyPatch applies a unified diff string to the original lines A,
 // returning the patched result or an error if the patch does not apply cleanly.
+// Each hunk's context lines are verified along with its deleted lines, and
+// both must match exactly: a hunk whose context matches nowhere fails even if
+// its deletions match. A hunk may be found away from the line recorded in its
+// header; see ApplyPatchWithOptions for fuzz and offset control. Inserted
+// lines keep the line endings written in the patch, and a "\ No newline at
+// end of file" marker leaves the line before it without one, so the result
+// round-trips with SplitLines and JoinLines.
 //
 // Example:
 //
 //	patched, err := difflib.ApplyPatch(original, patchString)
 func ApplyPatch(a []string, patch string) ([]string, error) {
-	lines := strings.Split(patch, "\n")
-	// Skip header lines (--- and +++)
-	i := 0
-	for i < len(lines) && (strings.HasPrefix(lines[i], "---") || strings.HasPrefix(lines[i], "+++")) {
-		i++
-	}
-
-	result := make([]string, len(a))
-	copy(result, a)
-	offset := 0
-
-	for i < len(lines) {
-		line := lines[i]
-		if !strings.HasPrefix(line, "@@") {
-			i++
-			continue
-		}
-		// Parse @@ -start,count +start,count @@
-		var oldStart, oldCount, newStart, newCount int
-		_, err := fmt.Sscanf(line, "@@ -%d,%d +%d,%d @@", &oldStart, &oldCount, &newStart, &newCount)
-		if err != nil {
-			// Try without counts
-			_, err = fmt.Sscanf(line, "@@ -%d +%d @@", &oldStart, &newStart)
-			if err != nil {
-				return nil, fmt.Errorf("difflib: malformed hunk header: %q", line)
-			}
-			oldCount, newCount = 1, 1
-		}
-		i++
-
-		pos := oldStart - 1 + offset
-		var removes, inserts []string
-
-		for i < len(lines) {
-			l := lines[i]
-			if strings.HasPrefix(l, "@@") || (strings.HasPrefix(l, "---") && i > 0) {
-				break
-			}
-			if strings.HasPrefix(l, "-") {
-				removes = append(removes, strings.TrimPrefix(l, "-"))
-				i++
-			} else if strings.HasPrefix(l, "+") {
-				inserts = append(inserts, strings.TrimPrefix(l, "+"))
-				i++
-			} else if strings.HasPrefix(l, " ") {
-				i++
-			} else {
-				i++
-			}
-		}
-
-		// Verify removes match
-		for ri, rem := range removes {
-			actual := strings.TrimRight(result[pos+ri], "")
-			expected := strings.TrimRight(rem, "")
-			if actual != expected {
-				return nil, fmt.Errorf("difflib: patch mismatch at line %d: expected %q, got %q",
-					pos+ri+1, expected, actual)
-			}
-		}
-
-		// Apply: splice out removes, splice in inserts
-		before := result[:pos]
-		after := result[pos+len(removes):]
-		next := make([]string, 0, len(before)+len(inserts)+len(after))
-		next = append(next, before...)
-		next = append(next, inserts...)
-		next = append(next, after...)
-		result = next
-		offset += len(inserts) - len(removes)
-	}
-	return result, nil
+	result, _, err := ApplyPatchWithOptions(a, patch, ApplyOptions{})
+	return result, err
 }
 
 // Restore returns either the A or B sequence reconstructed from ndiff output.
@@ -503,122 +661,328 @@ func ClosestMatches(target string, candidates []string, n int) []string {
 		s string
 		r float64
 	}
-	ranked_list := make([]ranked, 0, len(candidates))
+	n = max(n, 0)
+	top := make([]ranked, 0, n+1)
+	m := NewMatcher(splitChars(target), nil)
 	for _, c := range candidates {
-		ranked_list = append(ranked_list, ranked{c, StringRatio(target, c)})
-	}
-	// Simple insertion sort (n is typically small)
-	for i := 1; i < len(ranked_list); i++ {
-		for j := i; j > 0 && ranked_list[j].r > ranked_list[j-1].r; j-- {
-			ranked_list[j], ranked_list[j-1] = ranked_list[j-1], ranked_list[j]
+		m.SetSeq2(splitChars(c))
+		// Once n candidates are held, skip any whose cheap upper bounds
+		// cannot beat the worst of them.
+		if len(top) == n && (n == 0 || m.RealQuickRatio() <= top[n-1].r || m.QuickRatio() <= top[n-1].r) {
+			continue
+		}
+		r := m.Ratio()
+		// Insert after candidates of equal ratio so earlier ones win ties.
+		i := len(top)
+		for i > 0 && r > top[i-1].r {
+			i--
+		}
+		top = append(top, ranked{})
+		copy(top[i+1:], top[i:])
+		top[i] = ranked{c, r}
+		if len(top) > n {
+			top = top[:n]
 		}
 	}
-	if n > len(ranked_list) {
-		n = len(ranked_list)
-	}
-	out := make([]string, n)
+	out := make([]string, len(top))
 	for i := range out {
-		out[i] = ranked_list[i].s
+		out[i] = top[i].s
 	}
 	return out
 }
 
-// --- Internal: sequence matcher ---
+// --- Sequence matcher ---
 
-type matcher struct {
-	a, b    []string
-	b2j     map[string][]int
-	matches []SequenceMatch
+// Matcher compares a pair of sequences, like Python's SequenceMatcher. It
+// indexes b once, so when comparing many sequences against one, keep it as
+// b and change a with SetSeq1.
+//
+// Example:
+//
+//	m := difflib.NewMatcher(difflib.SplitLines(old), difflib.SplitLines(new))
+//	if m.RealQuickRatio() > 0.6 && m.QuickRatio() > 0.6 && m.Ratio() > 0.6 {
+//	    codes := m.GetOpCodes()
+//	    // ...
+//	}
+type Matcher struct {
+	a, b []string
+	// Elements are interned to integer IDs so that matching compares ints:
+	// ids numbers the distinct elements of b, and elements of a that do
+	// not occur in b get ID -1.
+	ids  map[string]int
+	aIDs []int
+	b2j  [][]int // positions in b of each ID
+
+	maxComparisons int
+	tieBreak       TieBreak
 }
 
-func newMatcher(a, b []string) *matcher {
-	m := &matcher{a: a, b: b}
-	m.buildB2J()
+// NewMatcher returns a Matcher comparing a with b.
+func NewMatcher(a, b []string) *Matcher {
+	m := &Matcher{a: a}
+	m.SetSeq2(b)
 	return m
 }
 
-func (m *matcher) buildB2J() {
-	m.b2j = make(map[string][]int, len(m.b))
-	for i, s := range m.b {
-		m.b2j[s] = append(m.b2j[s], i)
+// SetMaxComparisons bounds the work of matching to about n element
+// comparisons. Once the bound is exceeded the matcher falls back to a
+// coarse diff that keeps only the common prefix and suffix of the
+// sequences, so pathological inputs such as thousands of identical lines
+// cannot take quadratic time. Zero, the default, means no bound.
+//
+// Example:
+//
+//	m := difflib.NewMatcher(a, b)
+//	m.SetMaxComparisons(10_000_000)
+//	codes := m.GetOpCodes()
+func (m *Matcher) SetMaxComparisons(n int) {
+	m.maxComparisons = n
+}
+
+// SetSeqs sets both sequences to compare.
+func (m *Matcher) SetSeqs(a, b []string) {
+	m.a = a
+	m.SetSeq2(b)
+}
+
+// SetSeq1 sets the first sequence, keeping the index of the second.
+func (m *Matcher) SetSeq1(a []string) {
+	m.a = a
+	m.aIDs = make([]int, len(a))
+	for i, s := range a {
+		id, ok := m.ids[s]
+		if !ok {
+			id = -1
+		}
+		m.aIDs[i] = id
+	}
+}
+
+// SetSeq2 sets the second sequence and re-indexes it.
+func (m *Matcher) SetSeq2(b []string) {
+	m.b = b
+	m.ids = make(map[string]int)
+	m.b2j = m.b2j[:0]
+	for j, s := range b {
+		id, ok := m.ids[s]
+		if !ok {
+			id = len(m.b2j)
+			m.ids[s] = id
+			m.b2j = append(m.b2j, nil)
+		}
+		m.b2j[id] = append(m.b2j[id], j)
+	}
+	// Re-intern a against the new table.
+	m.SetSeq1(m.a)
+}
+
+func (m *Matcher) findLongestMatch(alo, ahi, blo, bhi int) SequenceMatch {
+	sc := getScratch(len(m.b))
+	defer scratchPool.Put(sc)
+	return longestMatch(m.aIDs, m.b2j, sc, nil, m.tieBreaker(), alo, ahi, blo, bhi)
+}
+
+// GetMatchingBlocks returns the matching blocks of the two sequences, ending
+// with a sentinel of Size 0; see the package-level GetMatchingBlocks.
+func (m *Matcher) GetMatchingBlocks() []SequenceMatch {
+	blocks, _ := m.matchingBlocks(nil)
+	return blocks
+}
+
+// GetMatchingBlocksContext is GetMatchingBlocks that stops early and
+// returns ctx.Err() when ctx is done.
+func (m *Matcher) GetMatchingBlocksContext(ctx context.Context) ([]SequenceMatch, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	blocks, ok := m.matchingBlocks(ctx.Done())
+	if !ok {
+		return nil, ctx.Err()
+	}
+	return blocks, nil
+}
+
+// matchingBlocks computes the matching blocks, falling back to coarse ones
+// if the comparison bound is exceeded. It reports false if done was closed
+// first.
+func (m *Matcher) matchingBlocks(done <-chan struct{}) ([]SequenceMatch, bool) {
+	lim := &matchLimit{done: done, max: m.maxComparisons}
+	var blocks []SequenceMatch
+	if !eachMatch(m.aIDs, m.b2j, len(m.b), lim, m.tieBreaker(), func(b SequenceMatch) {
+		blocks = append(blocks, b)
+	}) {
+		if lim.canceled {
+			return nil, false
+		}
+		return coarseBlocks(m.a, m.b), true
+	}
+	sort.Slice(blocks, func(i, j int) bool { return blocks[i].A < blocks[j].A })
+	// Merge adjacent blocks in place.
+	merged := blocks[:0]
+	for _, b := range blocks {
+		if k := len(merged) - 1; k >= 0 && merged[k].A+merged[k].Size == b.A && merged[k].B+merged[k].Size == b.B {
+			merged[k].Size += b.Size
+			continue
+		}
+		merged = append(merged, b)
+	}
+	return append(merged, SequenceMatch{len(m.a), len(m.b), 0}), true
+}
+
+// matchScratch holds the buffers longestMatch needs for a b of a given
+// length. They are pooled, so hot diffing paths allocate them once.
+type matchScratch struct {
+	// j2len[j+1] is the length of the match ending at the previous element
+	// of a and b[j]; touched lists its non-zero entries.
+	j2len, newJ2len     []int
+	touched, newTouched []int
+	stack               [][4]int
+}
+
+var scratchPool = sync.Pool{New: func() any { return new(matchScratch) }}
+
+// getScratch returns zeroed buffers for matching against n elements.
+func getScratch(n int) *matchScratch {
+	sc := scratchPool.Get().(*matchScratch)
+	if cap(sc.j2len) < n+1 {
+		sc.j2len = make([]int, n+1)
+		sc.newJ2len = make([]int, n+1)
 	}
+	sc.j2len, sc.newJ2len = sc.j2len[:n+1], sc.newJ2len[:n+1]
+	return sc
 }
 
-func (m *matcher) findLongestMatch(alo, ahi, blo, bhi int) SequenceMatch {
+// longestMatch finds the longest block of equal elements in
+// a[alo:ahi] and b[blo:bhi], choosing between equally long blocks with tie,
+// which by default prefers the earliest in a and then in b. a is given as
+// element IDs and b by the positions of each ID.
+//
+// If lim stops the search it ends early with a possibly shorter match.
+func longestMatch(aIDs []int, b2j [][]int, sc *matchScratch, lim *matchLimit, tie *tieBreaker, alo, ahi, blo, bhi int) SequenceMatch {
 	bestI, bestJ, bestSize := alo, blo, 0
-	j2len := make(map[int]int)
 	for i := alo; i < ahi; i++ {
-		newJ2len := make(map[int]int)
-		for _, j := range m.b2j[m.a[i]] {
-			if j < blo {
-				continue
-			}
-			if j >= bhi {
-				break
+		if lim.stop(i - alo) {
+			break
+		}
+		sc.newTouched = sc.newTouched[:0]
+		if id := aIDs[i]; id >= 0 {
+			if lim != nil {
+				lim.used += len(b2j[id])
 			}
-			k := j2len[j-1] + 1
-			newJ2len[j] = k
-			if k > bestSize {
-				bestI, bestJ, bestSize = i-k+1, j-k+1, k
+			for _, j := range b2j[id] {
+				if j < blo {
+					continue
+				}
+				if j >= bhi {
+					break
+				}
+				k := sc.j2len[j] + 1
+				sc.newJ2len[j+1] = k
+				sc.newTouched = append(sc.newTouched, j+1)
+				if k > bestSize || k == bestSize && tie.better(i-k+1, j-k+1, bestI, bestJ) {
+					bestI, bestJ, bestSize = i-k+1, j-k+1, k
+				}
 			}
 		}
-		j2len = newJ2len
+		for _, t := range sc.touched {
+			sc.j2len[t] = 0
+		}
+		sc.j2len, sc.newJ2len = sc.newJ2len, sc.j2len
+		sc.touched, sc.newTouched = sc.newTouched, sc.touched
+	}
+	for _, t := range sc.touched {
+		sc.j2len[t] = 0
 	}
+	sc.touched = sc.touched[:0]
 	return SequenceMatch{bestI, bestJ, bestSize}
 }
 
-func (m *matcher) GetMatchingBlocks() []SequenceMatch {
-	queue := [][4]int{{0, len(m.a), 0, len(m.b)}}
-	var blocks []SequenceMatch
-	for len(queue) > 0 {
-		q := queue[0]
-		queue = queue[1:]
+// eachMatch calls fn with every block of the recursive longest-match
+// decomposition of a and b, in no particular order. It reports false if lim
+// stopped it.
+func eachMatch(aIDs []int, b2j [][]int, lenB int, lim *matchLimit, tie *tieBreaker, fn func(SequenceMatch)) bool {
+	sc := getScratch(lenB)
+	defer scratchPool.Put(sc)
+	stack := append(sc.stack[:0], [4]int{0, len(aIDs), 0, lenB})
+	for len(stack) > 0 {
+		q := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
 		alo, ahi, blo, bhi := q[0], q[1], q[2], q[3]
-		match := m.findLongestMatch(alo, ahi, blo, bhi)
-		if match.Size > 0 {
-			blocks = append(blocks, match)
-			if alo < match.A && blo < match.B {
-				queue = append(queue, [4]int{alo, match.A, blo, match.B})
-			}
-			if match.A+match.Size < ahi && match.B+match.Size < bhi {
-				queue = append(queue, [4]int{match.A + match.Size, ahi, match.B + match.Size, bhi})
-			}
+		match := longestMatch(aIDs, b2j, sc, lim, tie, alo, ahi, blo, bhi)
+		if lim.stop(0) {
+			sc.stack = stack
+			return false
 		}
-	}
-	// Sort blocks by A index
-	sortMatchBlocks(blocks)
-	// Merge adjacent
-	var merged []SequenceMatch
-	i1, j1, k1 := 0, 0, 0
-	for _, b := range blocks {
-		if i1+k1 == b.A && j1+k1 == b.B {
-			k1 += b.Size
-		} else {
-			if k1 > 0 {
-				merged = append(merged, SequenceMatch{i1, j1, k1})
-			}
-			i1, j1, k1 = b.A, b.B, b.Size
+		if match.Size == 0 {
+			continue
+		}
+		fn(match)
+		if alo < match.A && blo < match.B {
+			stack = append(stack, [4]int{alo, match.A, blo, match.B})
+		}
+		if match.A+match.Size < ahi && match.B+match.Size < bhi {
+			stack = append(stack, [4]int{match.A + match.Size, ahi, match.B + match.Size, bhi})
 		}
 	}
-	if k1 > 0 {
-		merged = append(merged, SequenceMatch{i1, j1, k1})
-	}
-	merged = append(merged, SequenceMatch{len(m.a), len(m.b), 0})
-	return merged
+	sc.stack = stack
+	return true
+}
+
+// matchLimit bounds the work of a matching run. A nil *matchLimit never
+// stops it.
+type matchLimit struct {
+	done     <-chan struct{} // stop once closed
+	max      int             // stop after this many comparisons, if > 0
+	used     int
+	canceled bool
 }
 
-func sortMatchBlocks(blocks []SequenceMatch) {
-	// Insertion sort by A
-	for i := 1; i < len(blocks); i++ {
-		for j := i; j > 0 && blocks[j].A < blocks[j-1].A; j-- {
-			blocks[j], blocks[j-1] = blocks[j-1], blocks[j]
+// stop reports whether matching must stop. The done channel is only polled
+// when row is a multiple of 256.
+func (l *matchLimit) stop(row int) bool {
+	if l == nil {
+		return false
+	}
+	if l.done != nil && row%256 == 0 && !l.canceled {
+		select {
+		case <-l.done:
+			l.canceled = true
+		default:
 		}
 	}
+	return l.canceled || l.max > 0 && l.used > l.max
+}
+
+// GetOpCodes returns the opcodes that turn the first sequence into the
+// second.
+func (m *Matcher) GetOpCodes() []OpCode {
+	return opCodesFromBlocks(m.GetMatchingBlocks())
+}
+
+// GetOpCodesContext is GetOpCodes that gives up when ctx is done. It then
+// returns ctx.Err() together with coarse opcodes that trim the common prefix
+// and suffix of the sequences and replace everything between them.
+func (m *Matcher) GetOpCodesContext(ctx context.Context) ([]OpCode, error) {
+	blocks, err := m.GetMatchingBlocksContext(ctx)
+	if err != nil {
+		return coarseOpCodes(m.a, m.b), err
+	}
+	return opCodesFromBlocks(blocks), nil
 }
 
-func (m *matcher) GetOpCodes() []OpCode {
-	blocks := m.GetMatchingBlocks()
+// opCodesFromBlocks turns matching blocks into opcodes.
+func opCodesFromBlocks(blocks []SequenceMatch) []OpCode {
 	var codes []OpCode
+	walkBlocks(blocks, func(c OpCode) error {
+		codes = append(codes, c)
+		return nil
+	})
+	return codes
+}
+
+// walkBlocks calls fn with each opcode described by blocks, in order, and
+// stops at the first error fn returns.
+func walkBlocks(blocks []SequenceMatch, fn func(OpCode) error) error {
 	i, j := 0, 0
 	for _, b := range blocks {
 		tag := OpEqual
@@ -632,23 +996,92 @@ func (m *matcher) GetOpCodes() []OpCode {
 			tag = OpEqual
 		}
 		if i < b.A || j < b.B {
-			codes = append(codes, OpCode{tag, i, b.A, j, b.B})
+			if err := fn(OpCode{tag, i, b.A, j, b.B}); err != nil {
+				return err
+			}
 		}
 		i, j = b.A+b.Size, b.B+b.Size
 		if b.Size > 0 {
-			codes = append(codes, OpCode{OpEqual, b.A, i, b.B, j})
+			if err := fn(OpCode{OpEqual, b.A, i, b.B, j}); err != nil {
+				return err
+			}
 		}
 	}
+	return nil
+}
+
+// coarseBlocks returns the matching blocks of coarseOpCodes.
+func coarseBlocks(a, b []string) []SequenceMatch {
+	var blocks []SequenceMatch
+	for _, c := range coarseOpCodes(a, b) {
+		if c.Tag == OpEqual {
+			blocks = append(blocks, SequenceMatch{c.I1, c.J1, c.I2 - c.I1})
+		}
+	}
+	return append(blocks, SequenceMatch{len(a), len(b), 0})
+}
+
+// coarseOpCodes diffs a and b without matching: it keeps their common
+// prefix and suffix and replaces everything between them.
+func coarseOpCodes(a, b []string) []OpCode {
+	pre := 0
+	for pre < len(a) && pre < len(b) && a[pre] == b[pre] {
+		pre++
+	}
+	suf := 0
+	for suf < len(a)-pre && suf < len(b)-pre && a[len(a)-1-suf] == b[len(b)-1-suf] {
+		suf++
+	}
+	var codes []OpCode
+	if pre > 0 {
+		codes = append(codes, OpCode{OpEqual, 0, pre, 0, pre})
+	}
+	i2, j2 := len(a)-suf, len(b)-suf
+	switch {
+	case pre < i2 && pre < j2:
+		codes = append(codes, OpCode{OpReplace, pre, i2, pre, j2})
+	case pre < i2:
+		codes = append(codes, OpCode{OpDelete, pre, i2, pre, j2})
+	case pre < j2:
+		codes = append(codes, OpCode{OpInsert, pre, i2, pre, j2})
+	}
+	if suf > 0 {
+		codes = append(codes, OpCode{OpEqual, i2, len(a), j2, len(b)})
+	}
 	return codes
 }
 
-func (m *matcher) Ratio() float64 {
-	blocks := m.GetMatchingBlocks()
+// Ratio returns the similarity of the two sequences in [0.0, 1.0], twice the
+// number of matching elements divided by the total number of elements.
+func (m *Matcher) Ratio() float64 {
 	matches := 0
-	for _, b := range blocks {
+	for _, b := range m.GetMatchingBlocks() {
 		matches += b.Size
 	}
-	total := len(m.a) + len(m.b)
+	return calcRatio(matches, len(m.a)+len(m.b))
+}
+
+// QuickRatio returns an upper bound on Ratio that is cheaper to compute: it
+// counts the elements the sequences have in common regardless of order.
+func (m *Matcher) QuickRatio() float64 {
+	used := make([]int, len(m.b2j))
+	matches := 0
+	for _, id := range m.aIDs {
+		if id >= 0 && used[id] < len(m.b2j[id]) {
+			used[id]++
+			matches++
+		}
+	}
+	return calcRatio(matches, len(m.a)+len(m.b))
+}
+
+// RealQuickRatio returns an upper bound on Ratio and QuickRatio computed
+// from the sequence lengths alone.
+func (m *Matcher) RealQuickRatio() float64 {
+	return calcRatio(min(len(m.a), len(m.b)), len(m.a)+len(m.b))
+}
+
+func calcRatio(matches, total int) float64 {
 	if total == 0 {
 		return 1.0
 	}
@@ -656,54 +1089,42 @@ func (m *matcher) Ratio() float64 {
 }
 
 // groupOpcodes groups opcodes into hunks, each surrounded by up to `ctx` equal lines.
+// Groups consisting solely of equal lines are dropped, so identical inputs
+// produce no groups at all.
 func groupOpcodes(codes []OpCode, ctx int) [][]OpCode {
 	if len(codes) == 0 {
 		return nil
 	}
-	// Filter leading/trailing equal blocks
-	var filtered []OpCode
-	for _, c := range codes {
-		if c.Tag == OpEqual {
-			i1 := int(math.Max(float64(c.I1), float64(c.I2-ctx)))
-			i2 := int(math.Min(float64(c.I2), float64(c.I1+ctx)))
-			j1 := c.J1 + (i1 - c.I1)
-			j2 := c.J1 + (i2 - c.I1)
-			filtered = append(filtered, OpCode{c.Tag, i1, i2, j1, j2})
-		} else {
-			filtered = append(filtered, c)
-		}
+	codes = append([]OpCode(nil), codes...)
+	// Trim leading/trailing equal blocks down to ctx lines
+	if c := codes[0]; c.Tag == OpEqual {
+		codes[0] = OpCode{OpEqual, max(c.I1, c.I2-ctx), c.I2, max(c.J1, c.J2-ctx), c.J2}
+	}
+	if c := codes[len(codes)-1]; c.Tag == OpEqual {
+		codes[len(codes)-1] = OpCode{OpEqual, c.I1, min(c.I2, c.I1+ctx), c.J1, min(c.J2, c.J1+ctx)}
 	}
 
 	var groups [][]OpCode
 	var group []OpCode
-	for _, c := range filtered {
+	for _, c := range codes {
 		if c.Tag == OpEqual && c.I2-c.I1 > ctx*2 {
 			// End of hunk: keep only first ctx lines
-			head := OpCode{OpEqual, c.I1, c.I1 + ctx, c.J1, c.J1 + ctx}
-			group = append(group, head)
+			group = append(group, OpCode{OpEqual, c.I1, c.I1 + ctx, c.J1, c.J1 + ctx})
 			groups = append(groups, group)
 			group = nil
 			// Start new hunk with last ctx lines
-			tail := OpCode{OpEqual, c.I2 - ctx, c.I2, c.J2 - ctx, c.J2}
-			group = append(group, tail)
-		} else {
-			group = append(group, c)
+			c = OpCode{OpEqual, c.I2 - ctx, c.I2, c.J2 - ctx, c.J2}
 		}
+		group = append(group, c)
 	}
-	if len(group) > 0 {
+	if len(group) > 0 && !(len(group) == 1 && group[0].Tag == OpEqual) {
 		groups = append(groups, group)
 	}
 	return groups
 }
 
 func buildHunk(a, b []string, group []OpCode) Hunk {
-	first, last := group[0], group[len(group)-1]
-	hunk := Hunk{
-		OldStart: first.I1 + 1,
-		OldLines: last.I2 - first.I1,
-		NewStart: first.J1 + 1,
-		NewLines: last.J2 - first.J1,
-	}
+	hunk := groupHunkHeader(group)
 	for _, op := range group {
 		switch op.Tag {
 		case OpEqual:
@@ -729,3 +1150,23 @@ func buildHunk(a, b []string, group []OpCode) Hunk {
 	}
 	return hunk
 }
+
+// groupHunkHeader returns a Hunk with the header fields of an opcode group
+// set and no lines.
+func groupHunkHeader(group []OpCode) Hunk {
+	first, last := group[0], group[len(group)-1]
+	hunk := Hunk{
+		OldStart: first.I1 + 1,
+		OldLines: last.I2 - first.I1,
+		NewStart: first.J1 + 1,
+		NewLines: last.J2 - first.J1,
+	}
+	// An empty range is named by the line before it, as in GNU diff.
+	if hunk.OldLines == 0 {
+		hunk.OldStart--
+	}
+	if hunk.NewLines == 0 {
+		hunk.NewStart--
+	}
+	return hunk
+}
@@ -1,6 +1,11 @@
 package difflib_test
 
 import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"sort"
 	"strings"
 	"testing"
 
@@ -109,6 +114,65 @@ func TestUnifiedDiffHeaders(t *testing.T) {
 	}
 }
 
+func TestUnifiedDiffMultipleHunks(t *testing.T) {
+	var a []string
+	for i := 0; i < 20; i++ {
+		a = append(a, fmt.Sprintf("%d\n", i))
+	}
+	b := append([]string(nil), a...)
+	b[2] = "two\n"
+	b[17] = "seventeen\n"
+	result := difflib.UnifiedDiff(difflib.DiffInput{A: a, B: b, FromFile: "a", ToFile: "b"})
+	if len(result.Hunks) != 2 {
+		t.Fatalf("expected 2 hunks, got %d:\n%s", len(result.Hunks), result.String())
+	}
+	if h := result.Hunks[1]; h.OldStart != 15 || h.OldLines != 6 {
+		t.Errorf("second hunk header = -%d,%d, want -15,6", h.OldStart, h.OldLines)
+	}
+}
+
+func TestUnifiedDiffNoContext(t *testing.T) {
+	a := numbered(20)
+	b := append([]string(nil), a...)
+	b[2] = "two\n"
+	b = append(b[:10], b[11:]...)
+	tests := []struct {
+		context int
+		want    string
+	}{
+		{difflib.NoContext, "@@ -3,1 +3,1 @@\n-line 3\n+two\n@@ -11,1 +10,0 @@\n-line 11\n"},
+		{-5, "@@ -3,1 +3,1 @@\n-line 3\n+two\n@@ -11,1 +10,0 @@\n-line 11\n"},
+		{1, "@@ -2,3 +2,3 @@\n line 2\n-line 3\n+two\n line 4\n@@ -10,3 +10,2 @@\n line 10\n-line 11\n line 12\n"},
+	}
+	for _, tt := range tests {
+		d := difflib.UnifiedDiff(difflib.DiffInput{A: a, B: b, Context: tt.context})
+		var got strings.Builder
+		for _, h := range d.Hunks {
+			h.WriteTo(&got)
+		}
+		if got.String() != tt.want {
+			t.Errorf("Context %d:\n%s\nwant:\n%s", tt.context, got.String(), tt.want)
+		}
+	}
+	unset := difflib.UnifiedDiff(difflib.DiffInput{A: a, B: b}).String()
+	if three := difflib.UnifiedDiff(difflib.DiffInput{A: a, B: b, Context: 3}).String(); unset != three {
+		t.Errorf("zero Context gave:\n%s\nwant the default of 3:\n%s", unset, three)
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	for iter := 0; iter < 100; iter++ {
+		a, b := randomLines(rng, rng.Intn(30)), randomLines(rng, rng.Intn(30))
+		patch := difflib.UnifiedDiff(difflib.DiffInput{A: a, B: b, FromFile: "a", ToFile: "b", Context: difflib.NoContext}).String()
+		got, err := difflib.ApplyPatch(a, patch)
+		if err != nil {
+			t.Fatalf("iteration %d: %v\n%s", iter, err, patch)
+		}
+		if difflib.JoinLines(got) != difflib.JoinLines(b) {
+			t.Fatalf("iteration %d: patch does not produce b:\n%s", iter, patch)
+		}
+	}
+}
+
 func TestSequenceRatioIdentical(t *testing.T) {
 	a := difflib.SplitLines("foo\nbar\n")
 	ratio := difflib.SequenceRatio(a, a)
@@ -144,6 +208,109 @@ func TestStringRatio(t *testing.T) {
 	}
 }
 
+func TestLongestCommonSubstring(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want string
+		i, j int
+	}{
+		{"", "", "", 0, 0},
+		{"abc", "xyz", "", 0, 0},
+		{"the quick fox", "a quick dog", " quick ", 3, 1},
+		{"abcd", "abcd", "abcd", 0, 0},
+		{"xabxcd", "abcd", "ab", 1, 0},
+		{"naïve café", "café naïve", "naïve", 0, 6},
+	}
+	for _, tt := range tests {
+		s, i, j := difflib.LongestCommonSubstring(tt.a, tt.b)
+		if s != tt.want || i != tt.i || j != tt.j {
+			t.Errorf("LongestCommonSubstring(%q, %q) = %q, %d, %d; want %q, %d, %d", tt.a, tt.b, s, i, j, tt.want, tt.i, tt.j)
+		}
+		if s != "" && (tt.a[i:i+len(s)] != s || tt.b[j:j+len(s)] != s) {
+			t.Errorf("offsets %d, %d do not locate %q", i, j, s)
+		}
+	}
+}
+
+func TestMatcherQuickRatios(t *testing.T) {
+	// Values from Python's SequenceMatcher.
+	tests := []struct {
+		a, b                    string
+		ratio, quick, realQuick float64
+	}{
+		{"", "", 1, 1, 1},
+		{"abcd", "bcde", 0.75, 0.75, 1},
+		{"abcd", "dcba", 0.25, 1, 1},
+		{"abc", "abcdef", 2.0 / 3, 2.0 / 3, 2.0 / 3},
+		{"aab", "abb", 2.0 / 3, 2.0 / 3, 1},
+	}
+	for _, tt := range tests {
+		m := difflib.NewMatcher(nil, nil)
+		m.SetSeqs(strings.Split(tt.a, ""), strings.Split(tt.b, ""))
+		if got := m.Ratio(); got != tt.ratio {
+			t.Errorf("%q, %q: Ratio() = %v, want %v", tt.a, tt.b, got, tt.ratio)
+		}
+		if got := m.QuickRatio(); got != tt.quick {
+			t.Errorf("%q, %q: QuickRatio() = %v, want %v", tt.a, tt.b, got, tt.quick)
+		}
+		if got := m.RealQuickRatio(); got != tt.realQuick {
+			t.Errorf("%q, %q: RealQuickRatio() = %v, want %v", tt.a, tt.b, got, tt.realQuick)
+		}
+	}
+}
+
+func TestMatcherSetSeqs(t *testing.T) {
+	a := difflib.SplitLines("a\nb\nc\nd\n")
+	b1 := difflib.SplitLines("x\ny\n")
+	b2 := difflib.SplitLines("b\nc\nz\na\n")
+	m := difflib.NewMatcher(a, b1)
+	if got := m.GetOpCodes(); !reflect.DeepEqual(got, difflib.GetOpCodes(a, b1)) {
+		t.Errorf("GetOpCodes() = %v", got)
+	}
+	// Elements of a absent from the old b must be found in the new one.
+	m.SetSeq2(b2)
+	if got, want := m.GetOpCodes(), difflib.GetOpCodes(a, b2); !reflect.DeepEqual(got, want) {
+		t.Errorf("after SetSeq2: GetOpCodes() = %v, want %v", got, want)
+	}
+	m.SetSeq1(b1)
+	if got := m.Ratio(); got != 0 {
+		t.Errorf("after SetSeq1: Ratio() = %v, want 0", got)
+	}
+	m.SetSeqs(b2, a)
+	if got, want := m.GetOpCodes(), difflib.GetOpCodes(b2, a); !reflect.DeepEqual(got, want) {
+		t.Errorf("after SetSeqs: GetOpCodes() = %v, want %v", got, want)
+	}
+}
+
+func TestClosestMatchesPruning(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	word := func() string {
+		b := make([]byte, 1+rng.Intn(8))
+		for i := range b {
+			b[i] = byte('a' + rng.Intn(4))
+		}
+		return string(b)
+	}
+	for iter := 0; iter < 50; iter++ {
+		target := word()
+		candidates := make([]string, 40)
+		for i := range candidates {
+			candidates[i] = word()
+		}
+		// Brute force: stable sort of every candidate by ratio.
+		want := append([]string(nil), candidates...)
+		sort.SliceStable(want, func(i, j int) bool {
+			return difflib.StringRatio(target, want[i]) > difflib.StringRatio(target, want[j])
+		})
+		for _, n := range []int{0, 1, 5, 40, 50} {
+			got := difflib.ClosestMatches(target, candidates, n)
+			if w := want[:min(n, len(want))]; strings.Join(got, ",") != strings.Join(w, ",") {
+				t.Fatalf("ClosestMatches(%q, %q, %d) = %q, want %q", target, candidates, n, got, w)
+			}
+		}
+	}
+}
+
 func TestGetMatchingBlocks(t *testing.T) {
 	a := difflib.SplitLines("a\nb\nc\n")
 	b := difflib.SplitLines("a\nX\nc\n")
@@ -170,6 +337,71 @@ func TestGetOpCodes(t *testing.T) {
 	}
 }
 
+func TestWalkOpCodes(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	for n := 0; n < 30; n++ {
+		a, b := randomLines(rng, rng.Intn(30)), randomLines(rng, rng.Intn(30))
+		var got []difflib.OpCode
+		err := difflib.WalkOpCodes(a, b, func(op difflib.OpCode, aLines, bLines []string) error {
+			if !reflect.DeepEqual(aLines, a[op.I1:op.I2]) || !reflect.DeepEqual(bLines, b[op.J1:op.J2]) {
+				t.Fatalf("lines for %v do not match the opcode", op)
+			}
+			got = append(got, op)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("WalkOpCodes error: %v", err)
+		}
+		if want := difflib.GetOpCodes(a, b); !reflect.DeepEqual(got, want) {
+			t.Fatalf("WalkOpCodes visited %v, want %v", got, want)
+		}
+	}
+}
+
+func TestWalkOpCodesStop(t *testing.T) {
+	a := difflib.SplitLines("a\nb\nc\nd\n")
+	b := difflib.SplitLines("a\nX\nc\nY\n")
+	stop := errors.New("stop")
+	calls := 0
+	err := difflib.WalkOpCodes(a, b, func(op difflib.OpCode, aLines, bLines []string) error {
+		calls++
+		if op.Tag == difflib.OpReplace {
+			return stop
+		}
+		return nil
+	})
+	if err != stop || calls != 2 {
+		t.Errorf("WalkOpCodes = %v after %d calls, want stop after 2", err, calls)
+	}
+}
+
+func ExampleWalkOpCodes() {
+	a := difflib.SplitLines("one\ntwo\nthree\n")
+	b := difflib.SplitLines("one\n2\nthree\nfour\n")
+	difflib.WalkOpCodes(a, b, func(op difflib.OpCode, aLines, bLines []string) error {
+		fmt.Printf("%-7s %q %q\n", op.Tag, aLines, bLines)
+		return nil
+	})
+	// Output:
+	// equal   ["one\n"] ["one\n"]
+	// replace ["two\n"] ["2\n"]
+	// equal   ["three\n"] ["three\n"]
+	// insert  [] ["four\n"]
+}
+
+func TestNDiffInputFoldEqual(t *testing.T) {
+	a := difflib.SplitLines("a\nb\nc\nd\ne\n")
+	b := difflib.SplitLines("a\nb\nc\nd\nE\n")
+	got := difflib.NDiffInput(difflib.DiffInput{A: a, B: b, FoldEqual: 3})
+	want := []string{"⋮ 4 identical lines (old 1-4, new 1-4)\n", "- e\n", "+ E\n"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("NDiffInput = %q, want %q", got, want)
+	}
+	if got := difflib.NDiffInput(difflib.DiffInput{A: a, B: b}); !reflect.DeepEqual(got, difflib.NDiff(a, b)) {
+		t.Errorf("NDiffInput without folding = %q, want NDiff's %q", got, difflib.NDiff(a, b))
+	}
+}
+
 func TestOpString(t *testing.T) {
 	cases := []struct {
 		op   difflib.Op
@@ -200,6 +432,32 @@ func TestNDiff(t *testing.T) {
 	}
 }
 
+func TestNDiffFancyReplace(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want string
+	}{
+		{"one\ntwo\nthree\n", "ore\ntree\nemu\n",
+			"- one\n?  ^\n+ ore\n?  ^\n- two\n- three\n?  -\n+ tree\n+ emu\n"},
+		{"zero\nabcdefgh\n", "abcdxfgh\n", "- zero\n- abcdefgh\n?     ^\n+ abcdxfgh\n?     ^\n"},
+		{"\tx = 1\n", "\tx = 10\n", "- \tx = 1\n+ \tx = 10\n? \t     +\n"},
+		{"alpha\nbeta\n", "gamma\n", "+ gamma\n- alpha\n- beta\n"},
+	}
+	for _, tt := range tests {
+		a, b := difflib.SplitLines(tt.a), difflib.SplitLines(tt.b)
+		delta := difflib.NDiff(a, b)
+		if got := strings.Join(delta, ""); got != tt.want {
+			t.Errorf("NDiff(%q, %q) = %q, want %q", tt.a, tt.b, got, tt.want)
+		}
+		if got := difflib.JoinLines(difflib.Restore(delta, 1)); got != tt.a {
+			t.Errorf("Restore(1) = %q, want %q", got, tt.a)
+		}
+		if got := difflib.JoinLines(difflib.Restore(delta, 2)); got != tt.b {
+			t.Errorf("Restore(2) = %q, want %q", got, tt.b)
+		}
+	}
+}
+
 func TestRestore(t *testing.T) {
 	a := difflib.SplitLines("one\ntwo\nthree\n")
 	b := difflib.SplitLines("one\nTWO\nthree\n")
@@ -233,6 +491,52 @@ func TestContextDiff(t *testing.T) {
 	}
 }
 
+func TestContextDiffMarkers(t *testing.T) {
+	tests := []struct {
+		name, a, b, want string
+	}{
+		{
+			name: "delete and insert",
+			a:    "a\nb\nc\nd\ne\n",
+			b:    "a\nc\nd\nE\ne\nf\n",
+			want: "*** o\n--- n\n***************\n*** 1,5 ****\n  a\n- b\n  c\n  d\n  e\n" +
+				"--- 1,6 ----\n  a\n  c\n  d\n+ E\n  e\n+ f\n",
+		},
+		{
+			name: "insert only",
+			a:    "a\nb\nc\n",
+			b:    "a\nX\nb\nc\n",
+			want: "*** o\n--- n\n***************\n*** 1,3 ****\n--- 1,4 ----\n  a\n+ X\n  b\n  c\n",
+		},
+		{
+			name: "delete only",
+			a:    "a\nb\n",
+			b:    "a\n",
+			want: "*** o\n--- n\n***************\n*** 1,2 ****\n  a\n- b\n--- 1 ----\n",
+		},
+		{
+			name: "empty old",
+			a:    "",
+			b:    "x\ny\n",
+			want: "*** o\n--- n\n***************\n*** 0 ****\n--- 1,2 ----\n+ x\n+ y\n",
+		},
+		{
+			name: "change",
+			a:    "one\ntwo\n",
+			b:    "one\nTWO\n",
+			want: "*** o\n--- n\n***************\n*** 1,2 ****\n  one\n! two\n--- 1,2 ----\n  one\n! TWO\n",
+		},
+	}
+	for _, tt := range tests {
+		got := strings.Join(difflib.ContextDiff(difflib.DiffInput{
+			A: difflib.SplitLines(tt.a), B: difflib.SplitLines(tt.b), FromFile: "o", ToFile: "n",
+		}), "")
+		if got != tt.want {
+			t.Errorf("%s: ContextDiff =\n%s\nwant\n%s", tt.name, got, tt.want)
+		}
+	}
+}
+
 func TestClosestMatch(t *testing.T) {
 	best, ratio := difflib.ClosestMatch("appel", []string{"apple", "mango", "apply"})
 	if best != "apple" && best != "apply" {
@@ -0,0 +1,91 @@
+// Package difftest provides test assertions that report mismatches as
+// unified diffs instead of two quoted strings, golden-file snapshots that
+// are rewritten by running the tests with -update, and a similarity
+// assertion for output that varies between runs.
+//
+// Basic usage:
+//
+//	func TestRender(t *testing.T) {
+//		got := render(doc)
+//		difftest.Equal(t, want, got, difftest.Options{})
+//	}
+//
+// A failure reads:
+//
+//	render_test.go:12: mismatch (-want +got):
+//	    --- want
+//	    +++ got
+//	    @@ -1,3 +1,3 @@
+//	     <ul>
+//	    -  <li>one</li>
+//	    +  <li>One</li>
+//	     </ul>
+package difftest
+
+import (
+	"testing"
+
+	difflib "github.com/njchilds90/go-difflib"
+)
+
+// Options configures Equal and Diff. The zero value is ready to use.
+type Options struct {
+	// WantLabel and GotLabel name the two sides in the diff header.
+	// Defaults: "want" and "got".
+	WantLabel, GotLabel string
+	// Context is the number of unchanged lines around each change.
+	// Default: 3. Use difflib.NoContext for none.
+	Context int
+	// Color colors the diff with difflib.DefaultTheme. Most test runners
+	// pass ANSI escapes through to the terminal; leave it off for CI logs.
+	Color bool
+}
+
+// Diff returns the unified diff from want to got, or "" if they are equal.
+// A missing newline at the end of either side is shown with a
+// "\ No newline at end of file" marker, so the difference stays visible.
+//
+// Example:
+//
+//	if d := difftest.Diff(want, got, difftest.Options{}); d != "" {
+//		t.Fatalf("mismatch (-want +got):\n%s", d)
+//	}
+func Diff(want, got string, opts Options) string {
+	if want == got {
+		return ""
+	}
+	if opts.WantLabel == "" {
+		opts.WantLabel = "want"
+	}
+	if opts.GotLabel == "" {
+		opts.GotLabel = "got"
+	}
+	d := difflib.UnifiedDiff(difflib.DiffInput{
+		A:        difflib.SplitLines(want),
+		B:        difflib.SplitLines(got),
+		FromFile: opts.WantLabel,
+		ToFile:   opts.GotLabel,
+		Context:  opts.Context,
+	}).String()
+	if opts.Color {
+		d = difflib.Colorize(d, difflib.DefaultTheme())
+	}
+	return d
+}
+
+// Equal reports whether want and got are equal. If they are not, it marks
+// the test as failed and logs the unified diff from want to got; the test
+// keeps running.
+//
+// Example:
+//
+//	difftest.Equal(t, string(golden), out.String(), difftest.Options{Color: true})
+func Equal(t testing.TB, want, got string, opts Options) bool {
+	t.Helper()
+	d := Diff(want, got, opts)
+	if d == "" {
+		return true
+	}
+	t.Errorf("mismatch (-want +got):\n%s", d)
+	return false
+}
@@ -0,0 +1,97 @@
+package difftest_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/njchilds90/go-difflib/difftest"
+)
+
+// fakeT records failures instead of failing the real test.
+type fakeT struct {
+	testing.TB
+	errors []string
+}
+
+func (f *fakeT) Helper() {}
+
+func (f *fakeT) Errorf(format string, args ...any) {
+	f.errors = append(f.errors, fmt.Sprintf(format, args...))
+}
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name      string
+		want, got string
+		opts      difftest.Options
+		diff      string
+	}{
+		{"equal", "a\nb\n", "a\nb\n", difftest.Options{}, ""},
+		{"empty", "", "", difftest.Options{}, ""},
+		{
+			"changed line", "a\nb\nc\n", "a\nB\nc\n", difftest.Options{},
+			"--- want\n+++ got\n@@ -1,3 +1,3 @@\n a\n-b\n+B\n c\n",
+		},
+		{
+			"labels and context", "a\nb\nc\n", "a\nB\nc\n",
+			difftest.Options{WantLabel: "golden", GotLabel: "output", Context: 1},
+			"--- golden\n+++ output\n@@ -1,3 +1,3 @@\n a\n-b\n+B\n c\n",
+		},
+		{
+			"missing final newline", "a\nb\n", "a\nb", difftest.Options{},
+			"--- want\n+++ got\n@@ -1,2 +1,2 @@\n a\n-b\n+b\n\\ No newline at end of file\n",
+		},
+		{
+			"added final newline", "x", "x\n", difftest.Options{},
+			"--- want\n+++ got\n@@ -1,1 +1,1 @@\n-x\n\\ No newline at end of file\n+x\n",
+		},
+		{
+			"single line", "hello", "help", difftest.Options{},
+			"--- want\n+++ got\n@@ -1,1 +1,1 @@\n-hello\n\\ No newline at end of file\n+help\n\\ No newline at end of file\n",
+		},
+	}
+	for _, tt := range tests {
+		if got := difftest.Diff(tt.want, tt.got, tt.opts); got != tt.diff {
+			t.Errorf("%s: Diff =\n%q\nwant\n%q", tt.name, got, tt.diff)
+		}
+	}
+}
+
+func TestDiffColor(t *testing.T) {
+	d := difftest.Diff("a\n", "b\n", difftest.Options{Color: true})
+	if !strings.Contains(d, "\x1b[") {
+		t.Errorf("colored diff has no escape sequences: %q", d)
+	}
+	if d := difftest.Diff("a\n", "a\n", difftest.Options{Color: true}); d != "" {
+		t.Errorf("equal inputs gave %q", d)
+	}
+}
+
+func TestEqual(t *testing.T) {
+	ft := &fakeT{}
+	if !difftest.Equal(ft, "a\nb\n", "a\nb\n", difftest.Options{}) || len(ft.errors) != 0 {
+		t.Errorf("equal strings: errors %q", ft.errors)
+	}
+	if difftest.Equal(ft, "a\nb\n", "a\nc\n", difftest.Options{}) {
+		t.Error("Equal reported different strings as equal")
+	}
+	want := "mismatch (-want +got):\n--- want\n+++ got\n@@ -1,2 +1,2 @@\n a\n-b\n+c\n"
+	if len(ft.errors) != 1 || ft.errors[0] != want {
+		t.Errorf("errors = %q, want [%q]", ft.errors, want)
+	}
+}
+
+func ExampleDiff() {
+	want := "apples\nbananas\ncherries\n"
+	got := "apples\nblueberries\ncherries\n"
+	fmt.Print(difftest.Diff(want, got, difftest.Options{}))
+	// Output:
+	// --- want
+	// +++ got
+	// @@ -1,3 +1,3 @@
+	//  apples
+	// -bananas
+	// +blueberries
+	//  cherries
+}
@@ -0,0 +1,59 @@
+package difftest
+
+import (
+	"errors"
+	"flag"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// update is the -update test flag. It is registered by this package, so a
+// test binary that imports difftest must not define its own -update flag.
+var update = flag.Bool("update", false, "rewrite golden files checked with difftest.Golden")
+
+// Golden compares got with the contents of the golden file at path and
+// reports whether they are equal. On a mismatch it marks the test as failed
+// and logs the unified diff from the golden file to got, labelled with path.
+//
+// When the test binary runs with -update, Golden instead writes got to path,
+// creating parent directories as needed, and reports true. Review the
+// resulting changes to the golden files before committing them.
+//
+// Example:
+//
+//	// go test ./... -update rewrites testdata/report.golden
+//	difftest.Golden(t, "testdata/report.golden", report.String(), difftest.Options{})
+func Golden(t testing.TB, path, got string, opts Options) bool {
+	t.Helper()
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("difftest: updating golden file: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatalf("difftest: updating golden file: %v", err)
+		}
+		return true
+	}
+	want, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("difftest: golden file %s does not exist; run the test with -update to create it", path)
+		return false
+	}
+	if err != nil {
+		t.Fatalf("difftest: reading golden file: %v", err)
+	}
+	if opts.WantLabel == "" {
+		opts.WantLabel = path
+	}
+	if opts.GotLabel == "" {
+		opts.GotLabel = "got"
+	}
+	d := Diff(string(want), got, opts)
+	if d == "" {
+		return true
+	}
+	t.Errorf("golden file mismatch (-%s +%s); run the test with -update to accept:\n%s", opts.WantLabel, opts.GotLabel, d)
+	return false
+}
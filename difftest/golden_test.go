@@ -0,0 +1,73 @@
+package difftest_test
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/njchilds90/go-difflib/difftest"
+)
+
+// setUpdate sets the -update flag for the rest of the test.
+func setUpdate(t *testing.T, v string) {
+	t.Helper()
+	old := flag.Lookup("update").Value.String()
+	if err := flag.Set("update", v); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { flag.Set("update", old) })
+}
+
+func TestGolden(t *testing.T) {
+	setUpdate(t, "false")
+	path := filepath.Join(t.TempDir(), "out.golden")
+	if err := os.WriteFile(path, []byte("one\ntwo\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	ft := &fakeT{}
+	if !difftest.Golden(ft, path, "one\ntwo\n", difftest.Options{}) || len(ft.errors) != 0 {
+		t.Errorf("matching output: errors %q", ft.errors)
+	}
+
+	if difftest.Golden(ft, path, "one\n2\n", difftest.Options{}) {
+		t.Error("Golden accepted mismatching output")
+	}
+	want := "golden file mismatch (-" + path + " +got); run the test with -update to accept:\n" +
+		"--- " + path + "\n+++ got\n@@ -1,2 +1,2 @@\n one\n-two\n+2\n"
+	if len(ft.errors) != 1 || ft.errors[0] != want {
+		t.Errorf("errors = %q, want [%q]", ft.errors, want)
+	}
+}
+
+func TestGoldenMissing(t *testing.T) {
+	setUpdate(t, "false")
+	ft := &fakeT{}
+	path := filepath.Join(t.TempDir(), "missing.golden")
+	if difftest.Golden(ft, path, "x\n", difftest.Options{}) {
+		t.Error("Golden accepted a missing file")
+	}
+	if len(ft.errors) != 1 || !strings.Contains(ft.errors[0], "does not exist; run the test with -update") {
+		t.Errorf("errors = %q", ft.errors)
+	}
+}
+
+func TestGoldenUpdate(t *testing.T) {
+	setUpdate(t, "true")
+	path := filepath.Join(t.TempDir(), "testdata", "new.golden")
+	ft := &fakeT{}
+	if !difftest.Golden(ft, path, "fresh\n", difftest.Options{}) || len(ft.errors) != 0 {
+		t.Fatalf("update: errors %q", ft.errors)
+	}
+	if got, err := os.ReadFile(path); err != nil || string(got) != "fresh\n" {
+		t.Fatalf("golden file = %q, %v", got, err)
+	}
+	if !difftest.Golden(ft, path, "changed\n", difftest.Options{}) {
+		t.Error("update did not accept changed output")
+	}
+	if got, _ := os.ReadFile(path); string(got) != "changed\n" {
+		t.Errorf("golden file = %q, want rewritten", got)
+	}
+}
@@ -0,0 +1,28 @@
+package difftest
+
+import (
+	"testing"
+
+	difflib "github.com/njchilds90/go-difflib"
+)
+
+// Similar reports whether got is at least minRatio similar to want, as
+// measured by difflib.StringRatio: SequenceRatio over the strings'
+// characters. If it is not, Similar marks the test as failed and logs the
+// computed ratio and the unified diff from want to got.
+//
+// Similar is meant for output that varies between runs, such as text from a
+// language model, where an exact comparison would be flaky.
+//
+// Example:
+//
+//	difftest.Similar(t, "The capital of France is Paris.", answer, 0.8)
+func Similar(t testing.TB, want, got string, minRatio float64) bool {
+	t.Helper()
+	ratio := difflib.StringRatio(want, got)
+	if ratio >= minRatio {
+		return true
+	}
+	t.Errorf("similarity %.3f is below %.3f (-want +got):\n%s", ratio, minRatio, Diff(want, got, Options{}))
+	return false
+}
@@ -0,0 +1,41 @@
+package difftest_test
+
+import (
+	"testing"
+
+	"github.com/njchilds90/go-difflib/difftest"
+)
+
+func TestSimilar(t *testing.T) {
+	tests := []struct {
+		want, got string
+		minRatio  float64
+		pass      bool
+	}{
+		{"same\n", "same\n", 1, true},
+		{"The capital of France is Paris.", "The capital of France is Paris!", 0.9, true},
+		{"The capital of France is Paris.", "Paris is the capital of France.", 0.9, false},
+		{"abcd", "abxy", 0.5, true},    // ratio exactly 0.5
+		{"abcd", "abxy", 0.501, false}, // just above it
+		{"", "", 1, true},
+		{"anything", "", 0, true},
+	}
+	for _, tt := range tests {
+		ft := &fakeT{}
+		if got := difftest.Similar(ft, tt.want, tt.got, tt.minRatio); got != tt.pass {
+			t.Errorf("Similar(%q, %q, %v) = %v, want %v", tt.want, tt.got, tt.minRatio, got, tt.pass)
+		}
+		if failed := len(ft.errors) > 0; failed == tt.pass {
+			t.Errorf("Similar(%q, %q, %v): errors %q", tt.want, tt.got, tt.minRatio, ft.errors)
+		}
+	}
+}
+
+func TestSimilarMessage(t *testing.T) {
+	ft := &fakeT{}
+	difftest.Similar(ft, "abcd\n", "abxy\n", 0.9)
+	want := "similarity 0.600 is below 0.900 (-want +got):\n--- want\n+++ got\n@@ -1,1 +1,1 @@\n-abcd\n+abxy\n"
+	if len(ft.errors) != 1 || ft.errors[0] != want {
+		t.Errorf("errors = %q, want [%q]", ft.errors, want)
+	}
+}
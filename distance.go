@@ -0,0 +1,117 @@
+package difflib
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// EditOptions sets the costs used by EditDistance. A zero cost selects the
+// default of 1, so the zero value gives the plain Levenshtein distance.
+type EditOptions struct {
+	Insert     int // cost of inserting one character
+	Delete     int // cost of deleting one character
+	Substitute int // cost of replacing one character with another
+}
+
+// EditDistance returns the minimum total cost of the insertions, deletions
+// and substitutions of characters (runes) that turn a into b. Unlike
+// StringRatio, which reports similarity, this is the exact weighted
+// Levenshtein distance, computed in O(len(a)*len(b)) time and O(len(b))
+// space.
+//
+// Example:
+//
+//	d := difflib.EditDistance("kitten", "sitting", difflib.EditOptions{})
+//	// d == 3
+//	d = difflib.EditDistance("kitten", "sitting", difflib.EditOptions{Substitute: 2})
+//	// d == 5
+func EditDistance(a, b string, opts EditOptions) int {
+	ins, del, sub := opts.Insert, opts.Delete, opts.Substitute
+	if ins == 0 {
+		ins = 1
+	}
+	if del == 0 {
+		del = 1
+	}
+	if sub == 0 {
+		sub = 1
+	}
+	ar, br := []rune(a), []rune(b)
+	row := make([]int, len(br)+1)
+	for j := range row {
+		row[j] = j * ins
+	}
+	for i := 1; i <= len(ar); i++ {
+		diag := row[0]
+		row[0] = i * del
+		for j := 1; j <= len(br); j++ {
+			cost := diag
+			if ar[i-1] != br[j-1] {
+				cost += sub
+			}
+			diag = row[j]
+			row[j] = min(cost, row[j]+del, row[j-1]+ins)
+		}
+	}
+	return row[len(br)]
+}
+
+// HammingDistance returns the number of positions at which the characters
+// (runes) of a and b differ. The strings must have the same number of
+// characters; otherwise an error is returned.
+//
+// Example:
+//
+//	d, err := difflib.HammingDistance("karolin", "kathrin")
+//	// d == 3
+func HammingDistance(a, b string) (int, error) {
+	ar, br := []rune(a), []rune(b)
+	if len(ar) != len(br) {
+		return 0, fmt.Errorf("difflib: hamming distance of strings of different lengths (%d and %d)", len(ar), len(br))
+	}
+	d := 0
+	for i := range ar {
+		if ar[i] != br[i] {
+			d++
+		}
+	}
+	return d, nil
+}
+
+// CommonPrefixLen returns the length in bytes of the longest common prefix
+// of a and b. The prefix never ends inside a multi-byte character, so
+// a[:n] and b[:n] are valid slices.
+//
+// Example:
+//
+//	n := difflib.CommonPrefixLen("interview", "internet")
+//	// n == 5 ("inter")
+func CommonPrefixLen(a, b string) int {
+	n := 0
+	for n < len(a) && n < len(b) && a[n] == b[n] {
+		n++
+	}
+	for n > 0 && n < len(a) && !utf8.RuneStart(a[n]) {
+		n--
+	}
+	return n
+}
+
+// CommonSuffixLen returns the length in bytes of the longest common suffix
+// of a and b. The suffix never starts inside a multi-byte character, so
+// a[len(a)-n:] and b[len(b)-n:] are valid slices.
+//
+// Example:
+//
+//	n := difflib.CommonSuffixLen("running", "jumping")
+//	// n == 3 ("ing")
+func CommonSuffixLen(a, b string) int {
+	n := 0
+	for n < len(a) && n < len(b) && a[len(a)-1-n] == b[len(b)-1-n] {
+		n++
+	}
+	for n > 0 && !utf8.RuneStart(a[len(a)-n]) {
+		n--
+	}
+	return n
+}
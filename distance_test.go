@@ -0,0 +1,89 @@
+package difflib_test
+
+import (
+	"fmt"
+	"testing"
+
+	difflib "github.com/njchilds90/go-difflib"
+)
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		opts difflib.EditOptions
+		want int
+	}{
+		{"", "", difflib.EditOptions{}, 0},
+		{"abc", "", difflib.EditOptions{}, 3},
+		{"", "abc", difflib.EditOptions{}, 3},
+		{"abc", "abc", difflib.EditOptions{}, 0},
+		{"kitten", "sitting", difflib.EditOptions{}, 3},
+		{"flaw", "lawn", difflib.EditOptions{}, 2},
+		{"héllo", "hello", difflib.EditOptions{}, 1},
+		{"kitten", "sitting", difflib.EditOptions{Substitute: 2}, 5},
+		// Substitution costlier than delete+insert is never used.
+		{"a", "b", difflib.EditOptions{Substitute: 5}, 2},
+		{"abc", "", difflib.EditOptions{Delete: 2}, 6},
+		{"", "abc", difflib.EditOptions{Insert: 3}, 9},
+		{"ab", "abcd", difflib.EditOptions{Insert: 2, Delete: 7}, 4},
+	}
+	for _, tt := range tests {
+		if got := difflib.EditDistance(tt.a, tt.b, tt.opts); got != tt.want {
+			t.Errorf("EditDistance(%q, %q, %+v) = %d, want %d", tt.a, tt.b, tt.opts, got, tt.want)
+		}
+	}
+}
+
+func ExampleEditDistance() {
+	fmt.Println(difflib.EditDistance("kitten", "sitting", difflib.EditOptions{}))
+	fmt.Println(difflib.EditDistance("kitten", "sitting", difflib.EditOptions{Substitute: 2}))
+	// Output:
+	// 3
+	// 5
+}
+
+func TestHammingDistance(t *testing.T) {
+	tests := []struct {
+		a, b    string
+		want    int
+		wantErr bool
+	}{
+		{"", "", 0, false},
+		{"karolin", "kathrin", 3, false},
+		{"1011101", "1001001", 2, false},
+		{"héllo", "hallo", 1, false},
+		{"abc", "ab", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := difflib.HammingDistance(tt.a, tt.b)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("HammingDistance(%q, %q) = %d, %v; want %d, error %v", tt.a, tt.b, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestCommonPrefixSuffixLen(t *testing.T) {
+	tests := []struct {
+		a, b           string
+		prefix, suffix int
+	}{
+		{"", "", 0, 0},
+		{"abc", "xyz", 0, 0},
+		{"abc", "abc", 3, 3},
+		{"interview", "internet", 5, 0},
+		{"running", "jumping", 0, 3},
+		{"abc", "abcdef", 3, 0},
+		// "é" and "è" share their first byte, "ä" and "ö" their first too.
+		{"café", "cafè", 3, 0},
+		{"xä", "xö", 1, 0},
+		{"äb", "öb", 0, 1},
+	}
+	for _, tt := range tests {
+		if got := difflib.CommonPrefixLen(tt.a, tt.b); got != tt.prefix {
+			t.Errorf("CommonPrefixLen(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.prefix)
+		}
+		if got := difflib.CommonSuffixLen(tt.a, tt.b); got != tt.suffix {
+			t.Errorf("CommonSuffixLen(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.suffix)
+		}
+	}
+}
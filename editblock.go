@@ -0,0 +1,334 @@
+package difflib
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// EditBlock is one SEARCH/REPLACE edit, the format in which aider and
+// many coding assistants express changes:
+//
+//	main.go
+//	<<<<<<< SEARCH
+//	fmt.Println("hello")
+//	=======
+//	fmt.Println("hello, world")
+//	>>>>>>> REPLACE
+type EditBlock struct {
+	// Path is the file the edit applies to, from the line before the
+	// block; empty if none was given.
+	Path string `json:"path,omitempty"`
+	// Search is the text to find, normally whole lines each ending in a
+	// newline. An empty Search appends Replace to the content, which is how
+	// new files are created.
+	Search string `json:"search"`
+	// Replace is the text that replaces Search.
+	Replace string `json:"replace"`
+}
+
+// editBlockFuzzyCutoff is the minimum similarity for a fuzzy match of a
+// search text that is not found verbatim.
+const editBlockFuzzyCutoff = 0.8
+
+var (
+	editSearchRe  = regexp.MustCompile(`^<{5,9} ?SEARCH\s*$`)
+	editDividerRe = regexp.MustCompile(`^={5,9}\s*$`)
+	editReplaceRe = regexp.MustCompile(`^>{5,9} ?REPLACE\s*$`)
+)
+
+// ParseEditBlocks extracts the SEARCH/REPLACE blocks from text, such as a
+// model's reply; other text between blocks is ignored. A block's path is
+// the last non-blank line before its SEARCH marker, skipping a Markdown
+// code fence; a block without one inherits the previous block's path.
+// Markers may use 5 to 9 marker characters.
+//
+// Example:
+//
+//	blocks, err := difflib.ParseEditBlocks(reply)
+//	for _, b := range blocks {
+//	    src := files[b.Path]
+//	    files[b.Path], err = difflib.ApplyEditBlocks(src, []difflib.EditBlock{b})
+//	}
+func ParseEditBlocks(text string) ([]EditBlock, error) {
+	lines := SplitLines(text)
+	var blocks []EditBlock
+	path := ""
+	for i := 0; i < len(lines); i++ {
+		if !editSearchRe.MatchString(strings.TrimRight(lines[i], "\r\n")) {
+			continue
+		}
+		if p := editBlockPath(lines[:i]); p != "" {
+			path = p
+		}
+		start := i
+		var search, replace strings.Builder
+		cur := &search
+		for i++; ; i++ {
+			if i == len(lines) {
+				return nil, fmt.Errorf("difflib: edit block at line %d is not terminated by >>>>>>> REPLACE", start+1)
+			}
+			l := strings.TrimRight(lines[i], "\r\n")
+			if cur == &search && editDividerRe.MatchString(l) {
+				cur = &replace
+				continue
+			}
+			if editReplaceRe.MatchString(l) {
+				if cur == &search {
+					return nil, fmt.Errorf("difflib: edit block at line %d has no ======= divider", start+1)
+				}
+				break
+			}
+			if editSearchRe.MatchString(l) {
+				return nil, fmt.Errorf("difflib: edit block at line %d is not terminated by >>>>>>> REPLACE", start+1)
+			}
+			cur.WriteString(lines[i])
+			if !strings.HasSuffix(lines[i], "\n") {
+				cur.WriteByte('\n')
+			}
+		}
+		blocks = append(blocks, EditBlock{Path: path, Search: search.String(), Replace: replace.String()})
+	}
+	return blocks, nil
+}
+
+// editBlockPath returns the path named on the last non-blank line of
+// before, skipping one opening code fence, or "" if that line is part of
+// the previous block.
+func editBlockPath(before []string) string {
+	fenceSkipped := false
+	for i := len(before) - 1; i >= 0; i-- {
+		l := strings.TrimSpace(before[i])
+		switch {
+		case l == "":
+			continue
+		case strings.HasPrefix(l, "```") && !fenceSkipped:
+			fenceSkipped = true
+			continue
+		case editReplaceRe.MatchString(l), strings.HasPrefix(l, "```"):
+			return ""
+		}
+		return strings.Trim(l, "`*#: ")
+	}
+	return ""
+}
+
+// FormatEditBlocks renders blocks in SEARCH/REPLACE format, each preceded
+// by its path if it has one. ParseEditBlocks reads the result back.
+//
+// Example:
+//
+//	fmt.Print(difflib.FormatEditBlocks(difflib.GenerateEditBlocks("main.go", a, b)))
+func FormatEditBlocks(blocks []EditBlock) string {
+	var b strings.Builder
+	for i, blk := range blocks {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		if blk.Path != "" {
+			b.WriteString(blk.Path + "\n")
+		}
+		b.WriteString("<<<<<<< SEARCH\n")
+		writeEditText(&b, blk.Search)
+		b.WriteString("=======\n")
+		writeEditText(&b, blk.Replace)
+		b.WriteString(">>>>>>> REPLACE\n")
+	}
+	return b.String()
+}
+
+func writeEditText(b *strings.Builder, s string) {
+	b.WriteString(s)
+	if s != "" && !strings.HasSuffix(s, "\n") {
+		b.WriteByte('\n')
+	}
+}
+
+// GenerateEditBlocks returns SEARCH/REPLACE blocks that turn a into b, one
+// per hunk of their diff. Each search text is given just enough context to
+// be found, in order, at the right place: the blocks are verified to
+// reproduce b with ApplyEditBlocks, adding context until they do. An empty
+// result means a and b are equal.
+//
+// Example:
+//
+//	blocks := difflib.GenerateEditBlocks("main.go", a, b)
+//	fmt.Print(difflib.FormatEditBlocks(blocks))
+func GenerateEditBlocks(path string, a, b []string) []EditBlock {
+	want := strings.Join(b, "")
+	if len(a) == 0 {
+		if want == "" {
+			return nil
+		}
+		return []EditBlock{{Path: path, Replace: want}}
+	}
+	codes := NewMatcher(a, b).GetOpCodes()
+	if len(codes) == 1 && codes[0].Tag == OpEqual {
+		return nil
+	}
+	orig := strings.Join(a, "")
+	for ctx := 1; ctx < len(a); ctx *= 2 {
+		var blocks []EditBlock
+		for _, group := range groupOpcodes(codes, ctx) {
+			first, last := group[0], group[len(group)-1]
+			blocks = append(blocks, EditBlock{
+				Path:    path,
+				Search:  strings.Join(a[first.I1:last.I2], ""),
+				Replace: strings.Join(b[first.J1:last.J2], ""),
+			})
+		}
+		if got, err := applyEditBlocks(orig, blocks, false); err == nil && got == want {
+			return blocks
+		}
+	}
+	return []EditBlock{{Path: path, Search: orig, Replace: want}}
+}
+
+// ApplyEditBlocks applies blocks to content in order, each to the result of
+// the previous one, ignoring their paths. Each search text is located by
+// the first of these that succeeds:
+//
+//  1. its first verbatim occurrence, preferring one that starts a line;
+//  2. lines equal apart from trailing whitespace and line endings;
+//  3. lines equal apart from a uniform difference in indentation, which is
+//     then applied to the replacement as well;
+//  4. the most similar run of as many lines, if its similarity ratio is at
+//     least 0.8.
+//
+// An error names the first block that could not be placed.
+//
+// Example:
+//
+//	out, err := difflib.ApplyEditBlocks(src, blocks)
+func ApplyEditBlocks(content string, blocks []EditBlock) (string, error) {
+	return applyEditBlocks(content, blocks, true)
+}
+
+// applyEditBlocks applies blocks in order, with the fuzzy strategies only
+// if fuzzy is set.
+func applyEditBlocks(content string, blocks []EditBlock, fuzzy bool) (string, error) {
+	for i, blk := range blocks {
+		out, ok := applyEditBlock(content, blk, fuzzy)
+		if !ok {
+			return "", fmt.Errorf("difflib: edit block %d: search text not found", i+1)
+		}
+		content = out
+	}
+	return content, nil
+}
+
+// applyEditBlock applies one block.
+func applyEditBlock(content string, blk EditBlock, fuzzy bool) (string, bool) {
+	if blk.Search == "" {
+		if content != "" && !strings.HasSuffix(content, "\n") {
+			content += "\n"
+		}
+		return content + blk.Replace, true
+	}
+	if i := indexLineStart(content, blk.Search); i >= 0 {
+		return content[:i] + blk.Replace + content[i+len(blk.Search):], true
+	}
+	if !fuzzy {
+		return "", false
+	}
+	lines, search := SplitLines(content), SplitLines(blk.Search)
+	replace := SplitLines(blk.Replace)
+	n := len(search)
+	splice := func(at int, repl []string) string {
+		return strings.Join(lines[:at], "") + strings.Join(repl, "") + strings.Join(lines[at+n:], "")
+	}
+	for at := 0; at+n <= len(lines); at++ {
+		if linesEqualTrimmed(lines[at:at+n], search) {
+			return splice(at, replace), true
+		}
+	}
+	for at := 0; at+n <= len(lines); at++ {
+		if add, drop, ok := indentDelta(lines[at:at+n], search); ok {
+			return splice(at, reindent(replace, add, drop)), true
+		}
+	}
+	best, bestAt := 0.0, -1
+	text := strings.Join(search, "")
+	for at := 0; at+n <= len(lines); at++ {
+		if r := StringRatio(strings.Join(lines[at:at+n], ""), text); r > best {
+			best, bestAt = r, at
+		}
+	}
+	if bestAt >= 0 && best >= editBlockFuzzyCutoff {
+		return splice(bestAt, replace), true
+	}
+	return "", false
+}
+
+// linesEqualTrimmed reports whether a and b are equal ignoring trailing
+// whitespace and line endings.
+func linesEqualTrimmed(a, b []string) bool {
+	for i := range a {
+		if strings.TrimRight(a[i], " \t\r\n") != strings.TrimRight(b[i], " \t\r\n") {
+			return false
+		}
+	}
+	return true
+}
+
+// indentDelta reports whether the lines of have differ from want only by a
+// common change of indentation: every non-blank line of have is either add
+// followed by the line of want, or the line of want with drop removed from
+// its start. At most one of add and drop is non-empty.
+func indentDelta(have, want []string) (add, drop string, ok bool) {
+	set := false
+	for i := range have {
+		h, w := strings.TrimRight(have[i], " \t\r\n"), strings.TrimRight(want[i], " \t\r\n")
+		if h == "" && w == "" {
+			continue
+		}
+		var a, d string
+		switch {
+		case strings.HasSuffix(h, w) && strings.TrimLeft(h[:len(h)-len(w)], " \t") == "":
+			a = h[:len(h)-len(w)]
+		case strings.HasSuffix(w, h) && strings.TrimLeft(w[:len(w)-len(h)], " \t") == "":
+			d = w[:len(w)-len(h)]
+		default:
+			return "", "", false
+		}
+		if set && (a != add || d != drop) {
+			return "", "", false
+		}
+		add, drop, set = a, d, true
+	}
+	return add, drop, set
+}
+
+// reindent adds add to the start of each non-blank line, or removes drop
+// where present.
+func reindent(lines []string, add, drop string) []string {
+	out := make([]string, len(lines))
+	for i, l := range lines {
+		switch {
+		case strings.TrimSpace(l) == "":
+			out[i] = l
+		case add != "":
+			out[i] = add + l
+		default:
+			out[i] = strings.TrimPrefix(l, drop)
+		}
+	}
+	return out
+}
+
+// indexLineStart returns the index of the first occurrence of sub in s that
+// starts a line, else of the first occurrence anywhere, else -1.
+func indexLineStart(s, sub string) int {
+	first := strings.Index(s, sub)
+	for i := first; i >= 0; {
+		if i == 0 || s[i-1] == '\n' {
+			return i
+		}
+		j := strings.Index(s[i+1:], sub)
+		if j < 0 {
+			break
+		}
+		i += 1 + j
+	}
+	return first
+}
@@ -0,0 +1,148 @@
+package difflib_test
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+
+	difflib "github.com/njchilds90/go-difflib"
+)
+
+func TestParseEditBlocks(t *testing.T) {
+	reply := "I'll rename the greeting.\n\n" +
+		"main.go\n```go\n<<<<<<< SEARCH\n\tfmt.Println(\"hi\")\n=======\n\tfmt.Println(\"hello\")\n>>>>>>> REPLACE\n```\n\n" +
+		"<<<<<<< SEARCH\nfunc a() {}\n=======\n>>>>>>> REPLACE\n\n" +
+		"**docs/README.md**\n<<<<<<<<< SEARCH\n=========\n# Title\n>>>>>>>>> REPLACE\n"
+	got, err := difflib.ParseEditBlocks(reply)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []difflib.EditBlock{
+		{Path: "main.go", Search: "\tfmt.Println(\"hi\")\n", Replace: "\tfmt.Println(\"hello\")\n"},
+		{Path: "main.go", Search: "func a() {}\n", Replace: ""},
+		{Path: "docs/README.md", Search: "", Replace: "# Title\n"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseEditBlocks =\n%#v\nwant\n%#v", got, want)
+	}
+}
+
+func TestParseEditBlocksErrors(t *testing.T) {
+	tests := []struct {
+		text, want string
+	}{
+		{"f\n<<<<<<< SEARCH\na\n=======\nb\n", "difflib: edit block at line 2 is not terminated by >>>>>>> REPLACE"},
+		{"<<<<<<< SEARCH\na\n>>>>>>> REPLACE\n", "difflib: edit block at line 1 has no ======= divider"},
+		{"<<<<<<< SEARCH\na\n<<<<<<< SEARCH\n", "difflib: edit block at line 1 is not terminated by >>>>>>> REPLACE"},
+	}
+	for _, tt := range tests {
+		if _, err := difflib.ParseEditBlocks(tt.text); err == nil || err.Error() != tt.want {
+			t.Errorf("ParseEditBlocks(%q) error = %v, want %q", tt.text, err, tt.want)
+		}
+	}
+	if blocks, err := difflib.ParseEditBlocks("no blocks here\n"); err != nil || blocks != nil {
+		t.Errorf("text without blocks = %v, %v", blocks, err)
+	}
+}
+
+func TestApplyEditBlocks(t *testing.T) {
+	src := "func main() {\n\tx := 1\n\tfmt.Println(x)\n}\n"
+	tests := []struct {
+		name   string
+		blocks []difflib.EditBlock
+		want   string
+	}{
+		{"exact", []difflib.EditBlock{{Search: "\tx := 1\n", Replace: "\tx := 2\n"}}, "func main() {\n\tx := 2\n\tfmt.Println(x)\n}\n"},
+		{"sequential", []difflib.EditBlock{
+			{Search: "\tx := 1\n", Replace: "\ty := 1\n"},
+			{Search: "\tfmt.Println(x)\n", Replace: "\tfmt.Println(y)\n"},
+		}, "func main() {\n\ty := 1\n\tfmt.Println(y)\n}\n"},
+		{"trailing whitespace", []difflib.EditBlock{{Search: "\tx := 1  \r\n", Replace: "\tx := 3\n"}}, "func main() {\n\tx := 3\n\tfmt.Println(x)\n}\n"},
+		{"missing indentation", []difflib.EditBlock{{Search: "x := 1\nfmt.Println(x)\n", Replace: "x := 4\nif x > 0 {\n\tfmt.Println(x)\n}\n"}},
+			"func main() {\n\tx := 4\n\tif x > 0 {\n\t\tfmt.Println(x)\n\t}\n}\n"},
+		{"extra indentation", []difflib.EditBlock{{Search: "\t\tx := 1\n\t\tfmt.Println(x)\n", Replace: "\t\tfmt.Println(1)\n"}},
+			"func main() {\n\tfmt.Println(1)\n}\n"},
+		{"similar", []difflib.EditBlock{{Search: "\tx := 1 // one\n\tfmt.Println(x)\n", Replace: "\tfmt.Println(1)\n"}},
+			"func main() {\n\tfmt.Println(1)\n}\n"},
+		{"append", []difflib.EditBlock{{Search: "", Replace: "// end\n"}}, src + "// end\n"},
+		{"line start preferred", []difflib.EditBlock{{Search: "x)\n", Replace: "z)\n"}}, "func main() {\n\tx := 1\n\tfmt.Println(z)\n}\n"},
+	}
+	for _, tt := range tests {
+		got, err := difflib.ApplyEditBlocks(src, tt.blocks)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: ApplyEditBlocks =\n%q\nwant\n%q", tt.name, got, tt.want)
+		}
+	}
+
+	blocks := []difflib.EditBlock{
+		{Search: "\tx := 1\n", Replace: "\tx := 2\n"},
+		{Search: "completely different\ntext here\n", Replace: ""},
+	}
+	if _, err := difflib.ApplyEditBlocks(src, blocks); err == nil || err.Error() != "difflib: edit block 2: search text not found" {
+		t.Errorf("unmatched block: error = %v", err)
+	}
+}
+
+func TestGenerateEditBlocks(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want []difflib.EditBlock
+	}{
+		{"equal", "a\nb\n", "a\nb\n", nil},
+		{"new file", "", "x\n", []difflib.EditBlock{{Path: "f", Replace: "x\n"}}},
+		{"one change", "a\nb\nc\nd\n", "a\nB\nc\nd\n", []difflib.EditBlock{{Path: "f", Search: "a\nb\nc\n", Replace: "a\nB\nc\n"}}},
+		{"repeated text needs more context", "x\ny\nx\nz\nx\nw\n", "x\ny\nx\nZ\nx\nw\n",
+			[]difflib.EditBlock{{Path: "f", Search: "x\nz\nx\n", Replace: "x\nZ\nx\n"}}},
+		{"delete all", "a\n", "", []difflib.EditBlock{{Path: "f", Search: "a\n", Replace: ""}}},
+	}
+	for _, tt := range tests {
+		got := difflib.GenerateEditBlocks("f", difflib.SplitLines(tt.a), difflib.SplitLines(tt.b))
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: GenerateEditBlocks =\n%#v\nwant\n%#v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestEditBlocksRoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewSource(7))
+	for iter := 0; iter < 200; iter++ {
+		a := randomLines(rng, rng.Intn(30))
+		b := randomLines(rng, rng.Intn(30))
+		blocks := difflib.GenerateEditBlocks("file.txt", a, b)
+		parsed, err := difflib.ParseEditBlocks(difflib.FormatEditBlocks(blocks))
+		if err != nil {
+			t.Fatalf("iteration %d: %v", iter, err)
+		}
+		got, err := difflib.ApplyEditBlocks(strings.Join(a, ""), parsed)
+		if err != nil {
+			t.Fatalf("iteration %d: %v", iter, err)
+		}
+		if want := strings.Join(b, ""); got != want {
+			t.Fatalf("iteration %d: round trip =\n%q\nwant\n%q", iter, got, want)
+		}
+	}
+}
+
+func ExampleGenerateEditBlocks() {
+	a := difflib.SplitLines("package main\n\nfunc main() {\n\tprintln(\"hi\")\n}\n")
+	b := difflib.SplitLines("package main\n\nfunc main() {\n\tprintln(\"hello\")\n}\n")
+	fmt.Print(difflib.FormatEditBlocks(difflib.GenerateEditBlocks("main.go", a, b)))
+	// Output:
+	// main.go
+	// <<<<<<< SEARCH
+	// func main() {
+	// 	println("hi")
+	// }
+	// =======
+	// func main() {
+	// 	println("hello")
+	// }
+	// >>>>>>> REPLACE
+}
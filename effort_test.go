@@ -0,0 +1,62 @@
+package difflib_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	difflib "github.com/njchilds90/go-difflib"
+)
+
+func TestMaxComparisons(t *testing.T) {
+	// Thousands of identical lines make every row match every column.
+	a := difflib.SplitLines(strings.Repeat("x\n", 5000) + "a\n" + strings.Repeat("x\n", 5000))
+	b := difflib.SplitLines(strings.Repeat("x\n", 4000) + "b\n" + strings.Repeat("x\n", 6000))
+	input := difflib.DiffInput{A: a, B: b, FromFile: "a", ToFile: "b", MaxComparisons: 1_000_000}
+	start := time.Now()
+	result := difflib.UnifiedDiff(input)
+	if d := time.Since(start); d > 2*time.Second {
+		t.Errorf("bounded diff took %v", d)
+	}
+	applied, err := difflib.ApplyPatch(a, result.String())
+	if err != nil || !reflect.DeepEqual(applied, b) {
+		t.Fatalf("degraded diff does not apply: %v", err)
+	}
+	if len(result.Hunks) != 1 {
+		t.Errorf("degraded diff has %d hunks, want 1", len(result.Hunks))
+	}
+}
+
+func TestMaxComparisonsNotReached(t *testing.T) {
+	a := difflib.SplitLines("one\ntwo\nthree\nfour\nfive\n")
+	b := difflib.SplitLines("one\nTWO\nthree\nfour\nFIVE\n")
+	want := difflib.UnifiedDiff(difflib.DiffInput{A: a, B: b, Context: 1})
+	got := difflib.UnifiedDiff(difflib.DiffInput{A: a, B: b, Context: 1, MaxComparisons: 1000})
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("UnifiedDiff() with a generous bound = %v, want %v", got, want)
+	}
+}
+
+func TestMatcherSetMaxComparisons(t *testing.T) {
+	a := difflib.SplitLines("p\nq\nr\ns\nt\nu\n")
+	b := difflib.SplitLines("p\nr\nq\nt\ns\nu\n")
+	m := difflib.NewMatcher(a, b)
+	full := m.GetOpCodes()
+	m.SetMaxComparisons(1)
+	want := []difflib.OpCode{
+		{Tag: difflib.OpEqual, I1: 0, I2: 1, J1: 0, J2: 1},
+		{Tag: difflib.OpReplace, I1: 1, I2: 5, J1: 1, J2: 5},
+		{Tag: difflib.OpEqual, I1: 5, I2: 6, J1: 5, J2: 6},
+	}
+	if got := m.GetOpCodes(); !reflect.DeepEqual(got, want) {
+		t.Errorf("bounded GetOpCodes() = %v, want %v", got, want)
+	}
+	if got := m.Ratio(); got != 2.0/6 {
+		t.Errorf("bounded Ratio() = %v, want %v", got, 2.0/6)
+	}
+	m.SetMaxComparisons(0)
+	if got := m.GetOpCodes(); !reflect.DeepEqual(got, full) {
+		t.Errorf("unbounded GetOpCodes() = %v, want %v", got, full)
+	}
+}
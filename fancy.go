@@ -0,0 +1,155 @@
+package difflib
+
+import (
+	"strings"
+	"unicode"
+)
+
+// similarLineCutoff is the ratio two lines of a replaced block need to be
+// paired up, as in Python's Differ.
+const similarLineCutoff = 0.75
+
+// replaceRun is a piece of a replaced block as pairSimilarLines splits it:
+// either one pair of similar lines a[I1] and b[J1] (paired is true, and Tag
+// is OpEqual if the lines are identical), or the lines a[I1:I2] and
+// b[J1:J2], among which no two are similar enough to pair.
+type replaceRun struct {
+	OpCode
+	paired bool
+}
+
+// pairSimilarLines splits the replaced block op of a and b into runs, in
+// order, the way Python's Differ._fancy_replace does: the most similar pair
+// of lines is matched first, then the lines before and after it are paired
+// in turn. Blocks of more than limit line pairs are not searched when limit
+// is positive.
+func pairSimilarLines(a, b []string, op OpCode, limit int) []replaceRun {
+	var runs []replaceRun
+	if limit > 0 && (op.I2-op.I1)*(op.J2-op.J1) > limit {
+		return append(runs, replaceRun{OpCode: op})
+	}
+	chars := func(lines []string) [][]string {
+		out := make([][]string, len(lines))
+		for k, l := range lines {
+			out[k] = splitChars(l)
+		}
+		return out
+	}
+	ac, bc := chars(a[op.I1:op.I2]), chars(b[op.J1:op.J2])
+	var pair func(alo, ahi, blo, bhi int)
+	split := func(alo, ahi, blo, bhi int) {
+		switch {
+		case alo < ahi && blo < bhi:
+			pair(alo, ahi, blo, bhi)
+		case alo < ahi:
+			runs = append(runs, replaceRun{OpCode: OpCode{OpDelete, alo, ahi, blo, blo}})
+		case blo < bhi:
+			runs = append(runs, replaceRun{OpCode: OpCode{OpInsert, alo, alo, blo, bhi}})
+		}
+	}
+	pair = func(alo, ahi, blo, bhi int) {
+		best, bestI, bestJ := similarLineCutoff-0.01, -1, -1
+		eqI, eqJ := -1, -1
+		m := NewMatcher(nil, nil)
+		for j := blo; j < bhi; j++ {
+			m.SetSeq2(bc[j-op.J1])
+			for i := alo; i < ahi; i++ {
+				if a[i] == b[j] {
+					if eqI < 0 {
+						eqI, eqJ = i, j
+					}
+					continue
+				}
+				m.SetSeq1(ac[i-op.I1])
+				if m.RealQuickRatio() > best && m.QuickRatio() > best {
+					if r := m.Ratio(); r > best {
+						best, bestI, bestJ = r, i, j
+					}
+				}
+			}
+		}
+		tag := OpReplace
+		if best < similarLineCutoff {
+			if eqI < 0 {
+				runs = append(runs, replaceRun{OpCode: OpCode{OpReplace, alo, ahi, blo, bhi}})
+				return
+			}
+			// No similar pair, but an identical one to synchronise on.
+			bestI, bestJ, tag = eqI, eqJ, OpEqual
+		}
+		split(alo, bestI, blo, bestJ)
+		runs = append(runs, replaceRun{OpCode{tag, bestI, bestI + 1, bestJ, bestJ + 1}, true})
+		split(bestI+1, ahi, bestJ+1, bhi)
+	}
+	split(op.I1, op.I2, op.J1, op.J2)
+	return runs
+}
+
+// emitNDiffReplace passes the ndiff lines of the replaced block op to emit:
+// similar lines are shown together with "? " lines marking their changed
+// characters, and unpaired lines are dumped shorter side first.
+func emitNDiffReplace(a, b []string, op OpCode, limit int, emit func(string)) {
+	dump := func(prefix string, lines []string) {
+		for _, l := range lines {
+			emit(prefix + l)
+		}
+	}
+	for _, run := range pairSimilarLines(a, b, op, limit) {
+		switch {
+		case run.paired && run.Tag == OpEqual:
+			emit("  " + a[run.I1])
+		case run.paired:
+			x, y := a[run.I1], b[run.J1]
+			xtags, ytags := ndiffHints(x, y)
+			emit("- " + x)
+			if xtags != "" {
+				emit("? " + xtags + "\n")
+			}
+			emit("+ " + y)
+			if ytags != "" {
+				emit("? " + ytags + "\n")
+			}
+		case run.J2-run.J1 < run.I2-run.I1:
+			dump("+ ", b[run.J1:run.J2])
+			dump("- ", a[run.I1:run.I2])
+		default:
+			dump("- ", a[run.I1:run.I2])
+			dump("+ ", b[run.J1:run.J2])
+		}
+	}
+}
+
+// ndiffHints returns the "? " guide lines for a pair of similar lines:
+// '^' under replaced characters, '-' under deleted ones and '+' under
+// inserted ones, keeping whitespace such as tabs under unchanged characters
+// so the marks line up.
+func ndiffHints(x, y string) (xtags, ytags string) {
+	xs, ys := splitChars(x), splitChars(y)
+	xt, yt := make([]string, len(xs)), make([]string, len(ys))
+	fill := func(tags, chars []string, lo, hi int, mark string) {
+		for k := lo; k < hi; k++ {
+			tags[k] = mark
+			if mark == " " && strings.TrimSpace(chars[k]) == "" {
+				tags[k] = chars[k]
+			}
+		}
+	}
+	for _, op := range NewMatcher(xs, ys).GetOpCodes() {
+		switch op.Tag {
+		case OpEqual:
+			fill(xt, xs, op.I1, op.I2, " ")
+			fill(yt, ys, op.J1, op.J2, " ")
+		case OpReplace:
+			fill(xt, xs, op.I1, op.I2, "^")
+			fill(yt, ys, op.J1, op.J2, "^")
+		case OpDelete:
+			fill(xt, xs, op.I1, op.I2, "-")
+		case OpInsert:
+			fill(yt, ys, op.J1, op.J2, "+")
+		}
+	}
+	trim := func(tags []string) string {
+		return strings.TrimRightFunc(strings.Join(tags, ""), unicode.IsSpace)
+	}
+	return trim(xt), trim(yt)
+}
@@ -0,0 +1,182 @@
+package difflib
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"hash/maphash"
+	"io"
+	"os"
+)
+
+// ErrBinary is returned, wrapped, by DiffFiles for files that differ and
+// have binary content.
+var ErrBinary = errors.New("difflib: binary files differ")
+
+// DiffFilesOptions controls DiffFiles.
+type DiffFilesOptions struct {
+	// FromFile and ToFile label the diff. They default to the paths, or to
+	// "/dev/null" for an empty path.
+	FromFile, ToFile string
+	// FromDate and ToDate, if set, follow the labels after a tab. See
+	// DiffInput.FromDate.
+	FromDate, ToDate string
+	// Context is the number of unchanged lines to include around each change.
+	// Defaults to 3 if zero; use NoContext for none.
+	Context int
+}
+
+// DiffFiles returns the unified diff of the files at pathA and pathB. An
+// empty path stands for an empty file, as for an added or deleted file.
+// Like UnifiedDiffReaders, whose output its String matches, it reads the
+// files in chunks and keeps only a hash and an offset per line while
+// diffing, then reads back just the lines that appear in hunks, so memory
+// use depends on the number of lines and the size of the diff rather than
+// on the size of the files. Files with a NUL byte in their first 8000
+// bytes are treated as binary: if they differ, the error wraps ErrBinary.
+//
+// Example:
+//
+//	d, err := difflib.DiffFiles("old/config.yaml", "new/config.yaml", difflib.DiffFilesOptions{})
+//	if errors.Is(err, difflib.ErrBinary) {
+//	    fmt.Println("Binary files differ")
+//	}
+func DiffFiles(pathA, pathB string, opts DiffFilesOptions) (DiffResult, error) {
+	d := DiffResult{
+		FromFile: diffFileLabel(opts.FromFile, pathA),
+		ToFile:   diffFileLabel(opts.ToFile, pathB),
+		FromDate: opts.FromDate,
+		ToDate:   opts.ToDate,
+	}
+	a, err := openDiffFile(pathA)
+	if err != nil {
+		return DiffResult{}, err
+	}
+	defer a.Close()
+	b, err := openDiffFile(pathB)
+	if err != nil {
+		return DiffResult{}, err
+	}
+	defer b.Close()
+
+	binA, err := sniffBinary(a, pathA)
+	if err != nil {
+		return DiffResult{}, err
+	}
+	binB, err := sniffBinary(b, pathB)
+	if err != nil {
+		return DiffResult{}, err
+	}
+	if binA || binB {
+		same, err := sameContent(a, b)
+		if err != nil {
+			return DiffResult{}, err
+		}
+		if !same {
+			return DiffResult{}, fmt.Errorf("%w: %s and %s", ErrBinary, d.FromFile, d.ToFile)
+		}
+		return d, nil
+	}
+
+	seed := maphash.MakeSeed()
+	sa, err := newLineSource(a, seed, "")
+	if err != nil {
+		return DiffResult{}, err
+	}
+	sb, err := newLineSource(b, seed, "")
+	if err != nil {
+		return DiffResult{}, err
+	}
+	lines := func(s *lineSource, prefix string, i, j int, h *Hunk) error {
+		for ; i < j; i++ {
+			buf, err := s.readLine(i)
+			if err != nil {
+				return err
+			}
+			h.Lines = append(h.Lines, prefix+string(buf))
+		}
+		return nil
+	}
+	for _, group := range groupOpcodes(streamOpCodes(sa.hashes, sb.hashes), contextLines(opts.Context)) {
+		h := groupHunkHeader(group)
+		for _, op := range group {
+			if op.Tag == OpEqual {
+				err = lines(sa, " ", op.I1, op.I2, &h)
+			} else if err = lines(sa, "-", op.I1, op.I2, &h); err == nil {
+				err = lines(sb, "+", op.J1, op.J2, &h)
+			}
+			if err != nil {
+				return DiffResult{}, err
+			}
+		}
+		d.Hunks = append(d.Hunks, h)
+	}
+	return d, nil
+}
+
+// diffFile is an input of DiffFiles: an open file or, for an empty path,
+// empty content.
+type diffFile interface {
+	io.Reader
+	io.ReaderAt
+	io.Seeker
+	io.Closer
+}
+
+// emptyFile is the diffFile of an empty path.
+type emptyFile struct{ *bytes.Reader }
+
+func (emptyFile) Close() error { return nil }
+
+func openDiffFile(path string) (diffFile, error) {
+	if path == "" {
+		return emptyFile{bytes.NewReader(nil)}, nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("difflib: %w", err)
+	}
+	return f, nil
+}
+
+// diffFileLabel returns label, or the label for path if label is empty.
+func diffFileLabel(label, path string) string {
+	switch {
+	case label != "":
+		return label
+	case path == "":
+		return devNull
+	}
+	return path
+}
+
+// sniffBinary reports whether f looks binary, as isBinary judges it.
+func sniffBinary(f io.ReaderAt, path string) (bool, error) {
+	buf := make([]byte, 8000)
+	n, err := f.ReadAt(buf, 0)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return false, fmt.Errorf("difflib: reading %s: %w", path, err)
+	}
+	return isBinary(buf[:n]), nil
+}
+
+// sameContent reports whether a and b hold the same bytes, reading them in
+// chunks from the start.
+func sameContent(a, b io.ReaderAt) (bool, error) {
+	bufA, bufB := make([]byte, 64*1024), make([]byte, 64*1024)
+	for off := int64(0); ; off += int64(len(bufA)) {
+		na, errA := a.ReadAt(bufA, off)
+		nb, errB := b.ReadAt(bufB, off)
+		for _, err := range []error{errA, errB} {
+			if err != nil && !errors.Is(err, io.EOF) {
+				return false, fmt.Errorf("difflib: reading input: %w", err)
+			}
+		}
+		if !bytes.Equal(bufA[:na], bufB[:nb]) {
+			return false, nil
+		}
+		if na < len(bufA) {
+			return true, nil
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package difflib_test
+
+import (
+	"bytes"
+	"errors"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	difflib "github.com/njchilds90/go-difflib"
+)
+
+func TestDiffFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"old.txt":  "one\ntwo\nthree\n",
+		"new.txt":  "one\n2\nthree\nfour",
+		"same.bin": "\x00\x01",
+		"copy.bin": "\x00\x01",
+		"diff.bin": "\x00\x02",
+	})
+	path := func(name string) string { return filepath.Join(dir, name) }
+
+	d, err := difflib.DiffFiles(path("old.txt"), path("new.txt"), difflib.DiffFilesOptions{FromFile: "a/x", ToFile: "b/x"})
+	want := "--- a/x\n+++ b/x\n@@ -1,3 +1,4 @@\n one\n-two\n+2\n three\n+four\n\\ No newline at end of file\n"
+	if err != nil || d.String() != want {
+		t.Errorf("DiffFiles = %q, %v; want %q", d.String(), err, want)
+	}
+	d, err = difflib.DiffFiles("", path("old.txt"), difflib.DiffFilesOptions{Context: difflib.NoContext})
+	if want := "--- /dev/null\n+++ " + path("old.txt") + "\n@@ -0,0 +1,3 @@\n+one\n+two\n+three\n"; err != nil || d.String() != want {
+		t.Errorf("DiffFiles of a new file = %q, %v; want %q", d.String(), err, want)
+	}
+	if d, err := difflib.DiffFiles(path("same.bin"), path("copy.bin"), difflib.DiffFilesOptions{}); err != nil || len(d.Hunks) != 0 {
+		t.Errorf("DiffFiles of identical binaries = %v, %v", d, err)
+	}
+	if _, err := difflib.DiffFiles(path("same.bin"), path("diff.bin"), difflib.DiffFilesOptions{}); !errors.Is(err, difflib.ErrBinary) {
+		t.Errorf("DiffFiles of different binaries: %v, want ErrBinary", err)
+	}
+	if _, err := difflib.DiffFiles(path("missing"), path("old.txt"), difflib.DiffFilesOptions{}); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("DiffFiles of a missing file: %v", err)
+	}
+}
+
+func TestDiffFilesMatchesReaders(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	dir := t.TempDir()
+	pa, pb := filepath.Join(dir, "a"), filepath.Join(dir, "b")
+	for iter := 0; iter < 50; iter++ {
+		a, b := randomLines(rng, rng.Intn(50)), randomLines(rng, rng.Intn(50))
+		writeFiles(t, dir, map[string]string{"a": difflib.JoinLines(a), "b": difflib.JoinLines(b)})
+		d, err := difflib.DiffFiles(pa, pb, difflib.DiffFilesOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var want bytes.Buffer
+		_, err = difflib.UnifiedDiffReaders(&want, strings.NewReader(difflib.JoinLines(a)), strings.NewReader(difflib.JoinLines(b)),
+			difflib.StreamDiffOptions{FromFile: pa, ToFile: pb})
+		if err != nil || d.String() != want.String() {
+			t.Fatalf("iteration %d: DiffFiles =\n%s\nwant\n%s", iter, d, want.String())
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package difflib
+
+import (
+	"strings"
+	"unicode"
+)
+
+// RatioOptions makes similarity scoring insensitive to case or accents.
+// Scores are computed on the folded strings, but functions taking these
+// options still return the original candidates.
+type RatioOptions struct {
+	// IgnoreCase compares strings under Unicode simple case folding, so
+	// "ΣΊΣΥΦΟΣ" matches "σίσυφος", final sigma included.
+	IgnoreCase bool
+	// IgnoreAccents strips diacritics from Latin letters and drops
+	// combining marks, so "Café" matches "Cafe".
+	IgnoreAccents bool
+}
+
+// StringRatioWithOptions is StringRatio with case and accent folding.
+//
+// Example:
+//
+//	r := difflib.StringRatioWithOptions("Café", "cafe",
+//	    difflib.RatioOptions{IgnoreCase: true, IgnoreAccents: true})
+//	// r == 1.0
+func StringRatioWithOptions(a, b string, opts RatioOptions) float64 {
+	return StringRatio(opts.fold(a), opts.fold(b))
+}
+
+// ClosestMatchWithOptions is ClosestMatch with case and accent folding. The
+// returned match is the candidate as given, not its folded form.
+//
+// Example:
+//
+//	best, ratio := difflib.ClosestMatchWithOptions("munchen", []string{"München", "Berlin"},
+//	    difflib.RatioOptions{IgnoreCase: true, IgnoreAccents: true})
+//	// best == "München", ratio == 1.0
+func ClosestMatchWithOptions(target string, candidates []string, opts RatioOptions) (string, float64) {
+	target = opts.fold(target)
+	best, ratio, ok := ClosestMatchFunc(target, candidates, opts.fold)
+	if !ok {
+		return "", 0
+	}
+	return best, ratio
+}
+
+// fold applies the options to s.
+func (o RatioOptions) fold(s string) string {
+	if !o.IgnoreCase && !o.IgnoreAccents {
+		return s
+	}
+	var b strings.Builder
+	for _, r := range s {
+		if o.IgnoreAccents {
+			if unicode.Is(unicode.Mn, r) {
+				continue
+			}
+			r = stripAccent(r)
+		}
+		if o.IgnoreCase {
+			r = foldCase(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// foldCase maps r to the smallest rune in its simple case folding orbit.
+func foldCase(r rune) rune {
+	m := r
+	for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+		m = min(m, f)
+	}
+	return m
+}
+
+// Base letters of U+00C0-U+024F and U+1E00-U+1EFF without their
+// diacritics, from their canonical decompositions plus a few letters with
+// strokes; '-' marks letters left as they are.
+const (
+	latin1Base = "AAAAAA-CEEEEIIIIDNOOOOO-OUUUUY--aaaaaa-ceeeeiiii-nooooo-ouuuuy-y" +
+		"AaAaAaCcCcCcCcDdDdEeEeEeEeEeGgGgGgGgHhHhIiIiIiIiIi--JjKk-LlLlLl-" +
+		"-LlNnNnNn---OoOoOo--RrRrRrSsSsSsSsTtTtTtUuUuUuUuUuUuWwYyYZzZzZz-" +
+		"b----------------------I--------Oo-------------Uu----Zz---------" +
+		"-------------AaIiOoUuUuUuUuUu-AaAa----GgKkOoOo--j---Gg--NnAa----" +
+		"AaAaEeEeIiIiOoOoRrRrUuUuSsTt--Hh------AaEeOoOoOoOoYy------------" +
+		"----------------"
+	latinAdditionalBase = "AaBbBbBbCcDdDdDdDdDdEeEeEeEeEeFfGgHhHhHhHhHhIiIiKkKkKkLlLlLlLlMm" +
+		"MmMmNnNnNnNnOoOoOoOoPpPpRrRrRrRrSsSsSsSsSsTtTtTtTtUuUuUuUuUuVvVv" +
+		"WwWwWwWwWwXxXxYyZzZzZzhtwy------AaAaAaAaAaAaAaAaAaAaAaAaEeEeEeEe" +
+		"EeEeEeEeIiIiOoOoOoOoOoOoOoOoOoOoOoOoUuUuUuUuUuUuUuYyYyYyYy------"
+)
+
+// stripAccent returns the base letter of an accented Latin letter, or r.
+func stripAccent(r rune) rune {
+	var c byte = '-'
+	switch {
+	case r >= 0xC0 && r < 0xC0+rune(len(latin1Base)):
+		c = latin1Base[r-0xC0]
+	case r >= 0x1E00 && r < 0x1E00+rune(len(latinAdditionalBase)):
+		c = latinAdditionalBase[r-0x1E00]
+	}
+	if c == '-' {
+		return r
+	}
+	return rune(c)
+}
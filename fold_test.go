@@ -0,0 +1,49 @@
+package difflib_test
+
+import (
+	"testing"
+
+	difflib "github.com/njchilds90/go-difflib"
+)
+
+func TestStringRatioWithOptions(t *testing.T) {
+	both := difflib.RatioOptions{IgnoreCase: true, IgnoreAccents: true}
+	tests := []struct {
+		a, b string
+		opts difflib.RatioOptions
+		want float64
+	}{
+		{"Café", "cafe", both, 1},
+		{"Café", "cafe", difflib.RatioOptions{IgnoreCase: true}, 0.75},
+		{"Café", "Cafe", difflib.RatioOptions{IgnoreAccents: true}, 1},
+		{"Café", "cafe", difflib.RatioOptions{IgnoreAccents: true}, 0.75},
+		{"ΣΊΣΥΦΟΣ", "σίσυφος", difflib.RatioOptions{IgnoreCase: true}, 1},
+		{"Ångström", "angstrom", both, 1},
+		{"Łódź", "lodz", both, 1},
+		{"Nguyễn", "nguyen", both, 1},
+		// Decomposed input: "e" followed by a combining acute accent.
+		{"e\u0301te\u0301", "ete", both, 1},
+		{"abc", "abd", both, difflib.StringRatio("abc", "abd")},
+	}
+	for _, tt := range tests {
+		if got := difflib.StringRatioWithOptions(tt.a, tt.b, tt.opts); got != tt.want {
+			t.Errorf("StringRatioWithOptions(%q, %q, %+v) = %v, want %v", tt.a, tt.b, tt.opts, got, tt.want)
+		}
+	}
+}
+
+func TestClosestMatchWithOptions(t *testing.T) {
+	cities := []string{"Berlin", "München", "Köln", "Zürich"}
+	opts := difflib.RatioOptions{IgnoreCase: true, IgnoreAccents: true}
+	best, ratio := difflib.ClosestMatchWithOptions("MUNCHEN", cities, opts)
+	if best != "München" || ratio != 1 {
+		t.Errorf("ClosestMatchWithOptions() = %q, %v; want %q, 1", best, ratio, "München")
+	}
+	best, ratio = difflib.ClosestMatchWithOptions("zurch", cities, opts)
+	if best != "Zürich" || ratio <= 0.8 {
+		t.Errorf("ClosestMatchWithOptions() = %q, %v; want %q", best, ratio, "Zürich")
+	}
+	if best, ratio := difflib.ClosestMatchWithOptions("x", nil, opts); best != "" || ratio != 0 {
+		t.Errorf("ClosestMatchWithOptions(nil) = %q, %v", best, ratio)
+	}
+}
@@ -0,0 +1,86 @@
+package difflib
+
+import (
+	"path"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxSectionLen is the longest hunk header section kept, in bytes, as in git.
+const maxSectionLen = 80
+
+// defaultFuncPattern matches lines starting with a letter, "_" or "$", which
+// is git's default notion of a function heading.
+var defaultFuncPattern = regexp.MustCompile(`^[[:alpha:]$_].*`)
+
+// funcPatterns holds the built-in function heading patterns by file
+// extension, modeled on git's userdiff drivers.
+var funcPatterns = map[string]*regexp.Regexp{}
+
+func init() {
+	for exts, pattern := range map[string]string{
+		".go":                            `^[ \t]*(func\b.*|type\b.*\b(struct|interface)\b.*)$`,
+		".py .pyi":                       `^[ \t]*((class|(async[ \t]+)?def)[ \t].*)$`,
+		".js .jsx .mjs .cjs .ts .tsx":    `^[ \t]*((export[ \t]+)?(default[ \t]+)?(async[ \t]+)?(function\b|class\b).*)$`,
+		".java .cs .kt .scala":           `^[ \t]*(((public|protected|private|internal|static|abstract|final|sealed|override|class|interface|enum|record|fun|object)[ \t]).*)$`,
+		".rs":                            `^[ \t]*((pub(\([^)]*\))?[ \t]+)?((async|const|unsafe|extern)[ \t]+)*(fn|struct|enum|union|impl|trait|mod|macro_rules!)\b.*)$`,
+		".rb":                            `^[ \t]*((class|module|def)[ \t].*)$`,
+		".php":                           `^[ \t]*(((public|protected|private|static|abstract|final)[ \t]+)*(function|class|interface|trait)\b.*)$`,
+		".sh .bash .zsh":                 `^[ \t]*((function[ \t]+)?[A-Za-z_][A-Za-z0-9_]*[ \t]*\(\).*|function[ \t]+.*)$`,
+		".md .markdown":                  `^(#{1,6}[ \t].*)$`,
+		".ini .toml .cfg .conf .desktop": `^[ \t]*(\[.*\])`,
+	} {
+		re := regexp.MustCompile(pattern)
+		for _, ext := range strings.Fields(exts) {
+			funcPatterns[ext] = re
+		}
+	}
+}
+
+// FuncPatternFor returns the function heading pattern used for the hunk
+// header sections of the named file, chosen by its extension: Go, Python,
+// JavaScript and TypeScript, Java, C#, Kotlin, Rust, Ruby, PHP, shell,
+// Markdown and INI-style files have patterns modeled on git's built-in diff
+// drivers. Other files, including C and C++, get git's default pattern,
+// which matches any line starting with a letter, "_" or "$".
+//
+// Example:
+//
+//	d := difflib.UnifiedDiff(difflib.DiffInput{
+//	    A: a, B: b, FromFile: "a/main.go", ToFile: "b/main.go",
+//	    FuncPattern: difflib.FuncPatternFor("main.go"),
+//	})
+func FuncPatternFor(name string) *regexp.Regexp {
+	if re, ok := funcPatterns[strings.ToLower(path.Ext(name))]; ok {
+		return re
+	}
+	return defaultFuncPattern
+}
+
+// hunkSection returns the last line of a before index i that matches re, or
+// its first submatch if re has groups, with trailing whitespace removed and
+// cut to maxSectionLen bytes. It returns "" if no line matches.
+func hunkSection(a []string, i int, re *regexp.Regexp) string {
+	for i = min(i, len(a)) - 1; i >= 0; i-- {
+		line := strings.TrimRight(a[i], "\r\n")
+		m := re.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		s := m[0]
+		if len(m) > 1 {
+			s = m[1]
+		}
+		s = strings.TrimRight(s, " \t")
+		if len(s) > maxSectionLen {
+			n := maxSectionLen
+			for n > 0 && !utf8.RuneStart(s[n]) {
+				n--
+			}
+			s = s[:n]
+		}
+		return s
+	}
+	return ""
+}
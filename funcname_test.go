@@ -0,0 +1,158 @@
+package difflib_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	difflib "github.com/njchilds90/go-difflib"
+)
+
+func TestFuncPatternFor(t *testing.T) {
+	tests := []struct {
+		file, line string
+		want       bool
+	}{
+		{"main.go", "func (s *Server) Run() error {", true},
+		{"main.go", "type Server struct {", true},
+		{"main.go", "\treturn nil", false},
+		{"main.go", "package main", false},
+		{"app.py", "    async def fetch(self):", true},
+		{"app.py", "class Foo(Base):", true},
+		{"app.py", "    return x", false},
+		{"index.ts", "export default async function main() {", true},
+		{"lib.rs", "pub(crate) fn parse(s: &str) -> u32 {", true},
+		{"lib.rs", "impl Display for X {", true},
+		{"README.md", "## Usage", true},
+		{"README.md", "Some text.", false},
+		{"setup.cfg", "[metadata]", true},
+		{"main.c", "int main(int argc, char **argv)", true},
+		{"main.c", "\tprintf(\"hi\");", false},
+		{"Main.JAVA", "    public static void main(String[] args) {", true},
+	}
+	for _, tt := range tests {
+		if got := difflib.FuncPatternFor(tt.file).MatchString(tt.line); got != tt.want {
+			t.Errorf("FuncPatternFor(%q).MatchString(%q) = %v, want %v", tt.file, tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestUnifiedDiffFuncPattern(t *testing.T) {
+	a := difflib.SplitLines("package main\n\nfunc a() {\n\tx := 1\n\ty := 2\n\tz := 3\n\treturn\n}\n\nfunc b() {}\n")
+	b := difflib.SplitLines("package main\n\nfunc a() {\n\tx := 1\n\ty := 2\n\tz := 3\n\treturn x\n}\n\nfunc b() {}\n")
+	tests := []struct {
+		name    string
+		pattern *regexp.Regexp
+		want    string
+	}{
+		{"none", nil, "@@ -4,7 +4,7 @@\n"},
+		{"go", difflib.FuncPatternFor("x.go"), "@@ -4,7 +4,7 @@ func a() {\n"},
+		{"group", regexp.MustCompile(`^func (\w+)`), "@@ -4,7 +4,7 @@ a\n"},
+		{"no match", regexp.MustCompile(`^class `), "@@ -4,7 +4,7 @@\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := difflib.UnifiedDiff(difflib.DiffInput{A: a, B: b, FuncPattern: tt.pattern})
+			if len(d.Hunks) != 1 {
+				t.Fatalf("got %d hunks, want 1", len(d.Hunks))
+			}
+			if s := d.String(); !strings.Contains(s, tt.want) {
+				t.Errorf("diff =\n%s\nwant header %q", s, tt.want)
+			}
+			// The section survives a round trip through the parser.
+			ps, err := difflib.ParsePatchSet(d.String())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := ps.Files[0].Hunks[0].Section; got != d.Hunks[0].Section {
+				t.Errorf("parsed Section = %q, want %q", got, d.Hunks[0].Section)
+			}
+		})
+	}
+}
+
+func TestUnifiedDiffFuncPatternTruncates(t *testing.T) {
+	long := "func " + strings.Repeat("é", 60) + "() {\n"
+	a := []string{long, "1\n", "2\n", "3\n", "4\n"}
+	b := []string{long, "1\n", "2\n", "3\n", "5\n"}
+	d := difflib.UnifiedDiff(difflib.DiffInput{A: a, B: b, FuncPattern: regexp.MustCompile(`^func`)})
+	if s := d.Hunks[0].Section; s != "func" {
+		t.Errorf("Section = %q, want %q", s, "func")
+	}
+	d = difflib.UnifiedDiff(difflib.DiffInput{A: a, B: b, FuncPattern: regexp.MustCompile(`^func.*`)})
+	if s := d.Hunks[0].Section; len(s) != 79 || !strings.HasPrefix(long, s) {
+		t.Errorf("Section = %q (%d bytes), want the first 79 bytes of the line", s, len(s))
+	}
+}
+
+func TestDiffTreesFuncContext(t *testing.T) {
+	dir := t.TempDir()
+	before := "# Title\n\n## Install\n\none\ntwo\nthree\nfour\n"
+	after := "# Title\n\n## Install\n\none\ntwo\nthree\nFOUR\n"
+	writeFiles(t, filepath.Join(dir, "a"), map[string]string{"doc.md": before, "doc.txt": before})
+	writeFiles(t, filepath.Join(dir, "b"), map[string]string{"doc.md": after, "doc.txt": after})
+	ps, err := difflib.DiffTrees(os.DirFS(filepath.Join(dir, "a")), os.DirFS(filepath.Join(dir, "b")), difflib.TreeDiffOptions{
+		FuncContext:  true,
+		FuncPatterns: map[string]*regexp.Regexp{".txt": regexp.MustCompile(`^#`)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"b/doc.md": "## Install", "b/doc.txt": "#"}
+	for _, f := range ps.Files {
+		if got := f.Hunks[0].Section; got != want[f.NewName] {
+			t.Errorf("%s: Section = %q, want %q", f.NewName, got, want[f.NewName])
+		}
+	}
+}
+
+func ExampleFuncPatternFor() {
+	a := difflib.SplitLines("def greet(name):\n    msg = 'hi'\n    msg += name\n    msg += '!'\n    print(msg)\n")
+	b := difflib.SplitLines("def greet(name):\n    msg = 'hi'\n    msg += name\n    msg += '!'\n    log(msg)\n")
+	fmt.Print(difflib.UnifiedDiff(difflib.DiffInput{
+		A: a, B: b, FromFile: "a/app.py", ToFile: "b/app.py",
+		FuncPattern: difflib.FuncPatternFor("app.py"),
+	}))
+	// Output:
+	// --- a/app.py
+	// +++ b/app.py
+	// @@ -2,4 +2,4 @@ def greet(name):
+	//      msg = 'hi'
+	//      msg += name
+	//      msg += '!'
+	// -    print(msg)
+	// +    log(msg)
+}
+
+func TestUnifiedDiffHunkLabel(t *testing.T) {
+	a := difflib.SplitLines("=== RUN TestA\nok\n--- PASS\n=== RUN TestB\nok\nok\nok\nok\nok\nok\nok\n--- PASS\n")
+	b := difflib.SplitLines("=== RUN TestA\nFAIL\n--- PASS\n=== RUN TestB\nok\nok\nok\nok\nok\nok\nok\n--- FAIL\n")
+	var starts []int
+	d := difflib.UnifiedDiff(difflib.DiffInput{
+		A: a, B: b,
+		FuncPattern: regexp.MustCompile(`never`),
+		HunkLabel: func(aStart int) string {
+			starts = append(starts, aStart)
+			for i := aStart; i >= 0; i-- {
+				if name, ok := strings.CutPrefix(a[i], "=== RUN "); ok {
+					return name + "\nignored"
+				}
+			}
+			return ""
+		},
+	})
+	var got []string
+	for _, h := range d.Hunks {
+		got = append(got, h.Section)
+	}
+	if want := []string{"TestA", "TestB"}; !reflect.DeepEqual(got, want) || !reflect.DeepEqual(starts, []int{0, 8}) {
+		t.Errorf("sections = %q, starts = %v; want %q, [0 8]", got, starts, want)
+	}
+	if s := d.String(); !strings.Contains(s, "@@ -9,4 +9,4 @@ TestB\n") {
+		t.Errorf("diff =\n%s", s)
+	}
+}
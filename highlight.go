@@ -0,0 +1,61 @@
+package difflib
+
+import (
+	"sort"
+	"strings"
+)
+
+// Highlight marks part of a diff line for a renderer to emphasise.
+type Highlight struct {
+	// Start and End are byte offsets into the line's text, which excludes
+	// its ' ', '+' or '-' prefix and its line ending.
+	Start, End int
+	// Class names the kind of content, such as "whitespace" or "secret".
+	// RenderHTML adds it, prefixed, to the class of the highlight's span if
+	// it is a valid class name; Colorize ignores it.
+	Class string
+}
+
+// LineClassifier returns the parts of a hunk line to highlight. kind is the
+// line's prefix, '+', '-' or ' ', and text is the line without its prefix
+// or line ending. Ranges outside text are clipped, and where ranges overlap
+// the one that starts first wins.
+type LineClassifier func(kind byte, text string) []Highlight
+
+// HighlightTrailingWhitespace is a LineClassifier that highlights trailing
+// spaces and tabs in added lines with class "whitespace", as git does.
+//
+// Example:
+//
+//	out := difflib.ColorizeFunc(d.String(), difflib.DefaultTheme(), difflib.HighlightTrailingWhitespace)
+func HighlightTrailingWhitespace(kind byte, text string) []Highlight {
+	if kind != '+' {
+		return nil
+	}
+	trimmed := strings.TrimRight(text, " \t")
+	if len(trimmed) == len(text) {
+		return nil
+	}
+	return []Highlight{{Start: len(trimmed), End: len(text), Class: "whitespace"}}
+}
+
+// classifyLine calls classify, if set, on a hunk line and returns its
+// highlights sorted, clipped to text and without overlaps.
+func classifyLine(classify LineClassifier, kind byte, text string) []Highlight {
+	if classify == nil {
+		return nil
+	}
+	hs := append([]Highlight(nil), classify(kind, text)...)
+	sort.SliceStable(hs, func(i, j int) bool { return hs[i].Start < hs[j].Start })
+	var out []Highlight
+	end := 0
+	for _, h := range hs {
+		h.Start, h.End = max(h.Start, end), min(h.End, len(text))
+		if h.Start >= h.End {
+			continue
+		}
+		out = append(out, h)
+		end = h.End
+	}
+	return out
+}
@@ -0,0 +1,391 @@
+package difflib
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"unicode/utf8"
+)
+
+// HTMLDiffOptions controls MakeHTMLTable and MakeHTMLFile, mirroring the
+// parameters of Python's difflib.HtmlDiff.
+type HTMLDiffOptions struct {
+	// FromDesc and ToDesc are the column headings for A and B.
+	FromDesc, ToDesc string
+	// TabSize is the tab stop spacing. Defaults to 8.
+	TabSize int
+	// WrapColumn, if positive, wraps lines longer than this many characters
+	// onto continuation rows, whose line number is shown as ">".
+	WrapColumn int
+	// Context shows only the changes and NumLines lines around them instead
+	// of the full files.
+	Context bool
+	// NumLines is the number of context lines shown around changes, and how
+	// far above a change its "next" link anchor is placed. Defaults to 5.
+	NumLines int
+	// Charset is declared in the page produced by MakeHTMLFile. Defaults to
+	// "utf-8".
+	Charset string
+}
+
+// htmlDiffStyles is the style sheet of MakeHTMLFile, as in Python.
+const htmlDiffStyles = `
+        table.diff {font-family:Courier; border:medium;}
+        .diff_header {background-color:#e0e0e0}
+        td.diff_header {text-align:right}
+        .diff_next {background-color:#c0c0c0}
+        .diff_add {background-color:#aaffaa}
+        .diff_chg {background-color:#ffff77}
+        .diff_sub {background-color:#ffaaaa}`
+
+// htmlDiffLegend is the legend table of MakeHTMLFile, as in Python.
+const htmlDiffLegend = `
+    <table class="diff" summary="Legends">
+        <tr> <th colspan="2"> Legends </th> </tr>
+        <tr> <td> <table border="" summary="Colors">
+                      <tr><th> Colors </th> </tr>
+                      <tr><td class="diff_add">&nbsp;Added&nbsp;</td></tr>
+                      <tr><td class="diff_chg">Changed</td> </tr>
+                      <tr><td class="diff_sub">Deleted</td> </tr>
+                  </table></td>
+             <td> <table border="" summary="Links">
+                      <tr><th colspan="2"> Links </th> </tr>
+                      <tr><td>(f)irst change</td> </tr>
+                      <tr><td>(n)ext change</td> </tr>
+                      <tr><td>(t)op</td> </tr>
+                  </table></td> </tr>
+    </table>`
+
+// MakeHTMLFile returns a complete HTML page containing the table produced
+// by MakeHTMLTable, a legend and the style sheet, like Python's
+// HtmlDiff.make_file.
+//
+// Example:
+//
+//	page := difflib.MakeHTMLFile(a, b, difflib.HTMLDiffOptions{
+//	    FromDesc: "config.old",
+//	    ToDesc:   "config.new",
+//	    Context:  true,
+//	})
+//	os.WriteFile("diff.html", []byte(page), 0o644)
+func MakeHTMLFile(a, b []string, opts HTMLDiffOptions) string {
+	charset := opts.Charset
+	if charset == "" {
+		charset = "utf-8"
+	}
+	return fmt.Sprintf(`<!DOCTYPE html PUBLIC "-//W3C//DTD XHTML 1.0 Transitional//EN"
+          "http://www.w3.org/TR/xhtml1/DTD/xhtml1-transitional.dtd">
+
+<html>
+
+<head>
+    <meta http-equiv="Content-Type"
+          content="text/html; charset=%s" />
+    <title></title>
+    <style type="text/css">%s
+    </style>
+</head>
+
+<body>
+%s%s
+</body>
+
+</html>
+`, html.EscapeString(charset), htmlDiffStyles, MakeHTMLTable(a, b, opts), htmlDiffLegend)
+}
+
+// MakeHTMLTable returns an HTML table showing A and B side by side with line
+// numbers, intra-line highlighting of changed lines and "first", "next" and
+// "top" navigation links, like Python's HtmlDiff.make_table. All text is
+// HTML-escaped. Changed line pairs that are similar enough are highlighted
+// character by character; other changes are highlighted as whole lines.
+//
+// Example:
+//
+//	table := difflib.MakeHTMLTable(a, b, difflib.HTMLDiffOptions{FromDesc: "before", ToDesc: "after"})
+func MakeHTMLTable(a, b []string, opts HTMLDiffOptions) string {
+	tabSize := opts.TabSize
+	if tabSize <= 0 {
+		tabSize = 8
+	}
+	numLines := opts.NumLines
+	if numLines <= 0 {
+		numLines = 5
+	}
+	const prefix = "difflib_chg_to0__"
+
+	rows, blocks := htmlDiffRows(a, b)
+	visible := make([]bool, len(rows))
+	for i := range rows {
+		visible[i] = !opts.Context
+	}
+	if opts.Context {
+		for i, r := range rows {
+			if r.changed {
+				for k := max(i-numLines, 0); k <= min(i+numLines, len(rows)-1); k++ {
+					visible[k] = true
+				}
+			}
+		}
+	}
+	for k, start := range blocks {
+		at := max(start-numLines, 0)
+		for !visible[at] {
+			at++
+		}
+		if rows[at].anchor == "" {
+			rows[at].anchor = fmt.Sprintf("%s%d", prefix, k)
+		}
+		link := fmt.Sprintf(`<a href="#%s%d">n</a>`, prefix, k+1)
+		if k == len(blocks)-1 {
+			link = fmt.Sprintf(`<a href="#%stop">t</a>`, prefix)
+		}
+		rows[start].next = link
+	}
+
+	var body strings.Builder
+	if len(blocks) == 0 {
+		msg := "No Differences Found"
+		if len(a) == 0 && len(b) == 0 {
+			msg = "Empty File"
+		}
+		fmt.Fprintf(&body, `<tr><td class="diff_next"><a href="#%stop">t</a></td><td></td><td>&nbsp;%s&nbsp;</td>`+
+			`<td class="diff_next"><a href="#%stop">t</a></td><td></td><td>&nbsp;%s&nbsp;</td></tr>`+"\n", prefix, msg, prefix, msg)
+	} else {
+		first, gap := true, false
+		for i, r := range rows {
+			if !visible[i] {
+				gap = true
+				continue
+			}
+			if gap && !first {
+				// Separate folded regions into their own row groups.
+				body.WriteString("        </tbody>        \n        <tbody>\n")
+			}
+			if first && r.next == "" {
+				r.next = fmt.Sprintf(`<a href="#%s0">f</a>`, prefix)
+			}
+			first, gap = false, false
+			writeHTMLDiffRow(&body, r, tabSize, opts.WrapColumn)
+		}
+	}
+
+	return fmt.Sprintf(`
+    <table class="diff" id="%stop"
+           cellspacing="0" cellpadding="0" rules="groups" >
+        <colgroup></colgroup> <colgroup></colgroup> <colgroup></colgroup>
+        <colgroup></colgroup> <colgroup></colgroup> <colgroup></colgroup>
+        %s
+        <tbody>
+%s        </tbody>
+    </table>`, prefix, htmlDiffHeader(opts), body.String())
+}
+
+func htmlDiffHeader(opts HTMLDiffOptions) string {
+	if opts.FromDesc == "" && opts.ToDesc == "" {
+		return ""
+	}
+	return fmt.Sprintf(`<thead><tr><th class="diff_next"><br /></th><th colspan="2" class="diff_header">%s</th>`+
+		`<th class="diff_next"><br /></th><th colspan="2" class="diff_header">%s</th></tr></thead>`,
+		html.EscapeString(opts.FromDesc), html.EscapeString(opts.ToDesc))
+}
+
+// htmlSeg is a run of text with an optional highlight class.
+type htmlSeg struct {
+	text, class string
+}
+
+// htmlSide is one side of a row: a line number (0 if absent) and its text.
+type htmlSide struct {
+	num  int
+	segs []htmlSeg
+}
+
+type htmlRow struct {
+	from, to htmlSide
+	changed  bool
+	anchor   string
+	next     string
+}
+
+// htmlDiffRows lays out a and b as table rows and returns them with the
+// index of the first row of each change block. Within a replaced block,
+// similar lines share a row, as pairSimilarLines pairs them.
+func htmlDiffRows(a, b []string) ([]htmlRow, []int) {
+	var rows []htmlRow
+	var blocks []int
+	line := func(s string) string { return strings.TrimRight(s, "\r\n") }
+	for _, op := range GetOpCodes(a, b) {
+		if op.Tag != OpEqual {
+			blocks = append(blocks, len(rows))
+		}
+		n := op.I2 - op.I1
+		if op.Tag == OpEqual {
+			for k := 0; k < n; k++ {
+				rows = append(rows, htmlRow{
+					from: htmlSide{op.I1 + k + 1, []htmlSeg{{line(a[op.I1+k]), ""}}},
+					to:   htmlSide{op.J1 + k + 1, []htmlSeg{{line(b[op.J1+k]), ""}}},
+				})
+			}
+			continue
+		}
+		runs := []replaceRun{{OpCode: op}}
+		if op.Tag == OpReplace {
+			runs = pairSimilarLines(a, b, op, 0)
+		}
+		for _, run := range runs {
+			n, m := run.I2-run.I1, run.J2-run.J1
+			for k := 0; k < max(n, m); k++ {
+				i, j := run.I1+k, run.J1+k
+				r := htmlRow{changed: true}
+				switch {
+				case k < n && k < m:
+					r.from.segs, r.to.segs = intralineSegs(line(a[i]), line(b[j]))
+					r.from.num, r.to.num = i+1, j+1
+				case k < n:
+					r.from = htmlSide{i + 1, []htmlSeg{{line(a[i]), "diff_sub"}}}
+				default:
+					r.to = htmlSide{j + 1, []htmlSeg{{line(b[j]), "diff_add"}}}
+				}
+				rows = append(rows, r)
+			}
+		}
+	}
+	return rows, blocks
+}
+
+// intralineSegs highlights the differences between a changed line pair
+// character by character, or the whole lines if they are too dissimilar.
+func intralineSegs(x, y string) ([]htmlSeg, []htmlSeg) {
+	from, to, ok := charDiffSegs(x, y)
+	if !ok {
+		return []htmlSeg{{x, "diff_chg"}}, []htmlSeg{{y, "diff_chg"}}
+	}
+	return from, to
+}
+
+// charDiffSegs diffs x and y character by character, classing changed runs
+// "diff_chg", deletions "diff_sub" and insertions "diff_add". ok is false if
+// the lines are too dissimilar for the result to be useful.
+func charDiffSegs(x, y string) (from, to []htmlSeg, ok bool) {
+	xs, ys := splitChars(x), splitChars(y)
+	m := NewMatcher(xs, ys)
+	if m.Ratio() < 0.75 {
+		return nil, nil, false
+	}
+	for _, op := range m.GetOpCodes() {
+		fx, ty := strings.Join(xs[op.I1:op.I2], ""), strings.Join(ys[op.J1:op.J2], "")
+		switch op.Tag {
+		case OpEqual:
+			from, to = append(from, htmlSeg{fx, ""}), append(to, htmlSeg{ty, ""})
+		case OpReplace:
+			from, to = append(from, htmlSeg{fx, "diff_chg"}), append(to, htmlSeg{ty, "diff_chg"})
+		case OpDelete:
+			from = append(from, htmlSeg{fx, "diff_sub"})
+		case OpInsert:
+			to = append(to, htmlSeg{ty, "diff_add"})
+		}
+	}
+	return from, to, true
+}
+
+// splitChars splits s into one string per rune.
+func splitChars(s string) []string {
+	out := make([]string, 0, len(s))
+	for _, r := range s {
+		out = append(out, string(r))
+	}
+	return out
+}
+
+// writeHTMLDiffRow writes r as one table row, or several if wrapping.
+func writeHTMLDiffRow(w *strings.Builder, r htmlRow, tabSize, wrap int) {
+	from := wrapSegs(expandSegTabs(r.from.segs, tabSize), wrap)
+	to := wrapSegs(expandSegTabs(r.to.segs, tabSize), wrap)
+	for k := 0; k < max(len(from), len(to)); k++ {
+		next, id := "", ""
+		if k == 0 {
+			next = r.next
+			if r.anchor != "" {
+				id = fmt.Sprintf(` id="%s"`, r.anchor)
+			}
+		}
+		fmt.Fprintf(w, `<tr><td class="diff_next"%s>%s</td>`, id, next)
+		writeHTMLDiffCell(w, "from0_", r.from.num, from, k)
+		fmt.Fprintf(w, `<td class="diff_next">%s</td>`, next)
+		writeHTMLDiffCell(w, "to0_", r.to.num, to, k)
+		w.WriteString("</tr>\n")
+	}
+}
+
+func writeHTMLDiffCell(w *strings.Builder, idPrefix string, num int, pieces [][]htmlSeg, k int) {
+	switch {
+	case num == 0 || k >= len(pieces):
+		w.WriteString(`<td class="diff_header"></td><td nowrap="nowrap"></td>`)
+		return
+	case k == 0:
+		fmt.Fprintf(w, `<td class="diff_header" id="%s%d">%d</td><td nowrap="nowrap">`, idPrefix, num, num)
+	default:
+		w.WriteString(`<td class="diff_header">&gt;</td><td nowrap="nowrap">`)
+	}
+	for _, s := range pieces[k] {
+		text := strings.ReplaceAll(html.EscapeString(s.text), " ", "&nbsp;")
+		if s.class != "" && s.text != "" {
+			fmt.Fprintf(w, `<span class="%s">%s</span>`, s.class, text)
+		} else {
+			w.WriteString(text)
+		}
+	}
+	w.WriteString("</td>")
+}
+
+// expandSegTabs expands tabs across the segments of a line.
+func expandSegTabs(segs []htmlSeg, tabSize int) []htmlSeg {
+	col := 0
+	out := make([]htmlSeg, len(segs))
+	for i, s := range segs {
+		var b strings.Builder
+		for _, r := range s.text {
+			if r == '\t' {
+				pad := tabSize - col%tabSize
+				b.WriteString(strings.Repeat(" ", pad))
+				col += pad
+				continue
+			}
+			b.WriteRune(r)
+			col++
+		}
+		out[i] = htmlSeg{b.String(), s.class}
+	}
+	return out
+}
+
+// wrapSegs splits a line's segments into pieces of at most width runes, or
+// returns a single piece if width is not positive.
+func wrapSegs(segs []htmlSeg, width int) [][]htmlSeg {
+	if width <= 0 {
+		return [][]htmlSeg{segs}
+	}
+	pieces := [][]htmlSeg{nil}
+	n := 0
+	for _, s := range segs {
+		text := s.text
+		for text != "" {
+			if n == width {
+				pieces = append(pieces, nil)
+				n = 0
+			}
+			take, k := 0, 0
+			for k < len(text) && take < width-n {
+				_, size := utf8.DecodeRuneInString(text[k:])
+				k += size
+				take++
+			}
+			last := len(pieces) - 1
+			pieces[last] = append(pieces[last], htmlSeg{text[:k], s.class})
+			n += take
+			text = text[k:]
+		}
+	}
+	return pieces
+}
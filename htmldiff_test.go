@@ -0,0 +1,105 @@
+package difflib_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	difflib "github.com/njchilds90/go-difflib"
+)
+
+func TestMakeHTMLTable(t *testing.T) {
+	a := difflib.SplitLines("one\ntwo <b>\nthree\n")
+	b := difflib.SplitLines("one\ntwo <i>\nfour\nfive\n")
+	got := difflib.MakeHTMLTable(a, b, difflib.HTMLDiffOptions{FromDesc: "old & busted", ToDesc: "new"})
+	for _, want := range []string{
+		`<th colspan="2" class="diff_header">old &amp; busted</th>`,
+		`<td class="diff_header" id="from0_1">1</td><td nowrap="nowrap">one</td>`,
+		`two&nbsp;&lt;<span class="diff_chg">b</span>&gt;`,
+		`two&nbsp;&lt;<span class="diff_chg">i</span>&gt;`,
+		`<span class="diff_chg">three</span>`,
+		`<td class="diff_header"></td><td nowrap="nowrap"></td>`,
+		`<td class="diff_header" id="to0_4">4</td><td nowrap="nowrap"><span class="diff_add">five</span></td>`,
+		`<td class="diff_next" id="difflib_chg_to0__0"><a href="#difflib_chg_to0__0">f</a></td>`,
+		`<a href="#difflib_chg_to0__top">t</a>`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("table lacks %s\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "<b>") {
+		t.Error("content is not escaped")
+	}
+}
+
+func TestMakeHTMLTableOptions(t *testing.T) {
+	var a []string
+	for i := 0; i < 40; i++ {
+		a = append(a, fmt.Sprintf("line %d\n", i))
+	}
+	b := append([]string(nil), a...)
+	b[5] = "changed\n"
+	b[30] = "changed too\n"
+
+	tests := []struct {
+		name  string
+		a, b  []string
+		opts  difflib.HTMLDiffOptions
+		check func(t *testing.T, table string)
+	}{
+		{"full", a, b, difflib.HTMLDiffOptions{}, func(t *testing.T, table string) {
+			if n := strings.Count(table, "<tr>"); n != 40 {
+				t.Errorf("got %d rows, want 40", n)
+			}
+			if !strings.Contains(table, `<a href="#difflib_chg_to0__1">n</a>`) {
+				t.Error("missing next link")
+			}
+		}},
+		{"context", a, b, difflib.HTMLDiffOptions{Context: true, NumLines: 2}, func(t *testing.T, table string) {
+			if n := strings.Count(table, "<tr>"); n != 10 {
+				t.Errorf("got %d rows, want 10", n)
+			}
+			if n := strings.Count(table, "<tbody>"); n != 2 {
+				t.Errorf("got %d tbody groups, want 2", n)
+			}
+		}},
+		{"wrap", []string{"abcdefghij\n"}, []string{"abcdefghiJ\n"}, difflib.HTMLDiffOptions{WrapColumn: 4}, func(t *testing.T, table string) {
+			if n := strings.Count(table, "<tr>"); n != 3 {
+				t.Errorf("got %d rows, want 3", n)
+			}
+			if !strings.Contains(table, `<td class="diff_header">&gt;</td><td nowrap="nowrap">efgh</td>`) ||
+				!strings.Contains(table, `<td class="diff_header">&gt;</td><td nowrap="nowrap">i<span class="diff_chg">J</span></td>`) {
+				t.Errorf("unexpected wrapping:\n%s", table)
+			}
+		}},
+		{"tabs", []string{"\tx\n"}, []string{"\ty\n"}, difflib.HTMLDiffOptions{TabSize: 4}, func(t *testing.T, table string) {
+			if !strings.Contains(table, ">&nbsp;&nbsp;&nbsp;&nbsp;x<") {
+				t.Errorf("tabs not expanded to 4 spaces:\n%s", table)
+			}
+		}},
+		{"no differences", a, a, difflib.HTMLDiffOptions{}, func(t *testing.T, table string) {
+			if !strings.Contains(table, "No Differences Found") {
+				t.Error("missing no-differences message")
+			}
+		}},
+		{"empty", nil, nil, difflib.HTMLDiffOptions{}, func(t *testing.T, table string) {
+			if !strings.Contains(table, "Empty File") {
+				t.Error("missing empty-file message")
+			}
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.check(t, difflib.MakeHTMLTable(tt.a, tt.b, tt.opts))
+		})
+	}
+}
+
+func TestMakeHTMLFile(t *testing.T) {
+	page := difflib.MakeHTMLFile([]string{"a\n"}, []string{"b\n"}, difflib.HTMLDiffOptions{})
+	for _, want := range []string{`charset=utf-8`, `.diff_add {background-color:#aaffaa}`, `summary="Legends"`, `<table class="diff" id="difflib_chg_to0__top"`} {
+		if !strings.Contains(page, want) {
+			t.Errorf("page lacks %s", want)
+		}
+	}
+}
@@ -0,0 +1,357 @@
+package difflib
+
+import (
+	"fmt"
+	"html"
+	"strings"
+)
+
+// HTMLView selects the layout produced by RenderHTML.
+type HTMLView int
+
+const (
+	// HTMLInline shows removed and added lines interleaved in one column,
+	// with old and new line numbers side by side.
+	HTMLInline HTMLView = iota
+	// HTMLSplit shows the old and new versions in two columns.
+	HTMLSplit
+)
+
+// HTMLOptions controls RenderHTML.
+type HTMLOptions struct {
+	// View selects the inline or split layout. Defaults to HTMLInline.
+	View HTMLView
+	// ClassPrefix is prepended to every class name, e.g. "diff-" yields
+	// "diff-del". It may contain only ASCII letters, digits, '-' and '_';
+	// anything else, or an empty prefix, is replaced by "diff-".
+	ClassPrefix string
+	// IntraLine marks the changed characters of similar removed/added line
+	// pairs with <mark> elements inside their <del> and <ins> elements.
+	IntraLine bool
+	// Classify, if set, is called for every hunk line, and the parts it
+	// returns are wrapped in <span> elements of class "hl" and the
+	// highlight's own Class.
+	Classify LineClassifier
+	// ColorMoved marks the lines of blocks moved within the diff (see
+	// DiffResult.MovedLines), found with MoveOptions, with the class "moved",
+	// or "moved-alt" for every other block.
+	ColorMoved  bool
+	MoveOptions MoveOptions
+}
+
+// RenderHTML renders a diff as an HTML fragment. Removed line content is
+// wrapped in <del> and added content in <ins>; every element carries a class
+// name (prefixed with opts.ClassPrefix) so the output can be restyled, for
+// example with the style sheet from HTMLTheme.CSS. All content, including
+// file names and hunk headers, is HTML-escaped, so untrusted diffs can be
+// rendered safely.
+//
+// Classes used, without prefix: "file" (the outer div), "inline" or
+// "split", "header", "from-file", "to-file", "hunk" (one table per hunk),
+// "hunk-header", "line", "context", "del", "add", "empty", "num", "code",
+// "hl", "moved" and "moved-alt".
+//
+// Example:
+//
+//	frag := difflib.RenderHTML(d, difflib.HTMLOptions{View: difflib.HTMLSplit, IntraLine: true})
+//	page := "<style>" + difflib.LightHTMLTheme().CSS("diff-") + "</style>" + frag
+func RenderHTML(d DiffResult, opts HTMLOptions) string {
+	p := htmlClassPrefix(opts.ClassPrefix)
+	view := "inline"
+	if opts.View == HTMLSplit {
+		view = "split"
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "<div class=\"%sfile %s%s\">\n", p, p, view)
+	if d.FromFile != "" || d.ToFile != "" {
+		fmt.Fprintf(&b, "<div class=\"%sheader\"><span class=\"%sfrom-file\">%s</span> <span class=\"%sto-file\">%s</span></div>\n",
+			p, p, html.EscapeString(d.FromFile), p, html.EscapeString(d.ToFile))
+	}
+	var moved [][]int
+	if opts.ColorMoved {
+		moved = d.movedBlocks(opts.MoveOptions)
+	}
+	for hi, h := range d.Hunks {
+		cols := 3
+		if opts.View == HTMLSplit {
+			cols = 4
+		}
+		fmt.Fprintf(&b, "<table class=\"%shunk\">\n<tr class=\"%shunk-header\"><td colspan=\"%d\">%s</td></tr>\n",
+			p, p, cols, html.EscapeString(h.header()))
+		var blocks []int
+		if moved != nil {
+			blocks = moved[hi]
+		}
+		for _, r := range htmlHunkRows(h, blocks, opts.IntraLine, opts.Classify) {
+			if opts.View == HTMLSplit {
+				writeSplitRow(&b, p, r)
+			} else {
+				writeInlineRows(&b, p, r)
+			}
+		}
+		b.WriteString("</table>\n")
+	}
+	b.WriteString("</div>\n")
+	return b.String()
+}
+
+// htmlLine is one side of a rendered diff line.
+type htmlLine struct {
+	num  int
+	segs []htmlSeg
+	hls  []Highlight
+	// moved is the line's moved block number, or 0.
+	moved int
+}
+
+// htmlHunkRow pairs an old and a new line; either may be missing (num 0).
+// Context rows have both sides equal.
+type htmlHunkRow struct {
+	context  bool
+	old, new htmlLine
+}
+
+// htmlHunkRows lays out a hunk as rows, pairing the removed and added lines
+// of each change run, by similarity if intra is set, and highlights lines
+// with classify. blocks, if not nil, holds the moved block number of each
+// line of h.
+func htmlHunkRows(h Hunk, blocks []int, intra bool, classify LineClassifier) []htmlHunkRow {
+	var rows []htmlHunkRow
+	i, j := hunkAnchor(h)+1, newAnchor(h)+1
+	var dels, adds []string
+	var delMoved, addMoved []int
+	flush := func() {
+		runs := []replaceRun{{OpCode: OpCode{OpReplace, 0, len(dels), 0, len(adds)}}}
+		if intra && len(dels) > 0 && len(adds) > 0 {
+			runs = pairSimilarLines(dels, adds, runs[0].OpCode, 0)
+		}
+		for _, run := range runs {
+			n, m := run.I2-run.I1, run.J2-run.J1
+			for k := 0; k < max(n, m); k++ {
+				d, a := run.I1+k, run.J1+k
+				var r htmlHunkRow
+				if k < n {
+					r.old.segs = []htmlSeg{{dels[d], ""}}
+					r.old.num, r.old.hls, r.old.moved = i, classifyLine(classify, '-', dels[d]), delMoved[d]
+					i++
+				}
+				if k < m {
+					r.new.segs = []htmlSeg{{adds[a], ""}}
+					r.new.num, r.new.hls, r.new.moved = j, classifyLine(classify, '+', adds[a]), addMoved[a]
+					j++
+				}
+				if run.paired {
+					if from, to, ok := charDiffSegs(dels[d], adds[a]); ok {
+						r.old.segs, r.new.segs = from, to
+					}
+				}
+				rows = append(rows, r)
+			}
+		}
+		dels, adds = dels[:0], adds[:0]
+		delMoved, addMoved = delMoved[:0], addMoved[:0]
+	}
+	for li, l := range h.Lines {
+		if l == "" {
+			continue
+		}
+		text := strings.TrimRight(l[1:], "\r\n")
+		block := 0
+		if blocks != nil {
+			block = blocks[li]
+		}
+		switch l[0] {
+		case '-':
+			dels = append(dels, text)
+			delMoved = append(delMoved, block)
+		case '+':
+			adds = append(adds, text)
+			addMoved = append(addMoved, block)
+		case ' ':
+			flush()
+			line := htmlLine{segs: []htmlSeg{{text, ""}}, hls: classifyLine(classify, ' ', text)}
+			r := htmlHunkRow{context: true, old: line, new: line}
+			r.old.num, r.new.num = i, j
+			rows = append(rows, r)
+			i, j = i+1, j+1
+		}
+	}
+	flush()
+	return rows
+}
+
+func writeInlineRows(b *strings.Builder, p string, r htmlHunkRow) {
+	if r.context {
+		fmt.Fprintf(b, "<tr class=\"%sline %scontext\"><td class=\"%snum\">%d</td><td class=\"%snum\">%d</td><td class=\"%scode\">%s</td></tr>\n",
+			p, p, p, r.old.num, p, r.new.num, p, htmlSegs(r.old, p, "", ""))
+		return
+	}
+	if r.old.num != 0 {
+		m := htmlMovedClass(p, r.old.moved)
+		fmt.Fprintf(b, "<tr class=\"%sline %sdel%s\"><td class=\"%snum\">%d</td><td class=\"%snum\"></td><td class=\"%scode\">%s</td></tr>\n",
+			p, p, m, p, r.old.num, p, p, htmlSegs(r.old, p, "del", p+"del"+m))
+	}
+	if r.new.num != 0 {
+		m := htmlMovedClass(p, r.new.moved)
+		fmt.Fprintf(b, "<tr class=\"%sline %sadd%s\"><td class=\"%snum\"></td><td class=\"%snum\">%d</td><td class=\"%scode\">%s</td></tr>\n",
+			p, p, m, p, p, r.new.num, p, htmlSegs(r.new, p, "ins", p+"add"+m))
+	}
+}
+
+func writeSplitRow(b *strings.Builder, p string, r htmlHunkRow) {
+	fmt.Fprintf(b, "<tr class=\"%sline\">", p)
+	cell := func(l htmlLine, kind, tag string) {
+		switch {
+		case l.num == 0:
+			fmt.Fprintf(b, "<td class=\"%snum %sempty\"></td><td class=\"%scode %sempty\"></td>", p, p, p, p)
+		case r.context:
+			fmt.Fprintf(b, "<td class=\"%snum\">%d</td><td class=\"%scode %scontext\">%s</td>", p, l.num, p, p, htmlSegs(l, p, "", ""))
+		default:
+			m := htmlMovedClass(p, l.moved)
+			fmt.Fprintf(b, "<td class=\"%snum\">%d</td><td class=\"%scode %s%s%s\">%s</td>", p, l.num, p, p, kind, m, htmlSegs(l, p, tag, p+kind+m))
+		}
+	}
+	cell(r.old, "del", "del")
+	cell(r.new, "add", "ins")
+	b.WriteString("</tr>\n")
+}
+
+// htmlMovedClass returns the class suffix, with a leading space, for a line
+// in moved block number block, or "" if block is 0.
+func htmlMovedClass(p string, block int) string {
+	switch {
+	case block == 0:
+		return ""
+	case block%2 == 0:
+		return " " + p + "moved-alt"
+	}
+	return " " + p + "moved"
+}
+
+// htmlSegs escapes the segments of l, marking classed ones with <mark> and
+// wrapping highlighted parts in spans, and wraps the result in tag (if not
+// empty) with the given class. p is the class prefix.
+func htmlSegs(l htmlLine, p, tag, class string) string {
+	var b strings.Builder
+	pos := 0
+	for _, s := range l.segs {
+		for s.text != "" {
+			n, span := len(s.text), ""
+			for _, h := range l.hls {
+				if h.End <= pos {
+					continue
+				}
+				if h.Start > pos {
+					n = min(n, h.Start-pos)
+				} else {
+					n, span = min(n, h.End-pos), p+"hl"
+					if c := htmlClassPrefix(h.Class); c == h.Class {
+						span += " " + p + c
+					}
+				}
+				break
+			}
+			text := html.EscapeString(s.text[:n])
+			if s.class != "" {
+				text = "<mark>" + text + "</mark>"
+			}
+			if span != "" {
+				text = "<span class=\"" + span + "\">" + text + "</span>"
+			}
+			b.WriteString(text)
+			s.text, pos = s.text[n:], pos+n
+		}
+	}
+	if tag == "" {
+		return b.String()
+	}
+	return fmt.Sprintf("<%s class=\"%s\">%s</%s>", tag, class, b.String(), tag)
+}
+
+// htmlClassPrefix returns prefix if it is safe to embed in a class
+// attribute, or "diff-" otherwise.
+func htmlClassPrefix(prefix string) string {
+	if prefix == "" {
+		return "diff-"
+	}
+	for _, r := range prefix {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+			return "diff-"
+		}
+	}
+	return prefix
+}
+
+// HTMLTheme holds the CSS colors used to style RenderHTML output.
+type HTMLTheme struct {
+	// Background and Foreground are the base colors of the diff.
+	Background, Foreground string
+	// Added and Removed are the background colors of added and removed lines.
+	Added, Removed string
+	// AddedMark and RemovedMark highlight changed characters within lines.
+	AddedMark, RemovedMark string
+	// HunkHeader is the background color of hunk header rows.
+	HunkHeader string
+	// LineNumber is the color of line numbers.
+	LineNumber string
+	// Highlight is the background color of parts picked out by
+	// HTMLOptions.Classify.
+	Highlight string
+	// MovedFrom and MovedTo are the background colors of removed and added
+	// lines of moved blocks, and MovedFromAlt and MovedToAlt those of every
+	// other block.
+	MovedFrom, MovedFromAlt string
+	MovedTo, MovedToAlt     string
+}
+
+// LightHTMLTheme returns a theme for light backgrounds.
+func LightHTMLTheme() HTMLTheme {
+	return HTMLTheme{
+		Background: "#ffffff", Foreground: "#24292f",
+		Added: "#e6ffec", Removed: "#ffebe9",
+		AddedMark: "#abf2bc", RemovedMark: "#ff8182",
+		HunkHeader: "#ddf4ff", LineNumber: "#6e7781",
+		Highlight: "#ffc0c0",
+		MovedFrom: "#f5e0ff", MovedFromAlt: "#dde4ff",
+		MovedTo: "#d8f5f5", MovedToAlt: "#fff5c2",
+	}
+}
+
+// DarkHTMLTheme returns a theme for dark backgrounds.
+func DarkHTMLTheme() HTMLTheme {
+	return HTMLTheme{
+		Background: "#0d1117", Foreground: "#e6edf3",
+		Added: "#12261e", Removed: "#25171c",
+		AddedMark: "#1f6f3c", RemovedMark: "#8e2c2c",
+		HunkHeader: "#121d2f", LineNumber: "#7d8590",
+		Highlight: "#a40e26",
+		MovedFrom: "#3b1f4a", MovedFromAlt: "#1f2a4a",
+		MovedTo: "#143a3a", MovedToAlt: "#3a3514",
+	}
+}
+
+// CSS returns a style sheet applying the theme to RenderHTML output
+// generated with the given class prefix.
+//
+// Example:
+//
+//	css := difflib.DarkHTMLTheme().CSS("diff-")
+func (t HTMLTheme) CSS(prefix string) string {
+	p := "." + htmlClassPrefix(prefix)
+	return fmt.Sprintf(`%[1]sfile { background: %[2]s; color: %[3]s; font-family: monospace; }
+%[1]shunk { border-collapse: collapse; width: 100%%; }
+%[1]shunk-header td { background: %[4]s; }
+%[1]snum { color: %[5]s; text-align: right; padding: 0 0.5em; user-select: none; }
+%[1]scode { white-space: pre-wrap; }
+%[1]sdel, %[1]sline%[1]sdel td { background: %[6]s; text-decoration: none; }
+%[1]sadd, %[1]sline%[1]sadd td { background: %[7]s; text-decoration: none; }
+%[1]sdel mark { background: %[8]s; color: inherit; }
+%[1]sadd mark { background: %[9]s; color: inherit; }
+%[1]shl { background: %[10]s; }
+%[1]smoved%[1]sdel, %[1]sline%[1]smoved%[1]sdel td { background: %[11]s; }
+%[1]smoved-alt%[1]sdel, %[1]sline%[1]smoved-alt%[1]sdel td { background: %[12]s; }
+%[1]smoved%[1]sadd, %[1]sline%[1]smoved%[1]sadd td { background: %[13]s; }
+%[1]smoved-alt%[1]sadd, %[1]sline%[1]smoved-alt%[1]sadd td { background: %[14]s; }
+`, p, t.Background, t.Foreground, t.HunkHeader, t.LineNumber, t.Removed, t.Added, t.RemovedMark, t.AddedMark, t.Highlight,
+		t.MovedFrom, t.MovedFromAlt, t.MovedTo, t.MovedToAlt)
+}
@@ -0,0 +1,143 @@
+package difflib_test
+
+import (
+	"strings"
+	"testing"
+
+	difflib "github.com/njchilds90/go-difflib"
+)
+
+func TestRenderHTML(t *testing.T) {
+	d := difflib.UnifiedDiff(difflib.DiffInput{
+		A:        difflib.SplitLines("keep\nthe old value\n"),
+		B:        difflib.SplitLines("keep\nthe new value\nextra\n"),
+		FromFile: "a.txt",
+		ToFile:   "b.txt",
+	})
+	tests := []struct {
+		name string
+		opts difflib.HTMLOptions
+		want string
+	}{
+		{"inline", difflib.HTMLOptions{}, `<div class="diff-file diff-inline">
+<div class="diff-header"><span class="diff-from-file">a.txt</span> <span class="diff-to-file">b.txt</span></div>
+<table class="diff-hunk">
+<tr class="diff-hunk-header"><td colspan="3">@@ -1,2 +1,3 @@</td></tr>
+<tr class="diff-line diff-context"><td class="diff-num">1</td><td class="diff-num">1</td><td class="diff-code">keep</td></tr>
+<tr class="diff-line diff-del"><td class="diff-num">2</td><td class="diff-num"></td><td class="diff-code"><del class="diff-del">the old value</del></td></tr>
+<tr class="diff-line diff-add"><td class="diff-num"></td><td class="diff-num">2</td><td class="diff-code"><ins class="diff-add">the new value</ins></td></tr>
+<tr class="diff-line diff-add"><td class="diff-num"></td><td class="diff-num">3</td><td class="diff-code"><ins class="diff-add">extra</ins></td></tr>
+</table>
+</div>
+`},
+		{"split intraline", difflib.HTMLOptions{View: difflib.HTMLSplit, IntraLine: true, ClassPrefix: "x_"}, `<div class="x_file x_split">
+<div class="x_header"><span class="x_from-file">a.txt</span> <span class="x_to-file">b.txt</span></div>
+<table class="x_hunk">
+<tr class="x_hunk-header"><td colspan="4">@@ -1,2 +1,3 @@</td></tr>
+<tr class="x_line"><td class="x_num">1</td><td class="x_code x_context">keep</td><td class="x_num">1</td><td class="x_code x_context">keep</td></tr>
+<tr class="x_line"><td class="x_num">2</td><td class="x_code x_del"><del class="x_del">the <mark>old</mark> value</del></td><td class="x_num">2</td><td class="x_code x_add"><ins class="x_add">the <mark>new</mark> value</ins></td></tr>
+<tr class="x_line"><td class="x_num x_empty"></td><td class="x_code x_empty"></td><td class="x_num">3</td><td class="x_code x_add"><ins class="x_add">extra</ins></td></tr>
+</table>
+</div>
+`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := difflib.RenderHTML(d, tt.opts); got != tt.want {
+				t.Errorf("RenderHTML() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderHTMLPairsSimilarLines(t *testing.T) {
+	d := difflib.UnifiedDiff(difflib.DiffInput{
+		A: difflib.SplitLines("zero\nthe old value\n"),
+		B: difflib.SplitLines("the new value\n"),
+	})
+	got := difflib.RenderHTML(d, difflib.HTMLOptions{View: difflib.HTMLSplit, IntraLine: true})
+	want := `<td class="diff-code diff-del"><del class="diff-del">the <mark>old</mark> value</del></td><td class="diff-num">1</td>`
+	if !strings.Contains(got, want) {
+		t.Errorf("RenderHTML() lacks %s\n%s", want, got)
+	}
+}
+
+func TestRenderHTMLClassify(t *testing.T) {
+	d := difflib.UnifiedDiff(difflib.DiffInput{
+		A: difflib.SplitLines("value = 1\n"),
+		B: difflib.SplitLines("value = 2 \t\nkey = <secret>\n"),
+	})
+	secret := func(kind byte, text string) []difflib.Highlight {
+		if i := strings.Index(text, "<secret>"); i >= 0 {
+			return []difflib.Highlight{{Start: i, End: i + 8, Class: "secret"}, {Start: i + 2, End: 100, Class: "bad class"}}
+		}
+		return difflib.HighlightTrailingWhitespace(kind, text)
+	}
+	got := difflib.RenderHTML(d, difflib.HTMLOptions{View: difflib.HTMLSplit, IntraLine: true, Classify: secret})
+	for _, want := range []string{
+		`<ins class="diff-add">value = <mark>2</mark><span class="diff-hl diff-whitespace"><mark> ` + "\t" + `</mark></span></ins>`,
+		`<ins class="diff-add">key = <span class="diff-hl diff-secret">&lt;secret&gt;</span></ins>`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output does not contain %s:\n%s", want, got)
+		}
+	}
+	if !strings.Contains(difflib.LightHTMLTheme().CSS(""), ".diff-hl {") {
+		t.Error("CSS has no rule for highlights")
+	}
+}
+
+func TestRenderHTMLColorMoved(t *testing.T) {
+	d := difflib.UnifiedDiff(difflib.DiffInput{
+		A: difflib.SplitLines("one\ntwo\nthree\nx\ny\nz\nw\n"),
+		B: difflib.SplitLines("x\ny\nz\nw\none\ntwo\nthree\n"),
+	})
+	inline := difflib.RenderHTML(d, difflib.HTMLOptions{ColorMoved: true})
+	for _, want := range []string{
+		`<tr class="diff-line diff-del diff-moved">`,
+		`<ins class="diff-add diff-moved">one</ins>`,
+	} {
+		if !strings.Contains(inline, want) {
+			t.Errorf("inline output does not contain %s:\n%s", want, inline)
+		}
+	}
+	split := difflib.RenderHTML(d, difflib.HTMLOptions{View: difflib.HTMLSplit, ColorMoved: true})
+	if want := `<td class="diff-code diff-add diff-moved"><ins class="diff-add diff-moved">two</ins></td>`; !strings.Contains(split, want) {
+		t.Errorf("split output does not contain %s:\n%s", want, split)
+	}
+	if strings.Contains(difflib.RenderHTML(d, difflib.HTMLOptions{}), "moved") {
+		t.Error("moved lines marked without ColorMoved")
+	}
+}
+
+func TestRenderHTMLEscapes(t *testing.T) {
+	d := difflib.UnifiedDiff(difflib.DiffInput{
+		A:        difflib.SplitLines("<script>alert(1)</script>\n"),
+		B:        difflib.SplitLines("\"quoted\" & 'single'\n"),
+		FromFile: "<img src=x onerror=alert(1)>",
+		ToFile:   "b",
+	})
+	for _, view := range []difflib.HTMLView{difflib.HTMLInline, difflib.HTMLSplit} {
+		got := difflib.RenderHTML(d, difflib.HTMLOptions{View: view, IntraLine: true, ClassPrefix: `"><script>`})
+		for _, bad := range []string{"<script>", "<img", `"quoted"`, "'single'"} {
+			if strings.Contains(got, bad) {
+				t.Errorf("view %d: output contains unescaped %s:\n%s", view, bad, got)
+			}
+		}
+		if !strings.Contains(got, `class="diff-file`) {
+			t.Errorf("view %d: unsafe class prefix was not replaced", view)
+		}
+	}
+}
+
+func TestHTMLThemeCSS(t *testing.T) {
+	css := difflib.DarkHTMLTheme().CSS("my-")
+	for _, want := range []string{".my-file {", ".my-add mark { background: #1f6f3c;", ".my-hunk { border-collapse: collapse; width: 100%; }"} {
+		if !strings.Contains(css, want) {
+			t.Errorf("CSS lacks %q:\n%s", want, css)
+		}
+	}
+	if !strings.Contains(difflib.LightHTMLTheme().CSS("}{"), ".diff-file {") {
+		t.Error("unsafe prefix was not replaced")
+	}
+}
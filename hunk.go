@@ -0,0 +1,193 @@
+package difflib
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Split divides the hunk into two valid hunks at index at of h.Lines: the
+// first hunk holds Lines[:at] and the second Lines[at:], with headers
+// recomputed. Both halves must contain at least one change, so at has to
+// fall inside a run of context lines between two changes, as with the
+// split command of `git add -p`.
+//
+// Example:
+//
+//	first, second, err := hunk.Split(4)
+func (h Hunk) Split(at int) (Hunk, Hunk, error) {
+	if at <= 0 || at >= len(h.Lines) {
+		return Hunk{}, Hunk{}, fmt.Errorf("difflib: split index %d out of range [1, %d)", at, len(h.Lines))
+	}
+	head, tail := h.Lines[:at], h.Lines[at:]
+	if !linesHaveChanges(head) || !linesHaveChanges(tail) {
+		return Hunk{}, Hunk{}, fmt.Errorf("difflib: split at %d would leave a hunk without changes", at)
+	}
+	first := Hunk{OldStart: h.OldStart, NewStart: h.NewStart, Lines: append([]string(nil), head...)}
+	first.OldLines, first.NewLines = countHunkLines(head)
+	second := Hunk{
+		OldStart: h.OldStart + first.OldLines,
+		NewStart: h.NewStart + first.NewLines,
+		Lines:    append([]string(nil), tail...),
+	}
+	second.OldLines, second.NewLines = countHunkLines(tail)
+	fixEmptyStarts(&first, h)
+	fixEmptyStarts(&second, h)
+	return first, second, nil
+}
+
+// Apply applies the hunk alone to lines, returning the patched lines. Like
+// ApplyPatch, it looks for the hunk's context and deleted lines at the
+// position in its header and then searches outward, so a hunk still applies
+// after earlier hunks of its patch were applied or skipped. lines is not
+// modified.
+//
+// Example:
+//
+//	for _, h := range d.Hunks {
+//	    if stage(h) {
+//	        lines, err = h.Apply(lines)
+//	    }
+//	}
+func (h Hunk) Apply(lines []string) ([]string, error) {
+	p, ok := placeHunk(lines, h, 0, 0, ApplyOptions{})
+	if !ok {
+		return nil, fmt.Errorf("difflib: hunk %s %s", h.header(), hunkMismatch(lines, h))
+	}
+	return splicePlacements(lines, []hunkPlacement{p}), nil
+}
+
+// CanApply reports whether Apply would succeed on lines.
+func (h Hunk) CanApply(lines []string) bool {
+	_, ok := placeHunk(lines, h, 0, 0, ApplyOptions{})
+	return ok
+}
+
+// SplitAll splits the hunk at every run of context lines that separates two
+// changes, dividing each such run evenly between its neighbours. A hunk with
+// a single change block is returned unchanged.
+//
+// Example:
+//
+//	for _, sub := range hunk.SplitAll() {
+//	    // ask the user about each smaller hunk
+//	}
+func (h Hunk) SplitAll() []Hunk {
+	var out []Hunk
+	rest := h
+	for {
+		at := firstSplitPoint(rest.Lines)
+		if at < 0 {
+			return append(out, rest)
+		}
+		first, second, err := rest.Split(at)
+		if err != nil {
+			return append(out, rest)
+		}
+		out = append(out, first)
+		rest = second
+	}
+}
+
+// firstSplitPoint returns the index in the middle of the first context run
+// that has changes on both sides, or -1 if there is none.
+func firstSplitPoint(lines []string) int {
+	i := 0
+	for i < len(lines) && strings.HasPrefix(lines[i], " ") {
+		i++
+	}
+	for i < len(lines) && !strings.HasPrefix(lines[i], " ") {
+		i++
+	}
+	start := i
+	for i < len(lines) && strings.HasPrefix(lines[i], " ") {
+		i++
+	}
+	if i == len(lines) {
+		return -1
+	}
+	return start + (i-start+1)/2
+}
+
+// MergeHunks joins two hunks of the same diff into one, with a preceding b.
+// The hunks must be adjacent or overlap; overlapping lines must be context
+// lines present in both. Merging hunks separated by unchanged lines is not
+// possible without the source text and returns an error.
+//
+// Example:
+//
+//	merged, err := difflib.MergeHunks(result.Hunks[0], result.Hunks[1])
+func MergeHunks(a, b Hunk) (Hunk, error) {
+	aEnd := a.OldStart + a.OldLines
+	if a.OldLines == 0 {
+		aEnd++
+	}
+	bStart := b.OldStart
+	if b.OldLines == 0 {
+		bStart++
+	}
+	gap := bStart - aEnd
+	if gap > 0 {
+		return Hunk{}, fmt.Errorf("difflib: hunks are %d lines apart", gap)
+	}
+	overlap := -gap
+	if overlap > 0 {
+		if overlap > len(a.Lines) || overlap > len(b.Lines) {
+			return Hunk{}, fmt.Errorf("difflib: hunks overlap by %d lines, more than their length", overlap)
+		}
+		tail, head := a.Lines[len(a.Lines)-overlap:], b.Lines[:overlap]
+		for i := range tail {
+			if !strings.HasPrefix(tail[i], " ") || tail[i] != head[i] {
+				return Hunk{}, fmt.Errorf("difflib: hunks overlap on changed or differing lines")
+			}
+		}
+	}
+	m := Hunk{OldStart: a.OldStart, NewStart: a.NewStart}
+	m.Lines = append(append([]string(nil), a.Lines...), b.Lines[overlap:]...)
+	m.OldLines, m.NewLines = countHunkLines(m.Lines)
+	if a.OldLines == 0 && m.OldLines > 0 {
+		m.OldStart++
+	}
+	if a.NewLines == 0 && m.NewLines > 0 {
+		m.NewStart++
+	}
+	return m, nil
+}
+
+// countHunkLines returns the number of old-side (context and removed) and
+// new-side (context and added) lines in a hunk body.
+func countHunkLines(lines []string) (oldLines, newLines int) {
+	for _, l := range lines {
+		switch {
+		case strings.HasPrefix(l, " "):
+			oldLines++
+			newLines++
+		case strings.HasPrefix(l, "-"):
+			oldLines++
+		case strings.HasPrefix(l, "+"):
+			newLines++
+		}
+	}
+	return oldLines, newLines
+}
+
+// linesHaveChanges reports whether a hunk body contains added or removed lines.
+func linesHaveChanges(lines []string) bool {
+	for _, l := range lines {
+		if strings.HasPrefix(l, "+") || strings.HasPrefix(l, "-") {
+			return true
+		}
+	}
+	return false
+}
+
+// fixEmptyStarts adjusts the start lines of a piece of h whose old or new
+// range is empty: unified diff headers name the line before an empty range.
+// Starts derived from an already empty range of h need no adjustment.
+func fixEmptyStarts(piece *Hunk, h Hunk) {
+	if h.OldLines > 0 && piece.OldLines == 0 {
+		piece.OldStart--
+	}
+	if h.NewLines > 0 && piece.NewLines == 0 {
+		piece.NewStart--
+	}
+}
@@ -0,0 +1,141 @@
+package difflib_test
+
+import (
+	"testing"
+
+	difflib "github.com/njchilds90/go-difflib"
+)
+
+// twoChangeDiff returns a single-hunk diff with two changes separated by
+// two context lines.
+func twoChangeDiff() ([]string, []string, difflib.DiffResult) {
+	a := numbered(10)
+	b := numbered(10)
+	b[2] = "three\n"
+	b[5] = "six\n"
+	return a, b, difflib.UnifiedDiff(difflib.DiffInput{A: a, B: b, FromFile: "a", ToFile: "b"})
+}
+
+func TestHunkSplit(t *testing.T) {
+	a, b, d := twoChangeDiff()
+	if len(d.Hunks) != 1 {
+		t.Fatalf("expected a single hunk, got %d", len(d.Hunks))
+	}
+	h := d.Hunks[0]
+	// Lines: 2 context, -3 +3, 2 context, -6 +6, 3 context
+	first, second, err := h.Split(5)
+	if err != nil {
+		t.Fatalf("Split error: %v", err)
+	}
+	if first.OldStart != 1 || first.OldLines != 4 || second.OldStart != 5 || second.OldLines != 5 {
+		t.Errorf("headers = -%d,%d and -%d,%d; want -1,4 and -5,5",
+			first.OldStart, first.OldLines, second.OldStart, second.OldLines)
+	}
+	split := difflib.DiffResult{FromFile: "a", ToFile: "b", Hunks: []difflib.Hunk{first, second}}
+	got, err := difflib.ApplyPatch(a, split.String())
+	if err != nil {
+		t.Fatalf("applying split hunks: %v", err)
+	}
+	if difflib.JoinLines(got) != difflib.JoinLines(b) {
+		t.Errorf("split hunks produced %q", difflib.JoinLines(got))
+	}
+
+	onlyFirst := difflib.DiffResult{Hunks: []difflib.Hunk{first}}
+	got, err = difflib.ApplyPatch(a, onlyFirst.String())
+	if err != nil || got[2] != "three\n" || got[5] != "line 6\n" {
+		t.Errorf("applying first half alone: err=%v lines=%q", err, got)
+	}
+
+	for _, at := range []int{0, 2, len(h.Lines)} {
+		if _, _, err := h.Split(at); err == nil {
+			t.Errorf("Split(%d) should fail", at)
+		}
+	}
+}
+
+func TestHunkApply(t *testing.T) {
+	a := numbered(30)
+	b := append(append(append([]string(nil), a[:3]...), "new\n"), a[3:]...)
+	b[21] = "twenty-one\n"
+	d := difflib.UnifiedDiff(difflib.DiffInput{A: a, B: b})
+	if len(d.Hunks) != 2 {
+		t.Fatalf("expected two hunks, got %d", len(d.Hunks))
+	}
+	first, second := d.Hunks[0], d.Hunks[1]
+
+	// Skip the first hunk, then apply it after the second.
+	onlySecond, err := second.Apply(a)
+	if err != nil {
+		t.Fatalf("second.Apply: %v", err)
+	}
+	if want := difflib.JoinLines(a[:20]) + "twenty-one\n" + difflib.JoinLines(a[21:]); difflib.JoinLines(onlySecond) != want {
+		t.Errorf("second.Apply = %q", onlySecond)
+	}
+	got, err := first.Apply(onlySecond)
+	if err != nil || difflib.JoinLines(got) != difflib.JoinLines(b) {
+		t.Errorf("first.Apply after second = %q, %v", got, err)
+	}
+	// The second hunk is found one line later once the first is applied.
+	withFirst, _ := first.Apply(a)
+	if got, err := second.Apply(withFirst); err != nil || difflib.JoinLines(got) != difflib.JoinLines(b) {
+		t.Errorf("second.Apply after first = %q, %v", got, err)
+	}
+
+	if !first.CanApply(a) || first.CanApply(b) {
+		t.Errorf("CanApply = %v on a and %v on b, want true and false", first.CanApply(a), first.CanApply(b))
+	}
+	_, err = first.Apply(numbered(2))
+	if want := `difflib: hunk @@ -1,6 +1,7 @@ failed at line 3: expected "line 3\n", got end of input`; err == nil || err.Error() != want {
+		t.Errorf("Apply to short input: %v, want %s", err, want)
+	}
+}
+
+func TestHunkSplitAll(t *testing.T) {
+	_, _, d := twoChangeDiff()
+	parts := d.Hunks[0].SplitAll()
+	if len(parts) != 2 {
+		t.Fatalf("SplitAll returned %d hunks, want 2", len(parts))
+	}
+	if parts := parts[0].SplitAll(); len(parts) != 1 {
+		t.Errorf("hunk with one change block should not split, got %d", len(parts))
+	}
+}
+
+func TestMergeHunks(t *testing.T) {
+	a, b, d := twoChangeDiff()
+	first, second, err := d.Hunks[0].Split(6)
+	if err != nil {
+		t.Fatalf("Split error: %v", err)
+	}
+	merged, err := difflib.MergeHunks(first, second)
+	if err != nil {
+		t.Fatalf("MergeHunks error: %v", err)
+	}
+	if merged.OldStart != d.Hunks[0].OldStart || merged.OldLines != d.Hunks[0].OldLines ||
+		merged.NewLines != d.Hunks[0].NewLines || len(merged.Lines) != len(d.Hunks[0].Lines) {
+		t.Errorf("merged = %+v, want %+v", merged, d.Hunks[0])
+	}
+
+	// Overlapping context is de-duplicated.
+	overlapped := second
+	overlapped.OldStart--
+	overlapped.NewStart--
+	overlapped.OldLines++
+	overlapped.NewLines++
+	overlapped.Lines = append([]string{first.Lines[len(first.Lines)-1]}, second.Lines...)
+	merged, err = difflib.MergeHunks(first, overlapped)
+	if err != nil {
+		t.Fatalf("MergeHunks with overlap error: %v", err)
+	}
+	got, err := difflib.ApplyPatch(a, difflib.DiffResult{Hunks: []difflib.Hunk{merged}}.String())
+	if err != nil || difflib.JoinLines(got) != difflib.JoinLines(b) {
+		t.Errorf("merged overlapping hunk: err=%v got %q", err, difflib.JoinLines(got))
+	}
+
+	far := second
+	far.OldStart += 5
+	far.NewStart += 5
+	if _, err := difflib.MergeHunks(first, far); err == nil {
+		t.Error("expected error merging non-adjacent hunks")
+	}
+}
@@ -0,0 +1,97 @@
+package difflib
+
+import (
+	"path"
+	"strings"
+)
+
+// ignoreRule is one pattern line of a .gitignore file.
+type ignoreRule struct {
+	// base is the directory containing the .gitignore file, "." for the root.
+	base     string
+	pattern  string
+	negate   bool
+	dirOnly  bool
+	anchored bool
+}
+
+// parseGitignore parses the contents of the .gitignore file in directory base.
+func parseGitignore(base, data string) []ignoreRule {
+	var rules []ignoreRule
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimRight(line, " \t")
+		r := ignoreRule{base: base}
+		if rest, ok := strings.CutPrefix(line, "!"); ok {
+			r.negate, line = true, rest
+		} else if strings.HasPrefix(line, `\`) {
+			line = line[1:]
+		}
+		if rest, ok := strings.CutSuffix(line, "/"); ok {
+			r.dirOnly, line = true, rest
+		}
+		r.anchored = strings.Contains(line, "/")
+		r.pattern = strings.TrimPrefix(line, "/")
+		if r.pattern != "" {
+			rules = append(rules, r)
+		}
+	}
+	return rules
+}
+
+// ignoredBy reports whether the slash-separated path name is ignored by
+// rules; as in git, the last matching rule wins.
+func ignoredBy(rules []ignoreRule, name string, isDir bool) bool {
+	ignored := false
+	for _, r := range rules {
+		if r.dirOnly && !isDir {
+			continue
+		}
+		rel := name
+		if r.base != "." {
+			var ok bool
+			if rel, ok = strings.CutPrefix(name, r.base+"/"); !ok {
+				continue
+			}
+		}
+		if matchPathPattern(r.pattern, rel, r.anchored) {
+			ignored = !r.negate
+		}
+	}
+	return ignored
+}
+
+// matchPathPattern matches a glob against a slash-separated path. Anchored
+// patterns match the whole path, others match its last element. A "**"
+// element matches any number of path elements.
+func matchPathPattern(pattern, name string, anchored bool) bool {
+	if !anchored {
+		ok, _ := path.Match(pattern, path.Base(name))
+		return ok
+	}
+	return matchGlobElems(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+func matchGlobElems(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for k := 0; k <= len(name); k++ {
+				if matchGlobElems(pattern[1:], name[k:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], name[0]); !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
@@ -0,0 +1,83 @@
+package difflib
+
+import "strings"
+
+// IncrementalDiff keeps the unified diff of a fixed A against a B that only
+// grows at the end, such as a log being tailed or streamed model output. Each
+// Append re-diffs only the region from the last matching block onwards
+// instead of both inputs from scratch, so the cost of a chunk depends on
+// the unmatched tail rather than on everything received so far. The result
+// always turns A into the current B, but may differ from a full UnifiedDiff
+// where a later chunk would have favoured an earlier alignment.
+//
+// Example:
+//
+//	d := difflib.NewIncrementalDiff(difflib.DiffInput{A: original, FromFile: "a", ToFile: "b"})
+//	for chunk := range stream {
+//	    d.Append(chunk)
+//	    render(d.Result())
+//	}
+type IncrementalDiff struct {
+	input DiffInput
+	codes []OpCode
+}
+
+// NewIncrementalDiff starts an incremental diff of input.A against the
+// initial content input.B, which may be empty.
+func NewIncrementalDiff(input DiffInput) *IncrementalDiff {
+	input.B = append([]string(nil), input.B...)
+	d := &IncrementalDiff{input: input}
+	d.codes = input.matcher().GetOpCodes()
+	return d
+}
+
+// Append adds text to the end of B. text need not end in a newline; a
+// partial last line is continued by the next Append.
+func (d *IncrementalDiff) Append(text string) {
+	if text == "" {
+		return
+	}
+	b := d.input.B
+	// Lines of the old B that the append cannot change.
+	stable := len(b)
+	if n := len(b); n > 0 && !strings.HasSuffix(b[n-1], "\n") {
+		text = b[n-1] + text
+		b = b[:n-1]
+		stable--
+	}
+	d.input.B = append(b, SplitLines(text)...)
+
+	// Keep the opcodes before the last equal block within the stable
+	// lines, and re-diff from the start of that block so it can realign.
+	k := len(d.codes)
+	for k > 0 && (d.codes[k-1].Tag != OpEqual || d.codes[k-1].J1 >= stable) {
+		k--
+	}
+	i0, j0 := 0, 0
+	if k > 0 {
+		k--
+		i0, j0 = d.codes[k].I1, d.codes[k].J1
+	}
+	m := NewMatcher(d.input.A[i0:], d.input.B[j0:])
+	m.SetMaxComparisons(d.input.MaxComparisons)
+	codes := d.codes[:k]
+	for _, c := range m.GetOpCodes() {
+		codes = append(codes, OpCode{c.Tag, c.I1 + i0, c.I2 + i0, c.J1 + j0, c.J2 + j0})
+	}
+	d.codes = mergeEdits(codes)
+}
+
+// B returns the content of B so far, as lines.
+func (d *IncrementalDiff) B() []string {
+	return d.input.B
+}
+
+// OpCodes returns the opcodes that turn A into the current B.
+func (d *IncrementalDiff) OpCodes() []OpCode {
+	return append([]OpCode(nil), d.codes...)
+}
+
+// Result returns the unified diff of A against the current B.
+func (d *IncrementalDiff) Result() DiffResult {
+	return unifiedFromOpCodes(d.input, d.codes)
+}
@@ -0,0 +1,78 @@
+package difflib_test
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+
+	difflib "github.com/njchilds90/go-difflib"
+)
+
+func TestIncrementalDiff(t *testing.T) {
+	rng := rand.New(rand.NewSource(9))
+	for iter := 0; iter < 50; iter++ {
+		a := randomLines(rng, 30)
+		var b strings.Builder
+		for _, l := range a {
+			switch rng.Intn(6) {
+			case 0: // drop
+			case 1:
+				b.WriteString("new " + l)
+			default:
+				b.WriteString(l)
+			}
+		}
+		full := b.String()
+		if rng.Intn(2) == 0 {
+			full = strings.TrimSuffix(full, "\n")
+		}
+
+		d := difflib.NewIncrementalDiff(difflib.DiffInput{A: a, FromFile: "a", ToFile: "b"})
+		for pos := 0; pos < len(full); {
+			n := min(1+rng.Intn(12), len(full)-pos)
+			d.Append(full[pos : pos+n])
+			pos += n
+
+			want := difflib.SplitLines(full[:pos])
+			if !reflect.DeepEqual(d.B(), want) {
+				t.Fatalf("B() = %q, want %q", d.B(), want)
+			}
+			patch := d.Result().String()
+			got, err := difflib.ApplyPatch(a, patch)
+			if err != nil || !reflect.DeepEqual(got, want) {
+				t.Fatalf("after %d bytes the diff does not produce B: %v\n%s", pos, err, patch)
+			}
+		}
+	}
+}
+
+func TestIncrementalDiffMatchesFullDiff(t *testing.T) {
+	a := difflib.SplitLines("one\ntwo\nthree\nfour\nfive\n")
+	d := difflib.NewIncrementalDiff(difflib.DiffInput{A: a, B: difflib.SplitLines("one\n")})
+	for _, chunk := range []string{"tw", "o\nTHREE\n", "four", "\n"} {
+		d.Append(chunk)
+	}
+	want := difflib.GetOpCodes(a, difflib.SplitLines("one\ntwo\nTHREE\nfour\n"))
+	if got := d.OpCodes(); !reflect.DeepEqual(got, want) {
+		t.Errorf("OpCodes() = %v, want %v", got, want)
+	}
+}
+
+func ExampleIncrementalDiff() {
+	original := difflib.SplitLines("Hello,\nworld!\nBye.\n")
+	d := difflib.NewIncrementalDiff(difflib.DiffInput{A: original, FromFile: "draft", ToFile: "stream"})
+	for _, chunk := range []string{"Hello,\nwor", "ld?\n", "Bye.\n"} {
+		d.Append(chunk)
+	}
+	fmt.Print(d.Result())
+	// Output:
+	// --- draft
+	// +++ stream
+	// @@ -1,3 +1,3 @@
+	//  Hello,
+	// -world!
+	// +world?
+	//  Bye.
+}
@@ -0,0 +1,215 @@
+package difflib
+
+// IndentHeuristic slides each deletion or insertion that can move along
+// the equal lines around it to the position git's indent heuristic prefers,
+// so that hunks start and end at natural boundaries: a block added between
+// two functions that begin and end alike is shown as one whole function
+// rather than starting mid-body. Each position is scored by the
+// indentation and blank lines where the change meets its context. Blocks
+// slide at most 100 lines, and the result transforms a into b with the
+// same number of changed lines.
+//
+// Example:
+//
+//	codes := difflib.IndentHeuristic(a, b, difflib.GetOpCodes(a, b))
+func IndentHeuristic(a, b []string, codes []OpCode) []OpCode {
+	// Empty equalities at both ends give every change two neighbours.
+	codes = append(append([]OpCode{{OpEqual, 0, 0, 0, 0}}, codes...),
+		OpCode{OpEqual, len(a), len(a), len(b), len(b)})
+	for k := 1; k < len(codes)-1; k++ {
+		c := codes[k]
+		if (c.Tag != OpDelete && c.Tag != OpInsert) || codes[k-1].Tag != OpEqual || codes[k+1].Tag != OpEqual {
+			continue
+		}
+		lines, start, end := a, c.I1, c.I2
+		if c.Tag == OpInsert {
+			lines, start, end = b, c.J1, c.J2
+		}
+		// The change may slide over the equal lines on either side.
+		lo := start - (codes[k-1].I2 - codes[k-1].I1)
+		hi := end + (codes[k+1].I2 - codes[k+1].I1)
+		shift := bestIndentShift(lines, start, end, lo, hi)
+		if shift == 0 {
+			continue
+		}
+		codes[k].I1, codes[k].I2 = c.I1+shift, c.I2+shift
+		codes[k].J1, codes[k].J2 = c.J1+shift, c.J2+shift
+		codes[k-1].I2 += shift
+		codes[k-1].J2 += shift
+		codes[k+1].I1 += shift
+		codes[k+1].J1 += shift
+	}
+	out := codes[:0]
+	for _, c := range codes {
+		if c.I1 != c.I2 || c.J1 != c.J2 {
+			out = append(out, c)
+		}
+	}
+	return mergeEdits(out)
+}
+
+// Scoring constants of git's indent heuristic (xdiff/xdiffi.c).
+const (
+	indentMaxSliding = 100
+	indentMax        = 200
+	indentMaxBlanks  = 20
+
+	startOfFilePenalty              = 1
+	endOfFilePenalty                = 21
+	totalBlankWeight                = -30
+	postBlankWeight                 = 6
+	relativeIndentPenalty           = -4
+	relativeIndentWithBlankPenalty  = 10
+	relativeOutdentPenalty          = 24
+	relativeOutdentWithBlankPenalty = 17
+	relativeDedentPenalty           = 23
+	relativeDedentWithBlankPenalty  = 17
+	indentWeight                    = 60
+)
+
+// bestIndentShift returns how far to move the changed lines [start, end)
+// of lines, which may slide anywhere within [lo, hi) where the lines they
+// pass over are equal, to reach the best scoring position.
+func bestIndentShift(lines []string, start, end, lo, hi int) int {
+	size := end - start
+	s, e := start, end
+	for s > lo && lines[s-1] == lines[e-1] {
+		s, e = s-1, e-1
+	}
+	earliestEnd := e
+	for e < hi && lines[s] == lines[e] {
+		s, e = s+1, e+1
+	}
+	if e == earliestEnd {
+		return 0
+	}
+	first := max(earliestEnd, e-size-1, e-indentMaxSliding)
+	best, bestScore := -1, splitScore{}
+	for shift := first; shift <= e; shift++ {
+		var score splitScore
+		score.add(measureSplit(lines, shift))
+		score.add(measureSplit(lines, shift-size))
+		if best == -1 || score.cmp(bestScore) <= 0 {
+			best, bestScore = shift, score
+		}
+	}
+	return best - end
+}
+
+// splitMeasurement describes the lines around a split between two lines.
+type splitMeasurement struct {
+	endOfFile  bool
+	indent     int // of the line after the split, or -1 if it is blank
+	preBlank   int // blank lines before the split
+	preIndent  int // of the first non-blank line before, or -1
+	postBlank  int // blank lines after the line after the split
+	postIndent int // of the first non-blank line after those, or -1
+}
+
+// measureSplit measures the split before lines[split].
+func measureSplit(lines []string, split int) splitMeasurement {
+	m := splitMeasurement{indent: -1, preIndent: -1, postIndent: -1}
+	if split >= len(lines) {
+		m.endOfFile = true
+	} else {
+		m.indent = gitIndent(lines[split])
+	}
+	for i := split - 1; i >= 0; i-- {
+		if m.preIndent = gitIndent(lines[i]); m.preIndent != -1 {
+			break
+		}
+		if m.preBlank++; m.preBlank == indentMaxBlanks {
+			m.preIndent = 0
+			break
+		}
+	}
+	for i := split + 1; i < len(lines); i++ {
+		if m.postIndent = gitIndent(lines[i]); m.postIndent != -1 {
+			break
+		}
+		if m.postBlank++; m.postBlank == indentMaxBlanks {
+			m.postIndent = 0
+			break
+		}
+	}
+	return m
+}
+
+// gitIndent returns the indentation of line as git's heuristic measures
+// it, with tabs advancing to the next multiple of eight and capped at
+// indentMax, or -1 if line is blank.
+func gitIndent(line string) int {
+	n := 0
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case ' ':
+			n++
+		case '\t':
+			n += 8 - n%8
+		case '\n', '\r', '\v', '\f':
+		default:
+			return n
+		}
+		if n >= indentMax {
+			return indentMax
+		}
+	}
+	return -1
+}
+
+// splitScore is the score of a pair of splits; lower is better.
+type splitScore struct {
+	effectiveIndent int
+	penalty         int
+}
+
+// add adds the score of the split m.
+func (s *splitScore) add(m splitMeasurement) {
+	if m.preIndent == -1 && m.preBlank == 0 {
+		s.penalty += startOfFilePenalty
+	}
+	if m.endOfFile {
+		s.penalty += endOfFilePenalty
+	}
+	postBlank := 0
+	if m.indent == -1 {
+		postBlank = 1 + m.postBlank
+	}
+	totalBlank := m.preBlank + postBlank
+	s.penalty += totalBlankWeight*totalBlank + postBlankWeight*postBlank
+	indent := m.indent
+	if indent == -1 {
+		indent = m.postIndent
+	}
+	anyBlanks := totalBlank != 0
+	s.effectiveIndent += indent
+	switch {
+	case indent == -1 || m.preIndent == -1 || indent == m.preIndent:
+	case indent > m.preIndent:
+		s.penalty += pick(anyBlanks, relativeIndentWithBlankPenalty, relativeIndentPenalty)
+	case m.postIndent != -1 && m.postIndent > indent:
+		s.penalty += pick(anyBlanks, relativeOutdentWithBlankPenalty, relativeOutdentPenalty)
+	default:
+		s.penalty += pick(anyBlanks, relativeDedentWithBlankPenalty, relativeDedentPenalty)
+	}
+}
+
+// cmp compares two scores, returning a negative number if s is better.
+func (s splitScore) cmp(t splitScore) int {
+	c := 0
+	switch {
+	case s.effectiveIndent > t.effectiveIndent:
+		c = 1
+	case s.effectiveIndent < t.effectiveIndent:
+		c = -1
+	}
+	return indentWeight*c + s.penalty - t.penalty
+}
+
+// pick returns yes if cond holds and no otherwise.
+func pick(cond bool, yes, no int) int {
+	if cond {
+		return yes
+	}
+	return no
+}
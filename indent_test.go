@@ -0,0 +1,80 @@
+package difflib_test
+
+import (
+	"math/rand"
+	"testing"
+
+	difflib "github.com/njchilds90/go-difflib"
+)
+
+func TestIndentHeuristic(t *testing.T) {
+	a := difflib.SplitLines("func a() {\n\treturn\n\tx++\n\tx++\n}\n\nfunc b() {\n\treturn\n}\n\n" +
+		"func c() {\n\tx++\n}\n\nfunc d() {\n\tx++\n\tx++\n\treturn\n}\n\n")
+	b := difflib.SplitLines("func a() {\n\treturn\n\tx++\n\tx++\n}\n\nfunc b() {\n\treturn\n}\n\nfunc n() {\n\treturn\n}\n\n" +
+		"func c() {\n\tx++\n}\n\nfunc d() {\n\tx++\n\tx++\n\treturn\n}\n\n")
+	tests := []struct {
+		name string
+		opts []difflib.Option
+		want string
+	}{
+		{"plain", nil, "@@ -7,0 +8,4 @@\n+\treturn\n+}\n+\n+func n() {\n"},
+		{"indent heuristic", []difflib.Option{difflib.WithIndentHeuristic()}, "@@ -10,0 +11,4 @@\n+func n() {\n+\treturn\n+}\n+\n"},
+	}
+	for _, tt := range tests {
+		d := difflib.Diff(a, b, append(tt.opts, difflib.WithContext(0))...)
+		if got := hunksString(d); got != tt.want {
+			t.Errorf("%s: got\n%s\nwant\n%s", tt.name, got, tt.want)
+		}
+	}
+	input := difflib.DiffInput{A: a, B: b, Context: difflib.NoContext, IndentHeuristic: true}
+	if got := hunksString(difflib.UnifiedDiff(input)); got != tests[1].want {
+		t.Errorf("UnifiedDiff with IndentHeuristic:\n%s", got)
+	}
+}
+
+func TestIndentHeuristicRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	lines := []string{"\n", "}\n", "\tx\n", "\t\ty\n", "func f() {\n"}
+	pick := func(n int) []string {
+		out := make([]string, n)
+		for i := range out {
+			out[i] = lines[rng.Intn(len(lines))]
+		}
+		return out
+	}
+	changed := func(codes []difflib.OpCode) (n int) {
+		for _, c := range codes {
+			if c.Tag != difflib.OpEqual {
+				n += c.I2 - c.I1 + c.J2 - c.J1
+			}
+		}
+		return n
+	}
+	for iter := 0; iter < 500; iter++ {
+		a, b := pick(rng.Intn(30)), pick(rng.Intn(30))
+		codes := difflib.GetOpCodes(a, b)
+		slid := difflib.IndentHeuristic(a, b, codes)
+		if changed(slid) != changed(codes) {
+			t.Fatalf("iteration %d: %d changed lines, want %d", iter, changed(slid), changed(codes))
+		}
+		var got []string
+		i, j := 0, 0
+		for _, c := range slid {
+			if c.I1 != i || c.J1 != j {
+				t.Fatalf("iteration %d: opcodes %v are not contiguous", iter, slid)
+			}
+			i, j = c.I2, c.J2
+			if c.Tag == difflib.OpEqual {
+				if difflib.JoinLines(a[c.I1:c.I2]) != difflib.JoinLines(b[c.J1:c.J2]) {
+					t.Fatalf("iteration %d: %v is not equal", iter, c)
+				}
+				got = append(got, a[c.I1:c.I2]...)
+				continue
+			}
+			got = append(got, b[c.J1:c.J2]...)
+		}
+		if difflib.JoinLines(got) != difflib.JoinLines(b) {
+			t.Fatalf("iteration %d: opcodes %v do not produce b", iter, slid)
+		}
+	}
+}
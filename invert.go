@@ -0,0 +1,102 @@
+package difflib
+
+import "strings"
+
+// Invert returns the reverse of the hunk: it turns the new side back into
+// the old side. Within each run of changes the removed lines are listed
+// before the added ones, as UnifiedDiff emits them.
+func (h Hunk) Invert() Hunk {
+	inv := Hunk{
+		OldStart: h.NewStart,
+		OldLines: h.NewLines,
+		NewStart: h.OldStart,
+		NewLines: h.OldLines,
+		Lines:    make([]string, 0, len(h.Lines)),
+	}
+	var dels, adds []string
+	flush := func() {
+		inv.Lines = append(inv.Lines, dels...)
+		inv.Lines = append(inv.Lines, adds...)
+		dels, adds = dels[:0], adds[:0]
+	}
+	for _, l := range h.Lines {
+		switch {
+		case strings.HasPrefix(l, "+"):
+			dels = append(dels, "-"+l[1:])
+		case strings.HasPrefix(l, "-"):
+			adds = append(adds, "+"+l[1:])
+		default:
+			flush()
+			inv.Lines = append(inv.Lines, l)
+		}
+	}
+	flush()
+	return inv
+}
+
+// Invert returns the reverse diff, which transforms ToFile back into
+// FromFile. Applying d and then d.Invert() restores the original input.
+//
+// Example:
+//
+//	undo := result.Invert()
+//	original, err := difflib.ApplyPatch(modified, undo.String())
+func (d DiffResult) Invert() DiffResult {
+	inv := DiffResult{FromFile: d.ToFile, ToFile: d.FromFile, FromDate: d.ToDate, ToDate: d.FromDate}
+	for _, h := range d.Hunks {
+		inv.Hunks = append(inv.Hunks, h.Invert())
+	}
+	return inv
+}
+
+// Invert returns the reverse of the file diff, swapping names, modes and
+// creation/deletion status along with the hunks. Git's "a/" and "b/" name
+// prefixes stay on the old and new side respectively.
+func (f FileDiff) Invert() FileDiff {
+	inv := f
+	inv.DiffResult = f.DiffResult.Invert()
+	inv.OldName, inv.NewName = swapPrefixed(f.OldName, f.NewName)
+	if inv.FromFile != devNull && inv.ToFile != devNull {
+		inv.FromFile, inv.ToFile = swapPrefixed(f.FromFile, f.ToFile)
+	} else if inv.FromFile != devNull {
+		inv.FromFile = inv.OldName
+	} else if inv.ToFile != devNull {
+		inv.ToFile = inv.NewName
+	}
+	inv.NewFile, inv.DeletedFile = f.DeletedFile, f.NewFile
+	inv.OldMode, inv.NewMode = f.NewMode, f.OldMode
+	if from, to, ok := strings.Cut(f.Index, ".."); ok {
+		hash, mode, hasMode := strings.Cut(to, " ")
+		inv.Index = hash + ".." + from
+		if hasMode {
+			inv.Index += " " + mode
+		}
+	}
+	return inv
+}
+
+// swapPrefixed swaps two names, keeping git's "a/" and "b/" prefixes in place
+// when both names carry them.
+func swapPrefixed(oldName, newName string) (string, string) {
+	oldRest, okOld := strings.CutPrefix(oldName, "a/")
+	newRest, okNew := strings.CutPrefix(newName, "b/")
+	if okOld && okNew {
+		return "a/" + newRest, "b/" + oldRest
+	}
+	return newName, oldName
+}
+
+// Invert returns a patch set that undoes p.
+//
+// Example:
+//
+//	ps, _ := difflib.ParsePatchSet(patch)
+//	revert := ps.Invert()
+//	fmt.Print(revert.String())
+func (p *PatchSet) Invert() *PatchSet {
+	inv := &PatchSet{Files: make([]FileDiff, 0, len(p.Files))}
+	for _, f := range p.Files {
+		inv.Files = append(inv.Files, f.Invert())
+	}
+	return inv
+}
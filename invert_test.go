@@ -0,0 +1,72 @@
+package difflib_test
+
+import (
+	"testing"
+
+	difflib "github.com/njchilds90/go-difflib"
+)
+
+func TestDiffResultInvert(t *testing.T) {
+	a := numbered(30)
+	b := numbered(30)
+	b[1] = "two\n"
+	b = append(b[:10], b[12:]...)
+	b = append(b, "thirty-one\n")
+	d := difflib.UnifiedDiff(difflib.DiffInput{A: a, B: b, FromFile: "a", ToFile: "b"})
+
+	inv := d.Invert()
+	if inv.FromFile != "b" || inv.ToFile != "a" {
+		t.Errorf("labels = %q, %q; want b, a", inv.FromFile, inv.ToFile)
+	}
+	want := difflib.UnifiedDiff(difflib.DiffInput{A: b, B: a, FromFile: "b", ToFile: "a"})
+	if inv.String() != want.String() {
+		t.Errorf("Invert() =\n%s\nwant\n%s", inv.String(), want.String())
+	}
+	restored, err := difflib.ApplyPatch(b, inv.String())
+	if err != nil {
+		t.Fatalf("applying inverted patch: %v", err)
+	}
+	if difflib.JoinLines(restored) != difflib.JoinLines(a) {
+		t.Error("inverted patch did not restore the original")
+	}
+	if d.Invert().Invert().String() != d.String() {
+		t.Error("double inversion should be the identity")
+	}
+}
+
+func TestPatchSetInvert(t *testing.T) {
+	ps, err := difflib.ParsePatchSet(gitPatch)
+	if err != nil {
+		t.Fatalf("ParsePatchSet error: %v", err)
+	}
+	inv := ps.Invert()
+	tests := []struct {
+		i                int
+		oldName, newName string
+		newFile, deleted bool
+		index            string
+	}{
+		{0, "a/main.go", "b/main.go", false, false, "bf269f4..83db48f 100644"},
+		{1, "a/new.txt", "b/new.txt", false, true, "3b18e51..0000000"},
+		{2, "a/old.txt", "b/old.txt", true, false, "0000000..3b18e51"},
+		{3, "a/docs/c d.md", "b/docs/a b.md", false, false, ""},
+		{4, "a/to.txt", "b/from.txt", false, false, "2222222..1111111 100644"},
+	}
+	for _, tt := range tests {
+		f := inv.Files[tt.i]
+		if f.OldName != tt.oldName || f.NewName != tt.newName || f.NewFile != tt.newFile ||
+			f.DeletedFile != tt.deleted || f.Index != tt.index {
+			t.Errorf("file %d: got %+v", tt.i, f)
+		}
+	}
+	if inv.Files[1].NewMode != "" || inv.Files[1].OldMode != "100755" {
+		t.Errorf("modes not swapped: old=%q new=%q", inv.Files[1].OldMode, inv.Files[1].NewMode)
+	}
+	reparsed, err := difflib.ParsePatchSet(inv.String())
+	if err != nil {
+		t.Fatalf("reparsing inverted patch: %v", err)
+	}
+	if reparsed.Invert().String() != ps.String() {
+		t.Errorf("inverting twice should round trip:\n%s\nvs\n%s", reparsed.Invert().String(), ps.String())
+	}
+}
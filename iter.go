@@ -0,0 +1,99 @@
+//go:build go1.23
+
+package difflib
+
+import (
+	"errors"
+	"iter"
+)
+
+// errStopIter ends a walk when the consumer of an iterator stops early.
+var errStopIter = errors.New("difflib: iteration stopped")
+
+// AllHunks returns an iterator over the hunks of d, in order. It is named
+// AllHunks because Hunks is the field that holds them. A DiffResult holds
+// hunks that are already computed, so this only ranges over them; use
+// Matcher.Hunks to build hunks one at a time while the diff is consumed.
+//
+// Example:
+//
+//	for h := range d.AllHunks() {
+//	    fmt.Printf("-%d +%d\n", h.OldStart, h.NewStart)
+//	}
+func (d DiffResult) AllHunks() iter.Seq[Hunk] {
+	return func(yield func(Hunk) bool) {
+		for _, h := range d.Hunks {
+			if !yield(h) {
+				return
+			}
+		}
+	}
+}
+
+// OpCodes returns an iterator over the opcodes that turn the first sequence
+// into the second, as GetOpCodes returns them. The opcodes are produced one
+// at a time from the matching blocks, so stopping early skips building the
+// rest of them.
+//
+// Example:
+//
+//	for op := range difflib.NewMatcher(a, b).OpCodes() {
+//	    if op.Tag != difflib.OpEqual {
+//	        fmt.Println("first change at line", op.I1+1)
+//	        break
+//	    }
+//	}
+func (m *Matcher) OpCodes() iter.Seq[OpCode] {
+	return func(yield func(OpCode) bool) {
+		walkBlocks(m.GetMatchingBlocks(), func(c OpCode) error {
+			if !yield(c) {
+				return errStopIter
+			}
+			return nil
+		})
+	}
+}
+
+// Hunks returns an iterator over the hunks of a unified diff of the
+// matcher's sequences, as UnifiedDiff builds them. context has the meaning
+// of DiffInput.Context: zero selects the default of 3 and NoContext selects
+// none. Opcodes are grouped as they are produced and each hunk's lines are
+// built only when it is reached, so stopping early skips the work for the
+// rest of the diff.
+//
+// Example:
+//
+//	for h := range difflib.NewMatcher(a, b).Hunks(3) {
+//	    fmt.Printf("first change near line %d\n", h.OldStart)
+//	    break
+//	}
+func (m *Matcher) Hunks(context int) iter.Seq[Hunk] {
+	return func(yield func(Hunk) bool) {
+		ctx := contextLines(context)
+		var group []OpCode
+		first := true
+		for c := range m.OpCodes() {
+			if c.Tag == OpEqual && first {
+				// Leading unchanged lines only give context to the first hunk.
+				c = OpCode{OpEqual, max(c.I1, c.I2-ctx), c.I2, max(c.J1, c.J2-ctx), c.J2}
+			}
+			first = false
+			if c.Tag == OpEqual && c.I2-c.I1 > ctx*2 {
+				group = append(group, OpCode{OpEqual, c.I1, c.I1 + ctx, c.J1, c.J1 + ctx})
+				if !yield(buildHunk(m.a, m.b, group)) {
+					return
+				}
+				group = group[:0]
+				c = OpCode{OpEqual, c.I2 - ctx, c.I2, c.J2 - ctx, c.J2}
+			}
+			group = append(group, c)
+		}
+		if len(group) == 0 || len(group) == 1 && group[0].Tag == OpEqual {
+			return
+		}
+		if c := &group[len(group)-1]; c.Tag == OpEqual {
+			c.I2, c.J2 = min(c.I2, c.I1+ctx), min(c.J2, c.J1+ctx)
+		}
+		yield(buildHunk(m.a, m.b, group))
+	}
+}
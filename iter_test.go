@@ -0,0 +1,87 @@
+//go:build go1.23
+
+package difflib_test
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	difflib "github.com/njchilds90/go-difflib"
+)
+
+func TestMatcherOpCodes(t *testing.T) {
+	rng := rand.New(rand.NewSource(5))
+	for n := 0; n < 30; n++ {
+		a, b := randomLines(rng, rng.Intn(30)), randomLines(rng, rng.Intn(30))
+		m := difflib.NewMatcher(a, b)
+		var got []difflib.OpCode
+		for op := range m.OpCodes() {
+			got = append(got, op)
+		}
+		if want := m.GetOpCodes(); !reflect.DeepEqual(got, want) {
+			t.Fatalf("OpCodes yielded %v, want %v", got, want)
+		}
+	}
+}
+
+func TestMatcherHunks(t *testing.T) {
+	rng := rand.New(rand.NewSource(6))
+	for n := 0; n < 200; n++ {
+		a, b := randomLines(rng, rng.Intn(40)), randomLines(rng, rng.Intn(40))
+		for _, ctx := range []int{0, difflib.NoContext, 1, 5} {
+			var got []difflib.Hunk
+			for h := range difflib.NewMatcher(a, b).Hunks(ctx) {
+				got = append(got, h)
+			}
+			if want := difflib.UnifiedDiff(difflib.DiffInput{A: a, B: b, Context: ctx}).Hunks; !reflect.DeepEqual(got, want) {
+				t.Fatalf("context %d: Hunks yielded %v, want %v", ctx, got, want)
+			}
+		}
+	}
+}
+
+func TestIteratorsStop(t *testing.T) {
+	a := difflib.SplitLines("a\nb\nc\nd\ne\nf\ng\nh\ni\nj\n")
+	b := difflib.SplitLines("A\nb\nc\nd\ne\nf\ng\nh\ni\nJ\n")
+	n := 0
+	for op := range difflib.NewMatcher(a, b).OpCodes() {
+		n++
+		if op.Tag != difflib.OpEqual {
+			break
+		}
+	}
+	if n != 1 {
+		t.Errorf("OpCodes yielded %d opcodes before break, want 1", n)
+	}
+	d := difflib.UnifiedDiff(difflib.DiffInput{A: a, B: b, Context: 1})
+	n = 0
+	for range d.AllHunks() {
+		n++
+		break
+	}
+	if n != 1 || len(d.Hunks) != 2 {
+		t.Errorf("AllHunks yielded %d hunks of %d before break, want 1 of 2", n, len(d.Hunks))
+	}
+	n = 0
+	for range difflib.NewMatcher(a, b).Hunks(1) {
+		n++
+		break
+	}
+	if n != 1 {
+		t.Errorf("Hunks yielded %d hunks before break, want 1", n)
+	}
+}
+
+func ExampleDiffResult_AllHunks() {
+	a := difflib.SplitLines("a\nb\nc\nd\ne\nf\ng\nh\ni\nj\n")
+	b := difflib.SplitLines("A\nb\nc\nd\ne\nf\ng\nh\ni\nJ\n")
+	d := difflib.UnifiedDiff(difflib.DiffInput{A: a, B: b, Context: 1})
+	for h := range d.AllHunks() {
+		fmt.Printf("-%d,%d +%d,%d\n", h.OldStart, h.OldLines, h.NewStart, h.NewLines)
+	}
+	// Output:
+	// -1,2 +1,2
+	// -9,2 +9,2
+}
@@ -0,0 +1,93 @@
+package difflib_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	difflib "github.com/njchilds90/go-difflib"
+)
+
+func TestDiffResultJSON(t *testing.T) {
+	d := difflib.UnifiedDiff(difflib.DiffInput{
+		A:        difflib.SplitLines("a\nb\n"),
+		B:        difflib.SplitLines("a\nc\n"),
+		FromFile: "old",
+		ToFile:   "new",
+	})
+	data, err := json.Marshal(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"from_file":"old","to_file":"new","hunks":[{"old_start":1,"old_lines":2,"new_start":1,"new_lines":2,"lines":[" a\n","-b\n","+c\n"]}]}`
+	if string(data) != want {
+		t.Errorf("Marshal() = %s\nwant %s", data, want)
+	}
+	var back difflib.DiffResult
+	if err := json.Unmarshal(data, &back); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(back, d) {
+		t.Errorf("round trip = %+v, want %+v", back, d)
+	}
+}
+
+func TestOpCodeJSON(t *testing.T) {
+	codes := difflib.GetOpCodes(difflib.SplitLines("a\nb\n"), difflib.SplitLines("a\nc\nd\n"))
+	data, err := json.Marshal(codes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `[{"tag":"equal","i1":0,"i2":1,"j1":0,"j2":1},{"tag":"replace","i1":1,"i2":2,"j1":1,"j2":3}]`
+	if string(data) != want {
+		t.Errorf("Marshal() = %s\nwant %s", data, want)
+	}
+	var back []difflib.OpCode
+	if err := json.Unmarshal(data, &back); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(back, codes) {
+		t.Errorf("round trip = %+v, want %+v", back, codes)
+	}
+}
+
+func TestOpText(t *testing.T) {
+	for op := difflib.OpEqual; op <= difflib.OpMoveTo; op++ {
+		text, err := op.MarshalText()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var back difflib.Op
+		if err := back.UnmarshalText(text); err != nil || back != op {
+			t.Errorf("UnmarshalText(%q) = %v, %v; want %v", text, back, err, op)
+		}
+	}
+	if _, err := difflib.Op(42).MarshalText(); err == nil {
+		t.Error("MarshalText(42) succeeded")
+	}
+	var op difflib.Op
+	if err := json.Unmarshal([]byte(`"bogus"`), &op); err == nil {
+		t.Error(`Unmarshal("bogus") succeeded`)
+	}
+}
+
+func TestPatchSetJSON(t *testing.T) {
+	ps, err := difflib.ParsePatchSet(gitPatch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(ps)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var back difflib.PatchSet
+	if err := json.Unmarshal(data, &back); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(&back, ps) {
+		t.Errorf("round trip mismatch:\n%s", data)
+	}
+	if back.String() != ps.String() {
+		t.Error("round trip changes the rendered patch")
+	}
+}
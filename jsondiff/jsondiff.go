@@ -0,0 +1,466 @@
+// Package jsondiff compares JSON documents structurally: object key order,
+// insignificant whitespace and the spelling of numbers (1.0, 1, 1e0) do not
+// count as differences.
+//
+// Compare lists the added, removed and changed values by JSON Pointer path.
+// Unified renders both documents in a normalized form (sorted keys, two-space
+// indentation) and returns their unified diff, with each hunk header
+// annotated with the path of its first change. CreateMergePatch and
+// ApplyMergePatch generate and apply RFC 7386 JSON Merge Patch documents.
+//
+// Basic usage:
+//
+//	changes, err := jsondiff.Compare(oldJSON, newJSON)
+//	for _, c := range changes {
+//		fmt.Println(c) // ~ /server/port: 8080 -> 9090
+//	}
+//
+//	diff, err := jsondiff.Unified(oldJSON, newJSON, jsondiff.Options{})
+package jsondiff
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+
+	difflib "github.com/njchilds90/go-difflib"
+)
+
+// Kind is the kind of a Change.
+type Kind string
+
+// Change kinds.
+const (
+	Added   Kind = "added"
+	Removed Kind = "removed"
+	Changed Kind = "changed"
+)
+
+// Change is one difference between two JSON documents.
+type Change struct {
+	// Kind says whether the value was added, removed or changed.
+	Kind Kind `json:"kind"`
+	// Path is the RFC 6901 JSON Pointer of the value: "" for the whole
+	// document, "/users/0/name" for a nested one. Removed array elements
+	// are indexed as in the old document, all other paths as in the new one.
+	Path string `json:"path"`
+	// Old is the compact normalized JSON of the old value; empty for Added.
+	Old json.RawMessage `json:"old,omitempty"`
+	// New is the compact normalized JSON of the new value; empty for Removed.
+	New json.RawMessage `json:"new,omitempty"`
+}
+
+// String formats the change on one line, prefixed with "+", "-" or "~".
+func (c Change) String() string {
+	switch c.Kind {
+	case Added:
+		return fmt.Sprintf("+ %s: %s", c.Path, c.New)
+	case Removed:
+		return fmt.Sprintf("- %s: %s", c.Path, c.Old)
+	}
+	return fmt.Sprintf("~ %s: %s -> %s", c.Path, c.Old, c.New)
+}
+
+// Options configures Unified. The zero value is ready to use.
+type Options struct {
+	// FromFile and ToFile label the two documents in the diff header.
+	// Defaults: "a" and "b".
+	FromFile, ToFile string
+	// Context is the number of unchanged lines around each change.
+	// Default: 3. Use difflib.NoContext for none.
+	Context int
+}
+
+// Compare parses two JSON documents and returns their differences in
+// document order, with object keys visited in sorted order. Arrays are
+// matched with difflib's sequence matcher, so an element inserted at the
+// front is reported as one addition rather than a change to every element.
+//
+// Example:
+//
+//	changes, _ := jsondiff.Compare([]byte(`{"a":1,"b":[1,2]}`), []byte(`{"b":[1,2,3],"a":1.0}`))
+//	// changes: [{added /b/2 3}]
+func Compare(a, b []byte) ([]Change, error) {
+	va, vb, err := parseBoth(a, b)
+	if err != nil {
+		return nil, err
+	}
+	var changes []Change
+	compare("", va, vb, &changes)
+	return changes, nil
+}
+
+// Unified parses two JSON documents and returns the unified diff of their
+// normalized forms, or "" if they are structurally equal. Each hunk header
+// ends with the JSON Pointer of the first changed line in the hunk, the way
+// git shows the enclosing function:
+//
+//	@@ -3,7 +3,7 @@ /server/port
+//
+// Example:
+//
+//	diff, err := jsondiff.Unified(oldJSON, newJSON, jsondiff.Options{FromFile: "old.json", ToFile: "new.json"})
+func Unified(a, b []byte, opts Options) (string, error) {
+	va, vb, err := parseBoth(a, b)
+	if err != nil {
+		return "", err
+	}
+	if opts.FromFile == "" {
+		opts.FromFile = "a"
+	}
+	if opts.ToFile == "" {
+		opts.ToFile = "b"
+	}
+	la, lb := layout(va), layout(vb)
+	d := difflib.UnifiedDiff(difflib.DiffInput{
+		A:        la.texts(),
+		B:        lb.texts(),
+		FromFile: opts.FromFile,
+		ToFile:   opts.ToFile,
+		Context:  opts.Context,
+	})
+	if len(d.Hunks) == 0 {
+		return "", nil
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", d.FromFile, d.ToFile)
+	for _, h := range d.Hunks {
+		fmt.Fprintf(&sb, "@@ -%d,%d +%d,%d @@", h.OldStart, h.OldLines, h.NewStart, h.NewLines)
+		if p := hunkPath(h, la, lb); p != "" {
+			sb.WriteString(" " + p)
+		}
+		sb.WriteByte('\n')
+		for _, l := range h.Lines {
+			sb.WriteString(l)
+		}
+	}
+	return sb.String(), nil
+}
+
+// Normalize returns doc re-encoded in the normalized form used by Unified:
+// keys sorted, two-space indentation, numbers in their shortest exact
+// decimal form and a trailing newline.
+//
+// Example:
+//
+//	out, _ := jsondiff.Normalize([]byte(`{"b":1e2,"a":[true]}`))
+//	// {
+//	//   "a": [
+//	//     true
+//	//   ],
+//	//   "b": 100
+//	// }
+func Normalize(doc []byte) ([]byte, error) {
+	v, err := parse(doc)
+	if err != nil {
+		return nil, fmt.Errorf("jsondiff: %w", err)
+	}
+	return []byte(strings.Join(layout(v).texts(), "")), nil
+}
+
+// parseBoth parses the two documents, naming the failing one in errors.
+func parseBoth(a, b []byte) (any, any, error) {
+	va, err := parse(a)
+	if err != nil {
+		return nil, nil, fmt.Errorf("jsondiff: old document: %w", err)
+	}
+	vb, err := parse(b)
+	if err != nil {
+		return nil, nil, fmt.Errorf("jsondiff: new document: %w", err)
+	}
+	return va, vb, nil
+}
+
+// parse decodes a single JSON value, keeping numbers as canonical
+// json.Numbers.
+func parse(doc []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(doc))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		return nil, errors.New("unexpected data after the top-level value")
+	}
+	return canonicalize(v), nil
+}
+
+// canonicalize rewrites every number in v to its canonical form, so that
+// equal numbers compare and render equal.
+func canonicalize(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, e := range v {
+			v[k] = canonicalize(e)
+		}
+	case []any:
+		for i, e := range v {
+			v[i] = canonicalize(e)
+		}
+	case json.Number:
+		return json.Number(canonicalNumber(string(v)))
+	}
+	return v
+}
+
+// canonicalNumber returns the shortest exact decimal form of a JSON number:
+// "1.50" and "15e-1" both become "1.5". Numbers with huge exponents are
+// kept as written rather than expanded.
+func canonicalNumber(s string) string {
+	if e := strings.IndexAny(s, "eE"); e >= 0 {
+		exp, err := strconv.Atoi(s[e+1:])
+		if err != nil || exp > 400 || exp < -400 {
+			return s
+		}
+	}
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return s
+	}
+	if r.IsInt() {
+		return r.Num().String()
+	}
+	digits := 0
+	ten := big.NewRat(10, 1)
+	for x := new(big.Rat).Set(r); !x.IsInt(); x.Mul(x, ten) {
+		digits++
+	}
+	return r.FloatString(digits)
+}
+
+// compare appends the differences between a and b at path to changes.
+func compare(path string, a, b any, changes *[]Change) {
+	switch a := a.(type) {
+	case map[string]any:
+		if b, ok := b.(map[string]any); ok {
+			compareObjects(path, a, b, changes)
+			return
+		}
+	case []any:
+		if b, ok := b.([]any); ok {
+			compareArrays(path, a, b, changes)
+			return
+		}
+	default:
+		if a == b {
+			return
+		}
+	}
+	*changes = append(*changes, Change{Kind: Changed, Path: path, Old: compact(a), New: compact(b)})
+}
+
+func compareObjects(path string, a, b map[string]any, changes *[]Change) {
+	keys := make([]string, 0, len(a)+len(b))
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		p := path + "/" + escapePointer(k)
+		va, inA := a[k]
+		vb, inB := b[k]
+		switch {
+		case !inB:
+			*changes = append(*changes, Change{Kind: Removed, Path: p, Old: compact(va)})
+		case !inA:
+			*changes = append(*changes, Change{Kind: Added, Path: p, New: compact(vb)})
+		default:
+			compare(p, va, vb, changes)
+		}
+	}
+}
+
+// compareArrays matches the elements of a and b by their normalized JSON.
+// Replaced runs are compared element by element as far as both sides go,
+// so a changed field inside an array element is reported at its own path.
+func compareArrays(path string, a, b []any, changes *[]Change) {
+	ka, kb := make([]string, len(a)), make([]string, len(b))
+	for i, v := range a {
+		ka[i] = string(compact(v))
+	}
+	for j, v := range b {
+		kb[j] = string(compact(v))
+	}
+	for _, op := range difflib.NewMatcher(ka, kb).GetOpCodes() {
+		if op.Tag == difflib.OpEqual {
+			continue
+		}
+		paired := min(op.I2-op.I1, op.J2-op.J1)
+		if op.Tag != difflib.OpReplace {
+			paired = 0
+		}
+		for k := 0; k < paired; k++ {
+			compare(path+"/"+strconv.Itoa(op.J1+k), a[op.I1+k], b[op.J1+k], changes)
+		}
+		for i := op.I1 + paired; i < op.I2; i++ {
+			*changes = append(*changes, Change{Kind: Removed, Path: path + "/" + strconv.Itoa(i), Old: compact(a[i])})
+		}
+		for j := op.J1 + paired; j < op.J2; j++ {
+			*changes = append(*changes, Change{Kind: Added, Path: path + "/" + strconv.Itoa(j), New: compact(b[j])})
+		}
+	}
+}
+
+// escapePointer escapes a key for use as a JSON Pointer reference token.
+func escapePointer(k string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(k, "~", "~0"), "/", "~1")
+}
+
+// compact returns the normalized single-line JSON of v.
+func compact(v any) json.RawMessage {
+	var sb strings.Builder
+	writeCompact(&sb, v)
+	return json.RawMessage(sb.String())
+}
+
+func writeCompact(sb *strings.Builder, v any) {
+	switch v := v.(type) {
+	case map[string]any:
+		sb.WriteByte('{')
+		for i, k := range sortedKeys(v) {
+			if i > 0 {
+				sb.WriteByte(',')
+			}
+			sb.WriteString(quote(k))
+			sb.WriteByte(':')
+			writeCompact(sb, v[k])
+		}
+		sb.WriteByte('}')
+	case []any:
+		sb.WriteByte('[')
+		for i, e := range v {
+			if i > 0 {
+				sb.WriteByte(',')
+			}
+			writeCompact(sb, e)
+		}
+		sb.WriteByte(']')
+	default:
+		sb.WriteString(scalar(v))
+	}
+}
+
+// line is one line of a normalized document and the JSON Pointer of the
+// value it belongs to.
+type line struct {
+	text, path string
+}
+
+type lines []line
+
+func (ls lines) texts() []string {
+	t := make([]string, len(ls))
+	for i, l := range ls {
+		t[i] = l.text
+	}
+	return t
+}
+
+// layout renders v in normalized indented form, one entry per line.
+func layout(v any) lines {
+	var ls lines
+	layoutValue(&ls, v, "", "", 0, false)
+	return ls
+}
+
+// layoutValue appends the lines of v, indented depth levels, with prefix
+// (a quoted key and colon, or "") before it and a comma after it if comma.
+func layoutValue(ls *lines, v any, prefix, path string, depth int, comma bool) {
+	indent := strings.Repeat("  ", depth)
+	end := "\n"
+	if comma {
+		end = ",\n"
+	}
+	switch v := v.(type) {
+	case map[string]any:
+		if len(v) == 0 {
+			*ls = append(*ls, line{indent + prefix + "{}" + end, path})
+			return
+		}
+		*ls = append(*ls, line{indent + prefix + "{\n", path})
+		keys := sortedKeys(v)
+		for i, k := range keys {
+			layoutValue(ls, v[k], quote(k)+": ", path+"/"+escapePointer(k), depth+1, i < len(keys)-1)
+		}
+		*ls = append(*ls, line{indent + "}" + end, path})
+	case []any:
+		if len(v) == 0 {
+			*ls = append(*ls, line{indent + prefix + "[]" + end, path})
+			return
+		}
+		*ls = append(*ls, line{indent + prefix + "[\n", path})
+		for i, e := range v {
+			layoutValue(ls, e, "", path+"/"+strconv.Itoa(i), depth+1, i < len(v)-1)
+		}
+		*ls = append(*ls, line{indent + "]" + end, path})
+	default:
+		*ls = append(*ls, line{indent + prefix + scalar(v) + end, path})
+	}
+}
+
+// hunkPath returns the path of the first added or removed line in h.
+func hunkPath(h difflib.Hunk, la, lb lines) string {
+	i, j := h.OldStart-1, h.NewStart-1
+	if h.OldLines == 0 {
+		i++
+	}
+	if h.NewLines == 0 {
+		j++
+	}
+	for _, l := range h.Lines {
+		switch l[0] {
+		case '-':
+			return la[i].path
+		case '+':
+			return lb[j].path
+		}
+		i++
+		j++
+	}
+	return ""
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// scalar formats a string, number, boolean or null.
+func scalar(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return strconv.FormatBool(v)
+	case json.Number:
+		return string(v)
+	case string:
+		return quote(v)
+	}
+	panic(fmt.Sprintf("jsondiff: unexpected value %T", v))
+}
+
+// quote returns s as a JSON string without escaping HTML characters.
+func quote(s string) string {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.Encode(s)
+	return strings.TrimSuffix(buf.String(), "\n")
+}
@@ -0,0 +1,133 @@
+package jsondiff_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/njchilds90/go-difflib/jsondiff"
+)
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want []string
+	}{
+		{"identical", `{"a":1}`, `{"a":1}`, nil},
+		{"key order and whitespace", `{"a":1,"b":[true,null]}`, "{\n  \"b\": [ true, null ],\n  \"a\": 1\n}", nil},
+		{"number spelling", `[1, 1.50, 2e2, -0.0]`, `[1.0, 15e-1, 200, 0]`, nil},
+		{"changed scalar", `{"port":8080}`, `{"port":9090}`, []string{"~ /port: 8080 -> 9090"}},
+		{"added and removed keys", `{"a":1,"b":2}`, `{"b":2,"c":{"d":3}}`, []string{"- /a: 1", `+ /c: {"d":3}`}},
+		{"type change", `{"a":"1"}`, `{"a":1}`, []string{`~ /a: "1" -> 1`}},
+		{"nested", `{"s":{"t":{"u":false}}}`, `{"s":{"t":{"u":true}}}`, []string{"~ /s/t/u: false -> true"}},
+		{"pointer escaping", `{"a/b":1,"c~d":1}`, `{"a/b":2,"c~d":2}`, []string{"~ /a~1b: 1 -> 2", "~ /c~0d: 1 -> 2"}},
+		{"array insert at front", `[1,2,3]`, `[0,1,2,3]`, []string{"+ /0: 0"}},
+		{"array remove", `["x","y","z"]`, `["x","z"]`, []string{`- /1: "y"`}},
+		{"array element field", `[{"id":1,"n":"a"},{"id":2,"n":"b"}]`, `[{"id":1,"n":"a"},{"id":2,"n":"B"}]`, []string{`~ /1/n: "b" -> "B"`}},
+		{"array replace longer", `[1,2]`, `[1,3,4]`, []string{"~ /1: 2 -> 3", "+ /2: 4"}},
+		{"root", `1`, `"1"`, []string{`~ : 1 -> "1"`}},
+		{"html not escaped", `{"h":"<a>"}`, `{"h":"<b>"}`, []string{`~ /h: "<a>" -> "<b>"`}},
+	}
+	for _, tt := range tests {
+		changes, err := jsondiff.Compare([]byte(tt.a), []byte(tt.b))
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		var got []string
+		for _, c := range changes {
+			got = append(got, c.String())
+		}
+		if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+			t.Errorf("%s: changes\n%s\nwant\n%s", tt.name, strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+		}
+	}
+}
+
+func TestCompareErrors(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want string
+	}{
+		{`{`, `{}`, "jsondiff: old document:"},
+		{`{}`, `{} {}`, "jsondiff: new document: unexpected data after the top-level value"},
+		{`{}`, ``, "jsondiff: new document: EOF"},
+	}
+	for _, tt := range tests {
+		_, err := jsondiff.Compare([]byte(tt.a), []byte(tt.b))
+		if err == nil || !strings.HasPrefix(err.Error(), tt.want) {
+			t.Errorf("Compare(%q, %q) error = %v, want prefix %q", tt.a, tt.b, err, tt.want)
+		}
+	}
+}
+
+func TestChangeJSON(t *testing.T) {
+	changes, err := jsondiff.Compare([]byte(`{"a":null,"b":1}`), []byte(`{"a":{"x":[1]},"c":true}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := json.Marshal(changes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `[{"kind":"changed","path":"/a","old":null,"new":{"x":[1]}},{"kind":"removed","path":"/b","old":1},{"kind":"added","path":"/c","new":true}]`
+	if string(out) != want {
+		t.Errorf("JSON =\n%s\nwant\n%s", out, want)
+	}
+}
+
+func TestUnified(t *testing.T) {
+	a := `{"name":"svc","server":{"host":"localhost","port":8080},"tags":["a","b"]}`
+	b := `{"tags":["a","b","c"],"server":{"port":9090,"host":"localhost"},"name":"svc"}`
+	got, err := jsondiff.Unified([]byte(a), []byte(b), jsondiff.Options{FromFile: "old.json", ToFile: "new.json", Context: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `--- old.json
++++ new.json
+@@ -4,3 +4,3 @@ /server/port
+     "host": "localhost",
+-    "port": 8080
++    "port": 9090
+   },
+@@ -8,3 +8,4 @@ /tags/1
+     "a",
+-    "b"
++    "b",
++    "c"
+   ]
+`
+	if got != want {
+		t.Errorf("Unified =\n%s\nwant\n%s", got, want)
+	}
+
+	if got, err := jsondiff.Unified([]byte(a), []byte(`{"name":"svc","tags":["a","b"],"server":{"host":"localhost","port":8080.0}}`), jsondiff.Options{}); err != nil || got != "" {
+		t.Errorf("equal documents: %q, %v", got, err)
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	got, err := jsondiff.Normalize([]byte(`{"b":1e2,"a":[true,{},[]],"c":"x<y"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "{\n  \"a\": [\n    true,\n    {},\n    []\n  ],\n  \"b\": 100,\n  \"c\": \"x<y\"\n}\n"
+	if string(got) != want {
+		t.Errorf("Normalize =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func ExampleCompare() {
+	old := []byte(`{"server":{"host":"localhost","port":8080},"debug":true}`)
+	new := []byte(`{"server":{"port":9090,"host":"localhost"},"workers":4}`)
+	changes, _ := jsondiff.Compare(old, new)
+	for _, c := range changes {
+		fmt.Println(c)
+	}
+	// Output:
+	// - /debug: true
+	// ~ /server/port: 8080 -> 9090
+	// + /workers: 4
+}
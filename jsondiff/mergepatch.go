@@ -0,0 +1,91 @@
+package jsondiff
+
+import "fmt"
+
+// CreateMergePatch returns the RFC 7386 JSON Merge Patch that turns the old
+// document into the new one, in compact normalized form. Members missing
+// from the new document are set to null; arrays and other non-object
+// values are replaced wholesale. The patch of two equal objects is "{}".
+//
+// Merge patches cannot set a member to null, since null means "remove":
+// such a member is removed when the patch is applied.
+//
+// Example:
+//
+//	patch, _ := jsondiff.CreateMergePatch(
+//		[]byte(`{"a":"b","c":{"d":"e","f":"g"}}`),
+//		[]byte(`{"a":"z","c":{"d":"e"}}`))
+//	// patch: {"a":"z","c":{"f":null}}
+func CreateMergePatch(a, b []byte) ([]byte, error) {
+	va, vb, err := parseBoth(a, b)
+	if err != nil {
+		return nil, err
+	}
+	return compact(mergeDiff(va, vb)), nil
+}
+
+// ApplyMergePatch applies an RFC 7386 JSON Merge Patch to doc and returns
+// the result in compact normalized form.
+//
+// Example:
+//
+//	out, err := jsondiff.ApplyMergePatch(
+//		[]byte(`{"title":"Hello","author":{"name":"Ann","email":"a@x"}}`),
+//		[]byte(`{"title":"Hi","author":{"email":null}}`))
+//	// out: {"author":{"name":"Ann"},"title":"Hi"}
+func ApplyMergePatch(doc, patch []byte) ([]byte, error) {
+	target, err := parse(doc)
+	if err != nil {
+		return nil, fmt.Errorf("jsondiff: document: %w", err)
+	}
+	p, err := parse(patch)
+	if err != nil {
+		return nil, fmt.Errorf("jsondiff: patch: %w", err)
+	}
+	return compact(mergePatch(target, p)), nil
+}
+
+// mergeDiff returns the merge patch from a to b.
+func mergeDiff(a, b any) any {
+	ma, okA := a.(map[string]any)
+	mb, okB := b.(map[string]any)
+	if !okA || !okB {
+		return b
+	}
+	patch := map[string]any{}
+	for k := range ma {
+		if _, ok := mb[k]; !ok {
+			patch[k] = nil
+		}
+	}
+	for k, vb := range mb {
+		va, ok := ma[k]
+		switch {
+		case !ok:
+			patch[k] = vb
+		case string(compact(va)) != string(compact(vb)):
+			patch[k] = mergeDiff(va, vb)
+		}
+	}
+	return patch
+}
+
+// mergePatch implements the MergePatch function of RFC 7386, section 2.
+func mergePatch(target, patch any) any {
+	mp, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+	mt, ok := target.(map[string]any)
+	if !ok {
+		mt = map[string]any{}
+	}
+	for k, v := range mp {
+		if v == nil {
+			delete(mt, k)
+		} else {
+			mt[k] = mergePatch(mt[k], v)
+		}
+	}
+	return mt
+}
@@ -0,0 +1,97 @@
+package jsondiff_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/njchilds90/go-difflib/jsondiff"
+)
+
+// The examples of RFC 7386, appendix A.
+var rfc7386Tests = []struct {
+	target, patch, result string
+}{
+	{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+	{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+	{`{"a":"b"}`, `{"a":null}`, `{}`},
+	{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+	{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+	{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+	{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+	{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+	{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+	{`{"a":"b"}`, `["c"]`, `["c"]`},
+	{`{"a":"foo"}`, `null`, `null`},
+	{`{"a":"foo"}`, `"bar"`, `"bar"`},
+	{`{"e":null}`, `{"a":1}`, `{"a":1,"e":null}`},
+	{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+	{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+}
+
+func TestApplyMergePatch(t *testing.T) {
+	for _, tt := range rfc7386Tests {
+		got, err := jsondiff.ApplyMergePatch([]byte(tt.target), []byte(tt.patch))
+		if err != nil {
+			t.Errorf("ApplyMergePatch(%s, %s): %v", tt.target, tt.patch, err)
+			continue
+		}
+		if string(got) != tt.result {
+			t.Errorf("ApplyMergePatch(%s, %s) = %s, want %s", tt.target, tt.patch, got, tt.result)
+		}
+	}
+}
+
+func TestCreateMergePatch(t *testing.T) {
+	tests := []struct {
+		a, b, patch string
+	}{
+		{`{"a":1}`, `{"a":1.0}`, `{}`},
+		{`{"a":"b","c":{"d":"e","f":"g"}}`, `{"a":"z","c":{"d":"e"}}`, `{"a":"z","c":{"f":null}}`},
+		{`{"a":[1,2]}`, `{"a":[1,2,3]}`, `{"a":[1,2,3]}`},
+		{`{"a":{"b":1}}`, `{"a":2}`, `{"a":2}`},
+		{`{"a":1}`, `[1]`, `[1]`},
+		{`[1]`, `{"a":1}`, `{"a":1}`},
+		{`{"x":{"y":{"z":1}},"k":true}`, `{"x":{"y":{"z":2}}}`, `{"k":null,"x":{"y":{"z":2}}}`},
+	}
+	for _, tt := range tests {
+		got, err := jsondiff.CreateMergePatch([]byte(tt.a), []byte(tt.b))
+		if err != nil {
+			t.Errorf("CreateMergePatch(%s, %s): %v", tt.a, tt.b, err)
+			continue
+		}
+		if string(got) != tt.patch {
+			t.Errorf("CreateMergePatch(%s, %s) = %s, want %s", tt.a, tt.b, got, tt.patch)
+		}
+		// Round trip: applying the patch to a yields b.
+		out, err := jsondiff.ApplyMergePatch([]byte(tt.a), got)
+		if err != nil {
+			t.Errorf("applying %s: %v", got, err)
+			continue
+		}
+		if changes, _ := jsondiff.Compare(out, []byte(tt.b)); len(changes) != 0 {
+			t.Errorf("applying %s to %s gave %s, want %s", got, tt.a, out, tt.b)
+		}
+	}
+}
+
+func TestMergePatchErrors(t *testing.T) {
+	if _, err := jsondiff.ApplyMergePatch([]byte(`{`), []byte(`{}`)); err == nil || !strings.HasPrefix(err.Error(), "jsondiff: document:") {
+		t.Errorf("bad document: %v", err)
+	}
+	if _, err := jsondiff.ApplyMergePatch([]byte(`{}`), []byte(`{"a":}`)); err == nil || !strings.HasPrefix(err.Error(), "jsondiff: patch:") {
+		t.Errorf("bad patch: %v", err)
+	}
+	if _, err := jsondiff.CreateMergePatch([]byte(`{}`), []byte(`nope`)); err == nil || !strings.HasPrefix(err.Error(), "jsondiff: new document:") {
+		t.Errorf("bad new document: %v", err)
+	}
+}
+
+func ExampleCreateMergePatch() {
+	old := []byte(`{"spec":{"replicas":2,"paused":true},"metadata":{"name":"web"}}`)
+	new := []byte(`{"spec":{"replicas":3},"metadata":{"name":"web"}}`)
+	patch, _ := jsondiff.CreateMergePatch(old, new)
+	fmt.Println(string(patch))
+	// Output:
+	// {"spec":{"paused":null,"replicas":3}}
+}
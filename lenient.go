@@ -0,0 +1,243 @@
+package difflib
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// LenientOptions controls ApplyPatchLenient.
+type LenientOptions struct {
+	// MinRatio is the minimum similarity, between 0 and 1, of the lines a
+	// hunk expects and the lines it is placed on. Defaults to 0.8 if zero.
+	MinRatio float64
+}
+
+// ApplyPatchLenient applies a unified diff that may be inaccurate, such as
+// one written by a language model, to the original lines A. Hunk headers are
+// mainly used to tell hunks apart: their counts are ignored, their line
+// numbers only choose between equally good matches, and both may be missing
+// altogether ("@@ ... @@" or a bare "@@"). Each hunk is instead placed where
+// its context and deleted lines best match A, ignoring leading and trailing
+// whitespace, anywhere in the input that no earlier hunk took; of equally
+// good places, the one nearest the line its header names wins. A hunk is
+// rejected if its best match is less similar than opts.MinRatio.
+//
+// Context lines are kept as they appear in A, so paraphrased context does
+// not leak into the result. Blank lines in a hunk are read as blank context,
+// and the hunk ends at the first line that is not part of a diff, so text
+// around the diff is ignored. Hunks without context or deleted lines are
+// inserted at the line named in their header.
+//
+// It returns the patched lines along with where each hunk was applied, or an
+// error naming the first hunk that could not be placed.
+//
+// Example:
+//
+//	patched, _, err := difflib.ApplyPatchLenient(original, modelReply,
+//	    difflib.LenientOptions{MinRatio: 0.7})
+func ApplyPatchLenient(a []string, patch string, opts LenientOptions) ([]string, []HunkResult, error) {
+	if opts.MinRatio == 0 {
+		opts.MinRatio = 0.8
+	}
+	hunks, err := parseLenientHunks(patch)
+	if err != nil {
+		return nil, nil, err
+	}
+	trimmed := make([]string, len(a))
+	for i, l := range a {
+		trimmed[i] = strings.TrimSpace(l)
+	}
+	placed := make([]hunkPlacement, 0, len(hunks))
+	// owner holds the number of the hunk that took each line of a, or 0, and
+	// cuts marks the points between lines where a hunk inserts.
+	owner := make([]int, len(a)+1)
+	cuts := make([]bool, len(a)+1)
+	for n, h := range hunks {
+		p, err := placeLenient(a, trimmed, owner, cuts, h, opts.MinRatio)
+		if err != nil {
+			return nil, nil, fmt.Errorf("difflib: hunk #%d: %w", n+1, err)
+		}
+		p.result.Hunk = n + 1
+		for i := p.pos; i < p.end; i++ {
+			owner[i] = n + 1
+		}
+		if p.pos == p.end {
+			cuts[p.pos] = true
+		}
+		placed = append(placed, p)
+	}
+	results := make([]HunkResult, len(placed))
+	for i, p := range placed {
+		results[i] = p.result
+	}
+	sort.Slice(placed, func(i, j int) bool {
+		if placed[i].pos != placed[j].pos {
+			return placed[i].pos < placed[j].pos
+		}
+		return placed[i].end < placed[j].end
+	})
+	for i := 1; i < len(placed); i++ {
+		if placed[i].pos < placed[i-1].end {
+			return nil, nil, fmt.Errorf("difflib: hunk #%d overlaps hunk #%d", placed[i].result.Hunk, placed[i-1].result.Hunk)
+		}
+	}
+	return splicePlacements(a, placed), results, nil
+}
+
+// placeLenient finds the window of a most similar to the old lines of h
+// that neither overlaps a line another hunk took nor spans a point where one
+// inserts, preferring the window nearest the header's line, or the earliest
+// without one, of equally good windows.
+func placeLenient(a, trimmed []string, owner []int, cuts []bool, h Hunk, minRatio float64) (hunkPlacement, error) {
+	from, to := hunkSides(h)
+	if len(from) == 0 {
+		pos := min(hunkAnchor(h), len(a))
+		if pos > 0 && owner[pos-1] != 0 && owner[pos-1] == owner[pos] {
+			return hunkPlacement{}, fmt.Errorf("insertion point %d is inside another hunk", pos)
+		}
+		return hunkPlacement{pos: pos, end: pos, lines: to, result: HunkResult{Line: pos + 1}}, nil
+	}
+	want := make([]string, len(from))
+	for i, l := range from {
+		want[i] = strings.TrimSpace(l)
+	}
+	wantText := strings.Join(want, "\n")
+	anchor := 0
+	if h.OldStart > 0 {
+		anchor = hunkAnchor(h)
+	}
+	dist := func(pos int) int { return max(pos-anchor, anchor-pos) }
+	best, bestPos := -1.0, -1
+	for pos := 0; pos+len(want) <= len(a); pos++ {
+		free := true
+		for i := pos; i < pos+len(want) && free; i++ {
+			free = owner[i] == 0 && (i == pos || !cuts[i])
+		}
+		if !free {
+			continue
+		}
+		r := 1.0
+		if !linesMatchAt(trimmed, want, pos) {
+			r = StringRatio(strings.Join(trimmed[pos:pos+len(want)], "\n"), wantText)
+		}
+		if r > best || r == best && dist(pos) < dist(bestPos) {
+			best, bestPos = r, pos
+		}
+		if r == 1 && pos >= anchor {
+			break // later windows are no better and farther away
+		}
+	}
+	if bestPos < 0 {
+		return hunkPlacement{}, fmt.Errorf("no room for %d old lines", len(want))
+	}
+	if best < minRatio {
+		return hunkPlacement{}, fmt.Errorf("best match at line %d has similarity %.2f, below %.2f", bestPos+1, best, minRatio)
+	}
+	// Take context lines from a; deleted lines are dropped.
+	var lines []string
+	k := bestPos
+	for _, l := range h.Lines {
+		switch l[0] {
+		case ' ':
+			lines = append(lines, a[k])
+			k++
+		case '-':
+			k++
+		case '+':
+			lines = append(lines, l[1:])
+		}
+	}
+	p := hunkPlacement{pos: bestPos, end: bestPos + len(want), lines: lines, result: HunkResult{Line: bestPos + 1}}
+	if h.OldStart > 0 {
+		p.result.Offset = bestPos - hunkAnchor(h)
+	}
+	return p, nil
+}
+
+// lenientNeedsLines reports whether cur has fewer body lines than the
+// counts of its header, want, say.
+func lenientNeedsLines(cur, want *Hunk) bool {
+	if cur == nil || want == nil {
+		return false
+	}
+	from, to := hunkSides(*cur)
+	return len(from) < want.OldLines || len(to) < want.NewLines
+}
+
+// parseLenientHunks reads the hunks of a single-file unified diff, ignoring
+// the counts in hunk headers except to tell body lines starting with "---"
+// and "+++" from file headers. Line numbers are kept where present.
+func parseLenientHunks(patch string) ([]Hunk, error) {
+	lines := SplitLines(patch)
+	var hunks []Hunk
+	var cur *Hunk
+	blanks := 0 // trailing blank lines read as context
+	// want holds the counts of the current hunk's header, if well formed.
+	// They only serve to read "---" and "+++" lines as body, not headers.
+	var want *Hunk
+	end := func() {
+		if cur == nil {
+			return
+		}
+		cur.Lines = cur.Lines[:len(cur.Lines)-blanks]
+		for _, l := range cur.Lines {
+			if l[0] != ' ' {
+				hunks = append(hunks, *cur)
+				break
+			}
+		}
+		cur, blanks = nil, 0
+	}
+	for i, l := range lines {
+		text := strings.TrimRight(l, "\r\n")
+		switch {
+		case strings.HasPrefix(text, "@@"):
+			end()
+			cur, want = &Hunk{}, nil
+			if h, err := parseHunkHeader(l); err == nil {
+				cur.OldStart, cur.NewStart = h.OldStart, h.NewStart
+				want = &h
+			}
+			continue
+		case strings.HasPrefix(text, "diff "),
+			strings.HasPrefix(text, "--- ") && i+1 < len(lines) && strings.HasPrefix(lines[i+1], "+++ ") &&
+				!lenientNeedsLines(cur, want):
+			end()
+			if len(hunks) > 0 {
+				return nil, fmt.Errorf("difflib: line %d: lenient patches must change a single file", i+1)
+			}
+			continue
+		case cur == nil:
+			continue
+		}
+		if !strings.HasSuffix(l, "\n") {
+			l += "\n"
+		}
+		switch {
+		case text == "":
+			cur.Lines = append(cur.Lines, " \n")
+			blanks++
+			continue
+		case text[0] == ' ' || text[0] == '-' || text[0] == '+':
+			cur.Lines = append(cur.Lines, l)
+		case text[0] == '\\':
+			trimLastNewline(cur.Lines)
+			continue
+		default:
+			end()
+			continue
+		}
+		blanks = 0
+	}
+	end()
+	for i := range hunks {
+		h := &hunks[i]
+		from, to := hunkSides(*h)
+		h.OldLines, h.NewLines = len(from), len(to)
+	}
+	if len(hunks) == 0 {
+		return nil, fmt.Errorf("difflib: patch has no hunks")
+	}
+	return hunks, nil
+}
@@ -0,0 +1,179 @@
+package difflib_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	difflib "github.com/njchilds90/go-difflib"
+)
+
+func TestApplyPatchLenient(t *testing.T) {
+	src := "package main\n\nimport \"fmt\"\n\nfunc main() {\n\tfmt.Println(\"hi\")\n}\n\nfunc helper() int {\n\treturn 1\n}\n"
+	tests := []struct {
+		name, patch, want string
+	}{
+		{
+			name:  "wrong line numbers and counts",
+			patch: "--- a/main.go\n+++ b/main.go\n@@ -40,2 +40,9 @@\n func helper() int {\n-\treturn 1\n+\treturn 2\n }\n",
+			want:  strings.Replace(src, "return 1", "return 2", 1),
+		},
+		{
+			name:  "bare headers",
+			patch: "@@\n func main() {\n-\tfmt.Println(\"hi\")\n+\tfmt.Println(\"hello\")\n@@ ... @@\n-\treturn 1\n+\treturn 3\n",
+			want:  strings.Replace(strings.Replace(src, "\"hi\"", "\"hello\"", 1), "return 1", "return 3", 1),
+		},
+		{
+			name:  "paraphrased context keeps the original",
+			patch: "@@\n  func main()  {\n-    fmt.Println(\"hi\")\n+\tfmt.Println(\"bye\")\n }  \n",
+			want:  strings.Replace(src, "\"hi\"", "\"bye\"", 1),
+		},
+		{
+			name:  "out of order hunks",
+			patch: "@@\n-\treturn 1\n+\treturn 4\n@@\n import \"fmt\"\n+import \"os\"\n",
+			want:  strings.Replace(strings.Replace(src, "return 1", "return 4", 1), "import \"fmt\"\n", "import \"fmt\"\nimport \"os\"\n", 1),
+		},
+		{
+			name:  "surrounding prose and blank context",
+			patch: "Here is the fix:\n```diff\n@@\n import \"fmt\"\n\n func main() {\n+\tdefer fmt.Println(\"done\")\n```\nThat should do it.\n",
+			want:  strings.Replace(src, "func main() {\n", "func main() {\n\tdefer fmt.Println(\"done\")\n", 1),
+		},
+		{
+			name:  "insertion uses its header",
+			patch: "@@ -0,0 +1,1 @@\n+// Package main is an example.\n",
+			want:  "// Package main is an example.\n" + src,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, err := difflib.ApplyPatchLenient(difflib.SplitLines(src), tt.patch, difflib.LenientOptions{})
+			if err != nil {
+				t.Fatalf("ApplyPatchLenient error: %v", err)
+			}
+			if s := difflib.JoinLines(got); s != tt.want {
+				t.Errorf("result =\n%s\nwant\n%s", s, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyPatchLenientMinRatio(t *testing.T) {
+	a := difflib.SplitLines("alpha beta gamma\ndelta\n")
+	patch := "@@\n-alpha beta gamme\n+alpha\n"
+	if _, _, err := difflib.ApplyPatchLenient(a, patch, difflib.LenientOptions{MinRatio: 0.99}); err == nil ||
+		!strings.Contains(err.Error(), "hunk #1: best match at line 1 has similarity") {
+		t.Errorf("error = %v, want a similarity error", err)
+	}
+	got, results, err := difflib.ApplyPatchLenient(a, patch, difflib.LenientOptions{})
+	if err != nil {
+		t.Fatalf("ApplyPatchLenient error: %v", err)
+	}
+	if s := difflib.JoinLines(got); s != "alpha\ndelta\n" {
+		t.Errorf("result = %q", s)
+	}
+	if len(results) != 1 || results[0].Line != 1 {
+		t.Errorf("results = %v", results)
+	}
+}
+
+func TestApplyPatchLenientErrors(t *testing.T) {
+	a := difflib.SplitLines("x\n")
+	tests := []struct {
+		name, patch, want string
+	}{
+		{"no hunks", "just prose\n", "patch has no hunks"},
+		{"two files", "--- a/x\n+++ b/x\n@@\n-x\n--- a/y\n+++ b/y\n@@\n-y\n", "line 5: lenient patches must change a single file"},
+		{"too long", "@@\n-x\n-y\n", "hunk #1: no room for 2 old lines"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := difflib.ApplyPatchLenient(a, tt.patch, difflib.LenientOptions{})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyPatchLenientPlacement(t *testing.T) {
+	a := difflib.SplitLines("x\ny\nx\ny\nx\ny\n")
+	tests := []struct {
+		name, patch, want string
+	}{
+		{"nearest to header", "@@ -5,1 +5,1 @@\n-x\n+X\n", "x\ny\nx\ny\nX\ny\n"},
+		{"nearest before header", "@@ -4,2 +4,2 @@\n-x\n+X\n y\n", "x\ny\nX\ny\nx\ny\n"},
+		{"earliest without header", "@@\n-x\n+X\n", "X\ny\nx\ny\nx\ny\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, err := difflib.ApplyPatchLenient(a, tt.patch, difflib.LenientOptions{})
+			if err != nil {
+				t.Fatalf("ApplyPatchLenient error: %v", err)
+			}
+			if s := difflib.JoinLines(got); s != tt.want {
+				t.Errorf("result = %q, want %q", s, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyPatchLenientInsertionPoints(t *testing.T) {
+	// A later hunk may not take lines around a point where an earlier one
+	// inserts.
+	a := difflib.SplitLines("a\nb\nc\nd\ne\nf\ng\n")
+	tests := []struct {
+		name, patch, want string
+		lines             []int
+	}{
+		{
+			name:  "window after insertion",
+			patch: "--- a/f\n@@ -1,2 +1,2 @@\n+++ b/f\n@@ -1,7 +1,7 @@\n a\n-b\n+B\n c\n+x\n e\n f\n-g\n",
+			want:  "a\n++ b/f\nb\nB\nd\nx\ne\nf\n",
+			lines: []int{2, 2},
+		},
+		{
+			name:  "window next to insertion",
+			patch: "@@ -2,0 +3,1 @@\n+new\n@@\n-c\n+C\n d\n",
+			want:  "a\nb\nnew\nC\nd\ne\nf\ng\n",
+			lines: []int{3, 3},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, results, err := difflib.ApplyPatchLenient(a, tt.patch, difflib.LenientOptions{})
+			if err != nil {
+				t.Fatalf("ApplyPatchLenient error: %v", err)
+			}
+			if s := difflib.JoinLines(got); s != tt.want {
+				t.Errorf("result = %q, want %q", s, tt.want)
+			}
+			for i, r := range results {
+				if r.Line != tt.lines[i] {
+					t.Errorf("hunk #%d applied at line %d, want %d", i+1, r.Line, tt.lines[i])
+				}
+			}
+		})
+	}
+	_, _, err := difflib.ApplyPatchLenient(a, "@@ -2,0 +3,1 @@\n+new\n@@\n b\n-c\n+C\n d\n", difflib.LenientOptions{})
+	if err == nil || !strings.Contains(err.Error(), "hunk #2") {
+		t.Errorf("error = %v, want hunk #2 rejected", err)
+	}
+}
+
+func ExampleApplyPatchLenient() {
+	original := difflib.SplitLines("one\ntwo\nthree\n")
+	// The line numbers are wrong and the context is misspelled.
+	patch := "@@ -10,3 +10,3 @@\n one\n-tow\n+2\n three\n"
+	patched, results, err := difflib.ApplyPatchLenient(original, patch, difflib.LenientOptions{MinRatio: 0.7})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Print(difflib.JoinLines(patched))
+	fmt.Println(results[0])
+	// Output:
+	// one
+	// 2
+	// three
+	// Hunk #1 succeeded at 1 (offset -9 lines).
+}
@@ -0,0 +1,67 @@
+package difflib
+
+import "sort"
+
+// LineMap translates line positions between the two sequences of a diff.
+// Positions are 0-based indices, like those of OpCode. It is safe for
+// concurrent use.
+type LineMap struct {
+	codes []OpCode
+}
+
+// LineMapping returns a LineMap for codes, as returned by GetOpCodes: a
+// complete, ordered cover of both sequences. Lookups take logarithmic time.
+//
+// Example:
+//
+//	m := difflib.LineMapping(difflib.NewMatcher(a, b).GetOpCodes())
+//	if j, ok := m.OldToNew(i); ok {
+//	    fmt.Printf("old line %d is now line %d\n", i+1, j+1)
+//	}
+func LineMapping(codes []OpCode) *LineMap {
+	return &LineMap{codes: codes}
+}
+
+// OldToNew returns the position in the new sequence of the old line i. If
+// the line was deleted or replaced, ok is false and j is where the change
+// that removed it starts in the new sequence, which is a reasonable place to
+// re-anchor something attached to the line. Positions past the end of the
+// old sequence map to the same distance past the end of the new one.
+func (m *LineMap) OldToNew(i int) (j int, ok bool) {
+	k := sort.Search(len(m.codes), func(k int) bool { return m.codes[k].I2 > i })
+	if k == len(m.codes) {
+		return m.past(i, true), false
+	}
+	c := m.codes[k]
+	if c.Tag == OpEqual {
+		return c.J1 + i - c.I1, true
+	}
+	return c.J1, false
+}
+
+// NewToOld returns the position in the old sequence of the new line j. If
+// the line was inserted or is a replacement, ok is false and i is where the
+// change that added it starts in the old sequence.
+func (m *LineMap) NewToOld(j int) (i int, ok bool) {
+	k := sort.Search(len(m.codes), func(k int) bool { return m.codes[k].J2 > j })
+	if k == len(m.codes) {
+		return m.past(j, false), false
+	}
+	c := m.codes[k]
+	if c.Tag == OpEqual {
+		return c.I1 + j - c.J1, true
+	}
+	return c.I1, false
+}
+
+// past maps a position at or past the end of one sequence to the other.
+func (m *LineMap) past(n int, old bool) int {
+	if len(m.codes) == 0 {
+		return n
+	}
+	last := m.codes[len(m.codes)-1]
+	if old {
+		return last.J2 + n - last.I2
+	}
+	return last.I2 + n - last.J2
+}
@@ -0,0 +1,76 @@
+package difflib_test
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	difflib "github.com/njchilds90/go-difflib"
+)
+
+func TestLineMapping(t *testing.T) {
+	a := difflib.SplitLines("a\nb\nc\nd\ne\n")
+	b := difflib.SplitLines("a\nX\nc\ne\nf\n")
+	m := difflib.LineMapping(difflib.NewMatcher(a, b).GetOpCodes())
+
+	type pos struct {
+		n  int
+		ok bool
+	}
+	oldToNew := []pos{{0, true}, {1, false}, {2, true}, {3, false}, {3, true}, {5, false}, {6, false}}
+	for i, want := range oldToNew {
+		if j, ok := m.OldToNew(i); j != want.n || ok != want.ok {
+			t.Errorf("OldToNew(%d) = %d, %v; want %d, %v", i, j, ok, want.n, want.ok)
+		}
+	}
+	newToOld := []pos{{0, true}, {1, false}, {2, true}, {4, true}, {5, false}, {5, false}, {6, false}}
+	for j, want := range newToOld {
+		if i, ok := m.NewToOld(j); i != want.n || ok != want.ok {
+			t.Errorf("NewToOld(%d) = %d, %v; want %d, %v", j, i, ok, want.n, want.ok)
+		}
+	}
+}
+
+func TestLineMappingEmpty(t *testing.T) {
+	m := difflib.LineMapping(nil)
+	if j, ok := m.OldToNew(3); j != 3 || ok {
+		t.Errorf("OldToNew(3) = %d, %v; want 3, false", j, ok)
+	}
+}
+
+func TestLineMappingConsistent(t *testing.T) {
+	rng := rand.New(rand.NewSource(7))
+	for n := 0; n < 50; n++ {
+		a, b := randomLines(rng, rng.Intn(40)), randomLines(rng, rng.Intn(40))
+		m := difflib.LineMapping(difflib.NewMatcher(a, b).GetOpCodes())
+		for i := range a {
+			j, ok := m.OldToNew(i)
+			if !ok {
+				continue
+			}
+			if a[i] != b[j] {
+				t.Fatalf("OldToNew(%d) = %d, but %q != %q", i, j, a[i], b[j])
+			}
+			if back, ok := m.NewToOld(j); !ok || back != i {
+				t.Fatalf("NewToOld(OldToNew(%d)) = %d, %v", i, back, ok)
+			}
+		}
+	}
+}
+
+func ExampleLineMapping() {
+	a := difflib.SplitLines("one\ntwo\nthree\n")
+	b := difflib.SplitLines("zero\none\nthree\n")
+	m := difflib.LineMapping(difflib.NewMatcher(a, b).GetOpCodes())
+	for i := range a {
+		if j, ok := m.OldToNew(i); ok {
+			fmt.Printf("old line %d -> new line %d\n", i+1, j+1)
+		} else {
+			fmt.Printf("old line %d deleted\n", i+1)
+		}
+	}
+	// Output:
+	// old line 1 -> new line 2
+	// old line 2 deleted
+	// old line 3 -> new line 3
+}
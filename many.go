@@ -0,0 +1,106 @@
+package difflib
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"sync"
+)
+
+// FilePair is one comparison for DiffMany. The old and new sides are taken
+// from A and B when set, and otherwise read from OldPath and NewPath; an
+// empty path stands for an empty file, as for an added or deleted file.
+type FilePair struct {
+	OldPath, NewPath string
+	A, B             []string
+	// FromFile and ToFile label the diff and default to the paths.
+	FromFile, ToFile string
+}
+
+// DiffManyOptions controls DiffMany.
+type DiffManyOptions struct {
+	// Concurrency is the number of worker goroutines. Zero selects
+	// runtime.GOMAXPROCS(0).
+	Concurrency int
+	// Context and MaxComparisons are as in DiffInput.
+	Context        int
+	MaxComparisons int
+}
+
+// DiffMany computes the unified diffs of many pairs of files using a pool
+// of workers. results[i] and errs[i] belong to pairs[i]; a pair that cannot
+// be read gets an empty result and a non-nil error without affecting the
+// others.
+//
+// Example:
+//
+//	results, errs := difflib.DiffMany(pairs, difflib.DiffManyOptions{})
+//	for i, r := range results {
+//	    if errs[i] != nil {
+//	        log.Print(errs[i])
+//	        continue
+//	    }
+//	    r.WriteTo(os.Stdout)
+//	}
+func DiffMany(pairs []FilePair, opts DiffManyOptions) (results []DiffResult, errs []error) {
+	workers := opts.Concurrency
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	results = make([]DiffResult, len(pairs))
+	errs = make([]error, len(pairs))
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(workers, len(pairs)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				results[i], errs[i] = diffPair(pairs[i], opts)
+			}
+		}()
+	}
+	for i := range pairs {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+	return results, errs
+}
+
+// diffPair loads and diffs one FilePair.
+func diffPair(p FilePair, opts DiffManyOptions) (DiffResult, error) {
+	load := func(lines []string, path string) ([]string, error) {
+		if lines != nil || path == "" {
+			return lines, nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("difflib: %w", err)
+		}
+		return SplitLines(string(data)), nil
+	}
+	a, err := load(p.A, p.OldPath)
+	if err != nil {
+		return DiffResult{}, err
+	}
+	b, err := load(p.B, p.NewPath)
+	if err != nil {
+		return DiffResult{}, err
+	}
+	from, to := p.FromFile, p.ToFile
+	if from == "" {
+		from = p.OldPath
+	}
+	if to == "" {
+		to = p.NewPath
+	}
+	return UnifiedDiff(DiffInput{
+		A:              a,
+		B:              b,
+		FromFile:       from,
+		ToFile:         to,
+		Context:        opts.Context,
+		MaxComparisons: opts.MaxComparisons,
+	}), nil
+}
@@ -0,0 +1,59 @@
+package difflib_test
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	difflib "github.com/njchilds90/go-difflib"
+)
+
+func TestDiffMany(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		p := filepath.Join(dir, name)
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+	old := write("old.txt", "one\ntwo\nthree\n")
+	new := write("new.txt", "one\nTWO\nthree\n")
+	pairs := []difflib.FilePair{
+		{OldPath: old, NewPath: new},
+		{OldPath: old, NewPath: filepath.Join(dir, "missing.txt")},
+		{A: difflib.SplitLines("a\n"), B: difflib.SplitLines("b\n"), FromFile: "x", ToFile: "y"},
+		{NewPath: new, FromFile: "/dev/null"},
+		{OldPath: old, NewPath: old},
+	}
+	for _, workers := range []int{0, 1, 4} {
+		results, errs := difflib.DiffMany(pairs, difflib.DiffManyOptions{Concurrency: workers})
+		if len(results) != len(pairs) || len(errs) != len(pairs) {
+			t.Fatalf("got %d results and %d errors for %d pairs", len(results), len(errs), len(pairs))
+		}
+		want0 := difflib.UnifiedDiff(difflib.DiffInput{
+			A:        difflib.SplitLines("one\ntwo\nthree\n"),
+			B:        difflib.SplitLines("one\nTWO\nthree\n"),
+			FromFile: old,
+			ToFile:   new,
+		})
+		if errs[0] != nil || !reflect.DeepEqual(results[0], want0) {
+			t.Errorf("pair 0 = %v, %v; want %v", results[0], errs[0], want0)
+		}
+		if !errors.Is(errs[1], fs.ErrNotExist) || !results[1].IsEmpty() {
+			t.Errorf("pair 1: err = %v, want fs.ErrNotExist", errs[1])
+		}
+		if want := "--- x\n+++ y\n@@ -1,1 +1,1 @@\n-a\n+b\n"; errs[2] != nil || results[2].String() != want {
+			t.Errorf("pair 2 = %q, %v; want %q", results[2].String(), errs[2], want)
+		}
+		if errs[3] != nil || results[3].FromFile != "/dev/null" || results[3].Stats().Added != 3 {
+			t.Errorf("pair 3 = %v, %v; want an added file", results[3], errs[3])
+		}
+		if errs[4] != nil || !results[4].IsEmpty() {
+			t.Errorf("pair 4 = %v, %v; want no changes", results[4], errs[4])
+		}
+	}
+}
@@ -0,0 +1,192 @@
+package difflib
+
+import (
+	"fmt"
+	"html"
+	"strings"
+)
+
+// MarkdownOptions controls RenderMarkdown.
+type MarkdownOptions struct {
+	// Collapsible wraps each file, or each hunk if PerHunk is set, in a
+	// <details> section whose summary names the file or hunk.
+	Collapsible bool
+	// PerHunk renders every hunk in its own fenced block.
+	PerHunk bool
+	// MaxChars, if positive, limits the length of the output in bytes. Whole
+	// hunks (or, for a hunk too large on its own, its leading lines) are
+	// included while they fit, followed by a note saying what was omitted.
+	// Budgets below about 100 bytes leave room only for the note. GitHub
+	// limits comments to 65536 characters.
+	MaxChars int
+}
+
+// markdownReserve is the room kept for the truncation note under MaxChars.
+const markdownReserve = 100
+
+// RenderMarkdown renders a patch set as Markdown, with each file's hunks in
+// fenced ```diff code blocks that GitHub and most other renderers
+// highlight. The fence is lengthened if the diff itself contains backtick
+// runs, so the content can never close it early. It is suited to bot
+// comments on pull requests; set MaxChars to stay under the host's size
+// limit.
+//
+// Example:
+//
+//	ps, _ := difflib.ParsePatchSet(patch)
+//	body := difflib.RenderMarkdown(ps, difflib.MarkdownOptions{
+//	    Collapsible: true,
+//	    MaxChars:    65536,
+//	})
+func RenderMarkdown(p *PatchSet, opts MarkdownOptions) string {
+	budget := -1
+	if opts.MaxChars > 0 {
+		budget = max(opts.MaxChars-markdownReserve, 0)
+	}
+	var b strings.Builder
+	totalHunks := 0
+	for _, f := range p.Files {
+		totalHunks += len(f.Hunks)
+	}
+	shownFiles, shownHunks := 0, 0
+	truncated := false
+	for _, f := range p.Files {
+		section, hunks, complete := fitMarkdownFile(f, opts, budget-b.Len(), budget >= 0)
+		if section == "" {
+			truncated = true
+			break
+		}
+		b.WriteString(section)
+		shownFiles++
+		shownHunks += hunks
+		if !complete {
+			truncated = true
+			break
+		}
+	}
+	if truncated {
+		fmt.Fprintf(&b, "\n_Diff truncated: showing %d of %d files and %d of %d hunks._\n",
+			shownFiles, len(p.Files), shownHunks, totalHunks)
+	}
+	return b.String()
+}
+
+// fitMarkdownFile renders as much of f as fits in budget (unlimited if
+// limited is false). It returns the section, the number of hunks included
+// (a partially included hunk counts) and whether f was rendered completely.
+func fitMarkdownFile(f FileDiff, opts MarkdownOptions, budget int, limited bool) (string, int, bool) {
+	full := renderMarkdownFile(f, f.Hunks, opts)
+	if !limited || len(full) <= budget {
+		return full, len(f.Hunks), true
+	}
+	for k := len(f.Hunks) - 1; k > 0; k-- {
+		if s := renderMarkdownFile(f, f.Hunks[:k], opts); len(s) <= budget {
+			return s, k, false
+		}
+	}
+	// Not even the first hunk fits: include as many of its lines as do.
+	if len(f.Hunks) == 0 {
+		return "", 0, false
+	}
+	h := f.Hunks[0]
+	best := ""
+	lo, hi := 1, len(h.Lines)
+	for lo <= hi {
+		mid := (lo + hi) / 2
+		part := h
+		part.Lines = h.Lines[:mid]
+		if s := renderMarkdownFile(f, []Hunk{part}, opts); len(s) <= budget {
+			best, lo = s, mid+1
+		} else {
+			hi = mid - 1
+		}
+	}
+	if best == "" {
+		return "", 0, false
+	}
+	return best, 1, false
+}
+
+// renderMarkdownFile renders the given hunks of f.
+func renderMarkdownFile(f FileDiff, hunks []Hunk, opts MarkdownOptions) string {
+	name := markdownFileName(f)
+	s := f.Stats()
+	var b strings.Builder
+	if opts.Collapsible && !opts.PerHunk {
+		fmt.Fprintf(&b, "<details>\n<summary><code>%s</code> (+%d -%d)</summary>\n\n", html.EscapeString(name), s.Added, s.Removed)
+	} else {
+		fmt.Fprintf(&b, "%s (+%d -%d)\n\n", markdownCode(name), s.Added, s.Removed)
+	}
+	if f.Binary {
+		b.WriteString("Binary file differs.\n\n")
+	}
+	if opts.PerHunk {
+		for _, h := range hunks {
+			var body strings.Builder
+			h.WriteTo(&body)
+			if opts.Collapsible {
+				fmt.Fprintf(&b, "<details>\n<summary><code>%s</code></summary>\n\n", html.EscapeString(h.header()))
+				b.WriteString(markdownFence(body.String()))
+				b.WriteString("\n</details>\n\n")
+			} else {
+				b.WriteString(markdownFence(body.String()))
+				b.WriteString("\n")
+			}
+		}
+	} else if len(hunks) > 0 {
+		var body strings.Builder
+		for _, h := range hunks {
+			h.WriteTo(&body)
+		}
+		b.WriteString(markdownFence(body.String()))
+		b.WriteString("\n")
+	}
+	if opts.Collapsible && !opts.PerHunk {
+		b.WriteString("</details>\n\n")
+	}
+	return b.String()
+}
+
+// markdownFileName returns the display name of f.
+func markdownFileName(f FileDiff) string {
+	if name := diffStatName(f); name != "" {
+		return name
+	}
+	if f.ToFile != "" && f.ToFile != devNull {
+		return f.ToFile
+	}
+	return f.FromFile
+}
+
+// markdownFence wraps diff text in a ```diff block whose fence is longer
+// than any backtick run in the text.
+func markdownFence(text string) string {
+	if text != "" && !strings.HasSuffix(text, "\n") {
+		text += "\n"
+	}
+	fence := strings.Repeat("`", max(3, longestRun(text, '`')+1))
+	return fence + "diff\n" + text + fence + "\n"
+}
+
+// markdownCode renders s as an inline code span.
+func markdownCode(s string) string {
+	fence := strings.Repeat("`", longestRun(s, '`')+1)
+	if strings.HasPrefix(s, "`") || strings.HasSuffix(s, "`") {
+		return fence + " " + s + " " + fence
+	}
+	return fence + s + fence
+}
+
+// longestRun returns the length of the longest run of c in s.
+func longestRun(s string, c byte) int {
+	best, n := 0, 0
+	for i := 0; i < len(s); i++ {
+		if s[i] == c {
+			n++
+			best = max(best, n)
+		} else {
+			n = 0
+		}
+	}
+	return best
+}
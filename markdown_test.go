@@ -0,0 +1,77 @@
+package difflib_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	difflib "github.com/njchilds90/go-difflib"
+)
+
+const markdownPatch = "--- a/x.go\n+++ b/x.go\n@@ -1,2 +1,2 @@\n keep\n-old\n+new\n@@ -10 +10 @@\n-ten\n+TEN\n" +
+	"--- a/y.md\n+++ b/y.md\n@@ -1 +1 @@\n-```go\n+````go\n"
+
+func TestRenderMarkdown(t *testing.T) {
+	ps, err := difflib.ParsePatchSet(markdownPatch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		opts difflib.MarkdownOptions
+		want string
+	}{
+		{"plain", difflib.MarkdownOptions{}, "`x.go` (+2 -2)\n\n" +
+			"```diff\n@@ -1,2 +1,2 @@\n keep\n-old\n+new\n@@ -10,1 +10,1 @@\n-ten\n+TEN\n```\n\n" +
+			"`y.md` (+1 -1)\n\n" +
+			"`````diff\n@@ -1,1 +1,1 @@\n-```go\n+````go\n`````\n\n"},
+		{"collapsible", difflib.MarkdownOptions{Collapsible: true}, "<details>\n<summary><code>x.go</code> (+2 -2)</summary>\n\n" +
+			"```diff\n@@ -1,2 +1,2 @@\n keep\n-old\n+new\n@@ -10,1 +10,1 @@\n-ten\n+TEN\n```\n\n</details>\n\n" +
+			"<details>\n<summary><code>y.md</code> (+1 -1)</summary>\n\n" +
+			"`````diff\n@@ -1,1 +1,1 @@\n-```go\n+````go\n`````\n\n</details>\n\n"},
+		{"per hunk", difflib.MarkdownOptions{PerHunk: true, Collapsible: true}, "`x.go` (+2 -2)\n\n" +
+			"<details>\n<summary><code>@@ -1,2 +1,2 @@</code></summary>\n\n```diff\n@@ -1,2 +1,2 @@\n keep\n-old\n+new\n```\n\n</details>\n\n" +
+			"<details>\n<summary><code>@@ -10,1 +10,1 @@</code></summary>\n\n```diff\n@@ -10,1 +10,1 @@\n-ten\n+TEN\n```\n\n</details>\n\n" +
+			"`y.md` (+1 -1)\n\n" +
+			"<details>\n<summary><code>@@ -1,1 +1,1 @@</code></summary>\n\n`````diff\n@@ -1,1 +1,1 @@\n-```go\n+````go\n`````\n\n</details>\n\n"},
+		{"budget drops second file", difflib.MarkdownOptions{MaxChars: 200}, "`x.go` (+2 -2)\n\n" +
+			"```diff\n@@ -1,2 +1,2 @@\n keep\n-old\n+new\n@@ -10,1 +10,1 @@\n-ten\n+TEN\n```\n\n" +
+			"\n_Diff truncated: showing 1 of 2 files and 2 of 3 hunks._\n"},
+		{"budget drops hunk", difflib.MarkdownOptions{MaxChars: 170}, "`x.go` (+2 -2)\n\n" +
+			"```diff\n@@ -1,2 +1,2 @@\n keep\n-old\n+new\n```\n\n" +
+			"\n_Diff truncated: showing 1 of 2 files and 1 of 3 hunks._\n"},
+		{"budget too small", difflib.MarkdownOptions{MaxChars: 10}, "\n_Diff truncated: showing 0 of 2 files and 0 of 3 hunks._\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := difflib.RenderMarkdown(ps, tt.opts)
+			if got != tt.want {
+				t.Errorf("RenderMarkdown() =\n%s\nwant\n%s", got, tt.want)
+			}
+			if tt.opts.MaxChars > 100 && len(got) > tt.opts.MaxChars {
+				t.Errorf("output is %d bytes, over the %d budget", len(got), tt.opts.MaxChars)
+			}
+		})
+	}
+}
+
+func TestRenderMarkdownLargeHunk(t *testing.T) {
+	var b strings.Builder
+	b.WriteString("--- a/big\n+++ b/big\n@@ -0,0 +1,1000 @@\n")
+	for i := 0; i < 1000; i++ {
+		fmt.Fprintf(&b, "+line %d\n", i)
+	}
+	ps, err := difflib.ParsePatchSet(b.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, budget := range []int{500, 2000, 65536} {
+		got := difflib.RenderMarkdown(ps, difflib.MarkdownOptions{MaxChars: budget})
+		if len(got) > budget {
+			t.Errorf("budget %d: output is %d bytes", budget, len(got))
+		}
+		if budget < 65536 && (!strings.Contains(got, "+line 0\n") || !strings.Contains(got, "showing 1 of 1 files and 1 of 1 hunks")) {
+			t.Errorf("budget %d: expected the start of the hunk and a truncation note:\n%s", budget, got)
+		}
+	}
+}
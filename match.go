@@ -0,0 +1,169 @@
+package difflib
+
+import "strings"
+
+// MatchOptions controls Match.
+type MatchOptions struct {
+	// Threshold is the worst score accepted, from 0 (perfect match only)
+	// towards 1 (anything matches). The score of a candidate adds the
+	// fraction of pattern characters that differ and its distance from the
+	// expected location divided by Distance. Zero selects the default of 0.5.
+	Threshold float64
+	// Distance is how far from the expected location, in characters, a match
+	// may be before it scores as badly as a complete mismatch. Zero selects
+	// the default of 1000.
+	Distance int
+}
+
+// matchMaxBits is the longest pattern the bitap bit masks can hold.
+const matchMaxBits = 64
+
+// Match finds the best fuzzy match of pattern in text near loc, using the
+// bitap algorithm of diff-match-patch's match_main. It tolerates both
+// character errors in the match and drift from the expected location, which
+// makes it useful for finding where stale content has moved to. loc and the
+// result are byte offsets into text; -1 is returned if no candidate scores
+// within the threshold. Patterns longer than 64 characters are located by
+// their first 64 characters.
+//
+// Example:
+//
+//	i := difflib.Match("I am the very model of a modern major general.", " that berry ", 5,
+//	    difflib.MatchOptions{Threshold: 0.7})
+//	// i == 4
+func Match(text, pattern string, loc int, opts MatchOptions) int {
+	loc = max(0, min(loc, len(text)))
+	switch {
+	case text == pattern:
+		return 0
+	case text == "":
+		return -1
+	case strings.HasPrefix(text[loc:], pattern):
+		return loc
+	}
+	if opts.Threshold == 0 {
+		opts.Threshold = 0.5
+	}
+	if opts.Distance == 0 {
+		opts.Distance = 1000
+	}
+	t, p := []rune(text), []rune(pattern)
+	if len(p) > matchMaxBits {
+		p = p[:matchMaxBits]
+	}
+	// Convert loc to a character index and the result back to bytes.
+	at := len([]rune(text[:loc]))
+	found := matchBitap(t, p, at, opts)
+	if found < 0 {
+		return -1
+	}
+	return len(string(t[:found]))
+}
+
+// matchBitap is the bitap search of diff-match-patch over characters.
+func matchBitap(text, pattern []rune, loc int, opts MatchOptions) int {
+	alphabet := map[rune]uint64{}
+	for i, c := range pattern {
+		alphabet[c] |= 1 << (len(pattern) - i - 1)
+	}
+	score := func(errors, x int) float64 {
+		proximity := loc - x
+		if proximity < 0 {
+			proximity = -proximity
+		}
+		return float64(errors)/float64(len(pattern)) + float64(proximity)/float64(opts.Distance)
+	}
+
+	// Exact matches near loc bound the threshold.
+	threshold := opts.Threshold
+	if best := runeIndex(text, pattern, loc); best >= 0 {
+		threshold = min(score(0, best), threshold)
+		if best = runeLastIndex(text, pattern, loc+len(pattern)); best >= 0 {
+			threshold = min(score(0, best), threshold)
+		}
+	}
+
+	matchMask := uint64(1) << (len(pattern) - 1)
+	best := -1
+	binMax := len(pattern) + len(text)
+	var lastRd []uint64
+	for d := 0; d < len(pattern); d++ {
+		// Binary search for how far from loc a match with d errors can be
+		// while staying within the threshold.
+		binMin, binMid := 0, binMax
+		for binMin < binMid {
+			if score(d, loc+binMid) <= threshold {
+				binMin = binMid
+			} else {
+				binMax = binMid
+			}
+			binMid = (binMax-binMin)/2 + binMin
+		}
+		binMax = binMid
+		start := max(1, loc-binMid+1)
+		finish := min(loc+binMid, len(text)) + len(pattern)
+
+		rd := make([]uint64, finish+2)
+		rd[finish+1] = 1<<d - 1
+		for j := finish; j >= start; j-- {
+			var charMatch uint64
+			if j-1 < len(text) {
+				charMatch = alphabet[text[j-1]]
+			}
+			if d == 0 {
+				rd[j] = (rd[j+1]<<1 | 1) & charMatch
+			} else {
+				rd[j] = (rd[j+1]<<1|1)&charMatch | ((lastRd[j+1]|lastRd[j])<<1 | 1) | lastRd[j+1]
+			}
+			if rd[j]&matchMask != 0 {
+				if s := score(d, j-1); s <= threshold {
+					threshold, best = s, j-1
+					if best <= loc {
+						break
+					}
+					// Past loc: only look as far back as we are ahead.
+					start = max(1, 2*loc-best)
+				}
+			}
+		}
+		if score(d+1, loc) > threshold {
+			break
+		}
+		lastRd = rd
+	}
+	return best
+}
+
+// runeIndex returns the index of the first occurrence of pattern in text at
+// or after from, or -1.
+func runeIndex(text, pattern []rune, from int) int {
+	for i := max(from, 0); i+len(pattern) <= len(text); i++ {
+		if runesEqual(text[i:i+len(pattern)], pattern) {
+			return i
+		}
+	}
+	return -1
+}
+
+// runeLastIndex returns the index of the last occurrence of pattern in text
+// starting at or before from, or -1.
+func runeLastIndex(text, pattern []rune, from int) int {
+	for i := min(from, len(text)-len(pattern)); i >= 0; i-- {
+		if runesEqual(text[i:i+len(pattern)], pattern) {
+			return i
+		}
+	}
+	return -1
+}
+
+func runesEqual(a, b []rune) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
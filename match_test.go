@@ -0,0 +1,65 @@
+package difflib_test
+
+import (
+	"fmt"
+	"testing"
+
+	difflib "github.com/njchilds90/go-difflib"
+)
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		text, pattern string
+		loc           int
+		opts          difflib.MatchOptions
+		want          int
+	}{
+		// Cases from the diff-match-patch test suite.
+		{"abcdef", "abcdef", 1000, difflib.MatchOptions{}, 0},
+		{"", "abcdef", 1, difflib.MatchOptions{}, -1},
+		{"abcdef", "", 3, difflib.MatchOptions{}, 3},
+		{"abcdef", "de", 3, difflib.MatchOptions{}, 3},
+		{"abcdef", "defy", 4, difflib.MatchOptions{}, 3},
+		{"abcdef", "abcdefy", 0, difflib.MatchOptions{}, 0},
+		{"I am the very model of a modern major general.", " that berry ", 5, difflib.MatchOptions{Threshold: 0.7}, 4},
+		{"abcdefghijk", "fgh", 5, difflib.MatchOptions{Distance: 100}, 5},
+		{"abcdefghijk", "fgh", 0, difflib.MatchOptions{Distance: 100}, 5},
+		{"abcdefghijk", "efxhi", 0, difflib.MatchOptions{Distance: 100}, 4},
+		{"abcdefghijk", "cdefxyhijk", 5, difflib.MatchOptions{Distance: 100}, 2},
+		{"abcdefghijk", "bxy", 1, difflib.MatchOptions{Distance: 100}, -1},
+		{"123456789xx0", "3456789x0", 2, difflib.MatchOptions{Distance: 100}, 2},
+		{"abcdef", "xxabc", 4, difflib.MatchOptions{Distance: 100}, 0},
+		{"abcdef", "defyy", 4, difflib.MatchOptions{Distance: 100}, 3},
+		{"abcdef", "xabcdefy", 0, difflib.MatchOptions{Distance: 100}, 0},
+		{"abcdefghijk", "efxyhi", 1, difflib.MatchOptions{Threshold: 0.4, Distance: 100}, 4},
+		{"abcdefghijk", "efxyhi", 1, difflib.MatchOptions{Threshold: 0.3, Distance: 100}, -1},
+		{"abcdefghijklmnopqrstuvwxyz", "abcdefg", 24, difflib.MatchOptions{Distance: 10}, -1},
+		{"abcdefghijklmnopqrstuvwxyz", "abcdxxefg", 1, difflib.MatchOptions{Distance: 10}, 0},
+		{"abcdefghijklmnopqrstuvwxyz", "abcdefg", 24, difflib.MatchOptions{Distance: 1000}, 0},
+		// Offsets are in bytes.
+		{"héllo wörld", "wörld", 0, difflib.MatchOptions{}, 7},
+		{"héllo wörld", "wurld", 9, difflib.MatchOptions{}, 7},
+		{"abc", "c", 99, difflib.MatchOptions{}, 2},
+	}
+	for _, tt := range tests {
+		if got := difflib.Match(tt.text, tt.pattern, tt.loc, tt.opts); got != tt.want {
+			t.Errorf("Match(%q, %q, %d, %+v) = %d, want %d", tt.text, tt.pattern, tt.loc, tt.opts, got, tt.want)
+		}
+	}
+}
+
+func TestMatchLongPattern(t *testing.T) {
+	text := "prefix " + fmt.Sprint(make([]int, 40)) + " suffix"
+	pattern := fmt.Sprint(make([]int, 40)) + " suffix!"
+	if got := difflib.Match(text, pattern, 0, difflib.MatchOptions{}); got != 7 {
+		t.Errorf("Match() = %d, want 7", got)
+	}
+}
+
+func ExampleMatch() {
+	text := "func main() {\n\tfmt.Println(\"hello, world\")\n}\n"
+	// The line was expected near offset 0 and has been edited since.
+	fmt.Println(difflib.Match(text, "fmt.Println(\"hello world\")", 0, difflib.MatchOptions{}))
+	// Output:
+	// 15
+}
@@ -0,0 +1,156 @@
+package difflib
+
+import "sort"
+
+// MatchIndex answers ClosestMatchesWithScores queries over a fixed, large
+// set of candidates without comparing the target against every one. It
+// keeps an inverted index from characters to the candidates containing
+// them, bucketed by candidate length, so a query only visits candidates of
+// a length that can reach the cutoff and shares characters with the
+// target. Those are ranked by their QuickRatio bound, and full ratios are
+// computed only until the bound drops below the n-th best score.
+//
+// Results are identical to ClosestMatchesWithScores over the same
+// candidates. A MatchIndex is safe for concurrent queries once built.
+//
+// Example:
+//
+//	idx := difflib.NewMatchIndex(dictionary)
+//	for _, m := range idx.Closest("recieve", 5, 0) {
+//	    fmt.Println(m.Text, m.Score)
+//	}
+type MatchIndex struct {
+	items []string
+	byLen map[int]*lenBucket
+}
+
+// lenBucket indexes the candidates of one length in characters.
+type lenBucket struct {
+	ids      []int
+	postings map[rune][]posting
+}
+
+// posting records that the candidate at position pos of its bucket
+// contains a character count times.
+type posting struct {
+	pos, count int
+}
+
+// NewMatchIndex returns an index over candidates. Indices in results refer
+// to positions in candidates.
+func NewMatchIndex(candidates []string) *MatchIndex {
+	x := &MatchIndex{}
+	for _, c := range candidates {
+		x.Add(c)
+	}
+	return x
+}
+
+// Add appends a candidate to the index. Its index in results is the number
+// of candidates added before it. The zero MatchIndex is an empty index
+// ready for Add.
+func (x *MatchIndex) Add(candidate string) {
+	if x.byLen == nil {
+		x.byLen = make(map[int]*lenBucket)
+	}
+	id := len(x.items)
+	x.items = append(x.items, candidate)
+	counts := make(map[rune]int)
+	n := 0
+	for _, r := range candidate {
+		counts[r]++
+		n++
+	}
+	b := x.byLen[n]
+	if b == nil {
+		b = &lenBucket{postings: make(map[rune][]posting)}
+		x.byLen[n] = b
+	}
+	for r, c := range counts {
+		b.postings[r] = append(b.postings[r], posting{len(b.ids), c})
+	}
+	b.ids = append(b.ids, id)
+}
+
+// Len returns the number of candidates in the index.
+func (x *MatchIndex) Len() int {
+	return len(x.items)
+}
+
+// Closest returns up to n candidates whose similarity to target is at least
+// cutoff, best first; see ClosestMatchesWithScores for the meaning of n
+// and cutoff.
+//
+// Example:
+//
+//	matches := idx.Closest("appel", 3, 0)
+func (x *MatchIndex) Closest(target string, n int, cutoff float64) []ScoredMatch {
+	if n <= 0 {
+		return nil
+	}
+	if cutoff == 0 {
+		cutoff = DefaultCutoff
+	}
+	tchars := splitChars(target)
+	tcounts := make(map[rune]int)
+	for _, r := range target {
+		tcounts[r]++
+	}
+
+	type bounded struct {
+		id    int
+		bound float64
+	}
+	var cands []bounded
+	for size, b := range x.byLen {
+		total := size + len(tchars)
+		if calcRatio(min(size, len(tchars)), total) < cutoff {
+			continue
+		}
+		common := make([]int, len(b.ids))
+		for r, tc := range tcounts {
+			for _, p := range b.postings[r] {
+				common[p.pos] += min(tc, p.count)
+			}
+		}
+		for pos, id := range b.ids {
+			if bound := calcRatio(common[pos], total); bound >= cutoff {
+				cands = append(cands, bounded{id, bound})
+			}
+		}
+	}
+	sort.Slice(cands, func(i, j int) bool {
+		if cands[i].bound != cands[j].bound {
+			return cands[i].bound > cands[j].bound
+		}
+		return cands[i].id < cands[j].id
+	})
+
+	var top []ScoredMatch
+	m := NewMatcher(nil, tchars)
+	for _, c := range cands {
+		if len(top) == n && c.bound < top[n-1].Score {
+			break
+		}
+		m.SetSeq1(splitChars(x.items[c.id]))
+		r := m.Ratio()
+		if r < cutoff {
+			continue
+		}
+		// Rank by score, then by position in the candidate list.
+		k := len(top)
+		for k > 0 && (r > top[k-1].Score || r == top[k-1].Score && c.id < top[k-1].Index) {
+			k--
+		}
+		if k == n {
+			continue
+		}
+		top = append(top, ScoredMatch{})
+		copy(top[k+1:], top[k:])
+		top[k] = ScoredMatch{x.items[c.id], c.id, r}
+		if len(top) > n {
+			top = top[:n]
+		}
+	}
+	return top
+}
@@ -0,0 +1,64 @@
+package difflib_test
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	difflib "github.com/njchilds90/go-difflib"
+)
+
+func TestMatchIndexMatchesLinearScan(t *testing.T) {
+	rng := rand.New(rand.NewSource(7))
+	word := func() string {
+		b := make([]rune, rng.Intn(9))
+		for i := range b {
+			b[i] = []rune("abcdeé")[rng.Intn(6)]
+		}
+		return string(b)
+	}
+	candidates := make([]string, 500)
+	for i := range candidates {
+		candidates[i] = word()
+	}
+	idx := difflib.NewMatchIndex(candidates)
+	if idx.Len() != len(candidates) {
+		t.Fatalf("Len() = %d, want %d", idx.Len(), len(candidates))
+	}
+	for iter := 0; iter < 30; iter++ {
+		target := word()
+		for _, n := range []int{1, 3, 20, 600} {
+			for _, cutoff := range []float64{0, -1, 0.3, 0.8, 1} {
+				want := difflib.ClosestMatchesWithScores(target, candidates, n, cutoff)
+				got := idx.Closest(target, n, cutoff)
+				if !reflect.DeepEqual(got, want) {
+					t.Fatalf("Closest(%q, %d, %v) = %v\nwant %v", target, n, cutoff, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestMatchIndexAdd(t *testing.T) {
+	var idx difflib.MatchIndex
+	if got := idx.Closest("x", 1, 0); got != nil {
+		t.Errorf("empty index: Closest() = %v", got)
+	}
+	idx.Add("apple")
+	idx.Add("ape")
+	want := []difflib.ScoredMatch{{Text: "apple", Index: 0, Score: 0.8}}
+	if got := idx.Closest("appel", 1, 0); !reflect.DeepEqual(got, want) {
+		t.Errorf("Closest() = %v, want %v", got, want)
+	}
+}
+
+func ExampleMatchIndex() {
+	idx := difflib.NewMatchIndex([]string{"receive", "deceive", "relieve", "recipe", "review"})
+	for _, m := range idx.Closest("recieve", 2, 0) {
+		fmt.Printf("%s %.2f\n", m.Text, m.Score)
+	}
+	// Output:
+	// receive 0.86
+	// relieve 0.86
+}
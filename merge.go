@@ -0,0 +1,219 @@
+package difflib
+
+import "strings"
+
+// ConflictStyle selects how Merge renders conflicting regions, mirroring
+// git's merge.conflictStyle setting.
+type ConflictStyle int
+
+const (
+	// ConflictMerge shows only the two sides of a conflict. Lines that both
+	// sides changed identically at the start or end of the conflict are moved
+	// outside the markers.
+	ConflictMerge ConflictStyle = iota
+	// ConflictDiff3 additionally shows the base version between ||||||| and
+	// ======= markers. The conflict is not trimmed.
+	ConflictDiff3
+	// ConflictZdiff3 shows the base version like ConflictDiff3, but moves
+	// lines common to both sides at the start or end of the conflict outside
+	// the markers.
+	ConflictZdiff3
+)
+
+// String returns the git name of the style: "merge", "diff3" or "zdiff3".
+func (s ConflictStyle) String() string {
+	switch s {
+	case ConflictMerge:
+		return "merge"
+	case ConflictDiff3:
+		return "diff3"
+	case ConflictZdiff3:
+		return "zdiff3"
+	default:
+		return "unknown"
+	}
+}
+
+// MergeOptions controls Merge.
+type MergeOptions struct {
+	// Style selects the conflict rendering. Defaults to ConflictMerge.
+	Style ConflictStyle
+	// OursLabel, BaseLabel and TheirsLabel are appended to the conflict
+	// markers. Empty labels produce bare markers.
+	OursLabel, BaseLabel, TheirsLabel string
+	// MarkerSize is the length of the conflict markers. Defaults to 7.
+	MarkerSize int
+}
+
+// MergeResult holds the outcome of a three-way merge.
+type MergeResult struct {
+	// Lines is the merged content, including conflict markers if any.
+	Lines []string
+	// Conflicts is the number of conflicting regions in Lines.
+	Conflicts int
+}
+
+// HasConflicts reports whether the merge produced any conflicts.
+func (r MergeResult) HasConflicts() bool {
+	return r.Conflicts > 0
+}
+
+// Merge performs a three-way merge of ours and theirs, both derived from base.
+// Regions changed on only one side take that side's version; regions changed
+// identically on both sides are taken once; regions changed differently are
+// emitted as conflicts rendered according to opts.Style.
+//
+// Example:
+//
+//	result := difflib.Merge(base, ours, theirs, difflib.MergeOptions{
+//	    Style:       difflib.ConflictDiff3,
+//	    OursLabel:   "HEAD",
+//	    TheirsLabel: "feature",
+//	})
+//	fmt.Print(difflib.JoinLines(result.Lines))
+func Merge(base, ours, theirs []string, opts MergeOptions) MergeResult {
+	var res MergeResult
+	for _, r := range mergeRegions(base, ours, theirs) {
+		if !r.conflict {
+			res.Lines = append(res.Lines, r.ours...)
+			continue
+		}
+		res.Conflicts++
+		res.Lines = appendConflict(res.Lines, r, opts)
+	}
+	return res
+}
+
+// mergeRegion is a stretch of the merge: either resolved lines (held in ours)
+// or a conflict between ours and theirs over the base lines.
+type mergeRegion struct {
+	conflict           bool
+	base, ours, theirs []string
+}
+
+// mergeRegions splits the three inputs into stable regions, where all three
+// agree, and unstable regions between them, resolving unstable regions
+// changed on at most one side.
+func mergeRegions(base, ours, theirs []string) []mergeRegion {
+	mo := baseMapping(base, ours)
+	mt := baseMapping(base, theirs)
+
+	var regions []mergeRegion
+	emit := func(r mergeRegion) {
+		if n := len(regions); n > 0 && !r.conflict && !regions[n-1].conflict {
+			regions[n-1].ours = append(regions[n-1].ours, r.ours...)
+			return
+		}
+		if !r.conflict {
+			// Copy so later appends never write into the inputs' arrays.
+			r.ours = append([]string(nil), r.ours...)
+		}
+		regions = append(regions, r)
+	}
+	ib, io, it := 0, 0, 0
+	for {
+		k := ib
+		for k < len(base) && (mo[k] < 0 || mt[k] < 0) {
+			k++
+		}
+		oe, te := len(ours), len(theirs)
+		if k < len(base) {
+			oe, te = mo[k], mt[k]
+		}
+		b, o, t := base[ib:k], ours[io:oe], theirs[it:te]
+		switch {
+		case len(b) == 0 && len(o) == 0 && len(t) == 0:
+		case linesEqual(o, b):
+			emit(mergeRegion{ours: t})
+		case linesEqual(t, b), linesEqual(o, t):
+			emit(mergeRegion{ours: o})
+		default:
+			regions = append(regions, mergeRegion{conflict: true, base: b, ours: o, theirs: t})
+		}
+		if k == len(base) {
+			break
+		}
+		emit(mergeRegion{ours: base[k : k+1]})
+		ib, io, it = k+1, mo[k]+1, mt[k]+1
+	}
+	return regions
+}
+
+// baseMapping returns, for each line of base, the index of the line it is
+// matched with in other, or -1 if it has no match.
+func baseMapping(base, other []string) []int {
+	m := make([]int, len(base))
+	for i := range m {
+		m[i] = -1
+	}
+	for _, blk := range NewMatcher(base, other).GetMatchingBlocks() {
+		for k := 0; k < blk.Size; k++ {
+			m[blk.A+k] = blk.B + k
+		}
+	}
+	return m
+}
+
+func linesEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// appendConflict renders a conflict region onto out.
+func appendConflict(out []string, r mergeRegion, opts MergeOptions) []string {
+	o, t := r.ours, r.theirs
+	var prefix, suffix []string
+	if opts.Style != ConflictDiff3 {
+		n := 0
+		for n < len(o) && n < len(t) && o[n] == t[n] {
+			n++
+		}
+		prefix, o, t = o[:n], o[n:], t[n:]
+		m := 0
+		for m < len(o) && m < len(t) && o[len(o)-1-m] == t[len(t)-1-m] {
+			m++
+		}
+		suffix, o, t = o[len(o)-m:], o[:len(o)-m], t[:len(t)-m]
+	}
+
+	size := opts.MarkerSize
+	if size <= 0 {
+		size = 7
+	}
+	marker := func(c byte, label string) string {
+		m := strings.Repeat(string(c), size)
+		if label != "" {
+			m += " " + label
+		}
+		return m + "\n"
+	}
+
+	out = append(out, prefix...)
+	out = append(out, marker('<', opts.OursLabel))
+	out = appendTerminated(out, o)
+	if opts.Style != ConflictMerge {
+		out = append(out, marker('|', opts.BaseLabel))
+		out = appendTerminated(out, r.base)
+	}
+	out = append(out, marker('=', ""))
+	out = appendTerminated(out, t)
+	out = append(out, marker('>', opts.TheirsLabel))
+	return append(out, suffix...)
+}
+
+// appendTerminated appends lines to out, adding a newline to the last line
+// if it lacks one so that a following conflict marker starts a new line.
+func appendTerminated(out, lines []string) []string {
+	out = append(out, lines...)
+	if n := len(out); len(lines) > 0 && !strings.HasSuffix(out[n-1], "\n") {
+		out[n-1] += "\n"
+	}
+	return out
+}
@@ -0,0 +1,96 @@
+package difflib_test
+
+import (
+	"fmt"
+	"testing"
+
+	difflib "github.com/njchilds90/go-difflib"
+)
+
+func TestMergeClean(t *testing.T) {
+	base := difflib.SplitLines("a\nb\nc\nd\ne\n")
+	ours := difflib.SplitLines("A\nb\nc\nd\ne\n")
+	theirs := difflib.SplitLines("a\nb\nc\nd\nE\nf\n")
+	res := difflib.Merge(base, ours, theirs, difflib.MergeOptions{})
+	if res.HasConflicts() {
+		t.Fatalf("unexpected conflicts:\n%s", difflib.JoinLines(res.Lines))
+	}
+	if got, want := difflib.JoinLines(res.Lines), "A\nb\nc\nd\nE\nf\n"; got != want {
+		t.Errorf("merged = %q, want %q", got, want)
+	}
+	if got := difflib.JoinLines(base); got != "a\nb\nc\nd\ne\n" {
+		t.Errorf("Merge modified base: %q", got)
+	}
+}
+
+func TestMergeSameChange(t *testing.T) {
+	base := difflib.SplitLines("a\nb\nc\n")
+	both := difflib.SplitLines("a\nB\nc\n")
+	res := difflib.Merge(base, both, both, difflib.MergeOptions{})
+	if res.HasConflicts() || difflib.JoinLines(res.Lines) != "a\nB\nc\n" {
+		t.Errorf("identical changes should merge cleanly, got %q", difflib.JoinLines(res.Lines))
+	}
+}
+
+func TestMergeConflictStyles(t *testing.T) {
+	base := difflib.SplitLines("start\nx\nend\n")
+	ours := difflib.SplitLines("start\nsame\nours\nend\n")
+	theirs := difflib.SplitLines("start\nsame\ntheirs\nend\n")
+	tests := []struct {
+		style difflib.ConflictStyle
+		want  string
+	}{
+		{difflib.ConflictMerge,
+			"start\nsame\n<<<<<<< ours\nours\n=======\ntheirs\n>>>>>>> theirs\nend\n"},
+		{difflib.ConflictDiff3,
+			"start\n<<<<<<< ours\nsame\nours\n||||||| base\nx\n=======\nsame\ntheirs\n>>>>>>> theirs\nend\n"},
+		{difflib.ConflictZdiff3,
+			"start\nsame\n<<<<<<< ours\nours\n||||||| base\nx\n=======\ntheirs\n>>>>>>> theirs\nend\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.style.String(), func(t *testing.T) {
+			res := difflib.Merge(base, ours, theirs, difflib.MergeOptions{
+				Style:       tt.style,
+				OursLabel:   "ours",
+				BaseLabel:   "base",
+				TheirsLabel: "theirs",
+			})
+			if res.Conflicts != 1 {
+				t.Errorf("Conflicts = %d, want 1", res.Conflicts)
+			}
+			if got := difflib.JoinLines(res.Lines); got != tt.want {
+				t.Errorf("merged =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeConflictMissingNewline(t *testing.T) {
+	base := []string{"a"}
+	res := difflib.Merge(base, []string{"b"}, []string{"c"}, difflib.MergeOptions{MarkerSize: 3})
+	if got, want := difflib.JoinLines(res.Lines), "<<<\nb\n===\nc\n>>>\n"; got != want {
+		t.Errorf("merged = %q, want %q", got, want)
+	}
+}
+
+func ExampleMerge() {
+	base := difflib.SplitLines("one\ntwo\nthree\n")
+	ours := difflib.SplitLines("one\nTWO\nthree\n")
+	theirs := difflib.SplitLines("one\n2\nthree\n")
+	res := difflib.Merge(base, ours, theirs, difflib.MergeOptions{
+		Style:       difflib.ConflictDiff3,
+		OursLabel:   "HEAD",
+		TheirsLabel: "feature",
+	})
+	fmt.Print(difflib.JoinLines(res.Lines))
+	// Output:
+	// one
+	// <<<<<<< HEAD
+	// TWO
+	// |||||||
+	// two
+	// =======
+	// 2
+	// >>>>>>> feature
+	// three
+}
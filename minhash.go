@@ -0,0 +1,249 @@
+package difflib
+
+import (
+	"hash/fnv"
+	"math"
+	"sort"
+	"strings"
+)
+
+// MinHashOptions controls document fingerprinting with NewMinHash.
+type MinHashOptions struct {
+	// Shingle is the number of consecutive words in each shingle. Zero
+	// selects 3.
+	Shingle int
+	// Hashes is the length of the signature. The error of the estimated
+	// similarity is about 1/sqrt(Hashes). Zero selects 128.
+	Hashes int
+	// Bands is the number of locality-sensitive hashing bands a
+	// DuplicateIndex splits signatures into. More bands find less similar
+	// pairs at the cost of more false candidates. Zero selects 32.
+	Bands int
+	// Seed varies the hash functions. Signatures are only comparable when
+	// made with the same options.
+	Seed uint64
+}
+
+func (o MinHashOptions) withDefaults() MinHashOptions {
+	if o.Shingle <= 0 {
+		o.Shingle = 3
+	}
+	if o.Hashes <= 0 {
+		o.Hashes = 128
+	}
+	if o.Bands <= 0 {
+		o.Bands = 32
+	}
+	o.Bands = min(o.Bands, o.Hashes)
+	return o
+}
+
+// MinHash is a MinHash signature of a document's word shingles.
+type MinHash []uint64
+
+// NewMinHash fingerprints text by splitting it into words, forming
+// overlapping shingles of opts.Shingle words and keeping, for each of
+// opts.Hashes hash functions, the minimum hash over all shingles. The
+// fraction of positions at which two signatures agree estimates the Jaccard
+// similarity of the documents' shingle sets.
+//
+// Example:
+//
+//	a := difflib.NewMinHash(docA, difflib.MinHashOptions{})
+//	b := difflib.NewMinHash(docB, difflib.MinHashOptions{})
+//	similar := a.Similarity(b) > 0.8
+func NewMinHash(text string, opts MinHashOptions) MinHash {
+	opts = opts.withDefaults()
+	sig := make(MinHash, opts.Hashes)
+	for i := range sig {
+		sig[i] = math.MaxUint64
+	}
+	words := strings.Fields(text)
+	n := max(len(words)-opts.Shingle+1, 1)
+	for i := 0; i < n && len(words) > 0; i++ {
+		h := fnv.New64a()
+		h.Write([]byte(strings.Join(words[i:min(i+opts.Shingle, len(words))], " ")))
+		x := h.Sum64()
+		for k := range sig {
+			if v := mix64(x ^ mix64(opts.Seed+uint64(k))); v < sig[k] {
+				sig[k] = v
+			}
+		}
+	}
+	return sig
+}
+
+// Similarity estimates the Jaccard similarity of the documents behind two
+// signatures made with the same options.
+func (m MinHash) Similarity(o MinHash) float64 {
+	n := min(len(m), len(o))
+	if n == 0 {
+		return 0
+	}
+	same := 0
+	for i := 0; i < n; i++ {
+		if m[i] == o[i] {
+			same++
+		}
+	}
+	return float64(same) / float64(n)
+}
+
+// mix64 is the splitmix64 finalizer.
+func mix64(x uint64) uint64 {
+	x += 0x9e3779b97f4a7c15
+	x = (x ^ x>>30) * 0xbf58476d1ce4e5b9
+	x = (x ^ x>>27) * 0x94d049bb133111eb
+	return x ^ x>>31
+}
+
+// DuplicateOptions controls a DuplicateIndex.
+type DuplicateOptions struct {
+	MinHashOptions
+	// Verify keeps the documents and confirms candidate pairs with
+	// SequenceRatio over their lines, which then becomes the reported
+	// score.
+	Verify bool
+}
+
+// Duplicate is a document found by DuplicateIndex.Query.
+type Duplicate struct {
+	// Index is the position of the document in the order it was added.
+	Index int `json:"index"`
+	// Score is the estimated Jaccard similarity, or the SequenceRatio if
+	// the index verifies candidates.
+	Score float64 `json:"score"`
+}
+
+// DuplicateIndex finds near-duplicate documents without comparing every
+// pair. Documents are fingerprinted with NewMinHash and their signatures
+// split into bands; documents sharing any band become candidates, which
+// are then scored.
+//
+// Example:
+//
+//	idx := difflib.NewDuplicateIndex(difflib.DuplicateOptions{Verify: true})
+//	for _, doc := range docs {
+//	    idx.Add(doc)
+//	}
+//	for _, p := range idx.Pairs(0.9) {
+//	    fmt.Println(p.I, p.J, p.Score)
+//	}
+type DuplicateIndex struct {
+	opts    DuplicateOptions
+	sigs    []MinHash
+	docs    []string
+	buckets []map[uint64][]int // per band: band hash to documents
+}
+
+// NewDuplicateIndex returns an empty index.
+func NewDuplicateIndex(opts DuplicateOptions) *DuplicateIndex {
+	opts.MinHashOptions = opts.MinHashOptions.withDefaults()
+	x := &DuplicateIndex{opts: opts, buckets: make([]map[uint64][]int, opts.Bands)}
+	for i := range x.buckets {
+		x.buckets[i] = make(map[uint64][]int)
+	}
+	return x
+}
+
+// Add fingerprints and indexes a document, returning its index.
+func (x *DuplicateIndex) Add(doc string) int {
+	id := len(x.sigs)
+	sig := NewMinHash(doc, x.opts.MinHashOptions)
+	x.sigs = append(x.sigs, sig)
+	if x.opts.Verify {
+		x.docs = append(x.docs, doc)
+	}
+	for b, key := range x.bandKeys(sig) {
+		x.buckets[b][key] = append(x.buckets[b][key], id)
+	}
+	return id
+}
+
+// Query returns the indexed documents similar to doc with a score of at
+// least threshold, best first.
+func (x *DuplicateIndex) Query(doc string, threshold float64) []Duplicate {
+	sig := NewMinHash(doc, x.opts.MinHashOptions)
+	seen := make(map[int]bool)
+	var out []Duplicate
+	for b, key := range x.bandKeys(sig) {
+		for _, id := range x.buckets[b][key] {
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+			if s, ok := x.score(sig, doc, id, threshold); ok {
+				out = append(out, Duplicate{id, s})
+			}
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Score != out[j].Score {
+			return out[i].Score > out[j].Score
+		}
+		return out[i].Index < out[j].Index
+	})
+	return out
+}
+
+// Pairs returns every pair of indexed documents with a score of at least
+// threshold, ordered by I and then J.
+func (x *DuplicateIndex) Pairs(threshold float64) []SimilarityPair {
+	seen := make(map[[2]int]bool)
+	var out []SimilarityPair
+	for _, bucket := range x.buckets {
+		for _, ids := range bucket {
+			for a := 0; a < len(ids); a++ {
+				for b := a + 1; b < len(ids); b++ {
+					i, j := ids[a], ids[b]
+					if seen[[2]int{i, j}] {
+						continue
+					}
+					seen[[2]int{i, j}] = true
+					var doc string
+					if x.opts.Verify {
+						doc = x.docs[i]
+					}
+					if s, ok := x.score(x.sigs[i], doc, j, threshold); ok {
+						out = append(out, SimilarityPair{i, j, s})
+					}
+				}
+			}
+		}
+	}
+	sort.Slice(out, func(a, b int) bool {
+		if out[a].I != out[b].I {
+			return out[a].I < out[b].I
+		}
+		return out[a].J < out[b].J
+	})
+	return out
+}
+
+// Len returns the number of indexed documents.
+func (x *DuplicateIndex) Len() int {
+	return len(x.sigs)
+}
+
+// score rates a candidate against document id.
+func (x *DuplicateIndex) score(sig MinHash, doc string, id int, threshold float64) (float64, bool) {
+	s := sig.Similarity(x.sigs[id])
+	if x.opts.Verify {
+		s = SequenceRatio(SplitLines(doc), SplitLines(x.docs[id]))
+	}
+	return s, s >= threshold
+}
+
+// bandKeys hashes each band of a signature.
+func (x *DuplicateIndex) bandKeys(sig MinHash) []uint64 {
+	rows := len(sig) / len(x.buckets)
+	keys := make([]uint64, len(x.buckets))
+	for b := range keys {
+		var h uint64
+		for _, v := range sig[b*rows : (b+1)*rows] {
+			h = mix64(h ^ v)
+		}
+		keys[b] = h
+	}
+	return keys
+}
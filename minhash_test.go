@@ -0,0 +1,106 @@
+package difflib_test
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+
+	difflib "github.com/njchilds90/go-difflib"
+)
+
+// randomDoc returns n random words from a large vocabulary.
+func randomDoc(rng *rand.Rand, n int) []string {
+	words := make([]string, n)
+	for i := range words {
+		words[i] = fmt.Sprintf("w%d", rng.Intn(100000))
+	}
+	return words
+}
+
+func TestMinHashSimilarity(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	doc := randomDoc(rng, 200)
+	text := strings.Join(doc, " ")
+	opts := difflib.MinHashOptions{Hashes: 256}
+	a := difflib.NewMinHash(text, opts)
+	if got := a.Similarity(difflib.NewMinHash(text, opts)); got != 1 {
+		t.Errorf("identical documents: Similarity() = %v, want 1", got)
+	}
+	if got := a.Similarity(difflib.NewMinHash(strings.Join(randomDoc(rng, 200), " "), opts)); got > 0.05 {
+		t.Errorf("unrelated documents: Similarity() = %v, want about 0", got)
+	}
+	// Changing every 10th word leaves roughly 70% of the 3-word shingles
+	// intact, a Jaccard similarity of about 0.54.
+	edited := append([]string(nil), doc...)
+	for i := 0; i < len(edited); i += 10 {
+		edited[i] = "changed"
+	}
+	got := a.Similarity(difflib.NewMinHash(strings.Join(edited, " "), opts))
+	if math.Abs(got-0.54) > 0.12 {
+		t.Errorf("edited document: Similarity() = %v, want about 0.54", got)
+	}
+	if got := difflib.NewMinHash("", opts).Similarity(difflib.NewMinHash("  ", opts)); got != 1 {
+		t.Errorf("empty documents: Similarity() = %v, want 1", got)
+	}
+}
+
+func TestDuplicateIndex(t *testing.T) {
+	rng := rand.New(rand.NewSource(5))
+	var docs []string
+	for i := 0; i < 200; i++ {
+		var lines []string
+		for j := 0; j < 10; j++ {
+			lines = append(lines, strings.Join(randomDoc(rng, 10), " ")+"\n")
+		}
+		docs = append(docs, strings.Join(lines, ""))
+	}
+	// Plant near-duplicates of documents 10 and 50; the second has the
+	// same words but different lines.
+	docs = append(docs, docs[10]+"with a short addendum\n", strings.Replace(docs[50], " ", "\n", -1))
+	for _, verify := range []bool{false, true} {
+		idx := difflib.NewDuplicateIndex(difflib.DuplicateOptions{Verify: verify})
+		for _, d := range docs {
+			idx.Add(d)
+		}
+		if idx.Len() != len(docs) {
+			t.Fatalf("Len() = %d, want %d", idx.Len(), len(docs))
+		}
+		pairs := idx.Pairs(0.8)
+		var got [][2]int
+		for _, p := range pairs {
+			got = append(got, [2]int{p.I, p.J})
+		}
+		want := [][2]int{{10, 200}}
+		if !verify {
+			// Words are shingled regardless of line breaks.
+			want = append(want, [2]int{50, 201})
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("verify=%v: Pairs() = %v, want %v", verify, got, want)
+		}
+		if verify && len(pairs) > 0 {
+			if want := difflib.SequenceRatio(difflib.SplitLines(docs[10]), difflib.SplitLines(docs[200])); pairs[0].Score != want {
+				t.Errorf("verified score = %v, want SequenceRatio %v", pairs[0].Score, want)
+			}
+		}
+		dups := idx.Query(docs[10], 0.8)
+		if len(dups) != 2 || dups[0].Index != 10 || dups[0].Score != 1 || dups[1].Index != 200 {
+			t.Errorf("verify=%v: Query() = %v, want documents 10 and 200", verify, dups)
+		}
+	}
+}
+
+func ExampleDuplicateIndex() {
+	idx := difflib.NewDuplicateIndex(difflib.DuplicateOptions{})
+	idx.Add("the quick brown fox jumps over the lazy dog near the river bank")
+	idx.Add("a completely different sentence about databases and indexes")
+	idx.Add("the quick brown fox jumps over the lazy dog near the river bend")
+	for _, p := range idx.Pairs(0.7) {
+		fmt.Println(p.I, p.J)
+	}
+	// Output:
+	// 0 2
+}
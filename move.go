@@ -0,0 +1,293 @@
+package difflib
+
+import "strings"
+
+const (
+	// OpMoveFrom marks lines deleted from A that reappear elsewhere in B.
+	// It is only produced by OpCodesWithMoves.
+	OpMoveFrom Op = OpReplace + 1 + iota
+	// OpMoveTo marks lines inserted into B that were moved from elsewhere
+	// in A. It is only produced by OpCodesWithMoves.
+	OpMoveTo
+)
+
+// MoveOptions controls moved-block detection.
+type MoveOptions struct {
+	// MinLines is the minimum number of lines in a moved block. Shorter
+	// blocks are reported as ordinary deletions and insertions. Defaults to 3.
+	MinLines int
+	// Threshold is the minimum StringRatio for a deleted and an inserted line
+	// to be considered the same, allowing near-verbatim moves such as
+	// re-indented code. Zero or 1 requires lines to be identical.
+	Threshold float64
+}
+
+// MovedBlock is a run of lines deleted at one place in A and inserted at
+// another place in B.
+type MovedBlock struct {
+	// A is the 0-based index in A of the first deleted line.
+	A int
+	// B is the 0-based index in B of the first inserted line.
+	B int
+	// Size is the number of lines in the block.
+	Size int
+	// Similarity is the mean StringRatio of the paired lines; it is 1 for a
+	// verbatim move.
+	Similarity float64
+}
+
+// DetectMoves finds blocks of lines that were deleted from one place in a
+// and inserted, verbatim or nearly so, at another place in b, like git's
+// --color-moved. A block is never paired with lines from the same change,
+// so in-place edits are not reported as moves. Blocks are returned in the
+// order of their position in b.
+//
+// Example:
+//
+//	for _, m := range difflib.DetectMoves(a, b, difflib.MoveOptions{}) {
+//	    fmt.Printf("lines %d-%d moved to %d\n", m.A+1, m.A+m.Size, m.B+1)
+//	}
+func DetectMoves(a, b []string, opts MoveOptions) []MovedBlock {
+	var dels, adds []moveLine
+	for run, c := range GetOpCodes(a, b) {
+		for i := c.I1; c.Tag != OpEqual && i < c.I2; i++ {
+			dels = append(dels, moveLine{idx: i, run: run, text: a[i]})
+		}
+		for j := c.J1; c.Tag != OpEqual && j < c.J2; j++ {
+			adds = append(adds, moveLine{idx: j, run: run, text: b[j]})
+		}
+	}
+	return findMoves(dels, adds, opts)
+}
+
+// OpCodesWithMoves returns the opcodes of a and b like GetOpCodes, with
+// deleted and inserted lines that belong to a moved block (see DetectMoves)
+// split out as OpMoveFrom and OpMoveTo. Within a change, the A-side pieces
+// come first, followed by the B-side pieces, so consecutive opcodes still
+// cover both sequences without gaps.
+//
+// Example:
+//
+//	for _, op := range difflib.OpCodesWithMoves(a, b, difflib.MoveOptions{}) {
+//	    if op.Tag == difflib.OpMoveTo {
+//	        fmt.Printf("moved here: %q\n", b[op.J1:op.J2])
+//	    }
+//	}
+func OpCodesWithMoves(a, b []string, opts MoveOptions) []OpCode {
+	moves := DetectMoves(a, b, opts)
+	movedA := make([]bool, len(a))
+	movedB := make([]bool, len(b))
+	for _, m := range moves {
+		for k := 0; k < m.Size; k++ {
+			movedA[m.A+k] = true
+			movedB[m.B+k] = true
+		}
+	}
+	var out []OpCode
+	for _, c := range GetOpCodes(a, b) {
+		if c.Tag == OpEqual || !anyTrue(movedA[c.I1:c.I2]) && !anyTrue(movedB[c.J1:c.J2]) {
+			out = append(out, c)
+			continue
+		}
+		for i := c.I1; i < c.I2; {
+			k := i
+			for k < c.I2 && movedA[k] == movedA[i] {
+				k++
+			}
+			tag := OpDelete
+			if movedA[i] {
+				tag = OpMoveFrom
+			}
+			out = append(out, OpCode{tag, i, k, c.J1, c.J1})
+			i = k
+		}
+		for j := c.J1; j < c.J2; {
+			k := j
+			for k < c.J2 && movedB[k] == movedB[j] {
+				k++
+			}
+			tag := OpInsert
+			if movedB[j] {
+				tag = OpMoveTo
+			}
+			out = append(out, OpCode{tag, c.I2, c.I2, j, k})
+			j = k
+		}
+	}
+	return out
+}
+
+// MovedLines reports, for each hunk and each of its lines, whether the line
+// is a '-' or '+' line belonging to a moved block (see DetectMoves). It works
+// on parsed patches too, since only the hunks are consulted; lines are
+// located using the hunk headers.
+//
+// Example:
+//
+//	moved := d.MovedLines(difflib.MoveOptions{})
+//	for i, h := range d.Hunks {
+//	    for k, l := range h.Lines {
+//	        if moved[i][k] {
+//	            fmt.Print("moved: ", l)
+//	        }
+//	    }
+//	}
+func (d DiffResult) MovedLines(opts MoveOptions) [][]bool {
+	blocks := d.movedBlocks(opts)
+	out := make([][]bool, len(blocks))
+	for hi, bl := range blocks {
+		out[hi] = make([]bool, len(bl))
+		for li, b := range bl {
+			out[hi][li] = b > 0
+		}
+	}
+	return out
+}
+
+// movedBlocks is MovedLines that numbers the moved blocks: each '-' or '+'
+// line of a moved block gets the block's 1-based index in the order of
+// DetectMoves, and every other line 0.
+func (d DiffResult) movedBlocks(opts MoveOptions) [][]int {
+	type pos struct{ hunk, line int }
+	var dels, adds []moveLine
+	var delPos, addPos []pos
+	run := 0
+	for hi, h := range d.Hunks {
+		i, j := hunkAnchor(h), newAnchor(h)
+		for li, l := range h.Lines {
+			if l == "" {
+				continue
+			}
+			switch l[0] {
+			case '-':
+				dels = append(dels, moveLine{idx: i, run: run, text: l[1:]})
+				delPos = append(delPos, pos{hi, li})
+				i++
+			case '+':
+				adds = append(adds, moveLine{idx: j, run: run, text: l[1:]})
+				addPos = append(addPos, pos{hi, li})
+				j++
+			case ' ':
+				run++
+				i++
+				j++
+			}
+		}
+		run++
+	}
+
+	out := make([][]int, len(d.Hunks))
+	for hi, h := range d.Hunks {
+		out[hi] = make([]int, len(h.Lines))
+	}
+	for n, m := range findMoves(dels, adds, opts) {
+		for k, dl := range dels {
+			if dl.idx >= m.A && dl.idx < m.A+m.Size {
+				out[delPos[k].hunk][delPos[k].line] = n + 1
+			}
+		}
+		for k, al := range adds {
+			if al.idx >= m.B && al.idx < m.B+m.Size {
+				out[addPos[k].hunk][addPos[k].line] = n + 1
+			}
+		}
+	}
+	return out
+}
+
+// moveLine is a deleted or inserted line considered for move detection.
+type moveLine struct {
+	// idx is the line's 0-based index in A (deletions) or B (insertions).
+	idx int
+	// run identifies the change the line belongs to; lines of the same
+	// change are never paired.
+	run  int
+	text string
+}
+
+// findMoves greedily pairs runs of inserted lines with the longest matching
+// run of deleted lines. dels and adds must be sorted by idx.
+func findMoves(dels, adds []moveLine, opts MoveOptions) []MovedBlock {
+	minLines := opts.MinLines
+	if minLines <= 0 {
+		minLines = 3
+	}
+	exact := opts.Threshold <= 0 || opts.Threshold >= 1
+	similarity := func(x, y string) float64 {
+		if x == y {
+			return 1
+		}
+		if exact {
+			return 0
+		}
+		if r := StringRatio(x, y); r >= opts.Threshold {
+			return r
+		}
+		return 0
+	}
+	byText := make(map[string][]int)
+	if exact {
+		for k, dl := range dels {
+			byText[dl.text] = append(byText[dl.text], k)
+		}
+	}
+
+	used := make([]bool, len(dels))
+	var moves []MovedBlock
+	for j := 0; j < len(adds); {
+		candidates := byText[adds[j].text]
+		if !exact {
+			candidates = candidates[:0]
+			for k := range dels {
+				candidates = append(candidates, k)
+			}
+		}
+		best, bestLen, bestSim := -1, 0, 0.0
+		for _, i := range candidates {
+			n, sum := 0, 0.0
+			for i+n < len(dels) && j+n < len(adds) && !used[i+n] && dels[i+n].run != adds[j+n].run {
+				if n > 0 && (dels[i+n].idx != dels[i+n-1].idx+1 || adds[j+n].idx != adds[j+n-1].idx+1) {
+					break
+				}
+				s := similarity(dels[i+n].text, adds[j+n].text)
+				if s == 0 {
+					break
+				}
+				sum += s
+				n++
+			}
+			if n > bestLen {
+				best, bestLen, bestSim = i, n, sum/float64(n)
+			}
+		}
+		if bestLen < minLines || blankLines(adds[j:j+bestLen]) {
+			j++
+			continue
+		}
+		for k := best; k < best+bestLen; k++ {
+			used[k] = true
+		}
+		moves = append(moves, MovedBlock{A: dels[best].idx, B: adds[j].idx, Size: bestLen, Similarity: bestSim})
+		j += bestLen
+	}
+	return moves
+}
+
+// blankLines reports whether every line is empty or whitespace.
+func blankLines(lines []moveLine) bool {
+	for _, l := range lines {
+		if strings.TrimSpace(l.text) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func anyTrue(flags []bool) bool {
+	for _, f := range flags {
+		if f {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,129 @@
+package difflib_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	difflib "github.com/njchilds90/go-difflib"
+)
+
+func TestDetectMoves(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		opts difflib.MoveOptions
+		want []difflib.MovedBlock
+	}{
+		{
+			name: "block moved",
+			a:    "f1\nf2\nf3\nx\ny\nz\nw\n",
+			b:    "x\ny\nz\nw\nf1\nf2\nf3\n",
+			want: []difflib.MovedBlock{{A: 0, B: 4, Size: 3, Similarity: 1}},
+		},
+		{
+			name: "too short",
+			a:    "f1\nf2\nx\ny\nz\n",
+			b:    "x\ny\nz\nf1\nf2\n",
+		},
+		{
+			name: "min lines",
+			a:    "f1\nf2\nx\ny\nz\n",
+			b:    "x\ny\nz\nf1\nf2\n",
+			opts: difflib.MoveOptions{MinLines: 2},
+			want: []difflib.MovedBlock{{A: 0, B: 3, Size: 2, Similarity: 1}},
+		},
+		{
+			name: "in-place edit is not a move",
+			a:    "x\nline one\nline two\nline three\ny\n",
+			b:    "x\nline one!\nline two!\nline three!\ny\n",
+			opts: difflib.MoveOptions{Threshold: 0.8},
+		},
+		{
+			name: "blank lines ignored",
+			a:    "\n\n\nx\ny\nz\nw\n",
+			b:    "x\ny\nz\nw\n\n\n\n",
+		},
+		{
+			name: "near verbatim",
+			a:    "func f() {\n\treturn 1\n}\nkeep\nkeep2\nkeep3\n",
+			b:    "keep\nkeep2\nkeep3\nfunc f() {\n    return 1\n}\n",
+			opts: difflib.MoveOptions{Threshold: 0.7},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := difflib.DetectMoves(difflib.SplitLines(tt.a), difflib.SplitLines(tt.b), tt.opts)
+			if tt.name == "near verbatim" {
+				if len(got) != 1 || got[0].Size != 3 || got[0].Similarity >= 1 || got[0].Similarity < 0.7 {
+					t.Errorf("DetectMoves() = %+v, want one near-verbatim block of 3 lines", got)
+				}
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DetectMoves() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOpCodesWithMoves(t *testing.T) {
+	a := difflib.SplitLines("f1\nf2\nf3\nx\ny\nz\nw\nold\n")
+	b := difflib.SplitLines("x\ny\nz\nw\nf1\nf2\nf3\nnew\n")
+	got := difflib.OpCodesWithMoves(a, b, difflib.MoveOptions{})
+	want := []difflib.OpCode{
+		{Tag: difflib.OpMoveFrom, I1: 0, I2: 3, J1: 0, J2: 0},
+		{Tag: difflib.OpEqual, I1: 3, I2: 7, J1: 0, J2: 4},
+		{Tag: difflib.OpDelete, I1: 7, I2: 8, J1: 4, J2: 4},
+		{Tag: difflib.OpMoveTo, I1: 8, I2: 8, J1: 4, J2: 7},
+		{Tag: difflib.OpInsert, I1: 8, I2: 8, J1: 7, J2: 8},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("OpCodesWithMoves() =\n%+v\nwant\n%+v", got, want)
+	}
+	// The opcodes must still cover both inputs contiguously.
+	i, j := 0, 0
+	for _, c := range got {
+		if c.I1 != i || c.J1 != j {
+			t.Fatalf("gap before %+v", c)
+		}
+		i, j = c.I2, c.J2
+	}
+	if i != len(a) || j != len(b) {
+		t.Errorf("opcodes end at %d,%d, want %d,%d", i, j, len(a), len(b))
+	}
+}
+
+func TestDiffResultMovedLines(t *testing.T) {
+	a := difflib.SplitLines("f1\nf2\nf3\n1\n2\n3\n4\n5\n6\n7\n8\nold\n")
+	b := difflib.SplitLines("1\n2\n3\n4\n5\n6\n7\n8\nf1\nf2\nf3\nnew\n")
+	d := difflib.UnifiedDiff(difflib.DiffInput{A: a, B: b})
+	// Round-trip through text so only the hunks are available.
+	ps, err := difflib.ParsePatchSet("--- a\n+++ b\n" + d.String()[len("--- \n+++ \n"):])
+	if err != nil {
+		t.Fatal(err)
+	}
+	moved := ps.Files[0].MovedLines(difflib.MoveOptions{})
+	var got []string
+	for i, h := range ps.Files[0].Hunks {
+		for k, l := range h.Lines {
+			if moved[i][k] {
+				got = append(got, l)
+			}
+		}
+	}
+	want := []string{"-f1\n", "-f2\n", "-f3\n", "+f1\n", "+f2\n", "+f3\n"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("moved lines = %q, want %q", got, want)
+	}
+}
+
+func ExampleDetectMoves() {
+	a := difflib.SplitLines("import\nfunc a\nbody a\nend a\nfunc b\nbody b\nend b\nreturn b\n")
+	b := difflib.SplitLines("import\nfunc b\nbody b\nend b\nreturn b\nfunc a\nbody a\nend a\n")
+	for _, m := range difflib.DetectMoves(a, b, difflib.MoveOptions{}) {
+		fmt.Printf("lines %d-%d moved to %d\n", m.A+1, m.A+m.Size, m.B+1)
+	}
+	// Output:
+	// lines 2-4 moved to 6
+}
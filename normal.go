@@ -0,0 +1,62 @@
+package difflib
+
+import (
+	"fmt"
+	"strings"
+)
+
+// NormalDiff returns the difference between a and b in the "normal" format
+// that diff(1) writes by default. Each change starts with a command such as
+// "3c3", "5a6,7" or "8,9d9", naming the affected lines of a and b, followed
+// by the removed lines prefixed with "< " and the added lines prefixed with
+// "> ", separated by "---" for a change. A line without a trailing newline
+// is followed by "\ No newline at end of file". The result is empty when a
+// and b are equal.
+//
+// Example:
+//
+//	out := difflib.NormalDiff(
+//	    difflib.SplitLines("one\ntwo\nthree\n"),
+//	    difflib.SplitLines("one\nTWO\nthree\n"),
+//	)
+//	// 2c2
+//	// < two
+//	// ---
+//	// > TWO
+func NormalDiff(a, b []string) string {
+	var sb strings.Builder
+	lines := func(prefix string, ls []string) {
+		for _, l := range ls {
+			sb.WriteString(prefix)
+			sb.WriteString(l)
+			if !strings.HasSuffix(l, "\n") {
+				sb.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+	}
+	for _, op := range GetOpCodes(a, b) {
+		switch op.Tag {
+		case OpDelete:
+			fmt.Fprintf(&sb, "%sd%d\n", normalRange(op.I1, op.I2), op.J1)
+			lines("< ", a[op.I1:op.I2])
+		case OpInsert:
+			fmt.Fprintf(&sb, "%da%s\n", op.I1, normalRange(op.J1, op.J2))
+			lines("> ", b[op.J1:op.J2])
+		case OpReplace:
+			fmt.Fprintf(&sb, "%sc%s\n", normalRange(op.I1, op.I2), normalRange(op.J1, op.J2))
+			lines("< ", a[op.I1:op.I2])
+			sb.WriteString("---\n")
+			lines("> ", b[op.J1:op.J2])
+		}
+	}
+	return sb.String()
+}
+
+// normalRange formats the 0-based half-open range [lo, hi) as the 1-based
+// "L" or "L,H" used by normal diff commands.
+func normalRange(lo, hi int) string {
+	if hi-lo == 1 {
+		return fmt.Sprint(hi)
+	}
+	return fmt.Sprintf("%d,%d", lo+1, hi)
+}
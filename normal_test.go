@@ -0,0 +1,45 @@
+package difflib_test
+
+import (
+	"fmt"
+	"testing"
+
+	difflib "github.com/njchilds90/go-difflib"
+)
+
+func TestNormalDiff(t *testing.T) {
+	tests := []struct {
+		name, a, b, want string
+	}{
+		{"equal", "x\n", "x\n", ""},
+		{"change", "one\ntwo\nthree\n", "one\nTWO\nthree\n", "2c2\n< two\n---\n> TWO\n"},
+		{"change ranges", "a\nb\nc\nd\n", "a\nB\nC\nD\nd\n", "2,3c2,4\n< b\n< c\n---\n> B\n> C\n> D\n"},
+		{"insert", "a\nd\n", "a\nb\nc\nd\n", "1a2,3\n> b\n> c\n"},
+		{"insert at start", "b\n", "a\nb\n", "0a1\n> a\n"},
+		{"delete", "a\nb\nc\nd\n", "a\nd\n", "2,3d1\n< b\n< c\n"},
+		{"delete at end", "a\nb\n", "a\n", "2d1\n< b\n"},
+		{"no newline", "a\nb", "a\nc", "2c2\n< b\n\\ No newline at end of file\n---\n> c\n\\ No newline at end of file\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := difflib.NormalDiff(difflib.SplitLines(tt.a), difflib.SplitLines(tt.b))
+			if got != tt.want {
+				t.Errorf("NormalDiff() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func ExampleNormalDiff() {
+	fmt.Print(difflib.NormalDiff(
+		difflib.SplitLines("one\ntwo\nthree\n"),
+		difflib.SplitLines("one\nTWO\nthree\nfour\n"),
+	))
+	// Output:
+	// 2c2
+	// < two
+	// ---
+	// > TWO
+	// 3a4
+	// > four
+}
@@ -0,0 +1,90 @@
+package difflib
+
+import "fmt"
+
+// CompactOpCodes returns codes in their simplest form: opcodes with empty
+// ranges are dropped, adjacent equal opcodes are joined, runs of adjacent
+// edits become a single delete, insert or replace, and an edit whose a or b
+// range is empty is tagged delete or insert rather than replace. codes must
+// cover both sequences contiguously, as ValidateOpCodes checks; the result
+// then describes the same edit.
+//
+// Example:
+//
+//	codes = difflib.CompactOpCodes(append(codes, extra...))
+func CompactOpCodes(codes []OpCode) []OpCode {
+	var out []OpCode
+	for _, c := range codes {
+		if c.I1 == c.I2 && c.J1 == c.J2 {
+			continue
+		}
+		if k := len(out) - 1; k >= 0 && c.Tag == OpEqual && out[k].Tag == OpEqual {
+			out[k].I2, out[k].J2 = c.I2, c.J2
+			continue
+		}
+		if c.Tag != OpEqual {
+			c.Tag = editTag(c)
+		}
+		out = append(out, c)
+	}
+	return mergeEdits(out)
+}
+
+// ValidateOpCodes checks that codes transform a into b: they start at the
+// beginning of both sequences, each starts where the previous one ends,
+// they end at the end of both, equal opcodes cover identical lines, and
+// deletes and inserts cover lines on one side only. It returns the first
+// problem found. Opcodes built or transformed by hand should be validated
+// before they are rendered.
+//
+// Example:
+//
+//	if err := difflib.ValidateOpCodes(a, b, codes); err != nil {
+//	    return err // difflib: opcode #3: starts at 4,4, not at 5,4 where #2 ends
+//	}
+func ValidateOpCodes(a, b []string, codes []OpCode) error {
+	i, j := 0, 0
+	for n, c := range codes {
+		fail := func(format string, args ...any) error {
+			return fmt.Errorf("difflib: opcode #%d: %s", n+1, fmt.Sprintf(format, args...))
+		}
+		switch {
+		case c.I1 != i || c.J1 != j:
+			if n == 0 {
+				return fail("starts at %d,%d, not at 0,0", c.I1, c.J1)
+			}
+			return fail("starts at %d,%d, not at %d,%d where #%d ends", c.I1, c.J1, i, j, n)
+		case c.I2 < c.I1 || c.J2 < c.J1:
+			return fail("ends at %d,%d, before it starts", c.I2, c.J2)
+		case c.I2 > len(a) || c.J2 > len(b):
+			return fail("ends at %d,%d, past the ends %d,%d", c.I2, c.J2, len(a), len(b))
+		}
+		switch c.Tag {
+		case OpEqual:
+			if c.I2-c.I1 != c.J2-c.J1 {
+				return fail("equal ranges have different lengths %d and %d", c.I2-c.I1, c.J2-c.J1)
+			}
+			for k := 0; k < c.I2-c.I1; k++ {
+				if a[c.I1+k] != b[c.J1+k] {
+					return fail("equal lines a[%d] and b[%d] differ", c.I1+k, c.J1+k)
+				}
+			}
+		case OpDelete:
+			if c.J1 != c.J2 {
+				return fail("delete covers %d lines of b", c.J2-c.J1)
+			}
+		case OpInsert:
+			if c.I1 != c.I2 {
+				return fail("insert covers %d lines of a", c.I2-c.I1)
+			}
+		case OpReplace:
+		default:
+			return fail("has tag %s, not equal, delete, insert or replace", c.Tag)
+		}
+		i, j = c.I2, c.J2
+	}
+	if i != len(a) || j != len(b) {
+		return fmt.Errorf("difflib: opcodes end at %d,%d, not at the ends %d,%d", i, j, len(a), len(b))
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package difflib_test
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+
+	difflib "github.com/njchilds90/go-difflib"
+)
+
+func TestCompactOpCodes(t *testing.T) {
+	tests := []struct {
+		name  string
+		codes []difflib.OpCode
+		want  []difflib.OpCode
+	}{
+		{"empty", nil, nil},
+		{"drops empty ranges",
+			[]difflib.OpCode{{difflib.OpEqual, 0, 0, 0, 0}, {difflib.OpEqual, 0, 2, 0, 2}, {difflib.OpInsert, 2, 2, 2, 2}},
+			[]difflib.OpCode{{difflib.OpEqual, 0, 2, 0, 2}}},
+		{"joins equals",
+			[]difflib.OpCode{{difflib.OpEqual, 0, 1, 0, 1}, {difflib.OpEqual, 1, 3, 1, 3}},
+			[]difflib.OpCode{{difflib.OpEqual, 0, 3, 0, 3}}},
+		{"delete and insert become replace",
+			[]difflib.OpCode{{difflib.OpEqual, 0, 1, 0, 1}, {difflib.OpDelete, 1, 2, 1, 1}, {difflib.OpInsert, 2, 2, 1, 3}, {difflib.OpEqual, 2, 3, 3, 4}},
+			[]difflib.OpCode{{difflib.OpEqual, 0, 1, 0, 1}, {difflib.OpReplace, 1, 2, 1, 3}, {difflib.OpEqual, 2, 3, 3, 4}}},
+		{"joins deletes",
+			[]difflib.OpCode{{difflib.OpDelete, 0, 1, 0, 0}, {difflib.OpDelete, 1, 3, 0, 0}},
+			[]difflib.OpCode{{difflib.OpDelete, 0, 3, 0, 0}}},
+		{"retags one-sided replace",
+			[]difflib.OpCode{{difflib.OpReplace, 0, 0, 0, 2}, {difflib.OpEqual, 0, 1, 2, 3}, {difflib.OpReplace, 1, 2, 3, 3}},
+			[]difflib.OpCode{{difflib.OpInsert, 0, 0, 0, 2}, {difflib.OpEqual, 0, 1, 2, 3}, {difflib.OpDelete, 1, 2, 3, 3}}},
+	}
+	for _, tt := range tests {
+		if got := difflib.CompactOpCodes(tt.codes); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: CompactOpCodes = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCompactOpCodesRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for iter := 0; iter < 200; iter++ {
+		a, b := randomLines(rng, rng.Intn(20)), randomLines(rng, rng.Intn(20))
+		codes := difflib.GetOpCodes(a, b)
+		if got := difflib.CompactOpCodes(codes); !reflect.DeepEqual(got, codes) {
+			t.Fatalf("iteration %d: CompactOpCodes(%v) = %v", iter, codes, got)
+		}
+		// Split every opcode into single-line pieces and compact them back.
+		var split []difflib.OpCode
+		for _, c := range codes {
+			for i, j := c.I1, c.J1; i < c.I2 || j < c.J2; {
+				p := difflib.OpCode{Tag: c.Tag, I1: i, J1: j, I2: min(i+1, c.I2), J2: min(j+1, c.J2)}
+				split = append(split, p, difflib.OpCode{Tag: difflib.OpReplace, I1: p.I2, I2: p.I2, J1: p.J2, J2: p.J2})
+				i, j = p.I2, p.J2
+			}
+		}
+		got := difflib.CompactOpCodes(split)
+		if err := difflib.ValidateOpCodes(a, b, got); err != nil || !reflect.DeepEqual(got, codes) {
+			t.Fatalf("iteration %d: CompactOpCodes(split) = %v (%v), want %v", iter, got, err, codes)
+		}
+	}
+}
+
+func TestValidateOpCodes(t *testing.T) {
+	a := difflib.SplitLines("a\nb\nc\n")
+	b := difflib.SplitLines("a\nB\nc\nd\n")
+	tests := []struct {
+		name  string
+		codes []difflib.OpCode
+		want  string
+	}{
+		{"valid", difflib.GetOpCodes(a, b), ""},
+		{"late start", []difflib.OpCode{{difflib.OpEqual, 1, 1, 0, 0}},
+			"difflib: opcode #1: starts at 1,0, not at 0,0"},
+		{"gap", []difflib.OpCode{{difflib.OpEqual, 0, 1, 0, 1}, {difflib.OpReplace, 2, 3, 1, 4}},
+			"difflib: opcode #2: starts at 2,1, not at 1,1 where #1 ends"},
+		{"backwards", []difflib.OpCode{{difflib.OpDelete, 0, -1, 0, 0}},
+			"difflib: opcode #1: ends at -1,0, before it starts"},
+		{"past end", []difflib.OpCode{{difflib.OpReplace, 0, 4, 0, 4}},
+			"difflib: opcode #1: ends at 4,4, past the ends 3,4"},
+		{"equal lengths", []difflib.OpCode{{difflib.OpEqual, 0, 1, 0, 2}},
+			"difflib: opcode #1: equal ranges have different lengths 1 and 2"},
+		{"equal content", []difflib.OpCode{{difflib.OpEqual, 0, 2, 0, 2}},
+			"difflib: opcode #1: equal lines a[1] and b[1] differ"},
+		{"delete", []difflib.OpCode{{difflib.OpDelete, 0, 3, 0, 4}},
+			"difflib: opcode #1: delete covers 4 lines of b"},
+		{"insert", []difflib.OpCode{{difflib.OpInsert, 0, 3, 0, 4}},
+			"difflib: opcode #1: insert covers 3 lines of a"},
+		{"tag", []difflib.OpCode{{difflib.OpMoveTo, 0, 3, 0, 4}},
+			"difflib: opcode #1: has tag move-to, not equal, delete, insert or replace"},
+		{"short", []difflib.OpCode{{difflib.OpEqual, 0, 1, 0, 1}},
+			"difflib: opcodes end at 1,1, not at the ends 3,4"},
+	}
+	for _, tt := range tests {
+		err := difflib.ValidateOpCodes(a, b, tt.codes)
+		if tt.want == "" {
+			if err != nil {
+				t.Errorf("%s: ValidateOpCodes() = %v, want nil", tt.name, err)
+			}
+			continue
+		}
+		if err == nil || err.Error() != tt.want {
+			t.Errorf("%s: ValidateOpCodes() = %v, want %q", tt.name, err, tt.want)
+		}
+	}
+}
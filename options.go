@@ -0,0 +1,160 @@
+package difflib
+
+import "regexp"
+
+// Option configures Diff and Render. Options are applied in order, so a
+// later option overrides an earlier one of the same kind.
+type Option func(*diffConfig)
+
+// Algorithm computes the opcodes that turn a into b. The opcodes must cover
+// both sequences in order, as Matcher.GetOpCodes does.
+type Algorithm func(a, b []string) []OpCode
+
+// Renderer turns a diff into text, such as DiffResult.String or a closure
+// around RenderHTML.
+type Renderer func(d DiffResult) string
+
+// diffConfig collects the settings of Diff's options.
+type diffConfig struct {
+	input     DiffInput
+	algorithm Algorithm
+	key       func(line string) string
+	render    Renderer
+}
+
+// WithLabels sets the file names written in the --- and +++ headers.
+func WithLabels(from, to string) Option {
+	return func(c *diffConfig) { c.input.FromFile, c.input.ToFile = from, to }
+}
+
+// WithDates sets the timestamps written after the labels, as
+// DiffInput.FromDate and ToDate do.
+func WithDates(from, to string) Option {
+	return func(c *diffConfig) { c.input.FromDate, c.input.ToDate = from, to }
+}
+
+// WithContext sets the number of unchanged lines around each change. Zero
+// means no context lines here, unlike DiffInput.Context; the default is 3.
+func WithContext(n int) Option {
+	if n == 0 {
+		n = NoContext
+	}
+	return func(c *diffConfig) { c.input.Context = n }
+}
+
+// WithMaxComparisons bounds the matching effort, as
+// DiffInput.MaxComparisons does. It has no effect with WithAlgorithm.
+func WithMaxComparisons(n int) Option {
+	return func(c *diffConfig) { c.input.MaxComparisons = n }
+}
+
+// WithTieBreak sets the rule for choosing between equally long matches, as
+// DiffInput.TieBreak does. It has no effect with WithAlgorithm.
+func WithTieBreak(t TieBreak) Option {
+	return func(c *diffConfig) { c.input.TieBreak = t }
+}
+
+// WithIndentHeuristic slides changes to natural boundaries, as
+// DiffInput.IndentHeuristic does.
+func WithIndentHeuristic() Option {
+	return func(c *diffConfig) { c.input.IndentHeuristic = true }
+}
+
+// WithFuncPattern labels each hunk with the nearest preceding line of a
+// that matches re, as DiffInput.FuncPattern does.
+func WithFuncPattern(re *regexp.Regexp) Option {
+	return func(c *diffConfig) { c.input.FuncPattern = re }
+}
+
+// WithHunkLabel labels each hunk by calling fn, as DiffInput.HunkLabel does.
+func WithHunkLabel(fn func(aStart int) string) Option {
+	return func(c *diffConfig) { c.input.HunkLabel = fn }
+}
+
+// WithAlgorithm computes the diff with alg instead of the default Matcher.
+func WithAlgorithm(alg Algorithm) Option {
+	return func(c *diffConfig) { c.algorithm = alg }
+}
+
+// WithLineKey compares lines by key(line) instead of by their text, so
+// lines with equal keys count as unchanged. Unchanged lines are shown as
+// they are in a.
+//
+// Example:
+//
+//	// Ignore changes in indentation and trailing whitespace.
+//	d := difflib.Diff(a, b, difflib.WithLineKey(strings.TrimSpace))
+func WithLineKey(key func(line string) string) Option {
+	return func(c *diffConfig) { c.key = key }
+}
+
+// WithRenderer sets the Renderer used by Render. The default is
+// DiffResult.String.
+func WithRenderer(r Renderer) Option {
+	return func(c *diffConfig) { c.render = r }
+}
+
+// Diff computes the unified diff of a and b configured by opts. It is
+// UnifiedDiff with options in place of a DiffInput, and also accepts the
+// options that DiffInput has no field for.
+//
+// Example:
+//
+//	d := difflib.Diff(a, b,
+//	    difflib.WithLabels("a/main.go", "b/main.go"),
+//	    difflib.WithContext(1),
+//	)
+//	fmt.Print(d)
+func Diff(a, b []string, opts ...Option) DiffResult {
+	c := newDiffConfig(a, b, opts)
+	return unifiedFromOpCodes(c.input, c.opCodes())
+}
+
+// Render is Diff followed by the Renderer set with WithRenderer, or by
+// DiffResult.String if there is none.
+//
+// Example:
+//
+//	page := difflib.Render(a, b, difflib.WithRenderer(func(d difflib.DiffResult) string {
+//	    return difflib.RenderHTML(d, difflib.HTMLOptions{})
+//	}))
+func Render(a, b []string, opts ...Option) string {
+	c := newDiffConfig(a, b, opts)
+	d := unifiedFromOpCodes(c.input, c.opCodes())
+	if c.render == nil {
+		return d.String()
+	}
+	return c.render(d)
+}
+
+// newDiffConfig applies opts to the configuration for diffing a and b.
+func newDiffConfig(a, b []string, opts []Option) *diffConfig {
+	c := &diffConfig{input: DiffInput{A: a, B: b}}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// opCodes diffs the configured inputs, by key if one is set.
+func (c *diffConfig) opCodes() []OpCode {
+	a, b := c.input.A, c.input.B
+	if c.key != nil {
+		a, b = lineKeys(a, c.key), lineKeys(b, c.key)
+	}
+	if c.algorithm != nil {
+		return c.algorithm(a, b)
+	}
+	input := c.input
+	input.A, input.B = a, b
+	return input.matcher().GetOpCodes()
+}
+
+// lineKeys returns the key of every line.
+func lineKeys(lines []string, key func(string) string) []string {
+	keys := make([]string, len(lines))
+	for i, l := range lines {
+		keys[i] = key(l)
+	}
+	return keys
+}
@@ -0,0 +1,107 @@
+package difflib_test
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	difflib "github.com/njchilds90/go-difflib"
+)
+
+func TestDiffMatchesUnifiedDiff(t *testing.T) {
+	a := numbered(30)
+	b := append([]string(nil), a...)
+	b[4] = "five\n"
+	b[20] = "twenty-one\n"
+	re := regexp.MustCompile(`^line 1`)
+	tests := []struct {
+		name  string
+		opts  []difflib.Option
+		input difflib.DiffInput
+	}{
+		{"defaults", nil, difflib.DiffInput{}},
+		{"labels", []difflib.Option{difflib.WithLabels("a/x", "b/x"), difflib.WithDates("d1", "d2")},
+			difflib.DiffInput{FromFile: "a/x", ToFile: "b/x", FromDate: "d1", ToDate: "d2"}},
+		{"context", []difflib.Option{difflib.WithContext(1)}, difflib.DiffInput{Context: 1}},
+		{"zero context", []difflib.Option{difflib.WithContext(0)}, difflib.DiffInput{Context: difflib.NoContext}},
+		{"func pattern", []difflib.Option{difflib.WithFuncPattern(re)}, difflib.DiffInput{FuncPattern: re}},
+		{"later wins", []difflib.Option{difflib.WithContext(0), difflib.WithContext(5)}, difflib.DiffInput{Context: 5}},
+	}
+	for _, tt := range tests {
+		input := tt.input
+		input.A, input.B = a, b
+		want := difflib.UnifiedDiff(input).String()
+		if got := difflib.Diff(a, b, tt.opts...).String(); got != want {
+			t.Errorf("%s: Diff =\n%s\nwant\n%s", tt.name, got, want)
+		}
+		if got := difflib.Render(a, b, tt.opts...); got != want {
+			t.Errorf("%s: Render =\n%s\nwant\n%s", tt.name, got, want)
+		}
+	}
+}
+
+func TestDiffWithLineKey(t *testing.T) {
+	a := difflib.SplitLines("func f() {\n\treturn 1\n}\n")
+	b := difflib.SplitLines("func f() {\n    return 1  \n}\nfunc g() {}\n")
+	d := difflib.Diff(a, b, difflib.WithLineKey(strings.TrimSpace), difflib.WithContext(0))
+	want := "@@ -3,0 +4,1 @@\n+func g() {}\n"
+	if got := hunksString(d); got != want {
+		t.Errorf("Diff with key = %q, want %q", got, want)
+	}
+	if len(difflib.Diff(a, b).Hunks[0].Lines) == 1 {
+		t.Error("Diff without key ignored whitespace")
+	}
+}
+
+func TestDiffWithAlgorithm(t *testing.T) {
+	// Replace everything: the coarsest correct diff.
+	whole := func(a, b []string) []difflib.OpCode {
+		return []difflib.OpCode{{Tag: difflib.OpReplace, I1: 0, I2: len(a), J1: 0, J2: len(b)}}
+	}
+	a, b := numbered(3), difflib.SplitLines("line 1\nline two\nline 3\n")
+	d := difflib.Diff(a, b, difflib.WithAlgorithm(whole))
+	want := "@@ -1,3 +1,3 @@\n-line 1\n-line 2\n-line 3\n+line 1\n+line two\n+line 3\n"
+	if got := hunksString(d); got != want {
+		t.Errorf("Diff = %q, want %q", got, want)
+	}
+}
+
+func TestRenderWithRenderer(t *testing.T) {
+	a, b := numbered(3), difflib.SplitLines("line 1\nline two\nline 3\n")
+	got := difflib.Render(a, b, difflib.WithRenderer(func(d difflib.DiffResult) string {
+		return fmt.Sprintf("%d hunk(s)", len(d.Hunks))
+	}))
+	if got != "1 hunk(s)" {
+		t.Errorf("Render = %q", got)
+	}
+	if got := difflib.Render(a, a); got != "" {
+		t.Errorf("Render of equal inputs = %q, want empty", got)
+	}
+}
+
+// hunksString returns the hunks of d without the file header.
+func hunksString(d difflib.DiffResult) string {
+	var b strings.Builder
+	for _, h := range d.Hunks {
+		h.WriteTo(&b)
+	}
+	return b.String()
+}
+
+func ExampleDiff() {
+	a := difflib.SplitLines("one\ntwo\nthree\nfour\n")
+	b := difflib.SplitLines("one\nTWO\nthree\nfour\n")
+	fmt.Print(difflib.Diff(a, b,
+		difflib.WithLabels("a/n.txt", "b/n.txt"),
+		difflib.WithContext(1),
+	))
+	// Output:
+	// --- a/n.txt
+	// +++ b/n.txt
+	// @@ -1,3 +1,3 @@
+	//  one
+	// -two
+	// +TWO
+	//  three
+}
@@ -0,0 +1,197 @@
+package difflib
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// parseDiff parses a single-file unified diff into a DiffResult.
+// Lines before the first hunk other than the --- / +++ headers are ignored,
+// as are lines between hunks, so patches produced by git (with "diff --git"
+// and "index" lines) are accepted. Hunk bodies are read using the counts in
+// the hunk header.
+func parseDiff(patch string) (DiffResult, error) {
+	var d DiffResult
+	lines := SplitLines(patch)
+	i := 0
+	for i < len(lines) {
+		line := lines[i]
+		switch {
+		case strings.HasPrefix(line, "--- ") && len(d.Hunks) == 0:
+			d.FromFile, d.FromDate = parseFileLabel(line[4:])
+			i++
+		case strings.HasPrefix(line, "+++ ") && len(d.Hunks) == 0:
+			d.ToFile, d.ToDate = parseFileLabel(line[4:])
+			i++
+		case strings.HasPrefix(line, "@@"):
+			h, n, err := parseHunk(lines[i:], i+1)
+			if err != nil {
+				return DiffResult{}, err
+			}
+			d.Hunks = append(d.Hunks, h)
+			i += n
+		default:
+			i++
+		}
+	}
+	return d, nil
+}
+
+// parseFileLabel splits the remainder of a --- or +++ header line into the
+// file name, unquoted if it is quoted, and the tab-separated timestamp, if
+// any, dropping the line ending.
+func parseFileLabel(s string) (name, date string) {
+	s = strings.TrimRight(s, "\r\n")
+	if name, rest, ok := unquoteName(s); ok {
+		return name, strings.TrimPrefix(rest, "\t")
+	}
+	name, date, _ = strings.Cut(s, "\t")
+	return name, date
+}
+
+// parseHunk parses a hunk header and its body from lines, returning the hunk
+// and the number of lines consumed. lineno is the 1-based line number of the
+// header within the patch and is used in error messages.
+func parseHunk(lines []string, lineno int) (Hunk, int, error) {
+	h, err := parseHunkHeader(lines[0])
+	if err != nil {
+		return Hunk{}, 0, fmt.Errorf("difflib: line %d: %w", lineno, err)
+	}
+	oldLeft, newLeft := h.OldLines, h.NewLines
+	n := 1
+	for oldLeft > 0 || newLeft > 0 {
+		if n >= len(lines) {
+			return Hunk{}, 0, fmt.Errorf("difflib: line %d: hunk ends early: want %d more old and %d more new lines",
+				lineno, oldLeft, newLeft)
+		}
+		l := lines[n]
+		switch {
+		case strings.HasPrefix(l, " "):
+			oldLeft--
+			newLeft--
+		case strings.HasPrefix(l, "-"):
+			oldLeft--
+		case strings.HasPrefix(l, "+"):
+			newLeft--
+		case strings.HasPrefix(l, `\`):
+			// "\ No newline at end of file" applies to the line before.
+			trimLastNewline(h.Lines)
+			n++
+			continue
+		default:
+			return Hunk{}, 0, fmt.Errorf("difflib: line %d: malformed hunk line %q", lineno+n, l)
+		}
+		if oldLeft < 0 || newLeft < 0 {
+			return Hunk{}, 0, fmt.Errorf("difflib: line %d: hunk body does not match header %q",
+				lineno+n, strings.TrimRight(lines[0], "\r\n"))
+		}
+		h.Lines = append(h.Lines, l)
+		n++
+	}
+	for n < len(lines) && strings.HasPrefix(lines[n], `\`) {
+		trimLastNewline(h.Lines)
+		n++
+	}
+	return h, n, nil
+}
+
+// noNewlineMarker follows a hunk line that has no line ending, as in diff(1).
+const noNewlineMarker = "\\ No newline at end of file\n"
+
+// trimLastNewline removes the newline from the last of lines, which a
+// "\ No newline at end of file" marker says the file does not have.
+func trimLastNewline(lines []string) {
+	if n := len(lines); n > 0 {
+		lines[n-1] = strings.TrimSuffix(lines[n-1], "\n")
+	}
+}
+
+// parseHunkHeader parses "@@ -l,s +l,s @@ section" into a Hunk with empty
+// Lines.
+// Counts may be omitted, in which case they default to 1.
+func parseHunkHeader(line string) (Hunk, error) {
+	bad := fmt.Errorf("malformed hunk header: %q", strings.TrimRight(line, "\r\n"))
+	rest, ok := strings.CutPrefix(line, "@@ -")
+	if !ok {
+		return Hunk{}, bad
+	}
+	ranges, section, ok := strings.Cut(rest, " @@")
+	if !ok {
+		return Hunk{}, bad
+	}
+	oldRange, newRange, ok := strings.Cut(ranges, " +")
+	if !ok {
+		return Hunk{}, bad
+	}
+	var h Hunk
+	var err1, err2 error
+	h.OldStart, h.OldLines, err1 = parseRange(oldRange)
+	h.NewStart, h.NewLines, err2 = parseRange(newRange)
+	if err1 != nil || err2 != nil {
+		return Hunk{}, bad
+	}
+	// A range of lines starts at line 1; only an empty range starts at 0.
+	if (h.OldStart == 0 && h.OldLines > 0) || (h.NewStart == 0 && h.NewLines > 0) {
+		return Hunk{}, bad
+	}
+	if section, ok := strings.CutPrefix(section, " "); ok {
+		h.Section = strings.TrimRight(section, " \t\r\n")
+	}
+	return h, nil
+}
+
+func parseRange(s string) (start, count int, err error) {
+	startStr, countStr, hasCount := strings.Cut(s, ",")
+	start, err = strconv.Atoi(startStr)
+	if err != nil || start < 0 {
+		return 0, 0, fmt.Errorf("bad range %q", s)
+	}
+	count = 1
+	if hasCount {
+		count, err = strconv.Atoi(countStr)
+		if err != nil || count < 0 {
+			return 0, 0, fmt.Errorf("bad range %q", s)
+		}
+	}
+	return start, count, nil
+}
+
+// hunkSides splits the hunk body into the lines it expects in the original
+// (context and deletions) and the lines it produces (context and insertions),
+// with the diff prefixes removed.
+func hunkSides(h Hunk) (from, to []string) {
+	for _, l := range h.Lines {
+		if l == "" {
+			continue
+		}
+		switch l[0] {
+		case ' ':
+			from = append(from, l[1:])
+			to = append(to, l[1:])
+		case '-':
+			from = append(from, l[1:])
+		case '+':
+			to = append(to, l[1:])
+		}
+	}
+	return from, to
+}
+
+// hunkContext returns the number of context lines before the first change
+// and after the last change in h.
+func hunkContext(h Hunk) (leading, trailing int) {
+	for _, l := range h.Lines {
+		if !strings.HasPrefix(l, " ") {
+			break
+		}
+		leading++
+	}
+	if leading == len(h.Lines) {
+		return leading, 0
+	}
+	for i := len(h.Lines) - 1; i >= 0 && strings.HasPrefix(h.Lines[i], " "); i-- {
+		trailing++
+	}
+	return leading, trailing
+}
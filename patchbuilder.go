@@ -0,0 +1,132 @@
+package difflib
+
+import "fmt"
+
+// PatchBuilder builds a patch holding a selection of the changes of a diff,
+// as `git add -p` does: whole hunks or single added and removed lines are
+// picked, and Build returns a diff that makes only those changes. Nothing
+// is selected initially.
+//
+// Example:
+//
+//	pb := difflib.NewPatchBuilder(d)
+//	pb.SelectHunk(0)
+//	pb.SelectLines(2, 4, 5) // two lines of the third hunk
+//	staged, err := pb.Build()
+type PatchBuilder struct {
+	diff DiffResult
+	// selected marks the chosen change lines of each hunk by index in Lines.
+	selected [][]bool
+}
+
+// NewPatchBuilder returns a builder for selections of the changes of d.
+// Hunk and line numbers are indexes into d.Hunks and each hunk's Lines.
+func NewPatchBuilder(d DiffResult) *PatchBuilder {
+	b := &PatchBuilder{diff: d, selected: make([][]bool, len(d.Hunks))}
+	for n, h := range d.Hunks {
+		b.selected[n] = make([]bool, len(h.Lines))
+	}
+	return b
+}
+
+// SelectHunk selects every change of hunk n.
+func (b *PatchBuilder) SelectHunk(n int) error {
+	return b.setHunk(n, true)
+}
+
+// DeselectHunk deselects every change of hunk n.
+func (b *PatchBuilder) DeselectHunk(n int) error {
+	return b.setHunk(n, false)
+}
+
+// SelectLines selects the given added or removed lines of hunk n. A removed
+// line that is not selected stays in the file; an added line that is not
+// selected is left out.
+func (b *PatchBuilder) SelectLines(n int, lines ...int) error {
+	return b.setLines(n, lines, true)
+}
+
+// DeselectLines deselects the given added or removed lines of hunk n.
+func (b *PatchBuilder) DeselectLines(n int, lines ...int) error {
+	return b.setLines(n, lines, false)
+}
+
+func (b *PatchBuilder) setHunk(n int, v bool) error {
+	if n < 0 || n >= len(b.diff.Hunks) {
+		return fmt.Errorf("difflib: hunk %d out of range [0, %d)", n, len(b.diff.Hunks))
+	}
+	for k, l := range b.diff.Hunks[n].Lines {
+		b.selected[n][k] = v && isChangeLine(l)
+	}
+	return nil
+}
+
+func (b *PatchBuilder) setLines(n int, lines []int, v bool) error {
+	if n < 0 || n >= len(b.diff.Hunks) {
+		return fmt.Errorf("difflib: hunk %d out of range [0, %d)", n, len(b.diff.Hunks))
+	}
+	h := b.diff.Hunks[n]
+	for _, k := range lines {
+		if k < 0 || k >= len(h.Lines) {
+			return fmt.Errorf("difflib: hunk %d: line %d out of range [0, %d)", n, k, len(h.Lines))
+		}
+		if !isChangeLine(h.Lines[k]) {
+			return fmt.Errorf("difflib: hunk %d: line %d is not an added or removed line", n, k)
+		}
+	}
+	for _, k := range lines {
+		b.selected[n][k] = v
+	}
+	return nil
+}
+
+// Build returns a diff against the same original that makes only the
+// selected changes. Removed lines that are not selected become context,
+// added lines that are not selected are dropped, hunks left without changes
+// are omitted, and headers are recomputed, so later hunks account for the
+// changes left out before them. The result is checked with
+// DiffResult.Validate; a selection can fail it by keeping a removed last
+// line that has no newline while adding lines after it.
+func (b *PatchBuilder) Build() (DiffResult, error) {
+	d := b.diff
+	out := DiffResult{FromFile: d.FromFile, ToFile: d.ToFile, FromDate: d.FromDate, ToDate: d.ToDate}
+	delta := 0 // new minus old lines of the hunks built so far
+	for n, h := range d.Hunks {
+		var lines []string
+		changed := false
+		for k, l := range h.Lines {
+			switch {
+			case !isChangeLine(l):
+				lines = append(lines, l)
+			case b.selected[n][k]:
+				lines = append(lines, l)
+				changed = true
+			case l[0] == '-':
+				lines = append(lines, " "+l[1:])
+			}
+		}
+		if !changed {
+			continue
+		}
+		anchor := hunkAnchor(h)
+		nh := Hunk{OldStart: anchor, NewStart: anchor + delta, Section: h.Section, Lines: lines}
+		nh.OldLines, nh.NewLines = countHunkLines(lines)
+		if nh.OldLines > 0 {
+			nh.OldStart++
+		}
+		if nh.NewLines > 0 {
+			nh.NewStart++
+		}
+		delta += nh.NewLines - nh.OldLines
+		out.Hunks = append(out.Hunks, nh)
+	}
+	if err := out.Validate(); err != nil {
+		return DiffResult{}, err
+	}
+	return out, nil
+}
+
+// isChangeLine reports whether l is an added or removed hunk line.
+func isChangeLine(l string) bool {
+	return l != "" && (l[0] == '+' || l[0] == '-')
+}
@@ -0,0 +1,74 @@
+package difflib_test
+
+import (
+	"testing"
+
+	difflib "github.com/njchilds90/go-difflib"
+)
+
+func TestPatchBuilder(t *testing.T) {
+	a := numbered(30)
+	b := append(append(append([]string(nil), a[:3]...), "new\n"), a[3:]...)
+	b[12] = "twelve\n"
+	b = append(b[:25], b[26:]...)
+	d := difflib.UnifiedDiff(difflib.DiffInput{A: a, B: b, FromFile: "a", ToFile: "b"})
+	if len(d.Hunks) != 3 {
+		t.Fatalf("expected three hunks, got %d:\n%s", len(d.Hunks), d)
+	}
+
+	apply := func(pb *difflib.PatchBuilder) []string {
+		t.Helper()
+		staged, err := pb.Build()
+		if err != nil {
+			t.Fatalf("Build: %v", err)
+		}
+		got, err := difflib.ApplyPatchStrict(a, staged.String())
+		if err != nil {
+			t.Fatalf("applying built patch: %v\n%s", err, staged)
+		}
+		return got
+	}
+
+	pb := difflib.NewPatchBuilder(d)
+	if staged, err := pb.Build(); err != nil || len(staged.Hunks) != 0 {
+		t.Errorf("empty selection = %v, %v", staged, err)
+	}
+
+	// Skipping the first hunk shifts the new lines of the later ones back.
+	pb.SelectHunk(1)
+	pb.SelectHunk(2)
+	want := append([]string(nil), a...)
+	want[11] = "twelve\n"
+	want = append(want[:24], want[25:]...)
+	if got := apply(pb); difflib.JoinLines(got) != difflib.JoinLines(want) {
+		t.Errorf("hunks 1 and 2 give\n%s", difflib.JoinLines(got))
+	}
+	pb.DeselectHunk(2)
+	pb.SelectHunk(0)
+	want = append(append(append([]string(nil), a[:3]...), "new\n"), a[3:]...)
+	want[12] = "twelve\n"
+	if got := apply(pb); difflib.JoinLines(got) != difflib.JoinLines(want) {
+		t.Errorf("hunks 0 and 1 give\n%s", difflib.JoinLines(got))
+	}
+
+	// Keep the removed line of the middle replacement but add its new text.
+	pb = difflib.NewPatchBuilder(d)
+	if h := d.Hunks[1]; h.Lines[3] != "-line 12\n" || h.Lines[4] != "+twelve\n" {
+		t.Fatalf("unexpected middle hunk %q", h.Lines)
+	}
+	pb.SelectHunk(1)
+	pb.DeselectLines(1, 3)
+	want = append(append(append([]string(nil), a[:12]...), "twelve\n"), a[12:]...)
+	if got := apply(pb); difflib.JoinLines(got) != difflib.JoinLines(want) {
+		t.Errorf("line selection gives\n%s", difflib.JoinLines(got))
+	}
+	if err := pb.SelectLines(1, 0); err == nil || err.Error() != "difflib: hunk 1: line 0 is not an added or removed line" {
+		t.Errorf("selecting a context line: %v", err)
+	}
+	if err := pb.SelectLines(1, 99); err == nil {
+		t.Error("selecting a line out of range succeeded")
+	}
+	if err := pb.SelectHunk(3); err == nil || err.Error() != "difflib: hunk 3 out of range [0, 3)" {
+		t.Errorf("selecting a hunk out of range: %v", err)
+	}
+}
@@ -0,0 +1,85 @@
+package difflib
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// SplitByFile returns one patch set per file of p, in patch order, so that
+// each file can be written out or applied on its own. The file diffs share
+// their hunks with p.
+//
+// Example:
+//
+//	for _, part := range ps.SplitByFile() {
+//	    name := strings.ReplaceAll(part.Files[0].NewName, "/", "_")
+//	    os.WriteFile(name+".patch", []byte(part.String()), 0o644)
+//	}
+func (p *PatchSet) SplitByFile() []*PatchSet {
+	out := make([]*PatchSet, len(p.Files))
+	for i, f := range p.Files {
+		out[i] = &PatchSet{Files: []FileDiff{f}}
+	}
+	return out
+}
+
+// Filter returns a patch set holding the files of p for which keep returns
+// true, in patch order. The file diffs share their hunks with p.
+//
+// Example:
+//
+//	code := ps.Filter(func(f difflib.FileDiff) bool {
+//	    return !f.Binary && strings.HasSuffix(f.NewName, ".go")
+//	})
+func (p *PatchSet) Filter(keep func(FileDiff) bool) *PatchSet {
+	out := &PatchSet{}
+	for _, f := range p.Files {
+		if keep(f) {
+			out.Files = append(out.Files, f)
+		}
+	}
+	return out
+}
+
+// SelectPaths returns a patch set holding the files of p whose old or new
+// path, with strip leading components removed as in ApplyFSOptions.Strip,
+// matches one of patterns. A pattern matches a path equal to it, any path
+// in the directory it names, and any path it matches as a path.Match glob,
+// so "internal" selects the whole subtree and "*.go" the Go files at the
+// top level. An error is returned for a malformed pattern.
+//
+// Example:
+//
+//	sub, err := ps.SelectPaths(1, "cmd/godiff", "go.mod")
+func (p *PatchSet) SelectPaths(strip int, patterns ...string) (*PatchSet, error) {
+	for _, pat := range patterns {
+		if _, err := path.Match(pat, ""); err != nil {
+			return nil, fmt.Errorf("difflib: bad pattern %q: %w", pat, err)
+		}
+	}
+	return p.Filter(func(f FileDiff) bool {
+		for _, name := range []string{stripPath(f.OldName, strip), stripPath(f.NewName, strip)} {
+			for _, pat := range patterns {
+				if pathMatches(pat, name) {
+					return true
+				}
+			}
+		}
+		return false
+	}), nil
+}
+
+// pathMatches reports whether name is pat, lies under the directory pat, or
+// matches the glob pat.
+func pathMatches(pat, name string) bool {
+	if name == "" {
+		return false
+	}
+	pat = strings.TrimSuffix(pat, "/")
+	if name == pat || strings.HasPrefix(name, pat+"/") {
+		return true
+	}
+	ok, _ := path.Match(pat, name)
+	return ok
+}
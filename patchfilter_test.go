@@ -0,0 +1,72 @@
+package difflib_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	difflib "github.com/njchilds90/go-difflib"
+)
+
+func patchNames(ps *difflib.PatchSet) []string {
+	var names []string
+	for _, f := range ps.Files {
+		names = append(names, f.NewName)
+	}
+	return names
+}
+
+func TestPatchSetSplitByFile(t *testing.T) {
+	ps, err := difflib.ParsePatchSet(gitPatch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := ps.SplitByFile()
+	if len(parts) != len(ps.Files) {
+		t.Fatalf("SplitByFile returned %d parts for %d files", len(parts), len(ps.Files))
+	}
+	var joined strings.Builder
+	for i, part := range parts {
+		if len(part.Files) != 1 || part.Files[0].NewName != ps.Files[i].NewName {
+			t.Errorf("part %d = %v", i, patchNames(part))
+		}
+		joined.WriteString(part.String())
+	}
+	if joined.String() != ps.String() {
+		t.Errorf("joined parts =\n%s\nwant\n%s", joined.String(), ps.String())
+	}
+}
+
+func TestPatchSetFilter(t *testing.T) {
+	ps, err := difflib.ParsePatchSet(gitPatch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := ps.Filter(func(f difflib.FileDiff) bool { return f.NewFile || f.DeletedFile })
+	if want := []string{"b/new.txt", "b/old.txt"}; !reflect.DeepEqual(patchNames(got), want) {
+		t.Errorf("Filter = %v, want %v", patchNames(got), want)
+	}
+
+	tests := []struct {
+		name     string
+		strip    int
+		patterns []string
+		want     []string
+	}{
+		{"exact", 1, []string{"main.go"}, []string{"b/main.go"}},
+		{"glob", 1, []string{"*.txt"}, []string{"b/new.txt", "b/old.txt", "b/to.txt"}},
+		{"subtree", 1, []string{"docs"}, []string{"b/docs/c d.md"}},
+		{"old name", 1, []string{"from.txt"}, []string{"b/to.txt"}},
+		{"unstripped", 0, []string{"a/docs/"}, []string{"b/docs/c d.md"}},
+		{"none", 1, nil, nil},
+	}
+	for _, tt := range tests {
+		got, err := ps.SelectPaths(tt.strip, tt.patterns...)
+		if err != nil || !reflect.DeepEqual(patchNames(got), tt.want) {
+			t.Errorf("%s: SelectPaths = %v, %v; want %v", tt.name, patchNames(got), err, tt.want)
+		}
+	}
+	if _, err := ps.SelectPaths(1, "[a-"); err == nil {
+		t.Error("SelectPaths accepted a malformed pattern")
+	}
+}
@@ -0,0 +1,314 @@
+package difflib
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// devNull is the file label used for the missing side of a creation or deletion.
+const devNull = "/dev/null"
+
+// FileDiff is the portion of a multi-file patch that applies to one file.
+// The embedded DiffResult holds the --- / +++ labels and the hunks; the other
+// fields carry git's extended header information.
+type FileDiff struct {
+	DiffResult
+	// OldName and NewName are the file paths before and after the change as
+	// they appear in the patch, including any "a/" or "b/" prefix. Unlike
+	// FromFile and ToFile they are never "/dev/null".
+	OldName string `json:"old_name"`
+	NewName string `json:"new_name"`
+	// Git reports whether the file was introduced by a "diff --git" line.
+	// String emits git's extended headers only for such files.
+	Git bool `json:"git,omitempty"`
+	// NewFile and DeletedFile report whether the patch creates or removes the file.
+	NewFile     bool `json:"new_file,omitempty"`
+	DeletedFile bool `json:"deleted_file,omitempty"`
+	// Rename and Copy report whether NewName is a renamed or copied OldName.
+	Rename bool `json:"rename,omitempty"`
+	Copy   bool `json:"copy,omitempty"`
+	// Similarity is the similarity index of a rename or copy, in percent.
+	Similarity int `json:"similarity,omitempty"`
+	// OldMode and NewMode are the octal file modes from the extended headers
+	// (e.g. "100644"), or empty if unknown.
+	OldMode string `json:"old_mode,omitempty"`
+	NewMode string `json:"new_mode,omitempty"`
+	// Index is the remainder of the "index" header line, if any.
+	Index string `json:"index,omitempty"`
+	// Binary reports whether the patch only states that binary content differs.
+	Binary bool `json:"binary,omitempty"`
+}
+
+// String renders the file diff, including git extended headers when Git is set.
+func (f FileDiff) String() string {
+	var b strings.Builder
+	f.WriteTo(&b)
+	return b.String()
+}
+
+// WriteTo writes the file diff to w, including git extended headers when Git
+// is set. It implements io.WriterTo.
+func (f FileDiff) WriteTo(w io.Writer) (int64, error) {
+	cw := &countWriter{w: w}
+	if f.Git {
+		cw.printf("diff --git %s %s\n", quoteName(f.OldName), quoteName(f.NewName))
+		switch {
+		case f.NewFile:
+			cw.printf("new file mode %s\n", f.NewMode)
+		case f.DeletedFile:
+			cw.printf("deleted file mode %s\n", f.OldMode)
+		case f.OldMode != "" && f.NewMode != "" && f.OldMode != f.NewMode:
+			cw.printf("old mode %s\nnew mode %s\n", f.OldMode, f.NewMode)
+		}
+		if f.Rename || f.Copy {
+			verb := "rename"
+			if f.Copy {
+				verb = "copy"
+			}
+			cw.printf("similarity index %d%%\n", f.Similarity)
+			// The names carry git's a/ and b/ prefixes unless the patch was
+			// made with --no-prefix; rename and copy lines never have them.
+			cw.printf("%s from %s\n", verb, quoteName(strings.TrimPrefix(f.OldName, "a/")))
+			cw.printf("%s to %s\n", verb, quoteName(strings.TrimPrefix(f.NewName, "b/")))
+		}
+		if f.Index != "" {
+			cw.printf("index %s\n", f.Index)
+		}
+		if f.Binary {
+			cw.printf("Binary files %s and %s differ\n", quoteName(f.FromFile), quoteName(f.ToFile))
+		}
+	}
+	cw.writeTo(f.DiffResult)
+	return cw.n, cw.err
+}
+
+// PatchSet is a parsed multi-file patch, such as the output of `git diff`
+// or `diff -ru`.
+type PatchSet struct {
+	// Files holds one entry per file touched by the patch, in patch order.
+	Files []FileDiff `json:"files"`
+}
+
+// String renders the patch set as a multi-file unified diff.
+func (p *PatchSet) String() string {
+	var b strings.Builder
+	p.WriteTo(&b)
+	return b.String()
+}
+
+// WriteTo writes the patch set to w as a multi-file unified diff. It
+// implements io.WriterTo.
+func (p *PatchSet) WriteTo(w io.Writer) (int64, error) {
+	cw := &countWriter{w: w}
+	for _, f := range p.Files {
+		cw.writeTo(f)
+	}
+	return cw.n, cw.err
+}
+
+// CombineDiffs collects the diffs of several file pairs into one patch set,
+// in order, so that they render as a single multi-file patch with each
+// file's --- and +++ headers and can be summarised with PatchSet.Stats and
+// DiffStat. Diffs without hunks are left out, since they render as nothing,
+// and a "/dev/null" label marks its file as created or deleted.
+//
+// Example:
+//
+//	results, _ := difflib.DiffMany(pairs, difflib.DiffManyOptions{})
+//	ps := difflib.CombineDiffs(results...)
+//	fmt.Print(ps, difflib.DiffStat(ps))
+func CombineDiffs(results ...DiffResult) *PatchSet {
+	p := &PatchSet{}
+	for _, d := range results {
+		if len(d.Hunks) == 0 {
+			continue
+		}
+		f := FileDiff{DiffResult: d, OldName: d.FromFile, NewName: d.ToFile}
+		switch {
+		case d.FromFile == devNull:
+			f.OldName, f.NewFile = d.ToFile, true
+		case d.ToFile == devNull:
+			f.NewName, f.DeletedFile = d.FromFile, true
+		}
+		p.Files = append(p.Files, f)
+	}
+	return p
+}
+
+// ParsePatchSet parses a multi-file unified diff. It understands plain
+// --- / +++ file headers as well as git's "diff --git" headers with their
+// extended lines (new/deleted file, mode changes, renames, copies, index and
+// binary markers). Text that is not part of a file diff, such as a commit
+// message, is ignored.
+//
+// Example:
+//
+//	ps, err := difflib.ParsePatchSet(gitDiffOutput)
+//	for _, f := range ps.Files {
+//	    fmt.Println(f.NewName, len(f.Hunks))
+//	}
+func ParsePatchSet(patch string) (*PatchSet, error) {
+	ps := &PatchSet{}
+	lines := SplitLines(patch)
+	var cur *FileDiff
+	// inHeader is true while reading the header block of cur, before any hunk.
+	inHeader := false
+	start := func(f FileDiff) {
+		ps.Files = append(ps.Files, f)
+		cur = &ps.Files[len(ps.Files)-1]
+		inHeader = true
+	}
+	for i := 0; i < len(lines); {
+		line := strings.TrimRight(lines[i], "\r\n")
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			oldName, newName := parseGitNames(line[len("diff --git "):])
+			start(FileDiff{
+				DiffResult: DiffResult{FromFile: oldName, ToFile: newName},
+				OldName:    oldName,
+				NewName:    newName,
+				Git:        true,
+			})
+			i++
+		case strings.HasPrefix(line, "--- ") && i+1 < len(lines) && strings.HasPrefix(lines[i+1], "+++ "):
+			from, fromDate := parseFileLabel(line[4:])
+			to, toDate := parseFileLabel(lines[i+1][4:])
+			if cur == nil || !cur.Git || !inHeader {
+				start(FileDiff{})
+			}
+			cur.FromFile, cur.ToFile = from, to
+			cur.FromDate, cur.ToDate = fromDate, toDate
+			if from != devNull {
+				cur.OldName = from
+			}
+			if to != devNull {
+				cur.NewName = to
+			}
+			if cur.OldName == "" {
+				cur.OldName = cur.NewName
+			}
+			if cur.NewName == "" {
+				cur.NewName = cur.OldName
+			}
+			if !cur.Git {
+				cur.NewFile = from == devNull
+				cur.DeletedFile = to == devNull
+			}
+			i += 2
+		case strings.HasPrefix(line, "@@"):
+			if cur == nil {
+				return nil, fmt.Errorf("difflib: line %d: hunk without file header", i+1)
+			}
+			h, n, err := parseHunk(lines[i:], i+1)
+			if err != nil {
+				return nil, err
+			}
+			cur.Hunks = append(cur.Hunks, h)
+			inHeader = false
+			i += n
+		default:
+			if cur != nil && cur.Git && inHeader {
+				if err := parseExtendedHeader(cur, line); err != nil {
+					return nil, fmt.Errorf("difflib: line %d: %w", i+1, err)
+				}
+			}
+			i++
+		}
+	}
+	return ps, nil
+}
+
+// parseExtendedHeader applies one git extended header line to f.
+// Unrecognized lines are ignored.
+func parseExtendedHeader(f *FileDiff, line string) error {
+	field := func(prefix string) (string, bool) {
+		return strings.CutPrefix(line, prefix)
+	}
+	if v, ok := field("new file mode "); ok {
+		f.NewFile, f.NewMode = true, v
+		f.FromFile = devNull
+	} else if v, ok := field("deleted file mode "); ok {
+		f.DeletedFile, f.OldMode = true, v
+		f.ToFile = devNull
+	} else if v, ok := field("old mode "); ok {
+		f.OldMode = v
+	} else if v, ok := field("new mode "); ok {
+		f.NewMode = v
+	} else if _, ok := field("rename from "); ok {
+		f.Rename = true
+	} else if _, ok := field("rename to "); ok {
+		f.Rename = true
+	} else if _, ok := field("copy from "); ok {
+		f.Copy = true
+	} else if _, ok := field("copy to "); ok {
+		f.Copy = true
+	} else if v, ok := field("similarity index "); ok {
+		n, err := strconv.Atoi(strings.TrimSuffix(v, "%"))
+		if err != nil {
+			return fmt.Errorf("malformed similarity index %q", v)
+		}
+		f.Similarity = n
+	} else if v, ok := field("index "); ok {
+		f.Index = v
+		if mode, ok := indexMode(v); ok && f.OldMode == "" && f.NewMode == "" {
+			f.OldMode, f.NewMode = mode, mode
+		}
+	} else if strings.HasPrefix(line, "Binary files ") || line == "GIT binary patch" {
+		f.Binary = true
+	}
+	return nil
+}
+
+// indexMode extracts the trailing mode from "abc123..def456 100644".
+func indexMode(v string) (string, bool) {
+	_, mode, ok := strings.Cut(v, " ")
+	return mode, ok
+}
+
+// parseGitNames splits the "a/old b/new" part of a "diff --git" line,
+// unquoting quoted names. When both names are the same the split is
+// unambiguous even if the name contains spaces; otherwise the last " b/"
+// separates them.
+func parseGitNames(s string) (string, string) {
+	if oldName, rest, ok := unquoteName(s); ok {
+		rest = strings.TrimPrefix(rest, " ")
+		if newName, _, ok := unquoteName(rest); ok {
+			return oldName, newName
+		}
+		return oldName, rest
+	}
+	if i := strings.LastIndex(s, ` "`); i >= 0 && strings.HasSuffix(s, `"`) {
+		if newName, rest, ok := unquoteName(s[i+1:]); ok && rest == "" {
+			return s[:i], newName
+		}
+	}
+	if len(s)%2 == 1 {
+		half := len(s) / 2
+		oldName, newName := s[:half], s[half+1:]
+		if s[half] == ' ' && stripPath(oldName, 1) == stripPath(newName, 1) {
+			return oldName, newName
+		}
+	}
+	if i := strings.LastIndex(s, " b/"); i >= 0 {
+		return s[:i], s[i+1:]
+	}
+	if oldName, newName, ok := strings.Cut(s, " "); ok {
+		return oldName, newName
+	}
+	return s, s
+}
+
+// stripPath removes n leading slash-separated components from name, as the
+// -p option of patch(1) does. It returns "" if name has too few components.
+func stripPath(name string, n int) string {
+	for ; n > 0; n-- {
+		i := strings.IndexByte(name, '/')
+		if i < 0 {
+			return ""
+		}
+		name = name[i+1:]
+	}
+	return name
+}
@@ -0,0 +1,212 @@
+package difflib_test
+
+import (
+	"strings"
+	"testing"
+
+	difflib "github.com/njchilds90/go-difflib"
+)
+
+const gitPatch = `commit message line
+diff --git a/main.go b/main.go
+index 83db48f..bf269f4 100644
+--- a/main.go
++++ b/main.go
+@@ -1,3 +1,3 @@
+ package main
+-var x = 1
++var x = 2
+ func main() {}
+diff --git a/new.txt b/new.txt
+new file mode 100755
+index 0000000..3b18e51
+--- /dev/null
++++ b/new.txt
+@@ -0,0 +1,2 @@
++hello
++-- not a header
+diff --git a/old.txt b/old.txt
+deleted file mode 100644
+index 3b18e51..0000000
+--- a/old.txt
++++ /dev/null
+@@ -1 +0,0 @@
+-bye
+diff --git a/docs/a b.md b/docs/c d.md
+similarity index 100%
+rename from docs/a b.md
+rename to docs/c d.md
+diff --git a/from.txt b/to.txt
+similarity index 90%
+rename from from.txt
+rename to to.txt
+index 1111111..2222222 100644
+--- a/from.txt
++++ b/to.txt
+@@ -1,2 +1,2 @@
+ keep
+-old
++new
+`
+
+func TestParsePatchSet(t *testing.T) {
+	ps, err := difflib.ParsePatchSet(gitPatch)
+	if err != nil {
+		t.Fatalf("ParsePatchSet error: %v", err)
+	}
+	tests := []struct {
+		oldName, newName string
+		hunks            int
+		newFile, deleted bool
+		rename           bool
+		similarity       int
+		newMode          string
+	}{
+		{"a/main.go", "b/main.go", 1, false, false, false, 0, "100644"},
+		{"a/new.txt", "b/new.txt", 1, true, false, false, 0, "100755"},
+		{"a/old.txt", "b/old.txt", 1, false, true, false, 0, ""},
+		{"a/docs/a b.md", "b/docs/c d.md", 0, false, false, true, 100, ""},
+		{"a/from.txt", "b/to.txt", 1, false, false, true, 90, "100644"},
+	}
+	if len(ps.Files) != len(tests) {
+		t.Fatalf("got %d files, want %d", len(ps.Files), len(tests))
+	}
+	for i, tt := range tests {
+		f := ps.Files[i]
+		if f.OldName != tt.oldName || f.NewName != tt.newName {
+			t.Errorf("file %d: names = %q, %q; want %q, %q", i, f.OldName, f.NewName, tt.oldName, tt.newName)
+		}
+		if len(f.Hunks) != tt.hunks || f.NewFile != tt.newFile || f.DeletedFile != tt.deleted ||
+			f.Rename != tt.rename || f.Similarity != tt.similarity || f.NewMode != tt.newMode {
+			t.Errorf("file %d: got %+v", i, f)
+		}
+	}
+	if got := ps.Files[1].Hunks[0].Lines[1]; got != "+-- not a header\n" {
+		t.Errorf("hunk line = %q, want %q", got, "+-- not a header\n")
+	}
+}
+
+func TestParsePatchSetRoundTrip(t *testing.T) {
+	ps, err := difflib.ParsePatchSet(gitPatch)
+	if err != nil {
+		t.Fatalf("ParsePatchSet error: %v", err)
+	}
+	again, err := difflib.ParsePatchSet(ps.String())
+	if err != nil {
+		t.Fatalf("reparse error: %v", err)
+	}
+	if again.String() != ps.String() {
+		t.Errorf("round trip mismatch:\n%s\nvs\n%s", ps.String(), again.String())
+	}
+}
+
+func TestParsePatchSetNoPrefixRename(t *testing.T) {
+	patch := "diff --git old.txt new.txt\nsimilarity index 100%\nrename from old.txt\nrename to new.txt\n" +
+		"diff --git src/a.go lib/a.go\nsimilarity index 90%\ncopy from src/a.go\ncopy to lib/a.go\n"
+	ps, err := difflib.ParsePatchSet(patch)
+	if err != nil {
+		t.Fatalf("ParsePatchSet error: %v", err)
+	}
+	if got := ps.String(); got != patch {
+		t.Errorf("String() = %q, want %q", got, patch)
+	}
+}
+
+func TestParsePatchSetPlain(t *testing.T) {
+	patch := "--- a.txt\t2024-05-01 10:00:00\n+++ a.txt\t2024-05-02 10:00:00\n@@ -1 +1 @@\n-x\n+y\n" +
+		"--- b.txt\n+++ b.txt\n@@ -1 +1 @@\n-p\n+q\n"
+	ps, err := difflib.ParsePatchSet(patch)
+	if err != nil {
+		t.Fatalf("ParsePatchSet error: %v", err)
+	}
+	if len(ps.Files) != 2 || ps.Files[0].NewName != "a.txt" || ps.Files[1].NewName != "b.txt" {
+		t.Errorf("unexpected files: %+v", ps.Files)
+	}
+	if _, err := difflib.ParsePatchSet("@@ -1 +1 @@\n-x\n+y\n"); err == nil {
+		t.Error("expected error for hunk without file header")
+	}
+}
+
+func TestPatchSetQuotedNames(t *testing.T) {
+	// As written by git for "täst.txt" renamed to "a\"b\tc.txt".
+	patch := `diff --git "a/t\303\244st.txt" "b/a\"b\tc.txt"
+similarity index 50%
+rename from "t\303\244st.txt"
+rename to "a\"b\tc.txt"
+--- "a/t\303\244st.txt"
++++ "b/a\"b\tc.txt"
+@@ -1,2 +1,2 @@
+ x
+-y
++z
+`
+	ps, err := difflib.ParsePatchSet(patch)
+	if err != nil {
+		t.Fatalf("ParsePatchSet error: %v", err)
+	}
+	f := ps.Files[0]
+	if f.OldName != "a/täst.txt" || f.NewName != "b/a\"b\tc.txt" || f.FromFile != f.OldName || f.ToFile != f.NewName {
+		t.Errorf("names = %q %q %q %q", f.OldName, f.NewName, f.FromFile, f.ToFile)
+	}
+	if got := ps.String(); got != patch {
+		t.Errorf("String() =\n%s\nwant\n%s", got, patch)
+	}
+}
+
+func TestUnifiedDiffQuotedNames(t *testing.T) {
+	tests := []struct {
+		name, header string
+	}{
+		{"plain.txt", "--- plain.txt\n"},
+		{"with space.txt", "--- with space.txt\t\n"},
+		{"tab\there.txt", `--- "tab\there.txt"` + "\n"},
+		{"back\\slash", `--- "back\\slash"` + "\n"},
+		{"日本.txt", `--- "\346\227\245\346\234\254.txt"` + "\n"},
+		{"new\nline", `--- "new\nline"` + "\n"},
+	}
+	for _, tt := range tests {
+		d := difflib.UnifiedDiff(difflib.DiffInput{
+			A: []string{"a\n"}, B: []string{"b\n"}, FromFile: tt.name, ToFile: tt.name,
+		})
+		s := d.String()
+		if !strings.HasPrefix(s, tt.header) {
+			t.Errorf("%q: diff starts %q, want %q", tt.name, s[:strings.Index(s, "+++")], tt.header)
+		}
+		ps, err := difflib.ParsePatchSet(s)
+		if err != nil {
+			t.Fatalf("%q: ParsePatchSet error: %v", tt.name, err)
+		}
+		if f := ps.Files[0]; f.FromFile != tt.name || f.ToFile != tt.name || f.FromDate != "" {
+			t.Errorf("%q: parsed %q, %q (date %q)", tt.name, f.FromFile, f.ToFile, f.FromDate)
+		}
+	}
+}
+
+func TestCombineDiffs(t *testing.T) {
+	one := difflib.UnifiedDiff(difflib.DiffInput{
+		A: difflib.SplitLines("a\nb\n"), B: difflib.SplitLines("a\nB\n"), FromFile: "one.txt", ToFile: "one.txt",
+	})
+	same := difflib.UnifiedDiff(difflib.DiffInput{
+		A: difflib.SplitLines("x\n"), B: difflib.SplitLines("x\n"), FromFile: "same.txt", ToFile: "same.txt",
+	})
+	created := difflib.UnifiedDiff(difflib.DiffInput{
+		B: difflib.SplitLines("new\nfile\n"), FromFile: "/dev/null", ToFile: "two.txt",
+	})
+	ps := difflib.CombineDiffs(one, same, created)
+	if got, want := ps.String(), one.String()+created.String(); got != want {
+		t.Errorf("CombineDiffs().String() =\n%s\nwant\n%s", got, want)
+	}
+	if len(ps.Files) != 2 || !ps.Files[1].NewFile || ps.Files[1].OldName != "two.txt" {
+		t.Errorf("CombineDiffs files = %+v", ps.Files)
+	}
+	if got, want := ps.Stats(), (difflib.DiffStats{Added: 3, Removed: 1, Changed: 1}); got != want {
+		t.Errorf("Stats() = %+v, want %+v", got, want)
+	}
+	if got, want := difflib.ShortStat(ps), "2 files changed, 3 insertions(+), 1 deletion(-)"; got != want {
+		t.Errorf("ShortStat = %q, want %q", got, want)
+	}
+	parsed, err := difflib.ParsePatchSet(ps.String())
+	if err != nil || len(parsed.Files) != 2 || parsed.String() != ps.String() {
+		t.Errorf("ParsePatchSet of combined diffs = %v, %v", parsed, err)
+	}
+}
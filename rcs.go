@@ -0,0 +1,95 @@
+package difflib
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// RCSDiff returns the difference between a and b in the RCS format written
+// by `diff -n`, as stored by RCS and other delta-storage tools. Each change
+// is a "dL N" command deleting N lines starting at line L, or an "aL N"
+// command appending the N lines that follow after line L. Line numbers
+// always refer to a, so the commands can be applied in order without
+// adjusting them. A replacement is a delete followed by an append. The
+// result is empty when a and b are equal.
+//
+// A final line of b without a newline is written as is, like `diff -n`.
+//
+// Example:
+//
+//	delta := difflib.RCSDiff(
+//	    difflib.SplitLines("one\ntwo\nthree\n"),
+//	    difflib.SplitLines("one\nTWO\nthree\nfour\n"),
+//	)
+//	// d2 1
+//	// a2 1
+//	// TWO
+//	// a3 1
+//	// four
+func RCSDiff(a, b []string) string {
+	var sb strings.Builder
+	for _, op := range GetOpCodes(a, b) {
+		if op.Tag == OpDelete || op.Tag == OpReplace {
+			fmt.Fprintf(&sb, "d%d %d\n", op.I1+1, op.I2-op.I1)
+		}
+		if op.Tag == OpInsert || op.Tag == OpReplace {
+			fmt.Fprintf(&sb, "a%d %d\n", op.I2, op.J2-op.J1)
+			for _, l := range b[op.J1:op.J2] {
+				sb.WriteString(l)
+			}
+		}
+	}
+	return sb.String()
+}
+
+// ApplyRCS applies an RCS format delta, as produced by RCSDiff or
+// `diff -n`, to a and returns the result. Commands must be in increasing
+// line order and must not overlap; an error is returned for a malformed
+// command, an out-of-range line number or truncated appended text.
+//
+// Example:
+//
+//	b, err := difflib.ApplyRCS(a, difflib.RCSDiff(a, b))
+func ApplyRCS(a []string, delta string) ([]string, error) {
+	lines := SplitLines(delta)
+	var out []string
+	// next is the index in a of the first line not yet copied or deleted.
+	next := 0
+	for i := 0; i < len(lines); {
+		cmd := strings.TrimRight(lines[i], "\r\n")
+		lineno := i + 1
+		i++
+		if cmd == "" {
+			return nil, fmt.Errorf("difflib: line %d: empty RCS command", lineno)
+		}
+		pos, count, ok := strings.Cut(cmd[1:], " ")
+		l, err1 := strconv.Atoi(pos)
+		n, err2 := strconv.Atoi(count)
+		if !ok || err1 != nil || err2 != nil || l < 0 || n < 0 {
+			return nil, fmt.Errorf("difflib: line %d: malformed RCS command %q", lineno, cmd)
+		}
+		switch cmd[0] {
+		case 'd':
+			if l-1 < next || l-1+n > len(a) {
+				return nil, fmt.Errorf("difflib: line %d: %q is out of range or out of order", lineno, cmd)
+			}
+			out = append(out, a[next:l-1]...)
+			next = l - 1 + n
+		case 'a':
+			if l < next || l > len(a) {
+				return nil, fmt.Errorf("difflib: line %d: %q is out of range or out of order", lineno, cmd)
+			}
+			if i+n > len(lines) {
+				return nil, fmt.Errorf("difflib: line %d: %q expects %d lines, found %d", lineno, cmd, n, len(lines)-i)
+			}
+			out = append(out, a[next:l]...)
+			out = append(out, lines[i:i+n]...)
+			next = l
+			i += n
+		default:
+			return nil, fmt.Errorf("difflib: line %d: unknown RCS command %q", lineno, cmd)
+		}
+	}
+	return append(out, a[next:]...), nil
+}
@@ -0,0 +1,89 @@
+package difflib_test
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+
+	difflib "github.com/njchilds90/go-difflib"
+)
+
+func TestRCSDiff(t *testing.T) {
+	tests := []struct {
+		name, a, b, want string
+	}{
+		{"equal", "x\ny\n", "x\ny\n", ""},
+		{"replace", "one\ntwo\nthree\n", "one\nTWO\nthree\n", "d2 1\na2 1\nTWO\n"},
+		{"insert at start", "b\n", "a\nb\n", "a0 1\na\n"},
+		{"delete", "a\nb\nc\nd\n", "a\nd\n", "d2 2\n"},
+		{"append", "a\n", "a\nb\nc\n", "a1 2\nb\nc\n"},
+		{"no newline", "a\n", "a\nb", "a1 1\nb"},
+		{"empty", "", "a\n", "a0 1\na\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := difflib.SplitLines(tt.a), difflib.SplitLines(tt.b)
+			got := difflib.RCSDiff(a, b)
+			if got != tt.want {
+				t.Errorf("RCSDiff() = %q, want %q", got, tt.want)
+			}
+			back, err := difflib.ApplyRCS(a, got)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if difflib.JoinLines(back) != tt.b {
+				t.Errorf("ApplyRCS() = %q, want %q", difflib.JoinLines(back), tt.b)
+			}
+		})
+	}
+}
+
+func TestApplyRCSRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(7))
+	for i := 0; i < 200; i++ {
+		a, b := randomLines(rng, rng.Intn(20)), randomLines(rng, rng.Intn(20))
+		got, err := difflib.ApplyRCS(a, difflib.RCSDiff(a, b))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if difflib.JoinLines(got) != difflib.JoinLines(b) {
+			t.Fatalf("ApplyRCS(%q) = %q, want %q", a, got, b)
+		}
+	}
+}
+
+func TestApplyRCSErrors(t *testing.T) {
+	a := difflib.SplitLines("a\nb\nc\n")
+	tests := []struct {
+		name, delta, want string
+	}{
+		{"unknown", "x1 1\n", `line 1: unknown RCS command "x1 1"`},
+		{"malformed", "d1\n", `line 1: malformed RCS command "d1"`},
+		{"empty", "\n", "line 1: empty RCS command"},
+		{"past end", "d3 2\n", `line 1: "d3 2" is out of range or out of order`},
+		{"out of order", "d3 1\nd1 1\n", `line 2: "d1 1" is out of range or out of order`},
+		{"truncated", "a1 2\nx\n", `line 1: "a1 2" expects 2 lines, found 1`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := difflib.ApplyRCS(a, tt.delta)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("ApplyRCS() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func ExampleRCSDiff() {
+	fmt.Print(difflib.RCSDiff(
+		difflib.SplitLines("one\ntwo\nthree\n"),
+		difflib.SplitLines("one\nTWO\nthree\nfour\n"),
+	))
+	// Output:
+	// d2 1
+	// a2 1
+	// TWO
+	// a3 1
+	// four
+}
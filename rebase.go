@@ -0,0 +1,80 @@
+package difflib
+
+import "fmt"
+
+// rebaseFuzz is the fuzz factor RebasePatch uses, matching GNU patch's default.
+const rebaseFuzz = 2
+
+// RebasePatch re-anchors a single-file patch written against oldBase so that
+// it applies to newBase, the same file after unrelated edits. Each hunk's
+// position is first carried across the oldBase→newBase diff and the hunk is
+// then located by context around that position, ignoring up to two context
+// lines at either end if needed. Context lines that had to be ignored are
+// dropped from the rebased hunk so that it applies exactly to newBase.
+//
+// Hunks that can no longer be placed are left out of the returned patch and
+// reported in the ApplyReport, both as failed HunkResults and as reject
+// content in report.Rejects. For placed hunks, HunkResult.Line is the line in
+// newBase and Offset is the distance from the hunk's original header.
+//
+// Example:
+//
+//	rebased, report, err := difflib.RebasePatch(oldPR, mainAtPRBase, mainNow)
+//	if err == nil && !report.OK() {
+//	    fmt.Print(report) // Hunk #3 FAILED at 120.
+//	}
+func RebasePatch(patch *PatchSet, oldBase, newBase []string) (*PatchSet, ApplyReport, error) {
+	if len(patch.Files) != 1 {
+		return nil, ApplyReport{}, fmt.Errorf("difflib: RebasePatch needs a single-file patch, got %d files",
+			len(patch.Files))
+	}
+	f := patch.Files[0]
+	lines := LineMapping(GetOpCodes(oldBase, newBase))
+	opts := ApplyOptions{Fuzz: rebaseFuzz}
+
+	report := ApplyReport{Rejects: DiffResult{FromFile: f.FromFile, ToFile: f.ToFile}}
+	rebased := f
+	rebased.Hunks = nil
+	minPos, delta := 0, 0
+	for n, h := range f.Hunks {
+		moved := h
+		at, _ := lines.OldToNew(hunkAnchor(h))
+		moved.OldStart = at + 1
+		if h.OldLines == 0 {
+			moved.OldStart--
+		}
+		p, ok := placeHunk(newBase, moved, minPos, 0, opts)
+		if !ok {
+			report.Hunks = append(report.Hunks, HunkResult{
+				Hunk: n + 1,
+				Line: moved.OldStart,
+				Err:  hunkError(newBase, moved, n+1),
+			})
+			report.Rejects.Hunks = append(report.Rejects.Hunks, h)
+			continue
+		}
+		res := p.result
+		res.Hunk = n + 1
+		res.Offset = res.Line - h.OldStart
+		report.Hunks = append(report.Hunks, res)
+
+		nh := Hunk{
+			OldLines: h.OldLines - p.top - p.bottom,
+			NewLines: h.NewLines - p.top - p.bottom,
+			Lines:    h.Lines[p.top : len(h.Lines)-p.bottom],
+		}
+		nh.OldStart = p.pos + 1
+		if nh.OldLines == 0 {
+			nh.OldStart--
+		}
+		nh.NewStart = p.pos + delta + 1
+		if nh.NewLines == 0 {
+			nh.NewStart--
+		}
+		rebased.Hunks = append(rebased.Hunks, nh)
+		minPos = p.end
+		delta += nh.NewLines - nh.OldLines
+	}
+	rebased.Index = ""
+	return &PatchSet{Files: []FileDiff{rebased}}, report, nil
+}
@@ -0,0 +1,60 @@
+package difflib_test
+
+import (
+	"testing"
+
+	difflib "github.com/njchilds90/go-difflib"
+)
+
+func TestRebasePatch(t *testing.T) {
+	oldBase := numbered(40)
+	mine := append([]string(nil), oldBase...)
+	mine[4] = "five\n"
+	mine[29] = "thirty\n"
+	patch := filePatch("f", oldBase, mine)
+
+	// Upstream inserted lines at the top, edited context next to the first
+	// hunk and rewrote the region of the second hunk.
+	newBase := append([]string{"new 1\n", "new 2\n", "new 3\n"}, numbered(40)...)
+	newBase[3+7] = "upstream 8\n"
+	for i := 26; i < 34; i++ {
+		newBase[3+i] = "rewritten\n"
+	}
+
+	rebased, report, err := difflib.RebasePatch(patch, oldBase, newBase)
+	if err != nil {
+		t.Fatalf("RebasePatch error: %v", err)
+	}
+	if len(report.Hunks) != 2 {
+		t.Fatalf("expected 2 hunk results, got %v", report.Hunks)
+	}
+	first := report.Hunks[0]
+	if first.Err != nil || first.Offset != 3 || first.Fuzz != 1 {
+		t.Errorf("first hunk = %+v, want offset 3 with fuzz 1", first)
+	}
+	if report.Hunks[1].Err == nil {
+		t.Errorf("second hunk should fail to rebase: %+v", report.Hunks[1])
+	}
+	if len(report.Rejects.Hunks) != 1 {
+		t.Errorf("expected 1 rejected hunk, got %d", len(report.Rejects.Hunks))
+	}
+
+	got, err := difflib.ApplyPatch(newBase, rebased.Files[0].DiffResult.String())
+	if err != nil {
+		t.Fatalf("rebased patch does not apply: %v\n%s", err, rebased.String())
+	}
+	want := append([]string(nil), newBase...)
+	want[3+4] = "five\n"
+	if difflib.JoinLines(got) != difflib.JoinLines(want) {
+		t.Errorf("rebased patch result mismatch:\n%s", difflib.UnifiedDiff(difflib.DiffInput{A: want, B: got}).String())
+	}
+	if h := rebased.Files[0].Hunks[0]; h.OldStart != 6 || h.NewStart != 6 {
+		t.Errorf("rebased hunk header = -%d +%d, want -6 +6 (fuzz trims one context line)", h.OldStart, h.NewStart)
+	}
+}
+
+func TestRebasePatchRequiresSingleFile(t *testing.T) {
+	if _, _, err := difflib.RebasePatch(&difflib.PatchSet{}, nil, nil); err == nil {
+		t.Error("expected error for empty patch set")
+	}
+}
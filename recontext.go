@@ -0,0 +1,144 @@
+package difflib
+
+import "fmt"
+
+// WithContext returns the diff regrouped with n lines of context around each
+// change, given a, the original content the diff was made against. n has
+// the meaning of DiffInput.Context: zero selects the default of 3 and
+// NoContext selects none. It can expand a zero-context patch to the usual
+// three lines or shrink a patch with large context; hunks are merged or
+// split as the new context requires and their headers recomputed. An error
+// is returned if the hunks do not match a at the positions recorded in their
+// headers.
+//
+// Example:
+//
+//	parsed, _ := difflib.ParsePatchSet(zeroContextPatch)
+//	expanded, err := parsed.Files[0].DiffResult.WithContext(original, 3)
+func (d DiffResult) WithContext(a []string, n int) (DiffResult, error) {
+	codes, b, err := hunksToOpCodes(d.Hunks, a)
+	if err != nil {
+		return DiffResult{}, err
+	}
+	out := DiffResult{FromFile: d.FromFile, ToFile: d.ToFile, FromDate: d.FromDate, ToDate: d.ToDate}
+	out.Hunks = OpCodesToHunks(a, b, codes, n)
+	return out, nil
+}
+
+// HunksToOpCodes reconstructs the opcodes of a diff from its hunks alone,
+// so that a parsed patch can be analysed like a freshly computed diff. The
+// lines between hunks become equal opcodes, but without the original
+// content the opcodes end with the last hunk rather than at the end of the
+// file. The hunks must be in order, must not overlap and must have headers
+// that agree with their lines and with each other.
+//
+// Example:
+//
+//	set, _ := difflib.ParsePatchSet(patch)
+//	codes, err := difflib.HunksToOpCodes(set.Files[0].Hunks)
+func HunksToOpCodes(hunks []Hunk) ([]OpCode, error) {
+	var codes []OpCode
+	add := func(tag Op, i1, i2, j1, j2 int) {
+		if i1 == i2 && j1 == j2 {
+			return
+		}
+		if n := len(codes); n > 0 && codes[n-1].Tag == tag {
+			codes[n-1].I2, codes[n-1].J2 = i2, j2
+			return
+		}
+		codes = append(codes, OpCode{tag, i1, i2, j1, j2})
+	}
+	i, j := 0, 0
+	for n, h := range hunks {
+		ai, bj := hunkAnchor(h), newAnchor(h)
+		if ai < i || ai-i != bj-j {
+			return nil, fmt.Errorf("difflib: hunk #%d header -%d,%d +%d,%d is inconsistent with the preceding hunks",
+				n+1, h.OldStart, h.OldLines, h.NewStart, h.NewLines)
+		}
+		add(OpEqual, i, ai, j, bj)
+		i, j = ai, bj
+
+		dels, ins := 0, 0
+		flush := func() {
+			switch {
+			case dels > 0 && ins > 0:
+				add(OpReplace, i, i+dels, j, j+ins)
+			case dels > 0:
+				add(OpDelete, i, i+dels, j, j)
+			case ins > 0:
+				add(OpInsert, i, i, j, j+ins)
+			}
+			i, j = i+dels, j+ins
+			dels, ins = 0, 0
+		}
+		for _, l := range h.Lines {
+			if l == "" {
+				continue
+			}
+			switch l[0] {
+			case ' ':
+				flush()
+				add(OpEqual, i, i+1, j, j+1)
+				i, j = i+1, j+1
+			case '-':
+				dels++
+			case '+':
+				ins++
+			}
+		}
+		flush()
+		if i-ai != h.OldLines || j-bj != h.NewLines {
+			return nil, fmt.Errorf("difflib: hunk #%d header %s does not match its %d old and %d new lines",
+				n+1, h.header(), i-ai, j-bj)
+		}
+	}
+	return codes, nil
+}
+
+// OpCodesToHunks groups the opcodes of a and b into hunks with context
+// lines of context around each change, the reverse of HunksToOpCodes.
+// context has the meaning of DiffInput.Context: zero selects the default of
+// 3 and NoContext selects none. codes must transform a into b, as
+// ValidateOpCodes checks.
+//
+// Example:
+//
+//	codes := difflib.CleanupSemantic(a, b, difflib.GetOpCodes(a, b))
+//	d := difflib.DiffResult{FromFile: "a", ToFile: "b", Hunks: difflib.OpCodesToHunks(a, b, codes, 3)}
+func OpCodesToHunks(a, b []string, codes []OpCode, context int) []Hunk {
+	var hunks []Hunk
+	for _, group := range groupOpcodes(codes, contextLines(context)) {
+		hunks = append(hunks, buildHunk(a, b, group))
+	}
+	return hunks
+}
+
+// hunksToOpCodes reconstructs the opcodes of a diff from its hunks and the
+// original content a, also returning the new content. The hunks must be in
+// order, must not overlap and must match a exactly at their header positions.
+func hunksToOpCodes(hunks []Hunk, a []string) ([]OpCode, []string, error) {
+	codes, err := HunksToOpCodes(hunks)
+	if err != nil {
+		return nil, nil, err
+	}
+	var b []string
+	i := 0
+	for n, h := range hunks {
+		ai := hunkAnchor(h)
+		from, to := hunkSides(h)
+		if ai+len(from) > len(a) {
+			return nil, nil, fmt.Errorf("difflib: hunk #%d extends past the end of the input", n+1)
+		}
+		for k, text := range from {
+			if a[ai+k] != text {
+				return nil, nil, fmt.Errorf("difflib: hunk #%d does not match the input at line %d: expected %q, got %q",
+					n+1, ai+k+1, text, a[ai+k])
+			}
+		}
+		b = append(append(b, a[i:ai]...), to...)
+		i = ai + len(from)
+	}
+	b = append(b, a[i:]...)
+	codes = append(codes, OpCode{OpEqual, i, len(a), len(b) - (len(a) - i), len(b)})
+	return CompactOpCodes(codes), b, nil
+}
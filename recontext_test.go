@@ -0,0 +1,110 @@
+package difflib_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	difflib "github.com/njchilds90/go-difflib"
+)
+
+func TestDiffResultWithContext(t *testing.T) {
+	a := numbered(30)
+	b := numbered(30)
+	b[4] = "five\n"
+	b[11] = "twelve\n"
+	b = append(b[:20], b[21:]...)
+	wide := difflib.UnifiedDiff(difflib.DiffInput{A: a, B: b, FromFile: "a", ToFile: "b", Context: 3})
+
+	tests := []struct {
+		name  string
+		n     int
+		hunks int
+	}{
+		{"none", difflib.NoContext, 3},
+		{"default", 0, 2},
+		{"one", 1, 3},
+		{"three", 3, 2},
+		{"ten", 10, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := wide.WithContext(a, tt.n)
+			if err != nil {
+				t.Fatalf("WithContext error: %v", err)
+			}
+			if len(got.Hunks) != tt.hunks {
+				t.Errorf("got %d hunks, want %d:\n%s", len(got.Hunks), tt.hunks, got.String())
+			}
+			patched, err := difflib.ApplyPatch(a, got.String())
+			if err != nil {
+				t.Fatalf("applying recontextualized patch: %v\n%s", err, got.String())
+			}
+			if difflib.JoinLines(patched) != difflib.JoinLines(b) {
+				t.Errorf("recontextualized patch produced wrong result:\n%s", got.String())
+			}
+		})
+	}
+
+	zero, err := wide.WithContext(a, difflib.NoContext)
+	if err != nil {
+		t.Fatalf("WithContext error: %v", err)
+	}
+	if h := zero.Hunks[2]; h.OldStart != 21 || h.OldLines != 1 || h.NewStart != 20 || h.NewLines != 0 {
+		t.Errorf("deletion hunk header = -%d,%d +%d,%d; want -21,1 +20,0",
+			h.OldStart, h.OldLines, h.NewStart, h.NewLines)
+	}
+	expanded, err := zero.WithContext(a, 3)
+	if err != nil {
+		t.Fatalf("expanding zero-context diff: %v", err)
+	}
+	if expanded.String() != wide.String() {
+		t.Errorf("expanded diff =\n%s\nwant\n%s", expanded.String(), wide.String())
+	}
+}
+
+func TestDiffResultWithContextMismatch(t *testing.T) {
+	a := numbered(5)
+	b := numbered(5)
+	b[2] = "three\n"
+	d := difflib.UnifiedDiff(difflib.DiffInput{A: a, B: b})
+	other := numbered(5)
+	other[1] = "changed\n"
+	if _, err := d.WithContext(other, 3); err == nil {
+		t.Error("expected error when hunks do not match the original")
+	}
+}
+
+func TestHunksToOpCodes(t *testing.T) {
+	a := numbered(20)
+	b := numbered(20)
+	b[2] = "three\n"
+	b = append(b[:9], append([]string{"new\n"}, b[9:]...)...)
+	b = append(b[:15], b[16:]...)
+	d := difflib.UnifiedDiff(difflib.DiffInput{A: a, B: b, Context: 1})
+
+	codes, err := difflib.HunksToOpCodes(d.Hunks)
+	if err != nil {
+		t.Fatalf("HunksToOpCodes: %v", err)
+	}
+	last := codes[len(codes)-1]
+	if err := difflib.ValidateOpCodes(a[:last.I2], b[:last.J2], codes); err != nil {
+		t.Errorf("HunksToOpCodes = %v: %v", codes, err)
+	}
+	if got := difflib.OpCodesToHunks(a, b, codes, 1); !reflect.DeepEqual(got, d.Hunks) {
+		t.Errorf("OpCodesToHunks = %v, want %v", got, d.Hunks)
+	}
+	if got, want := difflib.OpCodesToHunks(a, b, difflib.GetOpCodes(a, b), difflib.NoContext),
+		difflib.UnifiedDiff(difflib.DiffInput{A: a, B: b, Context: difflib.NoContext}).Hunks; !reflect.DeepEqual(got, want) {
+		t.Errorf("OpCodesToHunks without context = %v, want %v", got, want)
+	}
+
+	bad := append([]difflib.Hunk(nil), d.Hunks...)
+	bad[0].OldLines++
+	if _, err := difflib.HunksToOpCodes(bad); err == nil || !strings.Contains(err.Error(), "does not match its 3 old and 3 new lines") {
+		t.Errorf("HunksToOpCodes with a wrong count: %v", err)
+	}
+	if _, err := difflib.HunksToOpCodes([]difflib.Hunk{d.Hunks[1], d.Hunks[0]}); err == nil || !strings.Contains(err.Error(), "inconsistent") {
+		t.Errorf("HunksToOpCodes with hunks out of order: %v", err)
+	}
+}
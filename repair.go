@@ -0,0 +1,230 @@
+package difflib
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Fix describes one change made by RepairPatch.
+type Fix struct {
+	// Line is the 1-based line of the input patch the fix applies to.
+	Line int `json:"line"`
+	// Description says what was changed.
+	Description string `json:"description"`
+}
+
+// String formats the fix as "line N: description".
+func (f Fix) String() string {
+	return fmt.Sprintf("line %d: %s", f.Line, f.Description)
+}
+
+// RepairPatch fixes common mechanical problems in a unified diff, such as
+// those in diffs written by hand or by a language model, and reports each
+// change it made, in line order:
+//
+//   - hunk lines that lost their leading space, including empty lines, are
+//     made context lines;
+//   - context lines holding only whitespace become empty context lines;
+//   - trailing whitespace is removed from file and hunk headers;
+//   - hunk counts are recomputed from the hunk bodies, and new start lines
+//     from the old ones, rewriting headers that are wrong or malformed (a
+//     bare "@@" starts after the previous hunk);
+//   - hunks without a preceding "---"/"+++" header get one, labeled "a"
+//     and "b";
+//   - blank lines at the end of the patch are removed.
+//
+// Other lines, such as git extended headers, are kept as they are. The
+// result can be passed to ApplyPatch or ParsePatchSet. An error is returned
+// if the patch has no hunks.
+//
+// Example:
+//
+//	fixed, fixes, err := difflib.RepairPatch(patch)
+//	for _, f := range fixes {
+//	    log.Println(f) // line 4: added missing space to context line
+//	}
+//	patched, err := difflib.ApplyPatch(original, fixed)
+func RepairPatch(patch string) (string, []Fix, error) {
+	lines := SplitLines(patch)
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	r := patchRepairer{}
+	if len(lines) < len(SplitLines(patch)) {
+		r.fix(len(lines)+1, "removed trailing blank lines")
+	}
+	for i := 0; i < len(lines); i++ {
+		l, n := lines[i], i+1
+		if !strings.HasSuffix(l, "\n") {
+			l += "\n"
+		}
+		text := strings.TrimRight(l, "\r\n")
+		switch {
+		case strings.HasPrefix(text, "diff "):
+			r.endHunk()
+			r.header = false
+			r.out = append(r.out, l)
+		case strings.HasPrefix(text, "--- ") && i+1 < len(lines) && strings.HasPrefix(lines[i+1], "+++ ") &&
+			!r.hunk.needsLines():
+			r.endHunk()
+			r.fileHeader(n, l)
+			r.fileHeader(n+1, lines[i+1])
+			r.header, r.delta, r.oldEnd = true, 0, 0
+			i++
+		case strings.HasPrefix(text, "@@"):
+			r.endHunk()
+			if !r.header {
+				r.fix(n, "added missing file header")
+				r.out = append(r.out, "--- a\n", "+++ b\n")
+				r.header, r.delta, r.oldEnd = true, 0, 0
+			}
+			r.startHunk(n, text)
+		case r.hunk == nil:
+			r.out = append(r.out, l)
+		case strings.HasPrefix(text, `\`):
+			r.out = append(r.out, l)
+		case text == "" || text[0] != ' ' && text[0] != '-' && text[0] != '+':
+			r.fix(n, "added missing space to context line")
+			r.body(" " + l)
+		case text[0] == ' ' && strings.TrimSpace(text) == "" && text != " ":
+			r.fix(n, "removed trailing whitespace from empty context line")
+			r.body(" " + l[len(text):])
+		default:
+			r.body(l)
+		}
+	}
+	r.endHunk()
+	if !r.sawHunk {
+		return "", nil, fmt.Errorf("difflib: patch has no hunks")
+	}
+	sort.SliceStable(r.fixes, func(i, j int) bool { return r.fixes[i].Line < r.fixes[j].Line })
+	return strings.Join(r.out, ""), r.fixes, nil
+}
+
+// patchRepairer holds the state of RepairPatch.
+type patchRepairer struct {
+	out   []string
+	fixes []Fix
+	// header is set once the current file has a ---/+++ header.
+	header  bool
+	sawHunk bool
+	// hunk is the hunk being read, with the index of its header in out.
+	hunk *repairHunk
+	// delta is the difference between new and old line numbers after the
+	// previous hunk of the file, and oldEnd the old line it ended at.
+	delta, oldEnd int
+}
+
+type repairHunk struct {
+	line, at   int
+	text       string
+	start      int
+	section    string
+	oldN, newN int
+	// want holds the counts of a well-formed header, which are used to
+	// tell "---" and "+++" body lines from a file header.
+	want *Hunk
+}
+
+// needsLines reports whether h has fewer body lines than its header says.
+// It is false for a nil hunk or one with a malformed header.
+func (h *repairHunk) needsLines() bool {
+	return h != nil && h.want != nil && (h.oldN < h.want.OldLines || h.newN < h.want.NewLines)
+}
+
+func (r *patchRepairer) fix(line int, format string, args ...any) {
+	r.fixes = append(r.fixes, Fix{Line: line, Description: fmt.Sprintf(format, args...)})
+}
+
+// fileHeader writes a --- or +++ line without trailing whitespace.
+func (r *patchRepairer) fileHeader(n int, l string) {
+	text := strings.TrimRight(l, "\r\n")
+	if trimmed := strings.TrimRight(text, " \t"); trimmed != text {
+		r.fix(n, "removed trailing whitespace from file header")
+		l = trimmed + l[len(text):]
+	}
+	if !strings.HasSuffix(l, "\n") {
+		l += "\n"
+	}
+	r.out = append(r.out, l)
+}
+
+// startHunk begins a hunk at header text, on input line n. Its old start
+// line is taken from the header if it has one.
+func (r *patchRepairer) startHunk(n int, text string) {
+	h := &repairHunk{line: n, at: len(r.out), text: text, start: -1}
+	if rest, ok := strings.CutPrefix(text, "@@ -"); ok {
+		digits := rest[:len(rest)-len(strings.TrimLeft(rest, "0123456789"))]
+		if start, err := strconv.Atoi(digits); err == nil {
+			h.start = start
+		}
+	}
+	if _, after, ok := strings.Cut(strings.TrimPrefix(text, "@@"), "@@"); ok {
+		h.section = strings.TrimRight(after, " \t")
+	}
+	if want, err := parseHunkHeader(text); err == nil {
+		h.want = &want
+	}
+	r.hunk, r.sawHunk = h, true
+	r.out = append(r.out, "") // the header, written by endHunk
+}
+
+// body writes a hunk body line and counts it.
+func (r *patchRepairer) body(l string) {
+	switch l[0] {
+	case ' ':
+		r.hunk.oldN++
+		r.hunk.newN++
+	case '-':
+		r.hunk.oldN++
+	case '+':
+		r.hunk.newN++
+	}
+	r.out = append(r.out, l)
+}
+
+// endHunk writes the header of the current hunk, if any, with the counts
+// of its body.
+func (r *patchRepairer) endHunk() {
+	h := r.hunk
+	if h == nil {
+		return
+	}
+	r.hunk = nil
+	start := h.start
+	if start < 0 {
+		// No usable line number: place it after the previous hunk.
+		start = r.oldEnd + 1
+		if h.oldN == 0 {
+			start = r.oldEnd
+		}
+	}
+	// first is the 0-based index of the first old line, or of the line the
+	// hunk inserts before if it has none.
+	first := start - 1
+	if h.oldN == 0 {
+		first = start
+	}
+	newStart := first + r.delta + 1
+	if h.newN == 0 {
+		newStart--
+	}
+	header := fmt.Sprintf("@@ -%d,%d +%d,%d @@%s", start, h.oldN, newStart, h.newN, h.section)
+	if p, err := parseHunkHeader(h.text); err == nil && p.OldStart == start && p.OldLines == h.oldN &&
+		p.NewStart == newStart && p.NewLines == h.newN {
+		// Keep a correct header as written, such as one omitting counts of 1.
+		header = strings.TrimRight(h.text, " \t")
+		if header != h.text {
+			r.fix(h.line, "removed trailing whitespace from hunk header")
+		}
+	} else if h.start < 0 {
+		r.fix(h.line, "replaced malformed hunk header %q with %q", h.text, header)
+	} else {
+		r.fix(h.line, "corrected hunk header %q to %q", h.text, header)
+	}
+	r.out[h.at] = header + "\n"
+	r.delta += h.newN - h.oldN
+	r.oldEnd = first + h.oldN
+}
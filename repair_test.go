@@ -0,0 +1,138 @@
+package difflib_test
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	difflib "github.com/njchilds90/go-difflib"
+)
+
+func TestRepairPatch(t *testing.T) {
+	tests := []struct {
+		name, patch, want string
+		fixes             []string
+	}{
+		{
+			name:  "valid patch is unchanged",
+			patch: "--- a/f\n+++ b/f\n@@ -1 +1 @@\n-x\n+y\n@@ -5,2 +5,3 @@ func f() {\n a\n+b\n c\n",
+			want:  "--- a/f\n+++ b/f\n@@ -1 +1 @@\n-x\n+y\n@@ -5,2 +5,3 @@ func f() {\n a\n+b\n c\n",
+		},
+		{
+			name:  "missing spaces on context lines",
+			patch: "--- a/f\n+++ b/f\n@@ -1,5 +1,5 @@\nfoo\n\n-bar\n+baz\n    \n end\n",
+			want:  "--- a/f\n+++ b/f\n@@ -1,5 +1,5 @@\n foo\n \n-bar\n+baz\n \n end\n",
+			fixes: []string{
+				"line 4: added missing space to context line",
+				"line 5: added missing space to context line",
+				"line 8: removed trailing whitespace from empty context line",
+			},
+		},
+		{
+			name:  "wrong counts shift later hunks",
+			patch: "--- a/f\n+++ b/f\n@@ -2,1 +2,1 @@\n a\n-b\n+c\n+d\n@@ -10,9 +10,9 @@\n x\n-y\n",
+			want:  "--- a/f\n+++ b/f\n@@ -2,2 +2,3 @@\n a\n-b\n+c\n+d\n@@ -10,2 +11,1 @@\n x\n-y\n",
+			fixes: []string{
+				`line 3: corrected hunk header "@@ -2,1 +2,1 @@" to "@@ -2,2 +2,3 @@"`,
+				`line 8: corrected hunk header "@@ -10,9 +10,9 @@" to "@@ -10,2 +11,1 @@"`,
+			},
+		},
+		{
+			name:  "missing file header and bare hunk headers",
+			patch: "@@ ... @@\n one\n-two\n+2\n@@\n-three\n\n\n",
+			want:  "--- a\n+++ b\n@@ -1,2 +1,2 @@\n one\n-two\n+2\n@@ -3,1 +2,0 @@\n-three\n",
+			fixes: []string{
+				"line 1: added missing file header",
+				`line 1: replaced malformed hunk header "@@ ... @@" with "@@ -1,2 +1,2 @@"`,
+				`line 5: replaced malformed hunk header "@@" with "@@ -3,1 +2,0 @@"`,
+				"line 7: removed trailing blank lines",
+			},
+		},
+		{
+			name:  "trailing whitespace on headers",
+			patch: "diff --git a/f b/f\nindex 1..2 100644\n--- a/f \n+++ b/f\t\n@@ -1 +1 @@  \n-x\n+y\n",
+			want:  "diff --git a/f b/f\nindex 1..2 100644\n--- a/f\n+++ b/f\n@@ -1 +1 @@\n-x\n+y\n",
+			fixes: []string{
+				"line 3: removed trailing whitespace from file header",
+				"line 4: removed trailing whitespace from file header",
+				"line 5: removed trailing whitespace from hunk header",
+			},
+		},
+		{
+			name:  "each file gets its own numbering",
+			patch: "--- a/f\n+++ b/f\n@@ -1 +1 @@\n-x\n+y\n+z\n--- a/g\n+++ b/g\n@@ -4,1 +5,1 @@\n-x\n+y\n",
+			want:  "--- a/f\n+++ b/f\n@@ -1,1 +1,2 @@\n-x\n+y\n+z\n--- a/g\n+++ b/g\n@@ -4,1 +4,1 @@\n-x\n+y\n",
+			fixes: []string{
+				`line 3: corrected hunk header "@@ -1 +1 @@" to "@@ -1,1 +1,2 @@"`,
+				`line 9: corrected hunk header "@@ -4,1 +5,1 @@" to "@@ -4,1 +4,1 @@"`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, fixes, err := difflib.RepairPatch(tt.patch)
+			if err != nil {
+				t.Fatalf("RepairPatch error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("RepairPatch =\n%s\nwant\n%s", got, tt.want)
+			}
+			var descs []string
+			for _, f := range fixes {
+				descs = append(descs, f.String())
+			}
+			if !reflect.DeepEqual(descs, tt.fixes) {
+				t.Errorf("fixes = %q, want %q", descs, tt.fixes)
+			}
+		})
+	}
+}
+
+func TestRepairPatchNoHunks(t *testing.T) {
+	if _, _, err := difflib.RepairPatch("--- a/f\n+++ b/f\n"); err == nil || !strings.Contains(err.Error(), "no hunks") {
+		t.Errorf("error = %v, want a no hunks error", err)
+	}
+}
+
+func TestRepairPatchThenApply(t *testing.T) {
+	original := difflib.SplitLines("package main\n\nfunc main() {\n\tprintln(1)\n}\n")
+	patch := "@@ -3,2 +3,2 @@\nfunc main() {\n-\tprintln(1)\n+\tprintln(2)\n}\n"
+	if _, err := difflib.ApplyPatch(original, patch); err == nil {
+		t.Fatal("ApplyPatch of the malformed patch succeeded")
+	}
+	fixed, _, err := difflib.RepairPatch(patch)
+	if err != nil {
+		t.Fatalf("RepairPatch error: %v", err)
+	}
+	got, err := difflib.ApplyPatch(original, fixed)
+	if err != nil {
+		t.Fatalf("ApplyPatch error: %v\n%s", err, fixed)
+	}
+	if s := difflib.JoinLines(got); s != "package main\n\nfunc main() {\n\tprintln(2)\n}\n" {
+		t.Errorf("result = %q", s)
+	}
+}
+
+func ExampleRepairPatch() {
+	patch := "@@ -1,1 +1,1 @@\none\n-two\n+2\n"
+	fixed, fixes, err := difflib.RepairPatch(patch)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Print(fixed)
+	for _, f := range fixes {
+		fmt.Println(f)
+	}
+	// Output:
+	// --- a
+	// +++ b
+	// @@ -1,2 +1,2 @@
+	//  one
+	// -two
+	// +2
+	// line 1: added missing file header
+	// line 1: corrected hunk header "@@ -1,1 +1,1 @@" to "@@ -1,2 +1,2 @@"
+	// line 2: added missing space to context line
+}
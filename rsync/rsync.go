@@ -0,0 +1,426 @@
+// Package rsync implements the rsync algorithm as three separate steps, so
+// that two machines can sync a large file without either holding both
+// copies:
+//
+//  1. The side with the old file computes its Signature: a weak rolling
+//     checksum and a strong hash of every fixed-size block.
+//  2. The side with the new file streams it past the signature with
+//     WriteDelta, producing a delta of block references and literal data.
+//  3. The side with the old file rebuilds the new one with ApplyDelta.
+//
+// Signatures and deltas have compact binary encodings suitable for sending
+// over a network.
+//
+// Basic usage:
+//
+//	sig, err := rsync.NewSignature(oldFile, 0)
+//	sig.WriteTo(conn) // ... ReadSignature on the other side
+//	err = rsync.WriteDelta(deltaFile, sig, newFile)
+//	err = rsync.ApplyDelta(out, oldFile, deltaFile)
+package rsync
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// DefaultBlockSize is the block size used when NewSignature is given zero.
+const DefaultBlockSize = 2048
+
+// StrongSize is the length of the strong block hash: a truncated SHA-256.
+const StrongSize = 16
+
+// Magic prefixes of the binary encodings, ending in a version byte.
+const (
+	signatureMagic = "RSIG\x01"
+	deltaMagic     = "RDLT\x01"
+)
+
+// Delta commands.
+const (
+	cmdCopy    = 'C' // block index and count
+	cmdLiteral = 'L' // length and data
+	cmdEnd     = 'E' // SHA-256 of the whole new file
+)
+
+// maxLiteral bounds the literal data WriteDelta buffers before emitting it.
+const maxLiteral = 1 << 16
+
+// ErrCorrupt is returned (wrapped) when a signature or delta is malformed or
+// the rebuilt file does not match the checksum recorded in the delta.
+var ErrCorrupt = errors.New("rsync: corrupt data")
+
+// Signature describes the blocks of a file.
+type Signature struct {
+	// BlockSize is the size of every block but the last, which may be shorter.
+	BlockSize int
+	// Size is the length of the file in bytes.
+	Size int64
+	// Blocks holds one checksum per block, in file order.
+	Blocks []BlockChecksum
+
+	index map[uint32][]int // weak checksum to block indexes, built on demand
+}
+
+// BlockChecksum holds the checksums of one block.
+type BlockChecksum struct {
+	Weak   uint32
+	Strong [StrongSize]byte
+}
+
+// NewSignature reads r to the end and returns the signature of its blocks.
+// A blockSize of zero selects DefaultBlockSize.
+//
+// Example:
+//
+//	f, _ := os.Open("disk.img")
+//	sig, err := rsync.NewSignature(f, 0)
+func NewSignature(r io.Reader, blockSize int) (*Signature, error) {
+	if blockSize == 0 {
+		blockSize = DefaultBlockSize
+	}
+	if blockSize < 0 {
+		return nil, fmt.Errorf("rsync: negative block size %d", blockSize)
+	}
+	sig := &Signature{BlockSize: blockSize}
+	buf := make([]byte, blockSize)
+	for {
+		n, err := io.ReadFull(r, buf)
+		if n > 0 {
+			sig.Size += int64(n)
+			sig.Blocks = append(sig.Blocks, BlockChecksum{Weak: weakSum(buf[:n]), Strong: strongSum(buf[:n])})
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return sig, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// blockLen returns the length of block i.
+func (s *Signature) blockLen(i int) int {
+	if i == len(s.Blocks)-1 {
+		return int(s.Size - int64(i)*int64(s.BlockSize))
+	}
+	return s.BlockSize
+}
+
+// find returns the block whose checksums match window, or -1.
+func (s *Signature) find(weak uint32, window []byte) int {
+	if s.index == nil {
+		s.index = make(map[uint32][]int, len(s.Blocks))
+		for i, b := range s.Blocks {
+			s.index[b.Weak] = append(s.index[b.Weak], i)
+		}
+	}
+	candidates := s.index[weak]
+	if len(candidates) == 0 {
+		return -1
+	}
+	strong := strongSum(window)
+	for _, i := range candidates {
+		if s.blockLen(i) == len(window) && s.Blocks[i].Strong == strong {
+			return i
+		}
+	}
+	return -1
+}
+
+// WriteTo writes the binary encoding of the signature to w. It implements
+// io.WriterTo.
+func (s *Signature) WriteTo(w io.Writer) (int64, error) {
+	buf := []byte(signatureMagic)
+	buf = binary.AppendUvarint(buf, uint64(s.BlockSize))
+	buf = binary.AppendUvarint(buf, uint64(s.Size))
+	buf = binary.AppendUvarint(buf, uint64(len(s.Blocks)))
+	for _, b := range s.Blocks {
+		buf = binary.BigEndian.AppendUint32(buf, b.Weak)
+		buf = append(buf, b.Strong[:]...)
+	}
+	n, err := w.Write(buf)
+	return int64(n), err
+}
+
+// ReadSignature decodes a signature written by Signature.WriteTo.
+//
+// Example:
+//
+//	sig, err := rsync.ReadSignature(conn)
+func ReadSignature(r io.Reader) (*Signature, error) {
+	br := bufio.NewReader(r)
+	if err := readMagic(br, signatureMagic); err != nil {
+		return nil, err
+	}
+	var fields [3]uint64
+	for i := range fields {
+		v, err := binary.ReadUvarint(br)
+		if err != nil {
+			return nil, corrupt(err)
+		}
+		fields[i] = v
+	}
+	blockSize, size, count := fields[0], fields[1], fields[2]
+	if blockSize == 0 || blockSize > 1<<30 || count != (size+blockSize-1)/blockSize {
+		return nil, fmt.Errorf("%w: inconsistent signature header", ErrCorrupt)
+	}
+	sig := &Signature{BlockSize: int(blockSize), Size: int64(size)}
+	var rec [4 + StrongSize]byte
+	for i := uint64(0); i < count; i++ {
+		if _, err := io.ReadFull(br, rec[:]); err != nil {
+			return nil, corrupt(err)
+		}
+		b := BlockChecksum{Weak: binary.BigEndian.Uint32(rec[:4])}
+		copy(b.Strong[:], rec[4:])
+		sig.Blocks = append(sig.Blocks, b)
+	}
+	return sig, nil
+}
+
+// WriteDelta reads the new file from r and writes to w a delta that rebuilds
+// it from the file described by sig. Only the current block and pending
+// literal data are held in memory.
+//
+// Example:
+//
+//	err := rsync.WriteDelta(conn, sig, newFile)
+func WriteDelta(w io.Writer, sig *Signature, r io.Reader) error {
+	bs := sig.BlockSize
+	if bs <= 0 {
+		return fmt.Errorf("rsync: invalid block size %d", bs)
+	}
+	hash := sha256.New()
+	br := bufio.NewReader(io.TeeReader(r, hash))
+	dw := &deltaWriter{w: bufio.NewWriter(w), start: -1}
+	dw.header(bs)
+
+	lastLen := 0
+	if len(sig.Blocks) > 0 {
+		lastLen = sig.blockLen(len(sig.Blocks) - 1)
+	}
+	// buf holds pending literal bytes followed by the window buf[pos:].
+	var buf []byte
+	pos := 0
+	var a, b uint32
+	valid, eof := false, false
+	for {
+		if !valid {
+			for !eof && len(buf)-pos < bs {
+				c, err := br.ReadByte()
+				if err == io.EOF {
+					eof = true
+					break
+				}
+				if err != nil {
+					return err
+				}
+				buf = append(buf, c)
+			}
+			if eof && len(buf)-pos > lastLen {
+				// Only the short last block can match a window cut off by
+				// the end of the file.
+				pos = len(buf) - lastLen
+			}
+			if len(buf) == pos {
+				break
+			}
+			a, b = rollingSums(buf[pos:])
+			valid = true
+		}
+		if i := sig.find(a&0xFFFF|b<<16, buf[pos:]); i >= 0 {
+			dw.literal(buf[:pos])
+			dw.copy(i)
+			buf, pos, valid = buf[:0], 0, false
+			continue
+		}
+		if eof {
+			break
+		}
+		c, err := br.ReadByte()
+		if err == io.EOF {
+			eof, valid = true, false
+			pos++
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		out := uint32(buf[pos])
+		buf = append(buf, c)
+		pos++
+		a = (a - out + uint32(c)) & 0xFFFF
+		b = (b - uint32(bs)*out + a) & 0xFFFF
+		if pos >= maxLiteral {
+			dw.literal(buf[:pos])
+			buf, pos = append(buf[:0], buf[pos:]...), 0
+		}
+	}
+	dw.literal(buf)
+	dw.end(hash.Sum(nil))
+	return dw.err
+}
+
+// ApplyDelta rebuilds the new file from base, the file the signature was
+// made from, and a delta written by WriteDelta, writing it to w. The result
+// is checked against the SHA-256 recorded in the delta; on a mismatch the
+// data already written to w must be discarded.
+//
+// Example:
+//
+//	old, _ := os.Open("disk.img")
+//	err := rsync.ApplyDelta(out, old, deltaReader)
+func ApplyDelta(w io.Writer, base io.ReaderAt, delta io.Reader) error {
+	br := bufio.NewReader(delta)
+	if err := readMagic(br, deltaMagic); err != nil {
+		return err
+	}
+	bs, err := binary.ReadUvarint(br)
+	if err != nil {
+		return corrupt(err)
+	}
+	if bs == 0 || bs > 1<<30 {
+		return fmt.Errorf("%w: invalid block size %d", ErrCorrupt, bs)
+	}
+	hash := sha256.New()
+	out := io.MultiWriter(w, hash)
+	for {
+		cmd, err := br.ReadByte()
+		if err != nil {
+			return corrupt(err)
+		}
+		switch cmd {
+		case cmdCopy:
+			block, err1 := binary.ReadUvarint(br)
+			count, err2 := binary.ReadUvarint(br)
+			if err := errors.Join(err1, err2); err != nil {
+				return corrupt(err)
+			}
+			if limit := uint64(1<<62) / bs; block > limit || count > limit {
+				return fmt.Errorf("%w: copy of %d blocks at %d out of range", ErrCorrupt, count, block)
+			}
+			sec := io.NewSectionReader(base, int64(block*bs), int64(count*bs))
+			if _, err := io.Copy(out, sec); err != nil {
+				return err
+			}
+		case cmdLiteral:
+			n, err := binary.ReadUvarint(br)
+			if err != nil {
+				return corrupt(err)
+			}
+			if _, err := io.CopyN(out, br, int64(n)); err != nil {
+				return corrupt(err)
+			}
+		case cmdEnd:
+			var sum [sha256.Size]byte
+			if _, err := io.ReadFull(br, sum[:]); err != nil {
+				return corrupt(err)
+			}
+			if !bytes.Equal(sum[:], hash.Sum(nil)) {
+				return fmt.Errorf("%w: checksum mismatch", ErrCorrupt)
+			}
+			return nil
+		default:
+			return fmt.Errorf("%w: unknown delta command %q", ErrCorrupt, cmd)
+		}
+	}
+}
+
+// deltaWriter encodes delta commands, merging copies of consecutive blocks.
+type deltaWriter struct {
+	w            *bufio.Writer
+	start, count int // pending copy, start -1 if none
+	err          error
+}
+
+func (d *deltaWriter) write(b []byte) {
+	if d.err == nil {
+		_, d.err = d.w.Write(b)
+	}
+}
+
+func (d *deltaWriter) header(blockSize int) {
+	d.write(binary.AppendUvarint([]byte(deltaMagic), uint64(blockSize)))
+}
+
+func (d *deltaWriter) flushCopy() {
+	if d.start < 0 {
+		return
+	}
+	buf := binary.AppendUvarint([]byte{cmdCopy}, uint64(d.start))
+	d.write(binary.AppendUvarint(buf, uint64(d.count)))
+	d.start = -1
+}
+
+func (d *deltaWriter) copy(block int) {
+	if d.start >= 0 && d.start+d.count == block {
+		d.count++
+		return
+	}
+	d.flushCopy()
+	d.start, d.count = block, 1
+}
+
+func (d *deltaWriter) literal(data []byte) {
+	if len(data) == 0 {
+		return
+	}
+	d.flushCopy()
+	d.write(binary.AppendUvarint([]byte{cmdLiteral}, uint64(len(data))))
+	d.write(data)
+}
+
+func (d *deltaWriter) end(sum []byte) {
+	d.flushCopy()
+	d.write([]byte{cmdEnd})
+	d.write(sum)
+	if d.err == nil {
+		d.err = d.w.Flush()
+	}
+}
+
+// rollingSums returns the two 16-bit halves of the rsync weak checksum of
+// block: the plain byte sum and the position-weighted sum.
+func rollingSums(block []byte) (a, b uint32) {
+	n := uint32(len(block))
+	for i, c := range block {
+		a += uint32(c)
+		b += (n - uint32(i)) * uint32(c)
+	}
+	return a & 0xFFFF, b & 0xFFFF
+}
+
+// weakSum returns the rsync weak checksum of block.
+func weakSum(block []byte) uint32 {
+	a, b := rollingSums(block)
+	return a | b<<16
+}
+
+// strongSum returns the truncated SHA-256 of block.
+func strongSum(block []byte) [StrongSize]byte {
+	full := sha256.Sum256(block)
+	var s [StrongSize]byte
+	copy(s[:], full[:])
+	return s
+}
+
+func readMagic(r io.Reader, magic string) error {
+	buf := make([]byte, len(magic))
+	if _, err := io.ReadFull(r, buf); err != nil || string(buf) != magic {
+		return fmt.Errorf("%w: bad magic", ErrCorrupt)
+	}
+	return nil
+}
+
+// corrupt wraps a read error, reporting a premature end as corruption.
+func corrupt(err error) error {
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return fmt.Errorf("%w: unexpected end of data", ErrCorrupt)
+	}
+	return err
+}
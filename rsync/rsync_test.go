@@ -0,0 +1,151 @@
+package rsync_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/njchilds90/go-difflib/rsync"
+)
+
+// sync runs the whole protocol, passing the signature through its binary
+// encoding, and returns the delta and the rebuilt file.
+func sync(t *testing.T, old, new []byte, blockSize int) (delta, got []byte) {
+	t.Helper()
+	sig, err := rsync.NewSignature(bytes.NewReader(old), blockSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wire bytes.Buffer
+	if _, err := sig.WriteTo(&wire); err != nil {
+		t.Fatal(err)
+	}
+	sig, err = rsync.ReadSignature(&wire)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var d bytes.Buffer
+	if err := rsync.WriteDelta(&d, sig, bytes.NewReader(new)); err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	if err := rsync.ApplyDelta(&out, bytes.NewReader(old), bytes.NewReader(d.Bytes())); err != nil {
+		t.Fatal(err)
+	}
+	return d.Bytes(), out.Bytes()
+}
+
+func TestSync(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	random := func(n int) []byte {
+		b := make([]byte, n)
+		rng.Read(b)
+		return b
+	}
+	base := random(100000)
+	insert := append(append(append([]byte{}, base[:40000]...), random(50)...), base[40000:]...)
+	tests := []struct {
+		name      string
+		old, new  []byte
+		blockSize int
+		maxDelta  int
+	}{
+		{"empty", nil, nil, 0, 50},
+		{"from empty", nil, []byte("hello"), 0, 60},
+		{"to empty", base, nil, 0, 50},
+		{"equal", base, base, 0, 60},
+		{"insert", base, insert, 0, 2200},
+		{"delete", base, append(append([]byte{}, base[:10000]...), base[12345:]...), 0, 4200},
+		{"short tail", base[:5000], base[:5000], 1024, 60},
+		{"moved blocks", base[:4096], append(append([]byte{}, base[2048:4096]...), base[:2048]...), 1024, 80},
+		// The short last block only matches at the end of the new file.
+		{"moved tail", base[:5000], append(append([]byte{}, base[4096:5000]...), base[:4096]...), 1024, 1000},
+		{"small blocks", []byte("abcdefghij"), []byte("xabcdefghij"), 3, 70},
+		{"literal flush", nil, random(200000), 0, 201000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			delta, got := sync(t, tt.old, tt.new, tt.blockSize)
+			if !bytes.Equal(got, tt.new) {
+				t.Errorf("rebuilt file differs: got %d bytes, want %d", len(got), len(tt.new))
+			}
+			if len(delta) > tt.maxDelta {
+				t.Errorf("len(delta) = %d, want at most %d", len(delta), tt.maxDelta)
+			}
+		})
+	}
+}
+
+func TestSyncRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	for i := 0; i < 100; i++ {
+		old := make([]byte, rng.Intn(300))
+		for k := range old {
+			old[k] = byte('a' + rng.Intn(3))
+		}
+		new := append([]byte{}, old...)
+		for k := 0; k < 3 && len(new) > 0; k++ {
+			p := rng.Intn(len(new))
+			new = append(new[:p], append([]byte{byte('a' + rng.Intn(3))}, new[p:]...)...)
+		}
+		if _, got := sync(t, old, new, 1+rng.Intn(8)); !bytes.Equal(got, new) {
+			t.Fatalf("sync(%q, %q) = %q", old, new, got)
+		}
+	}
+}
+
+func TestApplyDeltaErrors(t *testing.T) {
+	old := []byte(strings.Repeat("block data ", 100))
+	sig, _ := rsync.NewSignature(bytes.NewReader(old), 16)
+	var d bytes.Buffer
+	rsync.WriteDelta(&d, sig, bytes.NewReader(append([]byte("new "), old...)))
+	good := d.Bytes()
+
+	tests := []struct {
+		name  string
+		base  []byte
+		delta []byte
+		want  string
+	}{
+		{"magic", old, []byte("nope"), "bad magic"},
+		{"truncated", old, good[:len(good)-5], "unexpected end of data"},
+		{"command", old, append(append([]byte{}, good[:6]...), 'X'), `unknown delta command 'X'`},
+		{"wrong base", []byte(strings.Repeat("other data ", 100)), good, "checksum mismatch"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := rsync.ApplyDelta(&bytes.Buffer{}, bytes.NewReader(tt.base), bytes.NewReader(tt.delta))
+			if !errors.Is(err, rsync.ErrCorrupt) || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("ApplyDelta() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadSignatureErrors(t *testing.T) {
+	sig, _ := rsync.NewSignature(strings.NewReader("some file content"), 4)
+	var buf bytes.Buffer
+	sig.WriteTo(&buf)
+	for _, data := range [][]byte{nil, []byte("RSIG\x01\x00"), buf.Bytes()[:buf.Len()-1]} {
+		if _, err := rsync.ReadSignature(bytes.NewReader(data)); !errors.Is(err, rsync.ErrCorrupt) {
+			t.Errorf("ReadSignature(%q) error = %v, want ErrCorrupt", data, err)
+		}
+	}
+}
+
+func ExampleWriteDelta() {
+	old := []byte(strings.Repeat("0123456789abcdef", 64))
+	new := append([]byte("header\n"), old...)
+
+	sig, _ := rsync.NewSignature(bytes.NewReader(old), 64)
+	var delta bytes.Buffer
+	rsync.WriteDelta(&delta, sig, bytes.NewReader(new))
+	var out bytes.Buffer
+	err := rsync.ApplyDelta(&out, bytes.NewReader(old), &delta)
+	fmt.Println(err, bytes.Equal(out.Bytes(), new), delta.Len() < 100)
+	// Output:
+	// <nil> true true
+}
@@ -0,0 +1,33 @@
+package difflib
+
+// runeRatio is Matcher.Ratio specialised to runes: it interns the runes of
+// b directly instead of building a string per character.
+func runeRatio(a, b []rune) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return calcRatio(0, len(a)+len(b))
+	}
+	ids := make(map[rune]int, len(b))
+	var b2j [][]int
+	for j, r := range b {
+		id, ok := ids[r]
+		if !ok {
+			id = len(b2j)
+			ids[r] = id
+			b2j = append(b2j, nil)
+		}
+		b2j[id] = append(b2j[id], j)
+	}
+	aIDs := make([]int, len(a))
+	for i, r := range a {
+		id, ok := ids[r]
+		if !ok {
+			id = -1
+		}
+		aIDs[i] = id
+	}
+	matches := 0
+	eachMatch(aIDs, b2j, len(b), nil, nil, func(m SequenceMatch) {
+		matches += m.Size
+	})
+	return calcRatio(matches, len(a)+len(b))
+}
@@ -0,0 +1,28 @@
+package difflib_test
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	difflib "github.com/njchilds90/go-difflib"
+)
+
+func TestStringRatioMatchesSequenceRatio(t *testing.T) {
+	rng := rand.New(rand.NewSource(11))
+	alphabet := []rune("abcé日 ")
+	random := func() string {
+		r := make([]rune, rng.Intn(40))
+		for i := range r {
+			r[i] = alphabet[rng.Intn(len(alphabet))]
+		}
+		return string(r)
+	}
+	for i := 0; i < 2000; i++ {
+		a, b := random(), random()
+		want := difflib.SequenceRatio(strings.Split(a, ""), strings.Split(b, ""))
+		if got := difflib.StringRatio(a, b); got != want {
+			t.Fatalf("StringRatio(%q, %q) = %v, want %v", a, b, got, want)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package difflib
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// SideBySide renders a two-column diff of input.A and input.B, like
+// `diff -y` or sdiff, fitted to width columns (130 if width is zero or
+// negative). Every line of both inputs is shown. The gutter between the
+// columns marks each row: ' ' for equal lines, '|' for changed lines, '<'
+// for lines only in A and '>' for lines only in B. Tabs are expanded, and
+// lines longer than a column are wrapped onto continuation rows, whose
+// gutter is left blank, rather than truncated. Characters are assumed to be
+// one column wide. If input.FoldEqual is positive, long runs of equal lines
+// are shown as a single marker row; see DiffInput.FoldEqual.
+//
+// Example:
+//
+//	fmt.Print(difflib.SideBySide(difflib.DiffInput{
+//	    A: difflib.SplitLines("one\ntwo\nthree\n"),
+//	    B: difflib.SplitLines("one\nTWO\nthree\nfour\n"),
+//	}, 40))
+//	// one                  one
+//	// two                | TWO
+//	// three                three
+//	//                    > four
+func SideBySide(input DiffInput, width int) string {
+	if width <= 0 {
+		width = 130
+	}
+	// Each row is "<left> <marker> <right>".
+	col := max((width-3)/2, 1)
+	var b strings.Builder
+	row := func(left, right string, marker byte) {
+		lw, rw := wrapColumn(left, col), wrapColumn(right, col)
+		for k := 0; k < max(len(lw), len(rw)); k++ {
+			l, r := "", ""
+			if k < len(lw) {
+				l = lw[k]
+			}
+			if k < len(rw) {
+				r = rw[k]
+			}
+			m := marker
+			if k > 0 {
+				m = ' '
+			}
+			line := l + strings.Repeat(" ", col-utf8.RuneCountInString(l)) + " " + string(m) + " " + r
+			b.WriteString(strings.TrimRight(line, " "))
+			b.WriteString("\n")
+		}
+	}
+	a, bb := input.A, input.B
+	for _, op := range input.matcher().GetOpCodes() {
+		switch op.Tag {
+		case OpEqual:
+			if input.folds(op) {
+				b.WriteString(foldMarker(op))
+				continue
+			}
+			for k := 0; k < op.I2-op.I1; k++ {
+				row(a[op.I1+k], bb[op.J1+k], ' ')
+			}
+		default:
+			n, m := op.I2-op.I1, op.J2-op.J1
+			for k := 0; k < max(n, m); k++ {
+				switch {
+				case k < n && k < m:
+					row(a[op.I1+k], bb[op.J1+k], '|')
+				case k < n:
+					row(a[op.I1+k], "", '<')
+				default:
+					row("", bb[op.J1+k], '>')
+				}
+			}
+		}
+	}
+	return b.String()
+}
+
+// folds reports whether the equal opcode op is long enough for input's
+// FoldEqual to fold it.
+func (input DiffInput) folds(op OpCode) bool {
+	return input.FoldEqual > 0 && op.I2-op.I1 > input.FoldEqual
+}
+
+// foldMarker returns the line that stands for the folded equal opcode op.
+func foldMarker(op OpCode) string {
+	return fmt.Sprintf("⋮ %d identical lines (old %d-%d, new %d-%d)\n", op.I2-op.I1, op.I1+1, op.I2, op.J1+1, op.J2)
+}
+
+// wrapColumn expands tabs in line, drops its line ending and splits it into
+// pieces of at most width runes. It always returns at least one piece.
+func wrapColumn(line string, width int) []string {
+	line = strings.TrimRight(line, "\r\n")
+	var expanded strings.Builder
+	n := 0
+	for _, r := range line {
+		if r == '\t' {
+			pad := 8 - n%8
+			expanded.WriteString(strings.Repeat(" ", pad))
+			n += pad
+			continue
+		}
+		expanded.WriteRune(r)
+		n++
+	}
+	runes := []rune(expanded.String())
+	pieces := []string{}
+	for len(runes) > width {
+		pieces = append(pieces, string(runes[:width]))
+		runes = runes[width:]
+	}
+	return append(pieces, string(runes))
+}
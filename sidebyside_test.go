@@ -0,0 +1,109 @@
+package difflib_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	difflib "github.com/njchilds90/go-difflib"
+)
+
+func TestSideBySide(t *testing.T) {
+	tests := []struct {
+		name  string
+		a, b  string
+		width int
+		want  string
+	}{
+		{
+			name:  "markers",
+			a:     "one\ntwo\nthree\nfive\n",
+			b:     "one\nTWO\nthree\nfour\n",
+			width: 23,
+			want: "one          one\n" +
+				"two        | TWO\n" +
+				"three        three\n" +
+				"five       | four\n",
+		},
+		{
+			name:  "insert and delete",
+			a:     "a\nold\nz\n",
+			b:     "a\nz\nnew\n",
+			width: 11,
+			want: "a      a\n" +
+				"old  <\n" +
+				"z      z\n" +
+				"     > new\n",
+		},
+		{
+			name:  "wrapping",
+			a:     "abcdefgh\n",
+			b:     "xy\n",
+			width: 11,
+			want: "abcd | xy\n" +
+				"efgh\n",
+		},
+		{
+			name:  "tabs",
+			a:     "\tx\n",
+			b:     "\tx\n",
+			width: 23,
+			want:  "        x    " + "        x\n",
+		},
+		{
+			name: "empty",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := difflib.SideBySide(difflib.DiffInput{A: difflib.SplitLines(tt.a), B: difflib.SplitLines(tt.b)}, tt.width)
+			if got != tt.want {
+				t.Errorf("SideBySide() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSideBySideFitsWidth(t *testing.T) {
+	a := difflib.SplitLines(strings.Repeat("é", 300) + "\nshort\n")
+	b := difflib.SplitLines("short\n" + strings.Repeat("ü", 250) + "\n")
+	for _, width := range []int{0, 20, 80, 131} {
+		limit := width
+		if limit == 0 {
+			limit = 130
+		}
+		for _, line := range strings.Split(difflib.SideBySide(difflib.DiffInput{A: a, B: b}, width), "\n") {
+			if n := utf8.RuneCountInString(line); n > limit {
+				t.Errorf("width %d: line has %d columns: %q", width, n, line)
+			}
+		}
+	}
+}
+
+func TestSideBySideFoldEqual(t *testing.T) {
+	a := numbered(10)
+	b := append(append([]string{"new\n"}, a[:9]...), "last\n")
+	got := difflib.SideBySide(difflib.DiffInput{A: a, B: b, FoldEqual: 3}, 30)
+	want := "              > new\n" +
+		"⋮ 9 identical lines (old 1-9, new 2-10)\n" +
+		"line 10       | last\n"
+	if got != want {
+		t.Errorf("SideBySide() =\n%s\nwant\n%s", got, want)
+	}
+	if got := difflib.SideBySide(difflib.DiffInput{A: a, B: b, FoldEqual: 9}, 30); strings.Contains(got, "⋮") {
+		t.Errorf("run of 9 lines folded with FoldEqual 9:\n%s", got)
+	}
+}
+
+func ExampleSideBySide() {
+	fmt.Print(difflib.SideBySide(difflib.DiffInput{
+		A: difflib.SplitLines("one\ntwo\nthree\n"),
+		B: difflib.SplitLines("one\nTWO\nthree\nfour\n"),
+	}, 40))
+	// Output:
+	// one                  one
+	// two                | TWO
+	// three                three
+	//                    > four
+}
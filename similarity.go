@@ -0,0 +1,80 @@
+package difflib
+
+import (
+	"runtime"
+	"sync"
+)
+
+// SimilarityOptions controls SimilarityMatrix.
+type SimilarityOptions struct {
+	// Concurrency is the number of worker goroutines. Zero selects
+	// runtime.GOMAXPROCS(0).
+	Concurrency int
+	// Cutoff drops pairs whose ratio is below it, keeping the result sparse
+	// for large inputs. Zero keeps every pair.
+	Cutoff float64
+}
+
+// SimilarityPair is the similarity of items I and J, with I < J.
+type SimilarityPair struct {
+	I     int     `json:"i"`
+	J     int     `json:"j"`
+	Score float64 `json:"score"`
+}
+
+// SimilarityMatrix computes StringRatio for every pair of items using a pool
+// of workers, returning the upper triangle of the similarity matrix as pairs
+// ordered by I and then J. With a cutoff, pairs that the cheap quick ratio
+// bounds rule out are skipped without computing the full ratio, which makes
+// clustering and deduplication over large corpora practical.
+//
+// Example:
+//
+//	pairs := difflib.SimilarityMatrix(titles, difflib.SimilarityOptions{Cutoff: 0.9})
+//	for _, p := range pairs {
+//	    fmt.Printf("%q ~ %q (%.2f)\n", titles[p.I], titles[p.J], p.Score)
+//	}
+func SimilarityMatrix(items []string, opts SimilarityOptions) []SimilarityPair {
+	workers := opts.Concurrency
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	chars := make([][]string, len(items))
+	for i, s := range items {
+		chars[i] = splitChars(s)
+	}
+
+	rows := make([][]SimilarityPair, len(items))
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(workers, len(items)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			m := NewMatcher(nil, nil)
+			for i := range next {
+				m.SetSeq1(chars[i])
+				for j := i + 1; j < len(items); j++ {
+					m.SetSeq2(chars[j])
+					if opts.Cutoff > 0 && (m.RealQuickRatio() < opts.Cutoff || m.QuickRatio() < opts.Cutoff) {
+						continue
+					}
+					if r := m.Ratio(); r >= opts.Cutoff {
+						rows[i] = append(rows[i], SimilarityPair{i, j, r})
+					}
+				}
+			}
+		}()
+	}
+	for i := range items {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+
+	var pairs []SimilarityPair
+	for _, row := range rows {
+		pairs = append(pairs, row...)
+	}
+	return pairs
+}
@@ -0,0 +1,48 @@
+package difflib_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	difflib "github.com/njchilds90/go-difflib"
+)
+
+func TestSimilarityMatrix(t *testing.T) {
+	items := []string{"kitten", "sitting", "mitten", "", "kitchen", "kitten"}
+	var all []difflib.SimilarityPair
+	for i := range items {
+		for j := i + 1; j < len(items); j++ {
+			all = append(all, difflib.SimilarityPair{I: i, J: j, Score: difflib.StringRatio(items[i], items[j])})
+		}
+	}
+	for _, workers := range []int{0, 1, 3, 100} {
+		got := difflib.SimilarityMatrix(items, difflib.SimilarityOptions{Concurrency: workers})
+		if !reflect.DeepEqual(got, all) {
+			t.Errorf("workers=%d: SimilarityMatrix() = %v, want %v", workers, got, all)
+		}
+		var want []difflib.SimilarityPair
+		for _, p := range all {
+			if p.Score >= 0.7 {
+				want = append(want, p)
+			}
+		}
+		got = difflib.SimilarityMatrix(items, difflib.SimilarityOptions{Concurrency: workers, Cutoff: 0.7})
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("workers=%d, cutoff: SimilarityMatrix() = %v, want %v", workers, got, want)
+		}
+	}
+	if got := difflib.SimilarityMatrix(nil, difflib.SimilarityOptions{}); got != nil {
+		t.Errorf("SimilarityMatrix(nil) = %v, want nil", got)
+	}
+}
+
+func ExampleSimilarityMatrix() {
+	titles := []string{"Fix login bug", "Fix logout bug", "Add dark mode", "fix login bug"}
+	for _, p := range difflib.SimilarityMatrix(titles, difflib.SimilarityOptions{Cutoff: 0.8}) {
+		fmt.Printf("%q ~ %q (%.2f)\n", titles[p.I], titles[p.J], p.Score)
+	}
+	// Output:
+	// "Fix login bug" ~ "Fix logout bug" (0.81)
+	// "Fix login bug" ~ "fix login bug" (0.92)
+}
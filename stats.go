@@ -0,0 +1,241 @@
+package difflib
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DiffStats counts the lines a diff adds and removes.
+type DiffStats struct {
+	// Added and Removed are the number of '+' and '-' lines.
+	Added, Removed int
+	// Changed is the number of removed lines that are directly replaced by an
+	// added line, i.e. the sum over each run of adjacent changes of the
+	// smaller of its removed and added counts. Changed lines are included in
+	// Added and Removed.
+	Changed int
+}
+
+// Stats returns the added, removed and changed line counts of the diff.
+//
+// Example:
+//
+//	s := difflib.UnifiedDiff(input).Stats()
+//	fmt.Printf("+%d -%d\n", s.Added, s.Removed)
+func (d DiffResult) Stats() DiffStats {
+	var s DiffStats
+	for _, h := range d.Hunks {
+		dels, adds := 0, 0
+		flush := func() {
+			s.Changed += min(dels, adds)
+			dels, adds = 0, 0
+		}
+		for _, l := range h.Lines {
+			switch {
+			case strings.HasPrefix(l, "+"):
+				s.Added++
+				adds++
+			case strings.HasPrefix(l, "-"):
+				s.Removed++
+				dels++
+			case strings.HasPrefix(l, `\`):
+			default:
+				flush()
+			}
+		}
+		flush()
+	}
+	return s
+}
+
+// Stats returns the added, removed and changed line counts of all files of
+// the patch set together.
+//
+// Example:
+//
+//	s := difflib.CombineDiffs(results...).Stats()
+//	fmt.Printf("+%d -%d\n", s.Added, s.Removed)
+func (p *PatchSet) Stats() DiffStats {
+	var total DiffStats
+	for _, f := range p.Files {
+		s := f.Stats()
+		total.Added += s.Added
+		total.Removed += s.Removed
+		total.Changed += s.Changed
+	}
+	return total
+}
+
+// EditDistance returns the number of lines the diff adds or removes, the
+// line-level edit distance between its two sides when only insertions and
+// deletions count. Of two correct diffs of the same inputs, the one with the
+// smaller distance is closer to minimal.
+//
+// Example:
+//
+//	fmt.Println(difflib.UnifiedDiff(input).EditDistance())
+func (d DiffResult) EditDistance() int {
+	s := d.Stats()
+	return s.Added + s.Removed
+}
+
+// HunkCount returns the number of hunks in the diff.
+func (d DiffResult) HunkCount() int {
+	return len(d.Hunks)
+}
+
+// Compactness rates how much the diff's changes are gathered into few
+// blocks, from 1 for a diff whose changed lines form a single run (or that
+// has none) down towards 0 for one whose every changed line stands alone.
+// It is (e-b+1)/e for e changed lines in b runs of adjacent '+' and '-'
+// lines. Scattered changes are harder to read, so among diffs with similar
+// EditDistance the more compact one is usually preferable.
+//
+// Example:
+//
+//	plain := difflib.UnifiedDiff(input)
+//	input.IndentHeuristic = true
+//	if d := difflib.UnifiedDiff(input); d.Compactness() > plain.Compactness() {
+//	    plain = d
+//	}
+func (d DiffResult) Compactness() float64 {
+	edits, blocks := 0, 0
+	for _, h := range d.Hunks {
+		inBlock := false
+		for _, l := range h.Lines {
+			switch {
+			case strings.HasPrefix(l, "+") || strings.HasPrefix(l, "-"):
+				edits++
+				if !inBlock {
+					blocks++
+				}
+				inBlock = true
+			case strings.HasPrefix(l, `\`):
+			default:
+				inBlock = false
+			}
+		}
+	}
+	if edits == 0 {
+		return 1
+	}
+	return float64(edits-blocks+1) / float64(edits)
+}
+
+// diffStatWidth is the total width of a DiffStat line, as used by git.
+const diffStatWidth = 80
+
+// DiffStat renders a git-style diffstat of the patch set: one line per file
+// with its number of changed lines and a histogram of insertions and
+// deletions, followed by a summary line. Histograms are scaled down so that
+// lines fit in 80 columns.
+//
+// Example:
+//
+//	ps, _ := difflib.ParsePatchSet(patch)
+//	fmt.Print(difflib.DiffStat(ps))
+//	//  README.md |  3 ++-
+//	//  main.go   | 12 +++++++-----
+//	//  2 files changed, 8 insertions(+), 7 deletions(-)
+func DiffStat(p *PatchSet) string {
+	names := make([]string, len(p.Files))
+	stats := make([]DiffStats, len(p.Files))
+	nameWidth, maxChange := 0, 0
+	for i, f := range p.Files {
+		names[i] = diffStatName(f)
+		stats[i] = f.Stats()
+		nameWidth = max(nameWidth, len(names[i]))
+		maxChange = max(maxChange, stats[i].Added+stats[i].Removed)
+	}
+	countWidth := len(fmt.Sprint(maxChange))
+	graphWidth := max(diffStatWidth-nameWidth-countWidth-6, 10)
+
+	var b strings.Builder
+	for i, f := range p.Files {
+		s := stats[i]
+		fmt.Fprintf(&b, " %-*s | ", nameWidth, names[i])
+		if f.Binary {
+			b.WriteString("Bin\n")
+			continue
+		}
+		plus, minus := s.Added, s.Removed
+		if maxChange > graphWidth {
+			plus = scaleStat(plus, graphWidth, maxChange)
+			minus = scaleStat(minus, graphWidth, maxChange)
+		}
+		fmt.Fprintf(&b, "%*d", countWidth, s.Added+s.Removed)
+		if plus+minus > 0 {
+			b.WriteString(" " + strings.Repeat("+", plus) + strings.Repeat("-", minus))
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString(" " + ShortStat(p) + "\n")
+	return b.String()
+}
+
+// scaleStat scales n from [0, max] to [0, width], keeping non-zero values
+// visible.
+func scaleStat(n, width, maxN int) int {
+	if n == 0 {
+		return 0
+	}
+	return 1 + n*(width-1)/maxN
+}
+
+// diffStatName returns the path shown for f in a diffstat, without git's
+// "a/" and "b/" prefixes and with renames shown as "old => new".
+func diffStatName(f FileDiff) string {
+	oldName, newName := patchPathKey(f.OldName), patchPathKey(f.NewName)
+	if (f.Rename || f.Copy) && oldName != newName {
+		return oldName + " => " + newName
+	}
+	return newName
+}
+
+// NumStat renders the patch set like `git diff --numstat`: one line per file
+// holding the number of added lines, the number of removed lines and the
+// path, separated by tabs. Binary files show "-" for both counts.
+//
+// Example:
+//
+//	fmt.Print(difflib.NumStat(ps))
+//	// 1	1	README.md
+//	// 7	6	main.go
+func NumStat(p *PatchSet) string {
+	var b strings.Builder
+	for _, f := range p.Files {
+		if f.Binary {
+			fmt.Fprintf(&b, "-\t-\t%s\n", diffStatName(f))
+			continue
+		}
+		s := f.Stats()
+		fmt.Fprintf(&b, "%d\t%d\t%s\n", s.Added, s.Removed, diffStatName(f))
+	}
+	return b.String()
+}
+
+// ShortStat returns the one-line summary of the patch set printed by
+// `git diff --shortstat`, without a trailing newline. Counts that are zero
+// are omitted unless nothing was inserted or deleted.
+//
+// Example:
+//
+//	fmt.Println(difflib.ShortStat(ps)) // 3 files changed, 10 insertions(+)
+func ShortStat(p *PatchSet) string {
+	total := p.Stats()
+	adds, dels := total.Added, total.Removed
+	plural := func(n int, one, many string) string {
+		if n == 1 {
+			return fmt.Sprintf("%d %s", n, one)
+		}
+		return fmt.Sprintf("%d %s", n, many)
+	}
+	out := plural(len(p.Files), "file changed", "files changed")
+	if adds > 0 || adds+dels == 0 {
+		out += ", " + plural(adds, "insertion(+)", "insertions(+)")
+	}
+	if dels > 0 || adds+dels == 0 {
+		out += ", " + plural(dels, "deletion(-)", "deletions(-)")
+	}
+	return out
+}
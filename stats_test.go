@@ -0,0 +1,144 @@
+package difflib_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	difflib "github.com/njchilds90/go-difflib"
+)
+
+func TestDiffResultStats(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want difflib.DiffStats
+	}{
+		{"equal", "a\nb\n", "a\nb\n", difflib.DiffStats{}},
+		{"insert", "a\n", "a\nb\nc\n", difflib.DiffStats{Added: 2}},
+		{"delete", "a\nb\n", "a\n", difflib.DiffStats{Removed: 1}},
+		{"replace", "a\nb\nc\n", "a\nB\nC\nD\n", difflib.DiffStats{Added: 3, Removed: 2, Changed: 2}},
+		{"two runs", "1\n2\n3\n4\n5\n", "x\n2\n3\n4\ny\nz\n", difflib.DiffStats{Added: 3, Removed: 2, Changed: 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := difflib.UnifiedDiff(difflib.DiffInput{A: difflib.SplitLines(tt.a), B: difflib.SplitLines(tt.b)})
+			if got := d.Stats(); got != tt.want {
+				t.Errorf("Stats() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffResultQuality(t *testing.T) {
+	tests := []struct {
+		name        string
+		a, b        string
+		context     int
+		distance    int
+		hunks       int
+		compactness float64
+	}{
+		{"equal", "a\nb\n", "a\nb\n", 0, 0, 0, 1},
+		{"one block", "a\nb\nc\n", "a\nB\nC\nD\n", 0, 5, 1, 1},
+		{"two blocks", "1\n2\n3\n4\n5\n", "x\n2\n3\n4\ny\nz\n", 0, 5, 1, 0.8},
+		{"two hunks", "1\n2\n3\n4\n5\n", "x\n2\n3\n4\ny\nz\n", difflib.NoContext, 5, 2, 0.8},
+		{"scattered", "1\n2\n3\n4\n5\n", "1\n3\n5\n", 0, 2, 1, 0.5},
+	}
+	for _, tt := range tests {
+		d := difflib.UnifiedDiff(difflib.DiffInput{A: difflib.SplitLines(tt.a), B: difflib.SplitLines(tt.b), Context: tt.context})
+		if got := d.EditDistance(); got != tt.distance {
+			t.Errorf("%s: EditDistance() = %d, want %d", tt.name, got, tt.distance)
+		}
+		if got := d.HunkCount(); got != tt.hunks {
+			t.Errorf("%s: HunkCount() = %d, want %d", tt.name, got, tt.hunks)
+		}
+		if got := d.Compactness(); got != tt.compactness {
+			t.Errorf("%s: Compactness() = %v, want %v", tt.name, got, tt.compactness)
+		}
+	}
+}
+
+func TestDiffStat(t *testing.T) {
+	ps, err := difflib.ParsePatchSet(gitPatch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := ` main.go                    | 2 +-
+ new.txt                    | 2 ++
+ old.txt                    | 1 -
+ docs/a b.md => docs/c d.md | 0
+ from.txt => to.txt         | 2 +-
+ 5 files changed, 4 insertions(+), 3 deletions(-)
+`
+	if got := difflib.DiffStat(ps); got != want {
+		t.Errorf("DiffStat() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestDiffStatScaling(t *testing.T) {
+	var b strings.Builder
+	b.WriteString("--- a/big.txt\n+++ b/big.txt\n@@ -0,0 +1,200 @@\n")
+	for i := 0; i < 200; i++ {
+		fmt.Fprintf(&b, "+line %d\n", i)
+	}
+	ps, err := difflib.ParsePatchSet(b.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := strings.SplitN(difflib.DiffStat(ps), "\n", 2)[0]
+	if len(first) > 80 {
+		t.Errorf("line is %d columns, want at most 80: %q", len(first), first)
+	}
+	if !strings.Contains(first, "| 200 +++") || strings.Contains(first, "-") {
+		t.Errorf("unexpected line %q", first)
+	}
+}
+
+func ExampleDiffStat() {
+	ps, _ := difflib.ParsePatchSet("--- a/greeting.txt\n+++ b/greeting.txt\n@@ -1,2 +1,2 @@\n-hello\n+hi\n world\n")
+	fmt.Print(difflib.DiffStat(ps))
+	// Output:
+	//  greeting.txt | 2 +-
+	//  1 file changed, 1 insertion(+), 1 deletion(-)
+}
+
+func TestNumStat(t *testing.T) {
+	ps, err := difflib.ParsePatchSet(gitPatch + "diff --git a/img.png b/img.png\nindex 1111111..2222222 100644\nBinary files a/img.png and b/img.png differ\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "1\t1\tmain.go\n" +
+		"2\t0\tnew.txt\n" +
+		"0\t1\told.txt\n" +
+		"0\t0\tdocs/a b.md => docs/c d.md\n" +
+		"1\t1\tfrom.txt => to.txt\n" +
+		"-\t-\timg.png\n"
+	if got := difflib.NumStat(ps); got != want {
+		t.Errorf("NumStat() =\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestShortStat(t *testing.T) {
+	tests := []struct {
+		name  string
+		patch string
+		want  string
+	}{
+		{"empty", "", "0 files changed, 0 insertions(+), 0 deletions(-)"},
+		{"insertions only", "--- a/x\n+++ b/x\n@@ -1 +1,2 @@\n x\n+y\n", "1 file changed, 1 insertion(+)"},
+		{"deletions only", "--- a/x\n+++ b/x\n@@ -1,3 +1 @@\n x\n-y\n-z\n", "1 file changed, 2 deletions(-)"},
+		{"both", gitPatch, "5 files changed, 4 insertions(+), 3 deletions(-)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ps, err := difflib.ParsePatchSet(tt.patch)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := difflib.ShortStat(ps); got != tt.want {
+				t.Errorf("ShortStat() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,232 @@
+package difflib
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/maphash"
+	"io"
+	"os"
+)
+
+// StreamDiffOptions controls UnifiedDiffReaders.
+type StreamDiffOptions struct {
+	// FromFile and ToFile are the labels for the two inputs.
+	FromFile, ToFile string
+	// FromDate and ToDate, if set, follow the labels after a tab. See
+	// DiffInput.FromDate.
+	FromDate, ToDate string
+	// Context is the number of unchanged lines to include around each change.
+	// Defaults to 3 if zero; use NoContext for none.
+	Context int
+	// TempDir is the directory for spooling inputs that cannot be re-read.
+	// Defaults to os.TempDir().
+	TempDir string
+}
+
+// UnifiedDiffReaders writes the unified diff of the contents of a and b to w
+// and reports whether they differ. The output is formatted like UnifiedDiff's
+// and usually identical to it, but memory use does not depend on the length
+// of the lines:
+// each line is kept only as a 64-bit hash and a file offset, and hunk lines
+// are read back when the diff is written. Inputs that implement io.ReaderAt
+// and io.Seeker, such as *os.File, are re-read in place; other inputs are
+// spooled to a temporary file. Equal prefixes and suffixes are skipped
+// before matching, so long files with few changes diff quickly; this can
+// make the hunks differ from UnifiedDiff's when lines repeat.
+//
+// Lines are compared by hash; a hash collision, while astronomically
+// unlikely, could make two different lines compare equal.
+//
+// Example:
+//
+//	a, _ := os.Open("yesterday.log")
+//	b, _ := os.Open("today.log")
+//	changed, err := difflib.UnifiedDiffReaders(os.Stdout, a, b, difflib.StreamDiffOptions{
+//	    FromFile: "yesterday.log",
+//	    ToFile:   "today.log",
+//	})
+func UnifiedDiffReaders(w io.Writer, a, b io.Reader, opts StreamDiffOptions) (bool, error) {
+	seed := maphash.MakeSeed()
+	sa, err := newLineSource(a, seed, opts.TempDir)
+	if err != nil {
+		return false, err
+	}
+	defer sa.close()
+	sb, err := newLineSource(b, seed, opts.TempDir)
+	if err != nil {
+		return false, err
+	}
+	defer sb.close()
+
+	ctx := contextLines(opts.Context)
+	groups := groupOpcodes(streamOpCodes(sa.hashes, sb.hashes), ctx)
+	if len(groups) == 0 {
+		return false, nil
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "--- %s\n+++ %s\n", fileLabel(opts.FromFile, opts.FromDate), fileLabel(opts.ToFile, opts.ToDate))
+	for _, group := range groups {
+		h := groupHunkHeader(group)
+		fmt.Fprintf(bw, "@@ -%d,%d +%d,%d @@\n", h.OldStart, h.OldLines, h.NewStart, h.NewLines)
+		for _, op := range group {
+			if op.Tag == OpEqual {
+				err = sa.copyLines(bw, ' ', op.I1, op.I2)
+			} else if err = sa.copyLines(bw, '-', op.I1, op.I2); err == nil {
+				err = sb.copyLines(bw, '+', op.J1, op.J2)
+			}
+			if err != nil {
+				return true, err
+			}
+		}
+	}
+	return true, bw.Flush()
+}
+
+// streamOpCodes diffs two sequences of line hashes. The common prefix and
+// suffix are trimmed before the matcher runs on the rest.
+func streamOpCodes(a, b []uint64) []OpCode {
+	pre := 0
+	for pre < len(a) && pre < len(b) && a[pre] == b[pre] {
+		pre++
+	}
+	suf := 0
+	for suf < len(a)-pre && suf < len(b)-pre && a[len(a)-1-suf] == b[len(b)-1-suf] {
+		suf++
+	}
+	key := func(h uint64) string {
+		var buf [8]byte
+		binary.LittleEndian.PutUint64(buf[:], h)
+		return string(buf[:])
+	}
+	ka := make([]string, 0, len(a)-pre-suf)
+	for _, h := range a[pre : len(a)-suf] {
+		ka = append(ka, key(h))
+	}
+	kb := make([]string, 0, len(b)-pre-suf)
+	for _, h := range b[pre : len(b)-suf] {
+		kb = append(kb, key(h))
+	}
+
+	var codes []OpCode
+	add := func(c OpCode) {
+		if n := len(codes); n > 0 && c.Tag == OpEqual && codes[n-1].Tag == OpEqual {
+			codes[n-1].I2, codes[n-1].J2 = c.I2, c.J2
+			return
+		}
+		codes = append(codes, c)
+	}
+	if pre > 0 {
+		add(OpCode{OpEqual, 0, pre, 0, pre})
+	}
+	for _, c := range NewMatcher(ka, kb).GetOpCodes() {
+		add(OpCode{c.Tag, c.I1 + pre, c.I2 + pre, c.J1 + pre, c.J2 + pre})
+	}
+	if suf > 0 {
+		add(OpCode{OpEqual, len(a) - suf, len(a), len(b) - suf, len(b)})
+	}
+	return codes
+}
+
+// lineSource holds the hash and offset of every line of one input, and a
+// way to read the lines back.
+type lineSource struct {
+	r io.ReaderAt
+	// offsets[i] is the offset of line i; the final entry is the end offset.
+	offsets []int64
+	hashes  []uint64
+	tmp     *os.File
+	buf     []byte
+}
+
+func newLineSource(r io.Reader, seed maphash.Seed, tempDir string) (*lineSource, error) {
+	s := &lineSource{}
+	var base int64
+	var src io.Reader = r
+	ra, isReaderAt := r.(io.ReaderAt)
+	seeker, isSeeker := r.(io.Seeker)
+	if isReaderAt && isSeeker {
+		pos, err := seeker.Seek(0, io.SeekCurrent)
+		if err == nil {
+			s.r, base = ra, pos
+		}
+	}
+	if s.r == nil {
+		tmp, err := os.CreateTemp(tempDir, "difflib-*")
+		if err != nil {
+			return nil, fmt.Errorf("difflib: spooling input: %w", err)
+		}
+		s.tmp, s.r = tmp, tmp
+		src = io.TeeReader(r, tmp)
+	}
+
+	br := bufio.NewReaderSize(src, 64*1024)
+	var h maphash.Hash
+	h.SetSeed(seed)
+	off := base
+	lineLen := 0
+	for {
+		chunk, err := br.ReadSlice('\n')
+		h.Write(chunk)
+		lineLen += len(chunk)
+		if errors.Is(err, bufio.ErrBufferFull) {
+			continue
+		}
+		if lineLen > 0 {
+			s.offsets = append(s.offsets, off)
+			s.hashes = append(s.hashes, h.Sum64())
+			off += int64(lineLen)
+		}
+		h.Reset()
+		lineLen = 0
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			s.close()
+			return nil, fmt.Errorf("difflib: reading input: %w", err)
+		}
+	}
+	s.offsets = append(s.offsets, off)
+	return s, nil
+}
+
+// copyLines writes lines [i, j) to w, each preceded by prefix. A final line
+// without a newline is followed by a "\ No newline at end of file" marker.
+func (s *lineSource) copyLines(w *bufio.Writer, prefix byte, i, j int) error {
+	for ; i < j; i++ {
+		buf, err := s.readLine(i)
+		if err != nil {
+			return err
+		}
+		w.WriteByte(prefix)
+		w.Write(buf)
+		if n := len(buf); n == 0 || buf[n-1] != '\n' {
+			w.WriteString("\n" + noNewlineMarker)
+		}
+	}
+	return nil
+}
+
+// readLine reads line i back from the input. The result is valid until the
+// next call.
+func (s *lineSource) readLine(i int) ([]byte, error) {
+	n := int(s.offsets[i+1] - s.offsets[i])
+	if cap(s.buf) < n {
+		s.buf = make([]byte, n)
+	}
+	buf := s.buf[:n]
+	if m, err := s.r.ReadAt(buf, s.offsets[i]); m < n {
+		return nil, fmt.Errorf("difflib: re-reading input: %w", err)
+	}
+	return buf, nil
+}
+
+func (s *lineSource) close() {
+	if s.tmp != nil {
+		s.tmp.Close()
+		os.Remove(s.tmp.Name())
+	}
+}
@@ -0,0 +1,111 @@
+package difflib_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	difflib "github.com/njchilds90/go-difflib"
+)
+
+func TestUnifiedDiffReaders(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+	}{
+		{"equal", "a\nb\n", "a\nb\n"},
+		{"empty", "", ""},
+		{"from empty", "", "a\nb\n"},
+		{"to empty", "a\nb\n", ""},
+		{"replace", "a\nb\nc\n", "a\nB\nc\n"},
+		{"no trailing newline", "a\nb", "a\nc"},
+		{"prefix and suffix", "1\n2\n3\n4\n5\n6\n7\n8\n9\n", "1\n2\n3\n4\nX\n6\n7\n8\n9\n"},
+		{"two hunks", difflib.JoinLines(numbered(30)), strings.Replace(strings.Replace(difflib.JoinLines(numbered(30)), "3\n", "three\n", 1), "27\n", "", 1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := difflib.UnifiedDiff(difflib.DiffInput{
+				A: difflib.SplitLines(tt.a), B: difflib.SplitLines(tt.b), FromFile: "a", ToFile: "b",
+			}).String()
+			var got bytes.Buffer
+			changed, err := difflib.UnifiedDiffReaders(&got, strings.NewReader(tt.a), io.MultiReader(strings.NewReader(tt.b)),
+				difflib.StreamDiffOptions{FromFile: "a", ToFile: "b", TempDir: t.TempDir()})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.String() != want {
+				t.Errorf("got\n%s\nwant\n%s", got.String(), want)
+			}
+			if changed != (want != "") {
+				t.Errorf("changed = %v", changed)
+			}
+		})
+	}
+}
+
+func TestUnifiedDiffReadersRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for iter := 0; iter < 100; iter++ {
+		a := randomLines(rng, rng.Intn(40))
+		b := randomLines(rng, rng.Intn(40))
+		var patch bytes.Buffer
+		if _, err := difflib.UnifiedDiffReaders(&patch, strings.NewReader(difflib.JoinLines(a)),
+			io.MultiReader(strings.NewReader(difflib.JoinLines(b))), difflib.StreamDiffOptions{TempDir: t.TempDir()}); err != nil {
+			t.Fatal(err)
+		}
+		got, err := difflib.ApplyPatch(a, patch.String())
+		if err != nil {
+			t.Fatalf("iteration %d: %v\n%s", iter, err, patch.String())
+		}
+		if difflib.JoinLines(got) != difflib.JoinLines(b) {
+			t.Fatalf("iteration %d: patch does not produce b:\n%s", iter, patch.String())
+		}
+	}
+}
+
+func randomLines(rng *rand.Rand, n int) []string {
+	lines := make([]string, n)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("%c\n", 'a'+rng.Intn(5))
+	}
+	return lines
+}
+
+func TestUnifiedDiffReadersFiles(t *testing.T) {
+	dir := t.TempDir()
+	long := strings.Repeat("x", 200000) // longer than the read buffer
+	pa, pb := filepath.Join(dir, "a"), filepath.Join(dir, "b")
+	if err := os.WriteFile(pa, []byte("head\n"+long+"\ntail\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(pb, []byte("head\n"+long+"!\ntail\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	fa, err := os.Open(pa)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fa.Close()
+	fb, err := os.Open(pb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fb.Close()
+	// Start b part-way through to check offsets are relative to the reader position.
+	if _, err := fb.Seek(5, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	var got bytes.Buffer
+	if _, err := difflib.UnifiedDiffReaders(&got, fa, fb, difflib.StreamDiffOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	want := "--- \n+++ \n@@ -1,3 +1,2 @@\n-head\n-" + long + "\n+" + long + "!\n tail\n"
+	if got.String() != want {
+		t.Errorf("unexpected diff of length %d, want %d", got.Len(), len(want))
+	}
+}
@@ -0,0 +1,174 @@
+package difflib
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ValidateLines checks that lines is a sequence of lines as SplitLines
+// returns them: no line is empty, every line but the last ends with "\n",
+// and no line contains "\n" before its end. Inputs that break these rules
+// still diff, but their diffs do not describe the text they were made from.
+//
+// Example:
+//
+//	if err := difflib.ValidateLines(lines); err != nil {
+//	    return err // difflib: line 3 has no newline but is not the last line
+//	}
+func ValidateLines(lines []string) error {
+	if msg := checkLines(lines); msg != "" {
+		return fmt.Errorf("difflib: %s", msg)
+	}
+	return nil
+}
+
+// checkLines returns the first problem ValidateLines finds in lines, or "".
+func checkLines(lines []string) string {
+	for i, l := range lines {
+		switch {
+		case l == "":
+			return fmt.Sprintf("line %d is empty", i+1)
+		case strings.Contains(l[:len(l)-1], "\n"):
+			return fmt.Sprintf("line %d contains a newline before its end", i+1)
+		case i < len(lines)-1 && !strings.HasSuffix(l, "\n"):
+			return fmt.Sprintf("line %d has no newline but is not the last line", i+1)
+		}
+	}
+	return ""
+}
+
+// UnifiedDiffStrict is UnifiedDiff that returns an error instead of a diff
+// if input.A or input.B fails ValidateLines, for pipelines where bad input
+// must be caught rather than diffed.
+//
+// Example:
+//
+//	d, err := difflib.UnifiedDiffStrict(difflib.DiffInput{A: old, B: new})
+//	if err != nil {
+//	    return err // difflib: B: line 2 contains a newline before its end
+//	}
+func UnifiedDiffStrict(input DiffInput) (DiffResult, error) {
+	if msg := checkLines(input.A); msg != "" {
+		return DiffResult{}, fmt.Errorf("difflib: A: %s", msg)
+	}
+	if msg := checkLines(input.B); msg != "" {
+		return DiffResult{}, fmt.Errorf("difflib: B: %s", msg)
+	}
+	return UnifiedDiff(input), nil
+}
+
+// Validate checks that the hunks of d are consistent: every line has a ' ',
+// '+' or '-' prefix and ends with "\n" unless it is the last line of its
+// side, the counts in each header match its lines, each hunk changes
+// something, and hunks are in order, do not overlap and have new start
+// lines that agree with the preceding hunks. It returns the first problem
+// found. A DiffResult built by hand or edited should be validated before
+// it is written or applied.
+//
+// Example:
+//
+//	d.Hunks = append(d.Hunks, extra)
+//	if err := d.Validate(); err != nil {
+//	    return err
+//	}
+func (d DiffResult) Validate() error {
+	oldEnd, delta := 0, 0
+	oldDone, newDone := false, false
+	for n, h := range d.Hunks {
+		fail := func(format string, args ...any) error {
+			return fmt.Errorf("difflib: hunk #%d: %s", n+1, fmt.Sprintf(format, args...))
+		}
+		if h.OldStart < 0 || h.NewStart < 0 || h.OldLines < 0 || h.NewLines < 0 {
+			return fail("negative range in header %s", h.header())
+		}
+		if (h.OldStart == 0 && h.OldLines > 0) || (h.NewStart == 0 && h.NewLines > 0) {
+			return fail("start 0 with lines in header %s", h.header())
+		}
+		anchor, newAnchor := hunkAnchor(h), newAnchor(h)
+		if anchor < oldEnd {
+			return fail("starts at old line %d, before the previous hunk ends at line %d", h.OldStart, oldEnd)
+		}
+		if newAnchor-anchor != delta {
+			return fail("new start %d is inconsistent with the preceding hunks (expected %d)",
+				h.NewStart, h.NewStart-(newAnchor-anchor-delta))
+		}
+		oldEnd, delta = anchor+h.OldLines, delta+h.NewLines-h.OldLines
+
+		oldCount, newCount, changed := 0, 0, false
+		for i, l := range h.Lines {
+			if l == "" || !strings.ContainsRune(" +-", rune(l[0])) {
+				return fail("line %d has no ' ', '+' or '-' prefix", i+1)
+			}
+			if strings.Contains(l[:len(l)-1], "\n") {
+				return fail("line %d contains a newline before its end", i+1)
+			}
+			inOld, inNew := l[0] != '+', l[0] != '-'
+			if (inOld && oldDone) || (inNew && newDone) {
+				return fail("line %d follows a line without a newline", i+1)
+			}
+			if !strings.HasSuffix(l, "\n") {
+				oldDone, newDone = oldDone || inOld, newDone || inNew
+			}
+			if inOld {
+				oldCount++
+			}
+			if inNew {
+				newCount++
+			}
+			changed = changed || l[0] != ' '
+		}
+		if oldCount != h.OldLines || newCount != h.NewLines {
+			return fail("header %s does not match its %d old and %d new lines", h.header(), oldCount, newCount)
+		}
+		if !changed {
+			return fail("changes no lines")
+		}
+	}
+	return nil
+}
+
+// ApplyPatchStrict is ApplyPatch for pipelines where bad input must be
+// caught. Besides the checks ApplyPatch makes, it requires that a passes
+// ValidateLines, that patch is a well-formed diff of a single file with
+// ---/+++ headers and at least one hunk (see ValidatePatch), and that every
+// hunk applies exactly at the position recorded in its header.
+//
+// Example:
+//
+//	patched, err := difflib.ApplyPatchStrict(original, patch)
+//	if err != nil {
+//	    return fmt.Errorf("rejecting patch: %w", err)
+//	}
+func ApplyPatchStrict(a []string, patch string) ([]string, error) {
+	if msg := checkLines(a); msg != "" {
+		return nil, fmt.Errorf("difflib: input: %s", msg)
+	}
+	if errs := ValidatePatch(patch); len(errs) > 0 {
+		joined := make([]error, len(errs))
+		for i, e := range errs {
+			joined[i] = fmt.Errorf("difflib: patch:%w", e)
+		}
+		return nil, errors.Join(joined...)
+	}
+	ps, err := ParsePatchSet(patch)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case len(ps.Files) == 0 || len(ps.Files[0].Hunks) == 0:
+		return nil, errors.New("difflib: patch has no hunks")
+	case len(ps.Files) > 1:
+		return nil, fmt.Errorf("difflib: patch changes %d files, not one", len(ps.Files))
+	}
+	result, hunks, err := ApplyPatchWithOptions(a, patch, ApplyOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range hunks {
+		if r.Offset != 0 {
+			return nil, fmt.Errorf("difflib: hunk #%d applies at line %d, not where its header says", r.Hunk, r.Line)
+		}
+	}
+	return result, nil
+}
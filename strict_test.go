@@ -0,0 +1,116 @@
+package difflib_test
+
+import (
+	"strings"
+	"testing"
+
+	difflib "github.com/njchilds90/go-difflib"
+)
+
+func TestValidateLines(t *testing.T) {
+	tests := []struct {
+		lines []string
+		want  string
+	}{
+		{nil, ""},
+		{[]string{"a\n", "b"}, ""},
+		{[]string{"a\r\n", "b\r\n"}, ""},
+		{[]string{"a", "b\n"}, "line 1 has no newline but is not the last line"},
+		{[]string{"a\n", "", "c\n"}, "line 2 is empty"},
+		{[]string{"a\nb\n"}, "line 1 contains a newline before its end"},
+	}
+	for _, tt := range tests {
+		err := difflib.ValidateLines(tt.lines)
+		if tt.want == "" {
+			if err != nil {
+				t.Errorf("ValidateLines(%q) = %v, want nil", tt.lines, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("ValidateLines(%q) = %v, want %q", tt.lines, err, tt.want)
+		}
+	}
+}
+
+func TestUnifiedDiffStrict(t *testing.T) {
+	a := difflib.SplitLines("one\ntwo\n")
+	d, err := difflib.UnifiedDiffStrict(difflib.DiffInput{A: a, B: difflib.SplitLines("one\n2\n")})
+	if err != nil || len(d.Hunks) != 1 {
+		t.Fatalf("UnifiedDiffStrict = %v, %v", d, err)
+	}
+	_, err = difflib.UnifiedDiffStrict(difflib.DiffInput{A: a, B: []string{"one\n2"}})
+	if err == nil || err.Error() != "difflib: B: line 1 contains a newline before its end" {
+		t.Errorf("UnifiedDiffStrict with a joined line: %v", err)
+	}
+}
+
+func TestDiffResultValidate(t *testing.T) {
+	a, b := numbered(20), numbered(20)
+	b[2], b[15] = "three\n", "sixteen\n"
+	good := difflib.UnifiedDiff(difflib.DiffInput{A: a, B: b})
+	if err := good.Validate(); err != nil {
+		t.Fatalf("Validate of a generated diff: %v", err)
+	}
+	tests := []struct {
+		name string
+		edit func(d *difflib.DiffResult)
+		want string
+	}{
+		{"count", func(d *difflib.DiffResult) { d.Hunks[0].OldLines++ }, "hunk #1: header @@ -1,7 +1,6 @@ does not match"},
+		{"prefix", func(d *difflib.DiffResult) { d.Hunks[1].Lines[0] = "x\n" }, "hunk #2: line 1 has no ' ', '+' or '-' prefix"},
+		{"order", func(d *difflib.DiffResult) { d.Hunks[0], d.Hunks[1] = d.Hunks[1], d.Hunks[0] }, "hunk #2: starts at old line 1"},
+		{"new start", func(d *difflib.DiffResult) { d.Hunks[1].NewStart++ }, "hunk #2: new start 14 is inconsistent"},
+		{"no change", func(d *difflib.DiffResult) {
+			d.Hunks = []difflib.Hunk{{OldStart: 1, OldLines: 1, NewStart: 1, NewLines: 1, Lines: []string{" line 1\n"}}}
+		}, "hunk #1: changes no lines"},
+		{"newline", func(d *difflib.DiffResult) { d.Hunks[0].Lines[0] = " line 1" }, "hunk #1: line 2 follows a line without a newline"},
+	}
+	for _, tt := range tests {
+		d := good
+		d.Hunks = make([]difflib.Hunk, len(good.Hunks))
+		for i, h := range good.Hunks {
+			h.Lines = append([]string(nil), h.Lines...)
+			d.Hunks[i] = h
+		}
+		tt.edit(&d)
+		if err := d.Validate(); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: Validate = %v, want %q", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestApplyPatchStrict(t *testing.T) {
+	a := numbered(10)
+	b := append([]string(nil), a...)
+	b[4] = "five\n"
+	patch := difflib.UnifiedDiff(difflib.DiffInput{A: a, B: b, FromFile: "a/x", ToFile: "b/x"}).String()
+	if got, err := difflib.ApplyPatchStrict(a, patch); err != nil || difflib.JoinLines(got) != difflib.JoinLines(b) {
+		t.Fatalf("ApplyPatchStrict = %q, %v", difflib.JoinLines(got), err)
+	}
+	_, hunk, _ := strings.Cut(patch, "@@")
+	shifted := append(difflib.SplitLines("new 1\nnew 2\n"), a...)
+	tests := []struct {
+		name  string
+		a     []string
+		patch string
+		want  string
+	}{
+		{"empty", a, "", "patch has no hunks"},
+		{"garbage", a, "not a patch\n", "patch has no hunks"},
+		{"no header", a, "@@" + hunk, "hunk without file header"},
+		{"bad input", []string{"line 1", "line 2\n"}, patch, "input: line 1 has no newline"},
+		{"two files", a, patch + strings.ReplaceAll(patch, "/x", "/y"), "patch changes 2 files"},
+		{"offset", shifted, patch, "hunk #1 applies at line 4"},
+	}
+	for _, tt := range tests {
+		if _, err := difflib.ApplyPatchStrict(tt.a, tt.patch); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: ApplyPatchStrict error = %v, want %q", tt.name, err, tt.want)
+		}
+		if tt.name == "offset" {
+			if _, err := difflib.ApplyPatch(tt.a, tt.patch); err != nil {
+				t.Errorf("ApplyPatch rejects the offset patch: %v", err)
+			}
+		}
+	}
+}
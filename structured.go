@@ -0,0 +1,283 @@
+package difflib
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// StructuredVersion is the version of the structured diff schema written by
+// EncodeStructured. DecodeStructured rejects documents with a newer version.
+const StructuredVersion = 1
+
+// StructuredDiff is a self-describing JSON representation of a multi-file
+// diff, meant for web UIs and tools in other languages that should not have
+// to parse unified diff text. Every line carries its type and its line
+// numbers in the old and new file, so a consumer can render it directly.
+//
+// The schema, with all line numbers 1-based:
+//
+//	{
+//	  "version": 1,
+//	  "files": [{
+//	    "old_path": "a/main.go",      // as in the patch, never "/dev/null"
+//	    "new_path": "b/main.go",
+//	    "status": "modified",         // added, deleted, modified, renamed or copied
+//	    "similarity": 90,             // renames and copies only
+//	    "old_mode": "100644",         // optional
+//	    "new_mode": "100644",         // optional
+//	    "index": "83db48f..bf269f4",  // optional
+//	    "binary": true,               // only when content is not shown
+//	    "git": true,                  // written with git extended headers
+//	    "hunks": [{
+//	      "old_start": 1, "old_lines": 3, "new_start": 1, "new_lines": 3,
+//	      "lines": [
+//	        {"type": "context", "old_line": 1, "new_line": 1, "text": "package main"},
+//	        {"type": "delete", "old_line": 2, "text": "var x = 1"},
+//	        {"type": "add", "new_line": 2, "text": "var x = 2", "no_newline": true}
+//	      ]
+//	    }]
+//	  }]
+//	}
+//
+// Line text excludes the trailing newline; no_newline marks the last line
+// of a file that has none. Optional fields are omitted when empty.
+type StructuredDiff struct {
+	Version int              `json:"version"`
+	Files   []StructuredFile `json:"files"`
+}
+
+// StructuredFile is one file of a StructuredDiff.
+type StructuredFile struct {
+	OldPath    string           `json:"old_path"`
+	NewPath    string           `json:"new_path"`
+	Status     string           `json:"status"`
+	Similarity int              `json:"similarity,omitempty"`
+	OldMode    string           `json:"old_mode,omitempty"`
+	NewMode    string           `json:"new_mode,omitempty"`
+	Index      string           `json:"index,omitempty"`
+	Binary     bool             `json:"binary,omitempty"`
+	Git        bool             `json:"git,omitempty"`
+	Hunks      []StructuredHunk `json:"hunks"`
+}
+
+// StructuredHunk is one hunk of a StructuredFile. The ranges follow the
+// unified diff header conventions.
+type StructuredHunk struct {
+	OldStart int              `json:"old_start"`
+	OldLines int              `json:"old_lines"`
+	NewStart int              `json:"new_start"`
+	NewLines int              `json:"new_lines"`
+	Lines    []StructuredLine `json:"lines"`
+}
+
+// StructuredLine is one typed line of a StructuredHunk. Type is "context",
+// "add" or "delete"; OldLine is zero for added lines and NewLine is zero for
+// deleted lines.
+type StructuredLine struct {
+	Type      string `json:"type"`
+	OldLine   int    `json:"old_line,omitempty"`
+	NewLine   int    `json:"new_line,omitempty"`
+	Text      string `json:"text"`
+	NoNewline bool   `json:"no_newline,omitempty"`
+}
+
+// File statuses used in StructuredFile.Status.
+const (
+	StatusAdded    = "added"
+	StatusDeleted  = "deleted"
+	StatusModified = "modified"
+	StatusRenamed  = "renamed"
+	StatusCopied   = "copied"
+)
+
+// Line types used in StructuredLine.Type.
+const (
+	LineContext = "context"
+	LineAdd     = "add"
+	LineDelete  = "delete"
+)
+
+// ToStructured converts a patch set to the structured diff schema.
+//
+// Example:
+//
+//	s := difflib.ToStructured(ps)
+//	for _, l := range s.Files[0].Hunks[0].Lines {
+//	    fmt.Println(l.Type, l.OldLine, l.NewLine, l.Text)
+//	}
+func ToStructured(p *PatchSet) StructuredDiff {
+	s := StructuredDiff{Version: StructuredVersion, Files: []StructuredFile{}}
+	for _, f := range p.Files {
+		sf := StructuredFile{
+			OldPath: f.OldName,
+			NewPath: f.NewName,
+			OldMode: f.OldMode,
+			NewMode: f.NewMode,
+			Index:   f.Index,
+			Binary:  f.Binary,
+			Git:     f.Git,
+			Hunks:   []StructuredHunk{},
+		}
+		switch {
+		case f.NewFile:
+			sf.Status = StatusAdded
+		case f.DeletedFile:
+			sf.Status = StatusDeleted
+		case f.Rename:
+			sf.Status, sf.Similarity = StatusRenamed, f.Similarity
+		case f.Copy:
+			sf.Status, sf.Similarity = StatusCopied, f.Similarity
+		default:
+			sf.Status = StatusModified
+		}
+		for _, h := range f.Hunks {
+			sf.Hunks = append(sf.Hunks, structuredHunk(h))
+		}
+		s.Files = append(s.Files, sf)
+	}
+	return s
+}
+
+// structuredHunk numbers and types the lines of h.
+func structuredHunk(h Hunk) StructuredHunk {
+	sh := StructuredHunk{
+		OldStart: h.OldStart,
+		OldLines: h.OldLines,
+		NewStart: h.NewStart,
+		NewLines: h.NewLines,
+		Lines:    []StructuredLine{},
+	}
+	oldLine, newLine := hunkAnchor(h)+1, newAnchor(h)+1
+	for _, l := range h.Lines {
+		if l == "" {
+			continue
+		}
+		text, ok := strings.CutSuffix(l[1:], "\n")
+		sl := StructuredLine{Text: text, NoNewline: !ok}
+		switch l[0] {
+		case '+':
+			sl.Type, sl.NewLine = LineAdd, newLine
+			newLine++
+		case '-':
+			sl.Type, sl.OldLine = LineDelete, oldLine
+			oldLine++
+		default:
+			sl.Type, sl.OldLine, sl.NewLine = LineContext, oldLine, newLine
+			oldLine++
+			newLine++
+		}
+		sh.Lines = append(sh.Lines, sl)
+	}
+	return sh
+}
+
+// PatchSet converts the structured diff back into a patch set. It returns an
+// error for an unknown status or line type, or for a hunk whose lines do not
+// match its ranges. Line numbers are not checked; they are derived from the
+// hunk ranges when rendering.
+//
+// Example:
+//
+//	var s difflib.StructuredDiff
+//	json.Unmarshal(data, &s)
+//	ps, err := s.PatchSet()
+func (s StructuredDiff) PatchSet() (*PatchSet, error) {
+	if s.Version > StructuredVersion {
+		return nil, fmt.Errorf("difflib: unsupported structured diff version %d", s.Version)
+	}
+	ps := &PatchSet{}
+	for n, sf := range s.Files {
+		f := FileDiff{
+			DiffResult: DiffResult{FromFile: sf.OldPath, ToFile: sf.NewPath},
+			OldName:    sf.OldPath,
+			NewName:    sf.NewPath,
+			Git:        sf.Git,
+			OldMode:    sf.OldMode,
+			NewMode:    sf.NewMode,
+			Index:      sf.Index,
+			Binary:     sf.Binary,
+		}
+		switch sf.Status {
+		case StatusAdded:
+			f.NewFile, f.FromFile = true, devNull
+		case StatusDeleted:
+			f.DeletedFile, f.ToFile = true, devNull
+		case StatusRenamed:
+			f.Rename, f.Similarity = true, sf.Similarity
+		case StatusCopied:
+			f.Copy, f.Similarity = true, sf.Similarity
+		case StatusModified, "":
+		default:
+			return nil, fmt.Errorf("difflib: file #%d: unknown status %q", n+1, sf.Status)
+		}
+		for k, sh := range sf.Hunks {
+			h, err := sh.hunk()
+			if err != nil {
+				return nil, fmt.Errorf("difflib: file #%d hunk #%d: %w", n+1, k+1, err)
+			}
+			f.Hunks = append(f.Hunks, h)
+		}
+		ps.Files = append(ps.Files, f)
+	}
+	return ps, nil
+}
+
+// hunk converts sh back into a Hunk, checking the lines against the ranges.
+func (sh StructuredHunk) hunk() (Hunk, error) {
+	h := Hunk{OldStart: sh.OldStart, OldLines: sh.OldLines, NewStart: sh.NewStart, NewLines: sh.NewLines}
+	oldN, newN := 0, 0
+	for _, sl := range sh.Lines {
+		var prefix string
+		switch sl.Type {
+		case LineContext:
+			prefix = " "
+			oldN++
+			newN++
+		case LineDelete:
+			prefix = "-"
+			oldN++
+		case LineAdd:
+			prefix = "+"
+			newN++
+		default:
+			return Hunk{}, fmt.Errorf("unknown line type %q", sl.Type)
+		}
+		l := prefix + sl.Text
+		if !sl.NoNewline {
+			l += "\n"
+		}
+		h.Lines = append(h.Lines, l)
+	}
+	if oldN != sh.OldLines || newN != sh.NewLines {
+		return Hunk{}, fmt.Errorf("lines do not match ranges -%d,%d +%d,%d", sh.OldStart, sh.OldLines, sh.NewStart, sh.NewLines)
+	}
+	return h, nil
+}
+
+// EncodeStructured writes p to w as an indented structured diff document.
+//
+// Example:
+//
+//	err := difflib.EncodeStructured(w, ps)
+func EncodeStructured(w io.Writer, p *PatchSet) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(ToStructured(p))
+}
+
+// DecodeStructured reads a structured diff document from r and converts it
+// to a patch set.
+//
+// Example:
+//
+//	ps, err := difflib.DecodeStructured(r)
+//	fmt.Print(ps)
+func DecodeStructured(r io.Reader) (*PatchSet, error) {
+	var s StructuredDiff
+	if err := json.NewDecoder(r).Decode(&s); err != nil {
+		return nil, fmt.Errorf("difflib: decoding structured diff: %w", err)
+	}
+	return s.PatchSet()
+}
@@ -0,0 +1,156 @@
+package difflib_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	difflib "github.com/njchilds90/go-difflib"
+)
+
+func TestToStructured(t *testing.T) {
+	ps, err := difflib.ParsePatchSet("--- a/x\n+++ b/x\n@@ -2,3 +2,3 @@\n one\n-two\n+TWO\n three\n@@ -9,1 +9,0 @@\n-end\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := difflib.ToStructured(ps)
+	if s.Version != difflib.StructuredVersion || len(s.Files) != 1 {
+		t.Fatalf("ToStructured() = %+v", s)
+	}
+	f := s.Files[0]
+	if f.Status != difflib.StatusModified || f.OldPath != "a/x" || f.NewPath != "b/x" {
+		t.Errorf("file = %+v", f)
+	}
+	want := []difflib.StructuredLine{
+		{Type: "context", OldLine: 2, NewLine: 2, Text: "one"},
+		{Type: "delete", OldLine: 3, Text: "two"},
+		{Type: "add", NewLine: 3, Text: "TWO"},
+		{Type: "context", OldLine: 4, NewLine: 4, Text: "three"},
+	}
+	if !reflect.DeepEqual(f.Hunks[0].Lines, want) {
+		t.Errorf("hunk 1 lines = %+v, want %+v", f.Hunks[0].Lines, want)
+	}
+	want = []difflib.StructuredLine{{Type: "delete", OldLine: 9, Text: "end"}}
+	if !reflect.DeepEqual(f.Hunks[1].Lines, want) {
+		t.Errorf("hunk 2 lines = %+v, want %+v", f.Hunks[1].Lines, want)
+	}
+}
+
+func TestStructuredStatus(t *testing.T) {
+	ps, err := difflib.ParsePatchSet(gitPatch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, f := range difflib.ToStructured(ps).Files {
+		got = append(got, f.Status)
+	}
+	want := []string{"modified", "added", "deleted", "renamed", "renamed"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("statuses = %q, want %q", got, want)
+	}
+}
+
+func TestStructuredRoundTrip(t *testing.T) {
+	patches := []string{
+		gitPatch,
+		"--- a\n+++ b\n@@ -1 +1 @@\n-x\n\\ No newline at end of file\n+y\n\\ No newline at end of file\n",
+		"diff --git a/img.png b/img.png\nindex 1..2 100644\nBinary files a/img.png and b/img.png differ\n",
+	}
+	for _, patch := range patches {
+		ps, err := difflib.ParsePatchSet(patch)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var buf bytes.Buffer
+		if err := difflib.EncodeStructured(&buf, ps); err != nil {
+			t.Fatal(err)
+		}
+		back, err := difflib.DecodeStructured(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(back, ps) {
+			t.Errorf("round trip of %q:\ngot  %+v\nwant %+v", patch, back, ps)
+		}
+	}
+}
+
+func TestDecodeStructuredErrors(t *testing.T) {
+	tests := []struct {
+		name, doc, want string
+	}{
+		{"syntax", `{`, "decoding structured diff"},
+		{"version", `{"version":2,"files":[]}`, "unsupported structured diff version 2"},
+		{"status", `{"version":1,"files":[{"status":"moved"}]}`, `file #1: unknown status "moved"`},
+		{"line type", `{"version":1,"files":[{"hunks":[{"old_start":1,"old_lines":1,"new_start":1,"new_lines":1,"lines":[{"type":"same","text":"x"}]}]}]}`,
+			`file #1 hunk #1: unknown line type "same"`},
+		{"counts", `{"version":1,"files":[{"hunks":[{"old_start":1,"old_lines":2,"new_start":1,"new_lines":1,"lines":[{"type":"context","text":"x"}]}]}]}`,
+			"file #1 hunk #1: lines do not match ranges -1,2 +1,1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := difflib.DecodeStructured(strings.NewReader(tt.doc))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("DecodeStructured() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestStructuredJSONNames(t *testing.T) {
+	ps, _ := difflib.ParsePatchSet("--- /dev/null\n+++ b/new\n@@ -0,0 +1 @@\n+hi\n")
+	data, err := json.Marshal(difflib.ToStructured(ps))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"version":1,"files":[{"old_path":"b/new","new_path":"b/new","status":"added","hunks":[{"old_start":0,"old_lines":0,"new_start":1,"new_lines":1,"lines":[{"type":"add","new_line":1,"text":"hi"}]}]}]}`
+	if string(data) != want {
+		t.Errorf("json = %s\nwant %s", data, want)
+	}
+}
+
+func ExampleEncodeStructured() {
+	ps, _ := difflib.ParsePatchSet("--- a/x\n+++ b/x\n@@ -1,2 +1,2 @@\n keep\n-old\n+new\n")
+	difflib.EncodeStructured(os.Stdout, ps)
+	// Output:
+	// {
+	//   "version": 1,
+	//   "files": [
+	//     {
+	//       "old_path": "a/x",
+	//       "new_path": "b/x",
+	//       "status": "modified",
+	//       "hunks": [
+	//         {
+	//           "old_start": 1,
+	//           "old_lines": 2,
+	//           "new_start": 1,
+	//           "new_lines": 2,
+	//           "lines": [
+	//             {
+	//               "type": "context",
+	//               "old_line": 1,
+	//               "new_line": 1,
+	//               "text": "keep"
+	//             },
+	//             {
+	//               "type": "delete",
+	//               "old_line": 2,
+	//               "text": "old"
+	//             },
+	//             {
+	//               "type": "add",
+	//               "new_line": 2,
+	//               "text": "new"
+	//             }
+	//           ]
+	//         }
+	//       ]
+	//     }
+	//   ]
+	// }
+}
@@ -0,0 +1,86 @@
+package difflib
+
+import (
+	"sort"
+	"strings"
+)
+
+// SuggestOptions controls Suggest.
+type SuggestOptions struct {
+	// MaxDistance is the largest edit distance at which a word is
+	// suggested. Zero selects 2.
+	MaxDistance int
+	// Limit caps the number of suggestions. Zero means no limit.
+	Limit int
+	// CaseSensitive disables case folding; by default "STATUS" suggests
+	// "status".
+	CaseSensitive bool
+	// NoPrefix disables suggesting words that start with the input
+	// regardless of their edit distance, as in "stat" suggesting "status".
+	NoPrefix bool
+}
+
+// Suggest returns the words of vocabulary that input was probably meant to
+// be, for "did you mean" messages after a mistyped command or flag. A word
+// is suggested if it is within opts.MaxDistance edits of input or starts
+// with it. Words starting with input come first, then the rest by edit
+// distance; ties are broken alphabetically so the result does not depend
+// on the order of vocabulary. input itself is never suggested.
+//
+// Example:
+//
+//	if s := difflib.Suggest(name, commands, difflib.SuggestOptions{}); len(s) > 0 {
+//	    fmt.Fprintf(os.Stderr, "unknown command %q, did you mean %q?\n", name, s[0])
+//	}
+func Suggest(input string, vocabulary []string, opts SuggestOptions) []string {
+	if opts.MaxDistance <= 0 {
+		opts.MaxDistance = 2
+	}
+	fold := func(s string) string {
+		if opts.CaseSensitive {
+			return s
+		}
+		return strings.ToLower(s)
+	}
+	type suggestion struct {
+		word   string
+		prefix bool
+		dist   int
+	}
+	in := fold(input)
+	seen := map[string]bool{input: true}
+	var out []suggestion
+	for _, w := range vocabulary {
+		if seen[w] {
+			continue
+		}
+		seen[w] = true
+		fw := fold(w)
+		s := suggestion{
+			word:   w,
+			prefix: !opts.NoPrefix && in != "" && strings.HasPrefix(fw, in),
+			dist:   EditDistance(in, fw, EditOptions{}),
+		}
+		if s.prefix || s.dist <= opts.MaxDistance {
+			out = append(out, s)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		a, b := out[i], out[j]
+		if a.prefix != b.prefix {
+			return a.prefix
+		}
+		if a.dist != b.dist {
+			return a.dist < b.dist
+		}
+		return a.word < b.word
+	})
+	if opts.Limit > 0 && len(out) > opts.Limit {
+		out = out[:opts.Limit]
+	}
+	words := make([]string, len(out))
+	for i, s := range out {
+		words[i] = s.word
+	}
+	return words
+}
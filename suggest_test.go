@@ -0,0 +1,50 @@
+package difflib_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	difflib "github.com/njchilds90/go-difflib"
+)
+
+func TestSuggest(t *testing.T) {
+	commands := []string{"status", "stash", "commit", "checkout", "cherry-pick", "config", "set"}
+	tests := []struct {
+		name  string
+		input string
+		vocab []string
+		opts  difflib.SuggestOptions
+		want  []string
+	}{
+		{"typo", "comit", commands, difflib.SuggestOptions{}, []string{"commit"}},
+		{"transposition", "stauts", commands, difflib.SuggestOptions{}, []string{"status"}},
+		{"prefix first", "st", commands, difflib.SuggestOptions{}, []string{"stash", "status", "set"}},
+		{"no prefix", "st", commands, difflib.SuggestOptions{NoPrefix: true}, []string{"set"}},
+		{"case folded", "CHECKOT", commands, difflib.SuggestOptions{}, []string{"checkout"}},
+		{"case sensitive", "CHECKOT", commands, difflib.SuggestOptions{CaseSensitive: true}, []string{}},
+		{"max distance", "cnfg", commands, difflib.SuggestOptions{}, []string{"config"}},
+		{"max distance 1", "cnfg", commands, difflib.SuggestOptions{MaxDistance: 1}, []string{}},
+		{"limit", "st", commands, difflib.SuggestOptions{Limit: 2}, []string{"stash", "status"}},
+		{"exact input excluded", "stash", commands, difflib.SuggestOptions{MaxDistance: 3}, []string{"status"}},
+		{"case variant of input", "Stash", commands, difflib.SuggestOptions{MaxDistance: 1}, []string{"stash"}},
+		{"order independent", "sta", []string{"stay", "star", "stab", "star"}, difflib.SuggestOptions{}, []string{"stab", "star", "stay"}},
+		{"empty input", "", commands, difflib.SuggestOptions{}, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := difflib.Suggest(tt.input, tt.vocab, tt.opts); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Suggest(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func ExampleSuggest() {
+	commands := []string{"build", "test", "install", "clean"}
+	fmt.Println(difflib.Suggest("instal", commands, difflib.SuggestOptions{}))
+	fmt.Println(difflib.Suggest("tets", commands, difflib.SuggestOptions{}))
+	// Output:
+	// [install]
+	// [test]
+}
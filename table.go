@@ -0,0 +1,271 @@
+package difflib
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// TableOptions configures DiffTables. The zero value diffs comma-separated
+// tables keyed by their first column.
+type TableOptions struct {
+	// Comma is the field separator. Defaults to ','; use '\t' for TSV.
+	Comma rune
+	// KeyColumns names the header columns that identify a row. Rows are
+	// matched by the values of these columns, so sorting or reordering
+	// rows is not a change. Defaults to the first column of the old table.
+	KeyColumns []string
+}
+
+// CellChange is a changed cell of a modified row.
+type CellChange struct {
+	Column string `json:"column"`
+	Old    string `json:"old"`
+	New    string `json:"new"`
+}
+
+// RowChange is an added, removed or modified row.
+type RowChange struct {
+	// Op is OpInsert for an added row, OpDelete for a removed row and
+	// OpReplace for a modified one.
+	Op Op `json:"op"`
+	// Key holds the row's values in the key columns.
+	Key []string `json:"key"`
+	// OldRow and NewRow are the 1-based data row numbers, not counting the
+	// header, in each table; 0 where the row is absent.
+	OldRow int `json:"old_row,omitempty"`
+	NewRow int `json:"new_row,omitempty"`
+	// Old and New are the whole records, as read.
+	Old []string `json:"old,omitempty"`
+	New []string `json:"new,omitempty"`
+	// Cells lists the changed cells of a modified row, in the new table's
+	// column order.
+	Cells []CellChange `json:"cells,omitempty"`
+}
+
+// TableDiff is the result of DiffTables. It encodes to JSON directly.
+type TableDiff struct {
+	// KeyColumns are the columns rows were matched by.
+	KeyColumns []string `json:"key_columns"`
+	// AddedColumns and RemovedColumns list columns present in only one
+	// table. Their values are not compared.
+	AddedColumns   []string `json:"added_columns,omitempty"`
+	RemovedColumns []string `json:"removed_columns,omitempty"`
+	// Rows lists the modified and added rows in the new table's order,
+	// followed by the removed rows in the old table's order.
+	Rows []RowChange `json:"rows"`
+
+	comma rune
+}
+
+// table is a parsed CSV table.
+type table struct {
+	header  []string
+	columns map[string]int
+	records [][]string
+}
+
+// DiffTables compares two CSV or TSV tables that start with a header row.
+// Rows are matched by their key columns and columns by their header names,
+// so neither row nor column order matters; a row is modified if any column
+// present in both tables differs. Records may have fewer fields than the
+// header; missing fields compare as empty. Quotes inside unquoted fields, as
+// found in TSV exports, are read literally.
+//
+// An error is returned if a table cannot be parsed, lacks a key column, or
+// has two rows with the same key.
+//
+// Example:
+//
+//	d, err := difflib.DiffTables(oldCSV, newCSV, difflib.TableOptions{KeyColumns: []string{"id"}})
+//	fmt.Print(d.Unified("old.csv", "new.csv"))
+func DiffTables(a, b io.Reader, opts TableOptions) (*TableDiff, error) {
+	if opts.Comma == 0 {
+		opts.Comma = ','
+	}
+	ta, err := readTable(a, opts.Comma)
+	if err != nil {
+		return nil, fmt.Errorf("difflib: old table: %w", err)
+	}
+	tb, err := readTable(b, opts.Comma)
+	if err != nil {
+		return nil, fmt.Errorf("difflib: new table: %w", err)
+	}
+	keys := opts.KeyColumns
+	if len(keys) == 0 {
+		if len(ta.header) == 0 {
+			return nil, fmt.Errorf("difflib: old table has no header")
+		}
+		keys = ta.header[:1]
+	}
+	for _, k := range keys {
+		if _, ok := ta.columns[k]; !ok {
+			return nil, fmt.Errorf("difflib: old table has no key column %q", k)
+		}
+		if _, ok := tb.columns[k]; !ok {
+			return nil, fmt.Errorf("difflib: new table has no key column %q", k)
+		}
+	}
+	rowsA, err := ta.index(keys)
+	if err != nil {
+		return nil, fmt.Errorf("difflib: old table: %w", err)
+	}
+	rowsB, err := tb.index(keys)
+	if err != nil {
+		return nil, fmt.Errorf("difflib: new table: %w", err)
+	}
+
+	d := &TableDiff{KeyColumns: keys, comma: opts.Comma}
+	var shared []string
+	for _, c := range tb.header {
+		if _, ok := ta.columns[c]; ok {
+			shared = append(shared, c)
+		} else {
+			d.AddedColumns = append(d.AddedColumns, c)
+		}
+	}
+	for _, c := range ta.header {
+		if _, ok := tb.columns[c]; !ok {
+			d.RemovedColumns = append(d.RemovedColumns, c)
+		}
+	}
+
+	for j, rec := range tb.records {
+		key := tb.key(rec, keys)
+		i, ok := rowsA[strings.Join(key, "\x00")]
+		if !ok {
+			d.Rows = append(d.Rows, RowChange{Op: OpInsert, Key: key, NewRow: j + 1, New: rec})
+			continue
+		}
+		var cells []CellChange
+		for _, c := range shared {
+			if va, vb := ta.field(ta.records[i], c), tb.field(rec, c); va != vb {
+				cells = append(cells, CellChange{Column: c, Old: va, New: vb})
+			}
+		}
+		if len(cells) > 0 {
+			d.Rows = append(d.Rows, RowChange{Op: OpReplace, Key: key, OldRow: i + 1, NewRow: j + 1, Old: ta.records[i], New: rec, Cells: cells})
+		}
+	}
+	for i, rec := range ta.records {
+		key := ta.key(rec, keys)
+		if _, ok := rowsB[strings.Join(key, "\x00")]; !ok {
+			d.Rows = append(d.Rows, RowChange{Op: OpDelete, Key: key, OldRow: i + 1, Old: rec})
+		}
+	}
+	return d, nil
+}
+
+// Unified renders the changed rows in a unified-diff-like form: the
+// records as CSV lines, one hunk per row headed by its key, and the header
+// rows first if the columns changed. Since rows are matched by key rather
+// than position, the output is for reading and cannot be applied as a
+// patch.
+//
+// Example:
+//
+//	--- old.csv
+//	+++ new.csv
+//	@@ id=2 @@
+//	-2,bob,30
+//	+2,bob,31
+//	@@ id=4 @@
+//	+4,dan,22
+func (d *TableDiff) Unified(fromFile, toFile string) string {
+	if len(d.Rows) == 0 && len(d.AddedColumns) == 0 && len(d.RemovedColumns) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- %s\n+++ %s\n", fromFile, toFile)
+	if len(d.AddedColumns) > 0 || len(d.RemovedColumns) > 0 {
+		b.WriteString("@@ columns @@\n")
+		for _, c := range d.RemovedColumns {
+			fmt.Fprintf(&b, "-%s\n", d.record([]string{c}))
+		}
+		for _, c := range d.AddedColumns {
+			fmt.Fprintf(&b, "+%s\n", d.record([]string{c}))
+		}
+	}
+	for _, r := range d.Rows {
+		b.WriteString("@@ ")
+		for i, k := range d.KeyColumns {
+			if i > 0 {
+				b.WriteByte(' ')
+			}
+			fmt.Fprintf(&b, "%s=%s", k, r.Key[i])
+		}
+		b.WriteString(" @@\n")
+		if r.Old != nil {
+			fmt.Fprintf(&b, "-%s\n", d.record(r.Old))
+		}
+		if r.New != nil {
+			fmt.Fprintf(&b, "+%s\n", d.record(r.New))
+		}
+	}
+	return b.String()
+}
+
+// record formats fields as one CSV line without the line terminator.
+func (d *TableDiff) record(fields []string) string {
+	var b strings.Builder
+	w := csv.NewWriter(&b)
+	w.Comma = d.comma
+	w.Write(fields)
+	w.Flush()
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// readTable reads a whole CSV table with its header.
+func readTable(r io.Reader, comma rune) (*table, error) {
+	cr := csv.NewReader(r)
+	cr.Comma = comma
+	cr.FieldsPerRecord = -1
+	cr.LazyQuotes = true
+	records, err := cr.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	t := &table{columns: map[string]int{}}
+	if len(records) == 0 {
+		return t, nil
+	}
+	t.header, t.records = records[0], records[1:]
+	for i, c := range t.header {
+		if _, dup := t.columns[c]; dup {
+			return nil, fmt.Errorf("duplicate column %q", c)
+		}
+		t.columns[c] = i
+	}
+	return t, nil
+}
+
+// index maps each row's joined key to its record index.
+func (t *table) index(keys []string) (map[string]int, error) {
+	rows := make(map[string]int, len(t.records))
+	for i, rec := range t.records {
+		k := strings.Join(t.key(rec, keys), "\x00")
+		if _, dup := rows[k]; dup {
+			return nil, fmt.Errorf("duplicate key %q in row %d", t.key(rec, keys), i+1)
+		}
+		rows[k] = i
+	}
+	return rows, nil
+}
+
+// key returns the values of the key columns of rec.
+func (t *table) key(rec, keys []string) []string {
+	k := make([]string, len(keys))
+	for i, c := range keys {
+		k[i] = t.field(rec, c)
+	}
+	return k
+}
+
+// field returns rec's value in the named column, or "" if rec is short.
+func (t *table) field(rec []string, column string) string {
+	if i := t.columns[column]; i < len(rec) {
+		return rec[i]
+	}
+	return ""
+}
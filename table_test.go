@@ -0,0 +1,123 @@
+package difflib_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	difflib "github.com/njchilds90/go-difflib"
+)
+
+func diffTables(t *testing.T, a, b string, opts difflib.TableOptions) *difflib.TableDiff {
+	t.Helper()
+	d, err := difflib.DiffTables(strings.NewReader(a), strings.NewReader(b), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+func TestDiffTables(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		opts difflib.TableOptions
+		want string
+	}{
+		{"identical", "id,name\n1,a\n", "id,name\n1,a\n", difflib.TableOptions{}, ""},
+		{"rows and columns reordered", "id,name,age\n1,a,30\n2,b,40\n", "age,name,id\n40,b,2\n30,a,1\n", difflib.TableOptions{}, ""},
+		{
+			"added removed modified", "id,name\n1,a\n2,b\n3,c\n", "id,name\n3,c\n2,B\n4,d\n", difflib.TableOptions{},
+			"--- a\n+++ b\n@@ id=2 @@\n-2,b\n+2,B\n@@ id=4 @@\n+4,d\n@@ id=1 @@\n-1,a\n",
+		},
+		{
+			"composite key", "first,last,age\nAda,L,36\nAda,K,20\n", "first,last,age\nAda,K,21\nAda,L,36\n",
+			difflib.TableOptions{KeyColumns: []string{"first", "last"}},
+			"--- a\n+++ b\n@@ first=Ada last=K @@\n-Ada,K,20\n+Ada,K,21\n",
+		},
+		{
+			"tsv with quoting", "id\tnote\n1\tsay \"hi\"\n", "id\tnote\n1\ta\tb\n", difflib.TableOptions{Comma: '\t', KeyColumns: []string{"id"}},
+			"--- a\n+++ b\n@@ id=1 @@\n-1\t\"say \"\"hi\"\"\"\n+1\ta\tb\n",
+		},
+		{
+			"column added and removed", "id,old\n1,x\n", "id,new\n1,y\n", difflib.TableOptions{},
+			"--- a\n+++ b\n@@ columns @@\n-old\n+new\n",
+		},
+		{
+			"short record", "id,a,b\n1,x\n", "id,a,b\n1,x,\n", difflib.TableOptions{}, "",
+		},
+	}
+	for _, tt := range tests {
+		d := diffTables(t, tt.a, tt.b, tt.opts)
+		if got := d.Unified("a", "b"); got != tt.want {
+			t.Errorf("%s: Unified =\n%q\nwant\n%q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDiffTablesCells(t *testing.T) {
+	d := diffTables(t, "id,name,age,city\n7,ann,30,Oslo\n", "city,id,age,name\nBergen,7,31,ann\n", difflib.TableOptions{})
+	if len(d.Rows) != 1 {
+		t.Fatalf("rows = %+v", d.Rows)
+	}
+	r := d.Rows[0]
+	if r.Op != difflib.OpReplace || r.OldRow != 1 || r.NewRow != 1 {
+		t.Errorf("row = %+v", r)
+	}
+	want := []difflib.CellChange{{Column: "city", Old: "Oslo", New: "Bergen"}, {Column: "age", Old: "30", New: "31"}}
+	if fmt.Sprint(r.Cells) != fmt.Sprint(want) {
+		t.Errorf("cells = %+v, want %+v", r.Cells, want)
+	}
+}
+
+func TestDiffTablesJSON(t *testing.T) {
+	d := diffTables(t, "id,v\n1,a\n2,b\n", "id,v\n1,A\n3,c\n", difflib.TableOptions{})
+	out, err := json.Marshal(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"key_columns":["id"],"rows":[` +
+		`{"op":"replace","key":["1"],"old_row":1,"new_row":1,"old":["1","a"],"new":["1","A"],"cells":[{"column":"v","old":"a","new":"A"}]},` +
+		`{"op":"insert","key":["3"],"new_row":2,"new":["3","c"]},` +
+		`{"op":"delete","key":["2"],"old_row":2,"old":["2","b"]}]}`
+	if string(out) != want {
+		t.Errorf("JSON =\n%s\nwant\n%s", out, want)
+	}
+}
+
+func TestDiffTablesErrors(t *testing.T) {
+	tests := []struct {
+		a, b string
+		opts difflib.TableOptions
+		want string
+	}{
+		{"id\n1\n1\n", "id\n", difflib.TableOptions{}, `difflib: old table: duplicate key ["1"] in row 2`},
+		{"id\n", "x\n", difflib.TableOptions{}, `difflib: new table has no key column "id"`},
+		{"id\n", "id\n", difflib.TableOptions{KeyColumns: []string{"k"}}, `difflib: old table has no key column "k"`},
+		{"", "id\n", difflib.TableOptions{}, "difflib: old table has no header"},
+		{"id,id\n", "id\n", difflib.TableOptions{}, `difflib: old table: duplicate column "id"`},
+		{"id\n", "id\n", difflib.TableOptions{Comma: '"'}, "difflib: old table: csv: invalid field or comment delimiter"},
+	}
+	for _, tt := range tests {
+		_, err := difflib.DiffTables(strings.NewReader(tt.a), strings.NewReader(tt.b), tt.opts)
+		if err == nil || !strings.HasPrefix(err.Error(), tt.want) {
+			t.Errorf("DiffTables(%q, %q) error = %v, want %q", tt.a, tt.b, err, tt.want)
+		}
+	}
+}
+
+func ExampleDiffTables() {
+	old := "id,name,age\n1,ann,30\n2,bob,30\n3,cid,50\n"
+	new := "id,name,age\n3,cid,50\n2,bob,31\n1,ann,30\n4,dan,22\n"
+	d, _ := difflib.DiffTables(strings.NewReader(old), strings.NewReader(new), difflib.TableOptions{})
+	fmt.Print(d.Unified("old.csv", "new.csv"))
+	// Output:
+	// --- old.csv
+	// +++ new.csv
+	// @@ id=2 @@
+	// -2,bob,30
+	// +2,bob,31
+	// @@ id=4 @@
+	// +4,dan,22
+}
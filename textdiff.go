@@ -0,0 +1,153 @@
+package difflib
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// TextDiff is one segment of a character-level diff between two strings, in
+// the style of Google's diff-match-patch: Op is OpEqual, OpDelete or
+// OpInsert and Text is the affected text.
+type TextDiff struct {
+	Op   Op     `json:"op"`
+	Text string `json:"text"`
+}
+
+// DiffText returns the character-level diff of a and b as a list of
+// segments. Replacements are reported as a deletion followed by an
+// insertion, as diff-match-patch does.
+//
+// Example:
+//
+//	diffs := difflib.DiffText("jumps over", "jumped over")
+//	// [{equal "jump"} {delete "s"} {insert "ed"} {equal " over"}]
+func DiffText(a, b string) []TextDiff {
+	as, bs := splitChars(a), splitChars(b)
+	var diffs []TextDiff
+	add := func(op Op, chars []string) {
+		if len(chars) > 0 {
+			diffs = append(diffs, TextDiff{op, strings.Join(chars, "")})
+		}
+	}
+	for _, op := range GetOpCodes(as, bs) {
+		switch op.Tag {
+		case OpEqual:
+			add(OpEqual, as[op.I1:op.I2])
+		case OpDelete, OpReplace:
+			add(OpDelete, as[op.I1:op.I2])
+			if op.Tag == OpDelete {
+				continue
+			}
+			fallthrough
+		case OpInsert:
+			add(OpInsert, bs[op.J1:op.J2])
+		}
+	}
+	return diffs
+}
+
+// ToDelta encodes diffs in the compact delta format of diff-match-patch's
+// diff_toDelta, such as "=5\t-2\t+ins": "=N" keeps and "-N" deletes N
+// characters of the source text, and "+text" inserts URI-encoded text.
+// Lengths count UTF-16 code units, as in the JavaScript and Java libraries,
+// so deltas can be exchanged with them.
+//
+// Example:
+//
+//	delta := difflib.ToDelta(difflib.DiffText(old, new))
+//	// send delta; the receiver calls FromDelta(old, delta)
+func ToDelta(diffs []TextDiff) string {
+	var tokens []string
+	for _, d := range diffs {
+		switch d.Op {
+		case OpEqual:
+			tokens = append(tokens, "="+strconv.Itoa(utf16Len(d.Text)))
+		case OpDelete:
+			tokens = append(tokens, "-"+strconv.Itoa(utf16Len(d.Text)))
+		case OpInsert:
+			tokens = append(tokens, "+"+encodeURI(d.Text))
+		}
+	}
+	return strings.Join(tokens, "\t")
+}
+
+// FromDelta decodes a diff-match-patch delta against the source text it was
+// made from, returning the full diff. An error is returned if the delta is
+// malformed or its lengths do not add up to the length of source.
+//
+// Example:
+//
+//	diffs, err := difflib.FromDelta("jumps over", "=4\t-1\t+ed\t=5")
+func FromDelta(source, delta string) ([]TextDiff, error) {
+	src := utf16.Encode([]rune(source))
+	var diffs []TextDiff
+	pos := 0
+	for _, token := range strings.Split(delta, "\t") {
+		if token == "" {
+			// Blank tokens are ok (from a trailing \t).
+			continue
+		}
+		param := token[1:]
+		switch token[0] {
+		case '+':
+			text, err := url.PathUnescape(param)
+			if err != nil {
+				return nil, fmt.Errorf("difflib: illegal escape in delta token %q: %v", token, err)
+			}
+			diffs = append(diffs, TextDiff{OpInsert, text})
+		case '-', '=':
+			n, err := strconv.Atoi(param)
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf("difflib: invalid number in delta token %q", token)
+			}
+			if pos+n > len(src) {
+				return nil, fmt.Errorf("difflib: delta length (%d) larger than source text length (%d)", pos+n, len(src))
+			}
+			text := string(utf16.Decode(src[pos : pos+n]))
+			pos += n
+			op := OpEqual
+			if token[0] == '-' {
+				op = OpDelete
+			}
+			diffs = append(diffs, TextDiff{op, text})
+		default:
+			return nil, fmt.Errorf("difflib: invalid diff operation in delta token %q", token)
+		}
+	}
+	if pos != len(src) {
+		return nil, fmt.Errorf("difflib: delta length (%d) does not equal source text length (%d)", pos, len(src))
+	}
+	return diffs, nil
+}
+
+// utf16Len returns the length of s in UTF-16 code units.
+func utf16Len(s string) int {
+	n := 0
+	for _, r := range s {
+		if r >= 0x10000 {
+			n += 2 // surrogate pair
+		} else {
+			n++
+		}
+	}
+	return n
+}
+
+// encodeURI escapes s like JavaScript's encodeURI, except that spaces are
+// left as is, matching diff-match-patch's delta encoding.
+func encodeURI(s string) string {
+	const keep = " ;,/?:@&=+$-_.!~*'()#"
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || strings.IndexByte(keep, c) >= 0 {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+	return b.String()
+}
@@ -0,0 +1,118 @@
+package difflib_test
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	difflib "github.com/njchilds90/go-difflib"
+)
+
+func TestDiffText(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want []difflib.TextDiff
+	}{
+		{"", "", nil},
+		{"abc", "abc", []difflib.TextDiff{{difflib.OpEqual, "abc"}}},
+		{"abc", "", []difflib.TextDiff{{difflib.OpDelete, "abc"}}},
+		{"jumps over", "jumped over", []difflib.TextDiff{
+			{difflib.OpEqual, "jump"}, {difflib.OpDelete, "s"}, {difflib.OpInsert, "ed"}, {difflib.OpEqual, " over"},
+		}},
+		{"héllo", "hallo", []difflib.TextDiff{
+			{difflib.OpEqual, "h"}, {difflib.OpDelete, "é"}, {difflib.OpInsert, "a"}, {difflib.OpEqual, "llo"},
+		}},
+	}
+	for _, tt := range tests {
+		if got := difflib.DiffText(tt.a, tt.b); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("DiffText(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestDelta(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		diffs  []difflib.TextDiff
+		delta  string
+	}{
+		{
+			// The diff_toDelta test of diff-match-patch.
+			name:   "dmp",
+			source: "jumps over the lazy",
+			diffs: []difflib.TextDiff{
+				{difflib.OpEqual, "jump"}, {difflib.OpDelete, "s"}, {difflib.OpInsert, "ed"},
+				{difflib.OpEqual, " over "}, {difflib.OpDelete, "the"}, {difflib.OpInsert, "a"},
+				{difflib.OpEqual, " lazy"}, {difflib.OpInsert, "old dog"},
+			},
+			delta: "=4\t-1\t+ed\t=6\t-3\t+a\t=5\t+old dog",
+		},
+		{
+			name:   "unicode",
+			source: "ڀ \x00 \t %ځ \x01 \n ^",
+			diffs: []difflib.TextDiff{
+				{difflib.OpEqual, "ڀ \x00 \t %"}, {difflib.OpDelete, "ځ \x01 \n ^"}, {difflib.OpInsert, "ڂ \x02 \\ |"},
+			},
+			delta: "=7\t-7\t+%DA%82 %02 %5C %7C",
+		},
+		{
+			name:  "unescaped",
+			diffs: []difflib.TextDiff{{difflib.OpInsert, "A-Z a-z 0-9 - _ . ! ~ * ' ( ) ; / ? : @ & = + $ , # "}},
+			delta: "+A-Z a-z 0-9 - _ . ! ~ * ' ( ) ; / ? : @ & = + $ , # ",
+		},
+		{
+			name:   "surrogate pair",
+			source: "\U0001F600x",
+			diffs:  []difflib.TextDiff{{difflib.OpEqual, "\U0001F600"}, {difflib.OpDelete, "x"}, {difflib.OpInsert, "\U0001F601"}},
+			delta:  "=2\t-1\t+%F0%9F%98%81",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := difflib.ToDelta(tt.diffs); got != tt.delta {
+				t.Errorf("ToDelta() = %q, want %q", got, tt.delta)
+			}
+			got, err := difflib.FromDelta(tt.source, tt.delta)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.diffs) {
+				t.Errorf("FromDelta() = %q, want %q", got, tt.diffs)
+			}
+		})
+	}
+}
+
+func TestFromDeltaErrors(t *testing.T) {
+	delta := "=4\t-1\t+ed\t=6\t-3\t+a\t=5\t+old dog"
+	tests := []struct {
+		name, source, delta, want string
+	}{
+		{"too long", "jumps over the lazyx", delta, "delta length (19) does not equal source text length (20)"},
+		{"too short", "umps over the lazy", delta, "delta length (19) larger than source text length (18)"},
+		{"escape", "", "+%c3%xy", "illegal escape"},
+		{"number", "abc", "=x", "invalid number"},
+		{"operation", "abc", "*3", "invalid diff operation"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := difflib.FromDelta(tt.source, tt.delta)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("FromDelta() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func ExampleToDelta() {
+	old, new := "The cat sat.", "The black cat sat!"
+	delta := difflib.ToDelta(difflib.DiffText(old, new))
+	fmt.Printf("%q\n", delta)
+	diffs, _ := difflib.FromDelta(old, delta)
+	fmt.Println(len(diffs))
+	// Output:
+	// "=3\t+ black\t=8\t-1\t+!"
+	// 5
+}
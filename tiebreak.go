@@ -0,0 +1,96 @@
+package difflib
+
+// TieBreak selects which of several equally long matching blocks a Matcher
+// picks at each step. The choice can change which lines a diff reports as
+// unchanged when the inputs repeat lines. Since the blocks on either side
+// of a pick are matched recursively, it can also change how many lines
+// match in total, so no rule gives the smallest diff for every input. Each
+// rule is fixed, so pinning one keeps output byte-for-byte stable.
+type TieBreak int
+
+const (
+	// TieEarliestA picks the block that starts earliest in a, then in b.
+	// It is the default and matches Python's difflib.
+	TieEarliestA TieBreak = iota
+	// TieEarliestB picks the block that starts earliest in b, then in a.
+	TieEarliestB
+	// TieLowestIndent picks the block whose first line in a is least
+	// indented, then the earliest in a, so that a match tends to start at
+	// the outermost line of a repeated structure rather than inside its
+	// body. Tabs count as eight columns; blank lines count as more indented
+	// than any other line.
+	TieLowestIndent
+)
+
+// SetTieBreak sets the rule for choosing between equally long matches.
+//
+// Example:
+//
+//	m := difflib.NewMatcher(a, b)
+//	m.SetTieBreak(difflib.TieLowestIndent)
+//	codes := m.GetOpCodes()
+func (m *Matcher) SetTieBreak(t TieBreak) {
+	m.tieBreak = t
+}
+
+// tieBreaker returns the tie-breaking state for matching m.a, or nil for
+// the default rule.
+func (m *Matcher) tieBreaker() *tieBreaker {
+	switch m.tieBreak {
+	case TieEarliestB:
+		return &tieBreaker{rule: TieEarliestB}
+	case TieLowestIndent:
+		indent := make([]int, len(m.a))
+		for i, l := range m.a {
+			indent[i] = lineIndent(l)
+		}
+		return &tieBreaker{rule: TieLowestIndent, indent: indent}
+	}
+	return nil
+}
+
+// tieBreaker decides between equally long matches. A nil *tieBreaker
+// applies TieEarliestA.
+type tieBreaker struct {
+	rule   TieBreak
+	indent []int // indentation of each line of a, for TieLowestIndent
+}
+
+// better reports whether a match starting at (i, j) beats one of the same
+// size starting at (bestI, bestJ). Matches are offered in order of their
+// end in a, then in b.
+func (t *tieBreaker) better(i, j, bestI, bestJ int) bool {
+	if t == nil {
+		return false
+	}
+	switch t.rule {
+	case TieEarliestB:
+		return j < bestJ || j == bestJ && i < bestI
+	case TieLowestIndent:
+		return t.indent[i] < t.indent[bestI] || t.indent[i] == t.indent[bestI] && i < bestI
+	}
+	return false
+}
+
+// blankIndent is the indentation given to blank lines.
+const blankIndent = 1 << 30
+
+// lineIndent returns the width of the leading whitespace of line, with
+// tabs advancing to the next multiple of eight, or blankIndent if line is
+// blank.
+func lineIndent(line string) int {
+	width := 0
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case ' ':
+			width++
+		case '\t':
+			width += 8 - width%8
+		case '\r', '\n':
+			return blankIndent
+		default:
+			return width
+		}
+	}
+	return blankIndent
+}
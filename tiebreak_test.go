@@ -0,0 +1,78 @@
+package difflib_test
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+
+	difflib "github.com/njchilds90/go-difflib"
+)
+
+func TestMatcherTieBreak(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b []string
+		tie  difflib.TieBreak
+		want []difflib.SequenceMatch
+	}{
+		{"earliest a", []string{"x\n", "y\n"}, []string{"y\n", "x\n"}, difflib.TieEarliestA,
+			[]difflib.SequenceMatch{{A: 0, B: 1, Size: 1}, {A: 2, B: 2}}},
+		{"earliest b", []string{"x\n", "y\n"}, []string{"y\n", "x\n"}, difflib.TieEarliestB,
+			[]difflib.SequenceMatch{{A: 1, B: 0, Size: 1}, {A: 2, B: 2}}},
+		{"lowest indent", []string{"\tx\n", "y\n"}, []string{"y\n", "\tx\n"}, difflib.TieLowestIndent,
+			[]difflib.SequenceMatch{{A: 1, B: 0, Size: 1}, {A: 2, B: 2}}},
+		{"indent tie", []string{"x\n", "y\n"}, []string{"y\n", "x\n"}, difflib.TieLowestIndent,
+			[]difflib.SequenceMatch{{A: 0, B: 1, Size: 1}, {A: 2, B: 2}}},
+		{"blank lines last", []string{"\n", "}\n"}, []string{"}\n", "\n"}, difflib.TieLowestIndent,
+			[]difflib.SequenceMatch{{A: 1, B: 0, Size: 1}, {A: 2, B: 2}}},
+		{"longer wins", []string{"\ta\n", "\tb\n", "c\n"}, []string{"c\n", "\ta\n", "\tb\n"}, difflib.TieLowestIndent,
+			[]difflib.SequenceMatch{{A: 0, B: 1, Size: 2}, {A: 3, B: 3}}},
+	}
+	for _, tt := range tests {
+		m := difflib.NewMatcher(tt.a, tt.b)
+		m.SetTieBreak(tt.tie)
+		if got := m.GetMatchingBlocks(); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: GetMatchingBlocks = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestMatcherTieBreakMatchedSize(t *testing.T) {
+	// Each rule matches a different number of lines here: 4, 2 and 3.
+	a := []string{"c\n", "a\n", " b\n", " b\n", "b\n", "a\n", " a\n", "a\n"}
+	b := []string{"c\n", " a\n", "c\n", "b\n", " b\n", " a\n", "b\n", " a\n"}
+	tests := []struct {
+		tie  difflib.TieBreak
+		want []difflib.SequenceMatch
+	}{
+		{difflib.TieEarliestA, []difflib.SequenceMatch{{A: 0, B: 0, Size: 1}, {A: 2, B: 4, Size: 1}, {A: 4, B: 6, Size: 1}, {A: 6, B: 7, Size: 1}, {A: 8, B: 8}}},
+		{difflib.TieEarliestB, []difflib.SequenceMatch{{A: 0, B: 0, Size: 1}, {A: 6, B: 1, Size: 1}, {A: 8, B: 8}}},
+		{difflib.TieLowestIndent, []difflib.SequenceMatch{{A: 0, B: 0, Size: 1}, {A: 4, B: 3, Size: 1}, {A: 6, B: 5, Size: 1}, {A: 8, B: 8}}},
+	}
+	for _, tt := range tests {
+		m := difflib.NewMatcher(a, b)
+		m.SetTieBreak(tt.tie)
+		if got := m.GetMatchingBlocks(); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("tie %d: GetMatchingBlocks = %v, want %v", tt.tie, got, tt.want)
+		}
+	}
+}
+
+func TestMatcherTieBreakRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for iter := 0; iter < 200; iter++ {
+		a, b := randomLines(rng, rng.Intn(30)), randomLines(rng, rng.Intn(30))
+		for _, tie := range []difflib.TieBreak{difflib.TieEarliestA, difflib.TieEarliestB, difflib.TieLowestIndent} {
+			d := difflib.UnifiedDiff(difflib.DiffInput{A: a, B: b, FromFile: "a", ToFile: "b", TieBreak: tie})
+			got, err := difflib.ApplyPatch(a, d.String())
+			if err != nil || difflib.JoinLines(got) != difflib.JoinLines(b) {
+				t.Fatalf("iteration %d, tie %d: patch does not produce b (%v):\n%s", iter, tie, err, d)
+			}
+		}
+		m := difflib.NewMatcher(a, b)
+		m.SetTieBreak(difflib.TieEarliestA)
+		if got, want := m.GetOpCodes(), difflib.NewMatcher(a, b).GetOpCodes(); !reflect.DeepEqual(got, want) {
+			t.Fatalf("iteration %d: TieEarliestA = %v, default = %v", iter, got, want)
+		}
+	}
+}
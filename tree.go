@@ -0,0 +1,338 @@
+package difflib
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// TreeDiffOptions controls DiffTrees.
+type TreeDiffOptions struct {
+	// Context is the number of context lines around each change. Defaults to
+	// 3 if zero; use NoContext for none.
+	Context int
+	// DetectRenames pairs deleted files with similar added files and reports
+	// them as renames instead of a deletion and a creation.
+	DetectRenames bool
+	// DetectCopies reports added files that are similar to a file that still
+	// exists as copies of it.
+	DetectCopies bool
+	// RenameThreshold is the minimum similarity, in percent, for a rename or
+	// copy to be detected. Defaults to 50, as in git.
+	RenameThreshold int
+	// Include, if non-empty, limits the diff to files matching at least one
+	// of these glob patterns. Exclude skips files and whole directories
+	// matching any of its patterns. Patterns without a slash match the last
+	// path element (e.g. "*.log", "vendor"); patterns with a slash match the
+	// whole slash-separated path from the tree root, where "**" matches any
+	// number of directories (e.g. "docs/**/*.md").
+	Include, Exclude []string
+	// GitIgnore makes each tree honor its own .gitignore files, with git's
+	// semantics for negation, directory-only and anchored patterns. The .git
+	// directory is skipped as well.
+	GitIgnore bool
+	// FuncContext names the enclosing function in each hunk header, using
+	// the pattern FuncPatterns gives for the file's extension (e.g. ".go"),
+	// or else FuncPatternFor's.
+	FuncContext  bool
+	FuncPatterns map[string]*regexp.Regexp
+}
+
+// skipTreePath reports whether name (a file, or a directory if isDir) is
+// filtered out of a tree diff by the include and exclude patterns.
+func (o TreeDiffOptions) skipTreePath(name string, isDir bool) bool {
+	for _, p := range o.Exclude {
+		p = strings.TrimSuffix(p, "/")
+		if matchPathPattern(p, name, strings.Contains(p, "/")) {
+			return true
+		}
+	}
+	if isDir || len(o.Include) == 0 {
+		return false
+	}
+	for _, p := range o.Include {
+		if matchPathPattern(p, name, strings.Contains(p, "/")) {
+			return false
+		}
+	}
+	return true
+}
+
+// treeFile is a regular file read from one side of a tree diff.
+type treeFile struct {
+	path string
+	data []byte
+	mode string
+}
+
+// DiffTrees compares two file trees and returns a git-style patch set with
+// one FileDiff per added, deleted or modified regular file, in path order.
+// Directories are descended into; other non-regular files are ignored.
+// Files containing a NUL byte are reported as binary. With DetectRenames or
+// DetectCopies set, files are paired by line similarity and emitted with
+// "rename from/to" or "copy from/to" headers and a similarity index.
+//
+// Example:
+//
+//	ps, err := difflib.DiffTrees(os.DirFS("old"), os.DirFS("new"), difflib.TreeDiffOptions{
+//	    DetectRenames: true,
+//	})
+//	fmt.Print(ps)
+func DiffTrees(oldFS, newFS fs.FS, opts TreeDiffOptions) (*PatchSet, error) {
+	oldFiles, err := readTree(oldFS, opts)
+	if err != nil {
+		return nil, err
+	}
+	newFiles, err := readTree(newFS, opts)
+	if err != nil {
+		return nil, err
+	}
+	threshold := opts.RenameThreshold
+	if threshold <= 0 {
+		threshold = 50
+	}
+
+	oldByPath := make(map[string]treeFile, len(oldFiles))
+	for _, f := range oldFiles {
+		oldByPath[f.path] = f
+	}
+	newByPath := make(map[string]bool, len(newFiles))
+	for _, f := range newFiles {
+		newByPath[f.path] = true
+	}
+
+	var files []FileDiff
+	var added, deleted []treeFile
+	for _, f := range newFiles {
+		o, ok := oldByPath[f.path]
+		switch {
+		case !ok:
+			added = append(added, f)
+		case !bytes.Equal(o.data, f.data) || o.mode != f.mode:
+			files = append(files, treeFileDiff(o, f, opts))
+		}
+	}
+	for _, f := range oldFiles {
+		if !newByPath[f.path] {
+			deleted = append(deleted, f)
+		}
+	}
+
+	if opts.DetectRenames {
+		var renames []FileDiff
+		renames, deleted, added = pairSimilar(deleted, added, threshold, true, opts)
+		files = append(files, renames...)
+	}
+	if opts.DetectCopies {
+		var sources []treeFile
+		for _, f := range oldFiles {
+			if newByPath[f.path] {
+				sources = append(sources, f)
+			}
+		}
+		var copies []FileDiff
+		copies, _, added = pairSimilar(sources, added, threshold, false, opts)
+		files = append(files, copies...)
+	}
+	for _, f := range added {
+		files = append(files, treeFileDiff(treeFile{}, f, opts))
+	}
+	for _, f := range deleted {
+		files = append(files, treeFileDiff(f, treeFile{}, opts))
+	}
+
+	sort.SliceStable(files, func(i, j int) bool {
+		return treeSortKey(files[i]) < treeSortKey(files[j])
+	})
+	return &PatchSet{Files: files}, nil
+}
+
+// readTree reads every regular file of fsys not filtered out by opts, in
+// lexical path order.
+func readTree(fsys fs.FS, opts TreeDiffOptions) ([]treeFile, error) {
+	var files []treeFile
+	var rules []ignoreRule
+	err := fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if name != "." {
+			if opts.skipTreePath(name, d.IsDir()) ||
+				opts.GitIgnore && (d.IsDir() && d.Name() == ".git" || ignoredBy(rules, name, d.IsDir())) {
+				if d.IsDir() {
+					return fs.SkipDir
+				}
+				return nil
+			}
+		}
+		if d.IsDir() {
+			if opts.GitIgnore {
+				if data, err := fs.ReadFile(fsys, path.Join(name, ".gitignore")); err == nil {
+					rules = append(rules, parseGitignore(name, string(data))...)
+				}
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return err
+		}
+		mode := "100644"
+		if info, err := d.Info(); err == nil && info.Mode().Perm()&0o111 != 0 {
+			mode = "100755"
+		}
+		files = append(files, treeFile{path: name, data: data, mode: mode})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("difflib: reading tree: %w", err)
+	}
+	return files, nil
+}
+
+// pairSimilar greedily pairs sources with targets in order of decreasing
+// similarity, returning the renames or copies found and the sources and
+// targets left unpaired. With exclusive set each source is used at most once.
+func pairSimilar(sources, targets []treeFile, threshold int, exclusive bool, opts TreeDiffOptions) ([]FileDiff, []treeFile, []treeFile) {
+	type candidate struct{ src, dst, score int }
+	var cands []candidate
+	for i, s := range sources {
+		for j, t := range targets {
+			if score := treeSimilarity(s.data, t.data, threshold); score >= threshold {
+				cands = append(cands, candidate{i, j, score})
+			}
+		}
+	}
+	sort.SliceStable(cands, func(i, j int) bool { return cands[i].score > cands[j].score })
+
+	usedSrc := make([]bool, len(sources))
+	usedDst := make([]bool, len(targets))
+	var out []FileDiff
+	for _, c := range cands {
+		if usedDst[c.dst] || exclusive && usedSrc[c.src] {
+			continue
+		}
+		usedSrc[c.src], usedDst[c.dst] = true, true
+		f := treeFileDiff(sources[c.src], targets[c.dst], opts)
+		f.Similarity = c.score
+		if exclusive {
+			f.Rename = true
+		} else {
+			f.Copy = true
+		}
+		out = append(out, f)
+	}
+
+	var restSrc, restDst []treeFile
+	for i, s := range sources {
+		if !usedSrc[i] {
+			restSrc = append(restSrc, s)
+		}
+	}
+	for j, t := range targets {
+		if !usedDst[j] {
+			restDst = append(restDst, t)
+		}
+	}
+	return out, restSrc, restDst
+}
+
+// treeSimilarity returns the similarity of two files in percent, or 0 if it
+// is certainly below threshold.
+func treeSimilarity(a, b []byte, threshold int) int {
+	if bytes.Equal(a, b) {
+		return 100
+	}
+	if isBinary(a) || isBinary(b) {
+		return 0
+	}
+	la, lb := SplitLines(string(a)), SplitLines(string(b))
+	// The ratio cannot exceed 2*min/(len(a)+len(b)).
+	if total := len(la) + len(lb); total == 0 || 200*min(len(la), len(lb))/total < threshold {
+		return 0
+	}
+	return int(SequenceRatio(la, lb) * 100)
+}
+
+// treeFileDiff builds the FileDiff turning o into f. A zero o is a creation
+// and a zero f a deletion.
+func treeFileDiff(o, f treeFile, opts TreeDiffOptions) FileDiff {
+	fd := FileDiff{Git: true, OldName: "a/" + o.path, NewName: "b/" + f.path, OldMode: o.mode, NewMode: f.mode}
+	switch {
+	case o.path == "":
+		fd.OldName, fd.NewFile, fd.OldMode = "a/"+f.path, true, ""
+	case f.path == "":
+		fd.NewName, fd.DeletedFile, fd.NewMode = "b/"+o.path, true, ""
+	}
+	fd.FromFile, fd.ToFile = fd.OldName, fd.NewName
+	if fd.NewFile {
+		fd.FromFile = devNull
+	}
+	if fd.DeletedFile {
+		fd.ToFile = devNull
+	}
+
+	if bytes.Equal(o.data, f.data) && o.path != "" && f.path != "" {
+		return fd
+	}
+	fd.Index = gitBlobHash(o.data, o.path == "") + ".." + gitBlobHash(f.data, f.path == "")
+	if fd.OldMode == fd.NewMode {
+		fd.Index += " " + fd.OldMode
+	}
+	if isBinary(o.data) || isBinary(f.data) {
+		fd.Binary = true
+		return fd
+	}
+	input := DiffInput{
+		A:       SplitLines(string(o.data)),
+		B:       SplitLines(string(f.data)),
+		Context: opts.Context,
+	}
+	if opts.FuncContext {
+		name := f.path
+		if name == "" {
+			name = o.path
+		}
+		input.FuncPattern = FuncPatternFor(name)
+		if re, ok := opts.FuncPatterns[path.Ext(name)]; ok {
+			input.FuncPattern = re
+		}
+	}
+	d := UnifiedDiff(input)
+	fd.Hunks = d.Hunks
+	return fd
+}
+
+// gitBlobHash returns the abbreviated git object name of data, or zeros for
+// a missing file.
+func gitBlobHash(data []byte, missing bool) string {
+	if missing {
+		return "0000000"
+	}
+	h := sha1.New()
+	fmt.Fprintf(h, "blob %d\x00", len(data))
+	h.Write(data)
+	return fmt.Sprintf("%x", h.Sum(nil))[:7]
+}
+
+// isBinary reports whether data looks binary, using git's heuristic of a
+// NUL byte in the first 8000 bytes.
+func isBinary(data []byte) bool {
+	return bytes.IndexByte(data[:min(len(data), 8000)], 0) >= 0
+}
+
+// treeSortKey orders tree diffs by the path they produce.
+func treeSortKey(f FileDiff) string {
+	if f.DeletedFile {
+		return stripPath(f.OldName, 1)
+	}
+	return stripPath(f.NewName, 1)
+}
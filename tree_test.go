@@ -0,0 +1,250 @@
+package difflib_test
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	difflib "github.com/njchilds90/go-difflib"
+)
+
+// bigFile returns n distinct lines with the given prefix.
+func bigFile(prefix string, n int) string {
+	var b strings.Builder
+	for i := 0; i < n; i++ {
+		b.WriteString(prefix)
+		b.WriteString(strings.Repeat("x", i%7))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+func mapFS(files map[string]string) fstest.MapFS {
+	m := fstest.MapFS{}
+	for name, content := range files {
+		m[name] = &fstest.MapFile{Data: []byte(content), Mode: 0o644}
+	}
+	return m
+}
+
+func TestDiffTrees(t *testing.T) {
+	body := bigFile("line ", 20)
+	oldTree := mapFS(map[string]string{
+		"keep.txt":     "same\n",
+		"mod.txt":      "a\nb\nc\n",
+		"gone.txt":     "bye\n",
+		"dir/old.go":   body,
+		"src/util.go":  body + "tail\n",
+		"bin/data.bin": "\x00\x01",
+	})
+	newTree := mapFS(map[string]string{
+		"keep.txt":     "same\n",
+		"mod.txt":      "a\nB\nc\n",
+		"added.txt":    "hello\n",
+		"dir/new.go":   body + "extra\n",
+		"src/util.go":  body + "tail\n",
+		"src/copy.go":  body + "tail\n",
+		"bin/data.bin": "\x00\x02",
+	})
+
+	tests := []struct {
+		name string
+		opts difflib.TreeDiffOptions
+		want []string // "flags path" per file
+	}{
+		{"plain", difflib.TreeDiffOptions{}, []string{
+			"N added.txt", "B bin/data.bin", "N dir/new.go", "D dir/old.go", "D gone.txt", "M mod.txt", "N src/copy.go",
+		}},
+		{"renames", difflib.TreeDiffOptions{DetectRenames: true}, []string{
+			"N added.txt", "B bin/data.bin", "R97 dir/old.go => dir/new.go", "D gone.txt", "M mod.txt", "N src/copy.go",
+		}},
+		{"renames and copies", difflib.TreeDiffOptions{DetectRenames: true, DetectCopies: true}, []string{
+			"N added.txt", "B bin/data.bin", "R97 dir/old.go => dir/new.go", "D gone.txt", "M mod.txt", "C100 src/util.go => src/copy.go",
+		}},
+		{"high threshold", difflib.TreeDiffOptions{DetectRenames: true, RenameThreshold: 99}, []string{
+			"N added.txt", "B bin/data.bin", "N dir/new.go", "D dir/old.go", "D gone.txt", "M mod.txt", "N src/copy.go",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ps, err := difflib.DiffTrees(oldTree, newTree, tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, f := range ps.Files {
+				got = append(got, describeFileDiff(f))
+			}
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("files =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func describeFileDiff(f difflib.FileDiff) string {
+	oldName, newName := strings.TrimPrefix(f.OldName, "a/"), strings.TrimPrefix(f.NewName, "b/")
+	switch {
+	case f.Binary:
+		return "B " + newName
+	case f.NewFile:
+		return "N " + newName
+	case f.DeletedFile:
+		return "D " + oldName
+	case f.Rename:
+		return "R" + strconv.Itoa(f.Similarity) + " " + oldName + " => " + newName
+	case f.Copy:
+		return "C" + strconv.Itoa(f.Similarity) + " " + oldName + " => " + newName
+	}
+	return "M " + newName
+}
+
+func TestDiffTreesRoundTrip(t *testing.T) {
+	oldFiles := map[string]string{
+		"a.txt":     "one\ntwo\nthree\n",
+		"moved.txt": bigFile("m", 10),
+		"del.txt":   "x\n",
+	}
+	newFiles := map[string]string{
+		"a.txt":          "one\n2\nthree\n",
+		"sub/moved.txt":  bigFile("m", 10) + "more\n",
+		"sub/copied.txt": "one\ntwo\nthree\n",
+		"new.txt":        "fresh\n",
+	}
+	oldDir, newDir := t.TempDir(), t.TempDir()
+	writeFiles(t, oldDir, oldFiles)
+	writeFiles(t, newDir, newFiles)
+
+	ps, err := difflib.DiffTrees(os.DirFS(oldDir), os.DirFS(newDir), difflib.TreeDiffOptions{DetectRenames: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := ps.String()
+	if !strings.Contains(text, "rename from moved.txt\nrename to sub/moved.txt\n") {
+		t.Errorf("patch lacks rename headers:\n%s", text)
+	}
+	parsed, err := difflib.ParsePatchSet(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := parsed.ApplyFS(difflib.DirFS(oldDir), difflib.ApplyFSOptions{Strip: 1}); err != nil {
+		t.Fatal(err)
+	}
+	for name, want := range newFiles {
+		if got, ok := readFile(t, oldDir, name); !ok || got != want {
+			t.Errorf("%s = %q, %v; want %q", name, got, ok, want)
+		}
+	}
+	for _, name := range []string{"moved.txt", "del.txt"} {
+		if _, err := os.Stat(filepath.Join(oldDir, name)); !os.IsNotExist(err) {
+			t.Errorf("%s still exists", name)
+		}
+	}
+}
+
+func TestDiffTreesModeChange(t *testing.T) {
+	oldTree := fstest.MapFS{"run.sh": {Data: []byte("echo\n"), Mode: 0o644}}
+	newTree := fstest.MapFS{"run.sh": {Data: []byte("echo\n"), Mode: 0o755}}
+	ps, err := difflib.DiffTrees(oldTree, newTree, difflib.TreeDiffOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "diff --git a/run.sh b/run.sh\nold mode 100644\nnew mode 100755\n"
+	if got := ps.String(); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestDiffTreesFilters(t *testing.T) {
+	oldTree := mapFS(map[string]string{
+		"main.go":             "package main\n",
+		"vendor/lib/lib.go":   "v1\n",
+		"docs/guide/intro.md": "old\n",
+		"docs/readme.txt":     "old\n",
+		"build/out.bin":       "old\n",
+		"app.log":             "old\n",
+		"keep.log":            "old\n",
+		"sub/tmp/cache":       "old\n",
+		".git/HEAD":           "ref: a\n",
+	})
+	newTree := mapFS(map[string]string{
+		"main.go":             "package main\n\nfunc main() {}\n",
+		"vendor/lib/lib.go":   "v2\n",
+		"docs/guide/intro.md": "new\n",
+		"docs/readme.txt":     "new\n",
+		"build/out.bin":       "new\n",
+		"app.log":             "new\n",
+		"keep.log":            "new\n",
+		"sub/tmp/cache":       "new\n",
+		".git/HEAD":           "ref: b\n",
+	})
+	ignore := "# build output\n/build/\n*.log\n!keep.log\ntmp/\n"
+	for _, tree := range []fstest.MapFS{oldTree, newTree} {
+		tree[".gitignore"] = &fstest.MapFile{Data: []byte(ignore)}
+	}
+
+	tests := []struct {
+		name string
+		opts difflib.TreeDiffOptions
+		want string
+	}{
+		{"no filters", difflib.TreeDiffOptions{},
+			".git/HEAD app.log build/out.bin docs/guide/intro.md docs/readme.txt keep.log main.go sub/tmp/cache vendor/lib/lib.go"},
+		{"exclude dir", difflib.TreeDiffOptions{Exclude: []string{"vendor/", ".git", "*.log"}},
+			"build/out.bin docs/guide/intro.md docs/readme.txt main.go sub/tmp/cache"},
+		{"include", difflib.TreeDiffOptions{Include: []string{"docs/**/*.md", "*.go"}},
+			"docs/guide/intro.md main.go vendor/lib/lib.go"},
+		{"include and exclude", difflib.TreeDiffOptions{Include: []string{"*.go"}, Exclude: []string{"vendor"}},
+			"main.go"},
+		{"gitignore", difflib.TreeDiffOptions{GitIgnore: true},
+			"docs/guide/intro.md docs/readme.txt keep.log main.go vendor/lib/lib.go"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ps, err := difflib.DiffTrees(oldTree, newTree, tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, f := range ps.Files {
+				got = append(got, strings.TrimPrefix(f.NewName, "b/"))
+			}
+			if strings.Join(got, " ") != tt.want {
+				t.Errorf("files = %q, want %q", strings.Join(got, " "), tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffTreesNestedGitignore(t *testing.T) {
+	oldTree := mapFS(map[string]string{
+		"pkg/.gitignore": "gen_*.go\n/local.txt\n",
+		"pkg/gen_a.go":   "1\n",
+		"pkg/a.go":       "1\n",
+		"pkg/local.txt":  "1\n",
+		"local.txt":      "1\n",
+		"gen_top.go":     "1\n",
+	})
+	newTree := mapFS(map[string]string{
+		"pkg/.gitignore": "gen_*.go\n/local.txt\n",
+		"pkg/gen_a.go":   "2\n",
+		"pkg/a.go":       "2\n",
+		"pkg/local.txt":  "2\n",
+		"local.txt":      "2\n",
+		"gen_top.go":     "2\n",
+	})
+	ps, err := difflib.DiffTrees(oldTree, newTree, difflib.TreeDiffOptions{GitIgnore: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, f := range ps.Files {
+		got = append(got, strings.TrimPrefix(f.NewName, "b/"))
+	}
+	if want := "gen_top.go local.txt pkg/a.go"; strings.Join(got, " ") != want {
+		t.Errorf("files = %q, want %q", strings.Join(got, " "), want)
+	}
+}
@@ -0,0 +1,184 @@
+package difflib
+
+import (
+	"bufio"
+	"errors"
+	"io"
+)
+
+// UnifiedWriterOptions controls NewUnifiedWriter.
+type UnifiedWriterOptions struct {
+	// FromFile and ToFile are the labels for the two inputs.
+	FromFile, ToFile string
+	// FromDate and ToDate, if set, follow the labels after a tab. See
+	// DiffInput.FromDate.
+	FromDate, ToDate string
+	// Context is the number of unchanged lines to include around each change.
+	// Defaults to 3 if zero; use NoContext for none.
+	Context int
+}
+
+// UnifiedWriter writes a unified diff while it is still being computed. It
+// is fed either opcodes, with the lines they cover, or ready-made hunks, and
+// writes each hunk to the underlying writer as soon as it is complete, so
+// memory use is bounded by the largest hunk rather than by the inputs. The
+// --- and +++ headers are written before the first hunk; nothing is written
+// if there are no changes.
+//
+// Example:
+//
+//	uw := difflib.NewUnifiedWriter(os.Stdout, difflib.UnifiedWriterOptions{FromFile: "a", ToFile: "b"})
+//	if err := difflib.WalkOpCodes(a, b, uw.WriteOpCode); err != nil {
+//	    return err
+//	}
+//	return uw.Close()
+type UnifiedWriter struct {
+	bw     *bufio.Writer
+	opts   UnifiedWriterOptions
+	ctx    int
+	header bool // whether the file header has been written
+	byOps  bool // whether WriteOpCode has been used
+	byHunk bool // whether WriteHunk has been used
+	err    error
+
+	// open reports whether cur is a hunk being built from opcodes; its
+	// header is filled in from i and j, the positions of its first line in
+	// a and b, when it is written.
+	open bool
+	cur  Hunk
+	i, j int
+	// lead holds the last equal lines before the next hunk and tail the
+	// equal lines after the open hunk, at most ctx and 2*ctx lines.
+	lead, tail []string
+}
+
+// NewUnifiedWriter returns a UnifiedWriter writing to w.
+func NewUnifiedWriter(w io.Writer, opts UnifiedWriterOptions) *UnifiedWriter {
+	return &UnifiedWriter{bw: bufio.NewWriter(w), opts: opts, ctx: contextLines(opts.Context)}
+}
+
+// WriteOpCode adds the next opcode of the diff together with the lines of
+// a and b it covers, the arguments WalkOpCodes passes to its callback.
+// Opcodes must be given in order and cover both inputs contiguously. Only
+// the lines needed for the output are kept, so the slices may be reused
+// once WriteOpCode returns.
+func (u *UnifiedWriter) WriteOpCode(op OpCode, aLines, bLines []string) error {
+	if u.err != nil {
+		return u.err
+	}
+	if u.byHunk {
+		return errors.New("difflib: WriteOpCode after WriteHunk")
+	}
+	u.byOps = true
+	if op.Tag == OpEqual {
+		switch {
+		case !u.open:
+			u.lead = keepLast(u.lead, aLines, u.ctx)
+		case len(u.tail)+len(aLines) <= 2*u.ctx:
+			u.tail = append(u.tail, aLines...)
+		default:
+			// The unchanged lines are too many to join two hunks: end the
+			// open one and keep the lines that may start the next.
+			n := min(u.ctx, len(u.tail))
+			u.appendLines(' ', u.tail[:n])
+			u.appendLines(' ', aLines[:u.ctx-n])
+			u.flushHunk()
+			u.lead, u.tail = keepLast(u.tail, aLines, u.ctx), nil
+		}
+		return u.err
+	}
+	if !u.open {
+		u.open = true
+		u.i, u.j = op.I1-len(u.lead), op.J1-len(u.lead)
+		u.appendLines(' ', u.lead)
+		u.lead = u.lead[:0]
+	} else {
+		u.appendLines(' ', u.tail)
+		u.tail = u.tail[:0]
+	}
+	u.appendLines('-', aLines)
+	u.appendLines('+', bLines)
+	return u.err
+}
+
+// WriteHunk writes h as the next hunk of the diff. Hunks must be given in
+// order and cannot be mixed with WriteOpCode on one writer.
+func (u *UnifiedWriter) WriteHunk(h Hunk) error {
+	if u.err != nil {
+		return u.err
+	}
+	if u.byOps {
+		return errors.New("difflib: WriteHunk after WriteOpCode")
+	}
+	u.byHunk = true
+	u.writeHunk(h)
+	return u.err
+}
+
+// Close writes the last hunk, if one is pending, and flushes the output. It
+// does not close the underlying writer.
+func (u *UnifiedWriter) Close() error {
+	if u.err == nil && u.open {
+		u.appendLines(' ', u.tail[:min(len(u.tail), u.ctx)])
+		u.tail = u.tail[:0]
+		u.flushHunk()
+	}
+	if u.err == nil {
+		u.err = u.bw.Flush()
+	}
+	return u.err
+}
+
+// appendLines adds lines to the open hunk with prefix tag.
+func (u *UnifiedWriter) appendLines(tag byte, lines []string) {
+	for _, l := range lines {
+		u.cur.Lines = append(u.cur.Lines, string(tag)+l)
+		if tag != '+' {
+			u.cur.OldLines++
+		}
+		if tag != '-' {
+			u.cur.NewLines++
+		}
+	}
+}
+
+// flushHunk completes the header of the open hunk and writes it.
+func (u *UnifiedWriter) flushHunk() {
+	h := u.cur
+	h.OldStart, h.NewStart = u.i+1, u.j+1
+	if h.OldLines == 0 {
+		h.OldStart--
+	}
+	if h.NewLines == 0 {
+		h.NewStart--
+	}
+	u.open = false
+	u.cur = Hunk{}
+	u.writeHunk(h)
+}
+
+// writeHunk writes h, preceded by the file header if it is the first, and
+// flushes it to the underlying writer.
+func (u *UnifiedWriter) writeHunk(h Hunk) {
+	cw := &countWriter{w: u.bw}
+	if !u.header {
+		u.header = true
+		cw.write("--- " + fileLabel(u.opts.FromFile, u.opts.FromDate) + "\n")
+		cw.write("+++ " + fileLabel(u.opts.ToFile, u.opts.ToDate) + "\n")
+	}
+	cw.writeTo(h)
+	if cw.err == nil {
+		cw.err = u.bw.Flush()
+	}
+	u.err = cw.err
+}
+
+// keepLast returns the last n lines of dst followed by lines, reusing the
+// storage of dst.
+func keepLast(dst, lines []string, n int) []string {
+	if len(lines) >= n {
+		return append(dst[:0], lines[len(lines)-n:]...)
+	}
+	dst = append(dst, lines...)
+	return append(dst[:0], dst[max(0, len(dst)-n):]...)
+}
@@ -0,0 +1,87 @@
+package difflib_test
+
+import (
+	"errors"
+	"math/rand"
+	"strings"
+	"testing"
+
+	difflib "github.com/njchilds90/go-difflib"
+)
+
+func TestUnifiedWriterMatchesUnifiedDiff(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for iter := 0; iter < 300; iter++ {
+		a, b := randomLines(rng, rng.Intn(40)), randomLines(rng, rng.Intn(40))
+		ctx := []int{0, difflib.NoContext, 1, 2}[iter%4]
+		var got strings.Builder
+		uw := difflib.NewUnifiedWriter(&got, difflib.UnifiedWriterOptions{FromFile: "a", ToFile: "b", Context: ctx})
+		// Split equal opcodes in two so that gaps arrive in pieces.
+		err := difflib.WalkOpCodes(a, b, func(op difflib.OpCode, aLines, bLines []string) error {
+			if op.Tag != difflib.OpEqual || len(aLines) < 2 {
+				return uw.WriteOpCode(op, aLines, bLines)
+			}
+			k := rng.Intn(len(aLines))
+			first, second := op, op
+			first.I2, first.J2 = op.I1+k, op.J1+k
+			second.I1, second.J1 = first.I2, first.J2
+			if err := uw.WriteOpCode(first, aLines[:k], bLines[:k]); err != nil {
+				return err
+			}
+			return uw.WriteOpCode(second, aLines[k:], bLines[k:])
+		})
+		if err == nil {
+			err = uw.Close()
+		}
+		want := difflib.UnifiedDiff(difflib.DiffInput{A: a, B: b, FromFile: "a", ToFile: "b", Context: ctx}).String()
+		if err != nil || got.String() != want {
+			t.Fatalf("iteration %d, context %d: UnifiedWriter wrote (%v)\n%s\nwant\n%s", iter, ctx, err, got.String(), want)
+		}
+	}
+}
+
+func TestUnifiedWriterStreams(t *testing.T) {
+	a := numbered(20)
+	b := numbered(20)
+	b[1] = "two\n"
+	b[17] = "eighteen\n"
+	var out strings.Builder
+	uw := difflib.NewUnifiedWriter(&out, difflib.UnifiedWriterOptions{FromFile: "a", ToFile: "b", Context: 1})
+	codes := difflib.GetOpCodes(a, b)
+	for _, op := range codes[:3] {
+		uw.WriteOpCode(op, a[op.I1:op.I2], b[op.J1:op.J2])
+	}
+	if want := "--- a\n+++ b\n@@ -1,3 +1,3 @@\n line 1\n-line 2\n+two\n line 3\n"; out.String() != want {
+		t.Errorf("after the first gap the output is\n%s\nwant\n%s", out.String(), want)
+	}
+	if err := uw.WriteHunk(difflib.Hunk{}); err == nil {
+		t.Error("WriteHunk after WriteOpCode succeeded")
+	}
+
+	d := difflib.UnifiedDiff(difflib.DiffInput{A: a, B: b, FromFile: "a", ToFile: "b"})
+	out.Reset()
+	uw = difflib.NewUnifiedWriter(&out, difflib.UnifiedWriterOptions{FromFile: "a", ToFile: "b"})
+	for _, h := range d.Hunks {
+		if err := uw.WriteHunk(h); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := uw.Close(); err != nil || out.String() != d.String() {
+		t.Errorf("WriteHunk output (%v)\n%s\nwant\n%s", err, out.String(), d.String())
+	}
+
+	out.Reset()
+	uw = difflib.NewUnifiedWriter(&out, difflib.UnifiedWriterOptions{})
+	uw.WriteOpCode(difflib.OpCode{Tag: difflib.OpEqual, I2: 2, J2: 2}, a[:2], a[:2])
+	if err := uw.Close(); err != nil || out.Len() != 0 {
+		t.Errorf("identical input wrote %q, %v", out.String(), err)
+	}
+
+	uw = difflib.NewUnifiedWriter(&failWriter{}, difflib.UnifiedWriterOptions{})
+	if err := uw.WriteHunk(d.Hunks[0]); !errors.Is(err, errWriteFailed) {
+		t.Errorf("WriteHunk to a failing writer: %v", err)
+	}
+	if err := uw.Close(); !errors.Is(err, errWriteFailed) {
+		t.Errorf("Close after a failed write: %v", err)
+	}
+}
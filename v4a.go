@@ -0,0 +1,386 @@
+package difflib
+
+import (
+	"fmt"
+	"strings"
+)
+
+// V4A patch markers.
+const (
+	v4aBegin  = "*** Begin Patch"
+	v4aEnd    = "*** End Patch"
+	v4aUpdate = "*** Update File: "
+	v4aAdd    = "*** Add File: "
+	v4aDelete = "*** Delete File: "
+	v4aMove   = "*** Move to: "
+	v4aEOF    = "*** End of File"
+)
+
+// ParseV4A parses a patch in the V4A format that OpenAI models produce for
+// their apply_patch tool:
+//
+//	*** Begin Patch
+//	*** Update File: src/app.py
+//	@@ def greet():
+//	-    print("hi")
+//	+    print("hello")
+//	*** Add File: NOTES.md
+//	+# Notes
+//	*** Delete File: old.txt
+//	*** End Patch
+//
+// V4A chunks carry no line numbers; they are located by their context
+// lines, after the "@@" anchor lines if any, matching exactly, then
+// ignoring trailing whitespace, then ignoring surrounding whitespace. A
+// chunk ending in "*** End of File" is matched at the end of the file.
+// Locating them needs the files, so read is called with the path of every
+// updated or deleted file to get its current contents.
+//
+// The result is a git-style PatchSet with "a/" and "b/" path prefixes and
+// line-numbered hunks whose context is taken from the files, so it can be
+// printed as a unified diff or applied with ApplyFS and Strip: 1. A "***
+// Move to:" line makes the file a rename.
+//
+// Example:
+//
+//	ps, err := difflib.ParseV4A(patch, func(name string) (string, error) {
+//	    data, err := os.ReadFile(filepath.Join(root, name))
+//	    return string(data), err
+//	})
+//	if err != nil {
+//	    return err
+//	}
+//	err = ps.ApplyFS(difflib.DirFS(root), difflib.ApplyFSOptions{Strip: 1})
+func ParseV4A(patch string, read func(path string) (string, error)) (*PatchSet, error) {
+	p := &v4aParser{read: read}
+	for _, l := range SplitLines(patch) {
+		p.lines = append(p.lines, strings.TrimRight(l, "\r\n"))
+	}
+	for p.i < len(p.lines) && strings.TrimSpace(p.lines[p.i]) == "" {
+		p.i++
+	}
+	if p.i == len(p.lines) || strings.TrimSpace(p.lines[p.i]) != v4aBegin {
+		return nil, fmt.Errorf("difflib: v4a patch must start with %q", v4aBegin)
+	}
+	p.i++
+	ps := &PatchSet{}
+	for {
+		if p.i == len(p.lines) {
+			return nil, fmt.Errorf("difflib: v4a patch must end with %q", v4aEnd)
+		}
+		line := p.lines[p.i]
+		var f FileDiff
+		var err error
+		switch {
+		case strings.TrimSpace(line) == v4aEnd:
+			return ps, nil
+		case strings.HasPrefix(line, v4aUpdate):
+			f, err = p.update(strings.TrimSpace(line[len(v4aUpdate):]))
+		case strings.HasPrefix(line, v4aAdd):
+			f, err = p.add(strings.TrimSpace(line[len(v4aAdd):]))
+		case strings.HasPrefix(line, v4aDelete):
+			f, err = p.delete(strings.TrimSpace(line[len(v4aDelete):]))
+		case strings.TrimSpace(line) == "":
+			p.i++
+			continue
+		default:
+			return nil, p.errorf("unexpected line %q", line)
+		}
+		if err != nil {
+			return nil, err
+		}
+		ps.Files = append(ps.Files, f)
+	}
+}
+
+// v4aParser holds the state of ParseV4A.
+type v4aParser struct {
+	lines []string
+	i     int
+	read  func(string) (string, error)
+}
+
+func (p *v4aParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("difflib: v4a patch line %d: %s", p.i+1, fmt.Sprintf(format, args...))
+}
+
+// atFileEnd reports whether the current line ends the current file section.
+func (p *v4aParser) atFileEnd() bool {
+	return p.i == len(p.lines) || strings.HasPrefix(p.lines[p.i], "*** ") && p.lines[p.i] != v4aEOF
+}
+
+// readFile returns the lines of the named file.
+func (p *v4aParser) readFile(path string) ([]string, error) {
+	if path == "" {
+		return nil, p.errorf("missing file path")
+	}
+	data, err := p.read(path)
+	if err != nil {
+		return nil, fmt.Errorf("difflib: v4a patch: reading %s: %w", path, err)
+	}
+	return SplitLines(data), nil
+}
+
+func (p *v4aParser) add(path string) (FileDiff, error) {
+	if path == "" {
+		return FileDiff{}, p.errorf("missing file path")
+	}
+	f := v4aFileDiff(path, path)
+	f.FromFile, f.NewFile, f.NewMode = devNull, true, "100644"
+	var h Hunk
+	for p.i++; !p.atFileEnd(); p.i++ {
+		line := p.lines[p.i]
+		if !strings.HasPrefix(line, "+") {
+			return f, p.errorf("added file line must start with \"+\": %q", line)
+		}
+		h.Lines = append(h.Lines, line+"\n")
+	}
+	if len(h.Lines) > 0 {
+		h.NewStart, h.NewLines = 1, len(h.Lines)
+		f.Hunks = []Hunk{h}
+	}
+	return f, nil
+}
+
+func (p *v4aParser) delete(path string) (FileDiff, error) {
+	old, err := p.readFile(path)
+	if err != nil {
+		return FileDiff{}, err
+	}
+	f := v4aFileDiff(path, path)
+	f.ToFile, f.DeletedFile, f.OldMode = devNull, true, "100644"
+	if len(old) > 0 {
+		h := Hunk{OldStart: 1, OldLines: len(old)}
+		for _, l := range old {
+			h.Lines = append(h.Lines, "-"+l)
+		}
+		f.Hunks = []Hunk{h}
+	}
+	p.i++
+	return f, nil
+}
+
+func (p *v4aParser) update(path string) (FileDiff, error) {
+	old, err := p.readFile(path)
+	if err != nil {
+		return FileDiff{}, err
+	}
+	p.i++
+	newPath := path
+	if p.i < len(p.lines) && strings.HasPrefix(p.lines[p.i], v4aMove) {
+		newPath = strings.TrimSpace(p.lines[p.i][len(v4aMove):])
+		p.i++
+	}
+	f := v4aFileDiff(path, newPath)
+	text := make([]string, len(old))
+	for k, l := range old {
+		text[k] = strings.TrimRight(l, "\r\n")
+	}
+	pos, delta := 0, 0
+	for !p.atFileEnd() {
+		h, next, err := p.chunk(old, text, pos, delta)
+		if err != nil {
+			return f, err
+		}
+		f.Hunks = append(f.Hunks, h)
+		pos, delta = next, delta+h.NewLines-h.OldLines
+	}
+	if newPath != path {
+		result, _, err := applyHunks(old, f.Hunks, ApplyOptions{})
+		if err != nil {
+			return f, &contextError{context: "v4a patch: " + path, err: err}
+		}
+		f.Rename = true
+		f.Similarity = treeSimilarity([]byte(JoinLines(old)), []byte(JoinLines(result)), 0)
+	} else if len(f.Hunks) == 0 {
+		return f, p.errorf("no changes for %s", path)
+	}
+	return f, nil
+}
+
+// chunk parses the chunk at the current line, locates it in the file lines
+// old (text holds them without line endings) at or after pos, and returns
+// it as a hunk with the position after it.
+func (p *v4aParser) chunk(old, text []string, pos, delta int) (Hunk, int, error) {
+	for p.i < len(p.lines) && strings.HasPrefix(p.lines[p.i], "@@") {
+		if anchor := strings.TrimSpace(p.lines[p.i][2:]); anchor != "" {
+			at := v4aFind(text, []string{anchor}, pos, false)
+			if at < 0 {
+				return Hunk{}, 0, p.errorf("anchor %q not found", anchor)
+			}
+			pos = at + 1
+		}
+		p.i++
+	}
+	start := p.i
+	var ops []byte
+	var body, want []string
+	eof := false
+	for !p.atFileEnd() && !strings.HasPrefix(p.lines[p.i], "@@") {
+		line := p.lines[p.i]
+		p.i++
+		if line == v4aEOF {
+			eof = true
+			break
+		}
+		if line == "" {
+			line = " "
+		}
+		if op := line[0]; op != ' ' && op != '-' && op != '+' {
+			p.i--
+			return Hunk{}, 0, p.errorf("chunk line must start with \" \", \"-\" or \"+\": %q", line)
+		}
+		ops = append(ops, line[0])
+		body = append(body, line[1:])
+		if line[0] != '+' {
+			want = append(want, line[1:])
+		}
+	}
+	if len(ops) == 0 {
+		return Hunk{}, 0, p.errorf("empty chunk")
+	}
+	at := v4aFind(text, want, pos, eof)
+	if at < 0 {
+		p.i = start
+		return Hunk{}, 0, p.errorf("context not found in file")
+	}
+	h := Hunk{OldStart: at + 1, NewStart: at + 1 + delta}
+	k := at
+	for n, op := range ops {
+		if op == '+' {
+			h.Lines = append(h.Lines, "+"+body[n]+v4aEnding(old, k))
+			h.NewLines++
+			continue
+		}
+		h.Lines = append(h.Lines, string(op)+old[k])
+		k++
+		h.OldLines++
+		if op == ' ' {
+			h.NewLines++
+		}
+	}
+	if h.OldLines == 0 {
+		h.OldStart--
+	}
+	if h.NewLines == 0 {
+		h.NewStart--
+	}
+	return h, k, nil
+}
+
+// v4aEnding returns the line ending for a line added before old[k]: that
+// of the old line it is inserted next to, so that added lines in a CRLF
+// file keep CRLF endings.
+func v4aEnding(old []string, k int) string {
+	if k >= len(old) {
+		k--
+	}
+	if k >= 0 && strings.HasSuffix(old[k], "\r\n") {
+		return "\r\n"
+	}
+	return "\n"
+}
+
+// v4aFind returns the first index at or after start where want matches
+// lines, trying exact, trailing-whitespace-insensitive and then
+// whitespace-insensitive comparison, or -1. Of equally good matches it thus
+// picks the one nearest after start, the line after the chunk's anchor or
+// the previous chunk. With eof, a match at the end of lines is tried first.
+func v4aFind(lines, want []string, start int, eof bool) int {
+	if len(want) == 0 {
+		if eof {
+			return len(lines)
+		}
+		return min(start, len(lines))
+	}
+	norms := []func(string) string{
+		func(s string) string { return s },
+		func(s string) string { return strings.TrimRight(s, " \t") },
+		strings.TrimSpace,
+	}
+	match := func(at int, norm func(string) string) bool {
+		for k, w := range want {
+			if norm(lines[at+k]) != norm(w) {
+				return false
+			}
+		}
+		return true
+	}
+	for _, norm := range norms {
+		if end := len(lines) - len(want); eof && end >= start && match(end, norm) {
+			return end
+		}
+		for at := start; at+len(want) <= len(lines); at++ {
+			if match(at, norm) {
+				return at
+			}
+		}
+	}
+	return -1
+}
+
+// v4aFileDiff returns a git-style FileDiff for the given paths.
+func v4aFileDiff(oldPath, newPath string) FileDiff {
+	return FileDiff{
+		DiffResult: DiffResult{FromFile: "a/" + oldPath, ToFile: "b/" + newPath},
+		OldName:    "a/" + oldPath,
+		NewName:    "b/" + newPath,
+		Git:        true,
+	}
+}
+
+// FormatV4A renders a patch set in the V4A format read by ParseV4A and by
+// OpenAI's apply_patch tool. strip leading path components are removed
+// from the file names, as with ApplyFSOptions.Strip; use 1 for patches
+// from git. Each hunk becomes a chunk introduced by a bare "@@" line. An
+// error is returned for binary diffs and copies, which V4A cannot express.
+// V4A has no "\ No newline at end of file" marker either, so such markers
+// are dropped and the patch, once parsed back, ends the file with a newline.
+//
+// Example:
+//
+//	ps, _ := difflib.ParsePatchSet(gitDiff)
+//	v4a, err := difflib.FormatV4A(ps, 1)
+func FormatV4A(ps *PatchSet, strip int) (string, error) {
+	var b strings.Builder
+	b.WriteString(v4aBegin + "\n")
+	for _, f := range ps.Files {
+		oldPath, newPath := stripPath(f.OldName, strip), stripPath(f.NewName, strip)
+		switch {
+		case f.Binary:
+			return "", fmt.Errorf("difflib: %s: binary diffs cannot be written as v4a", newPath)
+		case f.Copy:
+			return "", fmt.Errorf("difflib: %s: copies cannot be written as v4a", newPath)
+		case f.NewFile:
+			b.WriteString(v4aAdd + newPath + "\n")
+			for _, h := range f.Hunks {
+				writeV4ALines(&b, h.Lines)
+			}
+		case f.DeletedFile:
+			b.WriteString(v4aDelete + oldPath + "\n")
+		default:
+			b.WriteString(v4aUpdate + oldPath + "\n")
+			if newPath != oldPath {
+				b.WriteString(v4aMove + newPath + "\n")
+			}
+			for _, h := range f.Hunks {
+				b.WriteString("@@\n")
+				writeV4ALines(&b, h.Lines)
+			}
+		}
+	}
+	b.WriteString(v4aEnd + "\n")
+	return b.String(), nil
+}
+
+// writeV4ALines writes hunk lines without their line endings, dropping
+// "\ No newline at end of file" markers.
+func writeV4ALines(b *strings.Builder, lines []string) {
+	for _, l := range lines {
+		if strings.HasPrefix(l, `\`) {
+			continue
+		}
+		b.WriteString(strings.TrimRight(l, "\r\n"))
+		b.WriteByte('\n')
+	}
+}
@@ -0,0 +1,246 @@
+package difflib_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	difflib "github.com/njchilds90/go-difflib"
+)
+
+// readFrom returns a ParseV4A read function over files.
+func readFrom(files map[string]string) func(string) (string, error) {
+	return func(name string) (string, error) {
+		s, ok := files[name]
+		if !ok {
+			return "", os.ErrNotExist
+		}
+		return s, nil
+	}
+}
+
+func TestParseV4A(t *testing.T) {
+	files := map[string]string{
+		"app.py":  "import os\n\nclass A:\n    def run(self):\n        return 1\n\nclass B:\n    def run(self):\n        return 1\n",
+		"old.txt": "gone\n",
+		"log.txt": "a\nb\nc\n",
+	}
+	patch := `*** Begin Patch
+*** Update File: app.py
+@@ class B:
+@@     def run(self):
+-        return 1
++        return 2
+*** Add File: docs/notes.md
++# Notes
++
++hi
+*** Delete File: old.txt
+*** Update File: log.txt
+*** Move to: logs/log.txt
+@@
+ b
+ c
++d
+*** End of File
+*** End Patch
+`
+	ps, err := difflib.ParseV4A(patch, readFrom(files))
+	if err != nil {
+		t.Fatalf("ParseV4A error: %v", err)
+	}
+	want := `diff --git a/app.py b/app.py
+--- a/app.py
++++ b/app.py
+@@ -9,1 +9,1 @@
+-        return 1
++        return 2
+diff --git a/docs/notes.md b/docs/notes.md
+new file mode 100644
+--- /dev/null
++++ b/docs/notes.md
+@@ -0,0 +1,3 @@
++# Notes
++
++hi
+diff --git a/old.txt b/old.txt
+deleted file mode 100644
+--- a/old.txt
++++ /dev/null
+@@ -1,1 +0,0 @@
+-gone
+diff --git a/log.txt b/logs/log.txt
+similarity index 85%
+rename from log.txt
+rename to logs/log.txt
+--- a/log.txt
++++ b/logs/log.txt
+@@ -2,2 +2,3 @@
+ b
+ c
++d
+`
+	if got := ps.String(); got != want {
+		t.Errorf("ParseV4A =\n%s\nwant\n%s", got, want)
+	}
+
+	dir := t.TempDir()
+	writeFiles(t, dir, files)
+	if err := ps.ApplyFS(difflib.DirFS(dir), difflib.ApplyFSOptions{Strip: 1}); err != nil {
+		t.Fatalf("ApplyFS error: %v", err)
+	}
+	for name, content := range map[string]string{
+		"app.py":        strings.TrimSuffix(files["app.py"], "return 1\n") + "return 2\n",
+		"docs/notes.md": "# Notes\n\nhi\n",
+		"logs/log.txt":  "a\nb\nc\nd\n",
+	} {
+		if got, ok := readFile(t, dir, name); !ok || got != content {
+			t.Errorf("%s = %q (exists=%v), want %q", name, got, ok, content)
+		}
+	}
+	for _, name := range []string{"old.txt", "log.txt"} {
+		if _, ok := readFile(t, dir, name); ok {
+			t.Errorf("%s should have been removed", name)
+		}
+	}
+}
+
+func TestParseV4AFuzzyContext(t *testing.T) {
+	files := map[string]string{"a.txt": "one  \n\ttwo\nthree\n"}
+	patch := "*** Begin Patch\n*** Update File: a.txt\n@@\n one\n-  two\n+2\n three\n*** End Patch\n"
+	ps, err := difflib.ParseV4A(patch, readFrom(files))
+	if err != nil {
+		t.Fatalf("ParseV4A error: %v", err)
+	}
+	got, err := difflib.ApplyPatch(difflib.SplitLines(files["a.txt"]), ps.Files[0].DiffResult.String())
+	if err != nil {
+		t.Fatalf("ApplyPatch error: %v", err)
+	}
+	if s := difflib.JoinLines(got); s != "one  \n2\nthree\n" {
+		t.Errorf("result = %q", s)
+	}
+}
+
+func TestParseV4ACRLF(t *testing.T) {
+	files := map[string]string{"a.txt": "one\r\ntwo\r\nthree\r\n"}
+	patch := "*** Begin Patch\n*** Update File: a.txt\n@@\n one\n-two\n+2\n+2b\n three\n+four\n*** End Patch\n"
+	ps, err := difflib.ParseV4A(patch, readFrom(files))
+	if err != nil {
+		t.Fatalf("ParseV4A error: %v", err)
+	}
+	got, err := difflib.ApplyPatch(difflib.SplitLines(files["a.txt"]), ps.Files[0].DiffResult.String())
+	if err != nil {
+		t.Fatalf("ApplyPatch error: %v", err)
+	}
+	if s, want := difflib.JoinLines(got), "one\r\n2\r\n2b\r\nthree\r\nfour\r\n"; s != want {
+		t.Errorf("result = %q, want %q", s, want)
+	}
+}
+
+func TestParseV4ANearestMatch(t *testing.T) {
+	// Of equally good matches, the chunk goes to the one nearest after its
+	// anchor, not the first in the file.
+	files := map[string]string{"a.txt": "x\ny\nfunc b\nz\nx\ny\nx\ny\n"}
+	patch := "*** Begin Patch\n*** Update File: a.txt\n@@ func b\n-x\n+X\n y\n*** End Patch\n"
+	ps, err := difflib.ParseV4A(patch, readFrom(files))
+	if err != nil {
+		t.Fatalf("ParseV4A error: %v", err)
+	}
+	if h := ps.Files[0].Hunks[0]; h.OldStart != 5 {
+		t.Errorf("hunk starts at line %d, want 5", h.OldStart)
+	}
+}
+
+func TestParseV4AErrors(t *testing.T) {
+	files := map[string]string{"a.txt": "x\ny\n"}
+	tests := []struct {
+		name, patch, want string
+	}{
+		{"no begin", "*** Update File: a.txt\n", "must start with"},
+		{"no end", "*** Begin Patch\n*** Update File: a.txt\n@@\n-x\n", "must end with"},
+		{"missing file", "*** Begin Patch\n*** Update File: b.txt\n@@\n-x\n*** End Patch\n", "reading b.txt"},
+		{"anchor", "*** Begin Patch\n*** Update File: a.txt\n@@ z\n-x\n*** End Patch\n", `line 3: anchor "z" not found`},
+		{"context", "*** Begin Patch\n*** Update File: a.txt\n@@\n-q\n*** End Patch\n", "line 4: context not found"},
+		{"bad line", "*** Begin Patch\n*** Update File: a.txt\n@@\n?x\n*** End Patch\n", "line 4: chunk line must start"},
+		{"no changes", "*** Begin Patch\n*** Update File: a.txt\n*** End Patch\n", "no changes for a.txt"},
+		{"add line", "*** Begin Patch\n*** Add File: c.txt\nx\n*** End Patch\n", `line 3: added file line must start with "+"`},
+		{"stray", "*** Begin Patch\nhello\n*** End Patch\n", `line 2: unexpected line "hello"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := difflib.ParseV4A(tt.patch, readFrom(files))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatV4ARoundTrip(t *testing.T) {
+	before := map[string]string{
+		"main.go": "package main\n\nfunc main() {\n\tprintln(1)\n}\n\nfunc f() {}\n\nfunc g() {}\n\nfunc h() {}\n",
+		"old.txt": "bye\n",
+		"mv.txt":  "1\n2\n3\n4\n5\n",
+	}
+	after := map[string]string{
+		"main.go":    "package main\n\nfunc main() {\n\tprintln(2)\n}\n\nfunc f() {}\n\nfunc g() {}\n\nfunc h() { return }\n",
+		"new.txt":    "hello\n",
+		"dir/mv.txt": "1\n2\n3\n4\n5\n6\n",
+	}
+	dir := t.TempDir()
+	writeFiles(t, filepath.Join(dir, "a"), before)
+	writeFiles(t, filepath.Join(dir, "b"), after)
+	ps, err := difflib.DiffTrees(os.DirFS(filepath.Join(dir, "a")), os.DirFS(filepath.Join(dir, "b")), difflib.TreeDiffOptions{DetectRenames: true})
+	if err != nil {
+		t.Fatalf("DiffTrees error: %v", err)
+	}
+	v4a, err := difflib.FormatV4A(ps, 1)
+	if err != nil {
+		t.Fatalf("FormatV4A error: %v", err)
+	}
+	back, err := difflib.ParseV4A(v4a, readFrom(before))
+	if err != nil {
+		t.Fatalf("ParseV4A error: %v\n%s", err, v4a)
+	}
+	for i := range ps.Files {
+		ps.Files[i].Index = "" // V4A has no blob hashes.
+	}
+	if got, want := back.String(), ps.String(); got != want {
+		t.Errorf("round trip =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestFormatV4ARejectsBinary(t *testing.T) {
+	ps := &difflib.PatchSet{Files: []difflib.FileDiff{{OldName: "a/x.png", NewName: "b/x.png", Binary: true}}}
+	if _, err := difflib.FormatV4A(ps, 1); err == nil {
+		t.Error("FormatV4A of a binary diff succeeded")
+	}
+}
+
+func ExampleParseV4A() {
+	files := map[string]string{"greet.py": "def greet():\n    print(\"hi\")\n"}
+	patch := `*** Begin Patch
+*** Update File: greet.py
+@@ def greet():
+-    print("hi")
++    print("hello")
+*** End Patch
+`
+	ps, err := difflib.ParseV4A(patch, func(name string) (string, error) {
+		return files[name], nil
+	})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Print(ps)
+	// Output:
+	// diff --git a/greet.py b/greet.py
+	// --- a/greet.py
+	// +++ b/greet.py
+	// @@ -2,1 +2,1 @@
+	// -    print("hi")
+	// +    print("hello")
+}
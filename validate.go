@@ -0,0 +1,200 @@
+package difflib
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PatchError describes a problem found by ValidatePatch.
+type PatchError struct {
+	// Line is the 1-based line number in the patch.
+	Line int
+	// Column is the 1-based byte column in that line.
+	Column int
+	// Msg describes the problem.
+	Msg string
+}
+
+// Error formats the problem as "line:column: message".
+func (e PatchError) Error() string {
+	return fmt.Sprintf("%d:%d: %s", e.Line, e.Column, e.Msg)
+}
+
+// ValidatePatch checks a single- or multi-file unified diff for structural
+// problems and returns every problem found, in patch order. It reports
+// malformed hunk headers, bad ranges, hunk bodies whose line counts disagree
+// with their headers, body lines missing their ' ', '+' or '-' prefix, hunks
+// outside any file, overlapping or out-of-order hunks, and hunks whose new
+// start line is inconsistent with the preceding hunks. A nil result means
+// the patch is well formed; it does not mean it applies to any given input.
+//
+// Example:
+//
+//	for _, e := range difflib.ValidatePatch(patch) {
+//	    fmt.Printf("patch.diff:%v\n", e) // patch.diff:12:1: missing line prefix
+//	}
+func ValidatePatch(patch string) []PatchError {
+	v := patchValidator{lines: SplitLines(patch)}
+	v.run()
+	return v.errs
+}
+
+type patchValidator struct {
+	lines []string
+	errs  []PatchError
+	// Per-file state.
+	inFile  bool
+	oldEnd  int
+	delta   int
+	gitHead bool
+}
+
+func (v *patchValidator) errorf(line, col int, format string, args ...any) {
+	v.errs = append(v.errs, PatchError{Line: line, Column: col, Msg: fmt.Sprintf(format, args...)})
+}
+
+func (v *patchValidator) startFile(git bool) {
+	v.inFile, v.oldEnd, v.delta, v.gitHead = true, 0, 0, git
+}
+
+// isFileHeader reports whether lines[i] starts a --- / +++ header pair.
+func (v *patchValidator) isFileHeader(i int) bool {
+	return strings.HasPrefix(v.lines[i], "--- ") && i+1 < len(v.lines) && strings.HasPrefix(v.lines[i+1], "+++ ")
+}
+
+func (v *patchValidator) run() {
+	for i := 0; i < len(v.lines); {
+		line := v.lines[i]
+		switch {
+		case strings.HasPrefix(line, "diff "):
+			v.startFile(strings.HasPrefix(line, "diff --git "))
+			i++
+		case v.isFileHeader(i):
+			if !v.gitHead {
+				v.startFile(false)
+			}
+			v.gitHead = false
+			i += 2
+		case strings.HasPrefix(line, "@@"):
+			v.gitHead = false
+			i = v.hunk(i)
+		default:
+			i++
+		}
+	}
+}
+
+// hunk validates the hunk whose header is at index i and returns the index
+// of the first line after it.
+func (v *patchValidator) hunk(i int) int {
+	lineno := i + 1
+	header := strings.TrimRight(v.lines[i], "\r\n")
+	if !v.inFile {
+		v.errorf(lineno, 1, "hunk without file header")
+	}
+	h, col, msg := checkHunkHeader(header)
+	if msg != "" {
+		v.errorf(lineno, col, "%s", msg)
+		// Skip the body so it does not produce follow-on errors.
+		i++
+		for i < len(v.lines) && isHunkBodyLine(v.lines[i]) && !v.isFileHeader(i) {
+			i++
+		}
+		return i
+	}
+
+	anchor, newAnchor := hunkAnchor(h), newAnchor(h)
+	if anchor < v.oldEnd {
+		v.errorf(lineno, 4, "hunk starting at old line %d overlaps or precedes the previous hunk ending at line %d",
+			h.OldStart, v.oldEnd)
+	} else if newAnchor-anchor != v.delta {
+		v.errorf(lineno, strings.Index(header, " +")+2, "new start %d is inconsistent with preceding hunks (expected %d)",
+			h.NewStart, h.NewStart-(newAnchor-anchor-v.delta))
+	}
+	v.oldEnd = anchor + h.OldLines
+	v.delta += h.NewLines - h.OldLines
+
+	oldLeft, newLeft := h.OldLines, h.NewLines
+	i++
+	for oldLeft > 0 || newLeft > 0 {
+		// Lines starting with "---" or "+++" are body lines here: the
+		// counts, not the content, say where the hunk ends.
+		if i >= len(v.lines) || strings.HasPrefix(v.lines[i], "@@") || strings.HasPrefix(v.lines[i], "diff ") {
+			v.errorf(lineno, 1, "hunk body too short: missing %d old and %d new lines", max(oldLeft, 0), max(newLeft, 0))
+			return i
+		}
+		l := v.lines[i]
+		switch {
+		case strings.HasPrefix(l, `\`):
+			i++
+			continue
+		case strings.HasPrefix(l, "+"):
+			newLeft--
+		case strings.HasPrefix(l, "-"):
+			oldLeft--
+		case strings.HasPrefix(l, " "):
+			oldLeft--
+			newLeft--
+		default:
+			v.errorf(i+1, 1, "missing line prefix (expected ' ', '+' or '-')")
+			oldLeft--
+			newLeft--
+		}
+		if oldLeft < 0 || newLeft < 0 {
+			v.errorf(i+1, 1, "hunk body has more lines than its header counts")
+			oldLeft, newLeft = max(oldLeft, 0), max(newLeft, 0)
+		}
+		i++
+	}
+	for i < len(v.lines) && strings.HasPrefix(v.lines[i], `\`) {
+		i++
+	}
+	if i < len(v.lines) && isHunkBodyLine(v.lines[i]) && !v.isFileHeader(i) {
+		v.errorf(i+1, 1, "line after the end of the hunk; header counts are too small")
+		for i < len(v.lines) && isHunkBodyLine(v.lines[i]) && !v.isFileHeader(i) {
+			i++
+		}
+	}
+	return i
+}
+
+// isHunkBodyLine reports whether l looks like a line of a hunk body.
+func isHunkBodyLine(l string) bool {
+	return l != "" && strings.ContainsRune(" +-\\", rune(l[0]))
+}
+
+// checkHunkHeader parses a hunk header, returning the 1-based column and a
+// message describing the first problem found, or an empty message.
+func checkHunkHeader(header string) (Hunk, int, string) {
+	if !strings.HasPrefix(header, "@@ -") {
+		return Hunk{}, 1, fmt.Sprintf("malformed hunk header %q: expected \"@@ -\"", header)
+	}
+	end := strings.Index(header[4:], " @@")
+	if end < 0 {
+		return Hunk{}, len(header) + 1, fmt.Sprintf("malformed hunk header %q: missing closing \"@@\"", header)
+	}
+	ranges := header[4 : 4+end]
+	oldRange, newRange, ok := strings.Cut(ranges, " +")
+	if !ok {
+		return Hunk{}, 5, fmt.Sprintf("malformed hunk header %q: missing new range", header)
+	}
+	var h Hunk
+	var err error
+	if h.OldStart, h.OldLines, err = parseRange(oldRange); err != nil {
+		return Hunk{}, 5, err.Error()
+	}
+	newCol := 5 + len(oldRange) + 2
+	if h.NewStart, h.NewLines, err = parseRange(newRange); err != nil {
+		return Hunk{}, newCol, err.Error()
+	}
+	if h.OldStart == 0 && h.OldLines > 0 {
+		return Hunk{}, 5, fmt.Sprintf("bad range -%s: start 0 with %d lines", oldRange, h.OldLines)
+	}
+	if h.NewStart == 0 && h.NewLines > 0 {
+		return Hunk{}, newCol, fmt.Sprintf("bad range +%s: start 0 with %d lines", newRange, h.NewLines)
+	}
+	if h.OldLines == 0 && h.NewLines == 0 {
+		return Hunk{}, 5, "hunk changes no lines"
+	}
+	return h, 0, ""
+}
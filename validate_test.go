@@ -0,0 +1,48 @@
+package difflib_test
+
+import (
+	"strings"
+	"testing"
+
+	difflib "github.com/njchilds90/go-difflib"
+)
+
+func TestValidatePatch(t *testing.T) {
+	tests := []struct {
+		name  string
+		patch string
+		want  []string // "line:column:substring"
+	}{
+		{"valid", "--- a\n+++ b\n@@ -1,2 +1,2 @@\n a\n-b\n+B\n", nil},
+		{"valid multi-file", gitPatch, nil},
+		{"no file header", "@@ -1 +1 @@\n-a\n+b\n", []string{"1:1:without file header"}},
+		{"bad header", "--- a\n+++ b\n@@ -1,x +1 @@\n-a\n+b\n", []string{"3:5:bad range"}},
+		{"bad new range", "--- a\n+++ b\n@@ -1 +y @@\n-a\n+b\n", []string{"3:8:bad range"}},
+		{"missing close", "--- a\n+++ b\n@@ -1 +1\n-a\n+b\n", []string{"3:9:missing closing"}},
+		{"zero start", "--- a\n+++ b\n@@ -0,1 +1 @@\n-a\n+b\n", []string{"3:5:start 0"}},
+		{"missing prefix", "--- a\n+++ b\n@@ -1,3 +1,3 @@\n a\n-b\n+B\nc\n", []string{"7:1:missing line prefix"}},
+		{"too short", "--- a\n+++ b\n@@ -1,3 +1,3 @@\n a\n-b\n+B\n", []string{"3:1:too short"}},
+		{"too long", "--- a\n+++ b\n@@ -1,2 +1,2 @@\n a\n-b\n+B\n c\n", []string{"7:1:after the end"}},
+		{"overlap", "--- a\n+++ b\n@@ -1,2 +1,2 @@\n a\n-b\n+B\n@@ -2,2 +2,2 @@\n B\n-c\n+C\n",
+			[]string{"7:4:overlaps"}},
+		{"inconsistent new start", "--- a\n+++ b\n@@ -1,2 +1,3 @@\n a\n-b\n+B\n+B2\n@@ -5,1 +5,1 @@\n-e\n+E\n",
+			[]string{"8:9:inconsistent"}},
+		{"sql comment body", "--- a\n+++ b\n@@ -1,2 +1,1 @@\n--- comment\n keep\n", nil},
+		{"body lines like file headers", "--- a\n+++ b\n@@ -1,2 +1,2 @@\n--- x\n+++ y\n keep\n", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := difflib.ValidatePatch(tt.patch)
+			if len(errs) != len(tt.want) {
+				t.Fatalf("got %d errors %v, want %d", len(errs), errs, len(tt.want))
+			}
+			for i, w := range tt.want {
+				parts := strings.SplitN(w, ":", 3)
+				got := errs[i].Error()
+				if !strings.HasPrefix(got, parts[0]+":"+parts[1]+":") || !strings.Contains(got, parts[2]) {
+					t.Errorf("error %d = %q, want %s", i, got, w)
+				}
+			}
+		})
+	}
+}
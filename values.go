@@ -0,0 +1,271 @@
+package difflib
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DiffValues renders a and b as indented Go-like literals and returns the
+// unified diff of the two renderings, or "" if they render the same. It
+// gives readable test failures for structs, maps and slices:
+//
+//	--- a
+//	+++ b
+//	@@ -1,5 +1,5 @@
+//	 difflib_test.User{
+//	 	Name: "Ada",
+//	-	Age:  36,
+//	+	Age:  37,
+//	 	Tags: []string{
+//
+// The rendering is stable: map entries are sorted by their rendered keys,
+// unexported fields are included, pointers are followed (cycles print as
+// "<cycle>"), and structs with a String method and no exported fields, such
+// as time.Time, print through it. Reflection cannot call methods on values
+// read from unexported fields, so those structs print field by field there.
+// Values that render the same, such as two different functions or NaNs, are
+// not reported as different.
+//
+// Example:
+//
+//	if d := difflib.DiffValues(want, got); d != "" {
+//		t.Errorf("user mismatch:\n%s", d)
+//	}
+func DiffValues(a, b any) string {
+	ra, rb := prettyValue(a), prettyValue(b)
+	if ra == rb {
+		return ""
+	}
+	return UnifiedDiff(DiffInput{
+		A:        SplitLines(ra),
+		B:        SplitLines(rb),
+		FromFile: "a",
+		ToFile:   "b",
+	}).String()
+}
+
+// prettyValue renders v as an indented literal ending in a newline.
+func prettyValue(v any) string {
+	p := &prettyPrinter{visiting: map[uintptr]bool{}}
+	p.value(reflect.ValueOf(v), 0, true)
+	p.b.WriteByte('\n')
+	return p.b.String()
+}
+
+// prettyPrinter renders reflect.Values for DiffValues.
+type prettyPrinter struct {
+	b strings.Builder
+	// visiting holds the pointers, maps and slices on the current path,
+	// to detect cycles.
+	visiting map[uintptr]bool
+}
+
+var stringerType = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+
+// value renders v at the given indentation depth. typed reports whether the
+// static type of v is not evident from context (top level, interface values,
+// pointer targets), in which case basic values are written as conversions
+// like int64(5) unless their type is the default for the literal.
+func (p *prettyPrinter) value(v reflect.Value, depth int, typed bool) {
+	if !v.IsValid() {
+		p.b.WriteString("nil")
+		return
+	}
+	t := v.Type()
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.IsNil() {
+			p.b.WriteString("nil")
+			return
+		}
+		p.value(v.Elem(), depth, true)
+	case reflect.Pointer:
+		if v.IsNil() {
+			fmt.Fprintf(&p.b, "(%s)(nil)", t)
+			return
+		}
+		if p.enter(v.Pointer()) {
+			return
+		}
+		p.b.WriteByte('&')
+		p.value(v.Elem(), depth, true)
+		delete(p.visiting, v.Pointer())
+	case reflect.Struct:
+		if s, ok := opaqueString(v); ok {
+			fmt.Fprintf(&p.b, "%s(%q)", t, s)
+			return
+		}
+		p.b.WriteString(t.String())
+		if t.NumField() == 0 {
+			p.b.WriteString("{}")
+			return
+		}
+		p.b.WriteString("{\n")
+		width := 0
+		for i := 0; i < t.NumField(); i++ {
+			width = max(width, len(t.Field(i).Name))
+		}
+		for i := 0; i < t.NumField(); i++ {
+			name := t.Field(i).Name
+			p.indent(depth + 1)
+			p.b.WriteString(name)
+			p.b.WriteString(": ")
+			p.b.WriteString(strings.Repeat(" ", width-len(name)))
+			p.value(v.Field(i), depth+1, false)
+			p.b.WriteString(",\n")
+		}
+		p.indent(depth)
+		p.b.WriteByte('}')
+	case reflect.Map:
+		if v.IsNil() {
+			fmt.Fprintf(&p.b, "%s(nil)", t)
+			return
+		}
+		if p.enter(v.Pointer()) {
+			return
+		}
+		defer delete(p.visiting, v.Pointer())
+		type entry struct {
+			key string
+			val reflect.Value
+		}
+		entries := make([]entry, 0, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			entries = append(entries, entry{p.inline(iter.Key(), depth+1), iter.Value()})
+		}
+		sort.Slice(entries, func(i, j int) bool { return entries[i].key < entries[j].key })
+		p.b.WriteString(t.String())
+		if len(entries) == 0 {
+			p.b.WriteString("{}")
+			return
+		}
+		p.b.WriteString("{\n")
+		for _, e := range entries {
+			p.indent(depth + 1)
+			p.b.WriteString(e.key)
+			p.b.WriteString(": ")
+			p.value(e.val, depth+1, false)
+			p.b.WriteString(",\n")
+		}
+		p.indent(depth)
+		p.b.WriteByte('}')
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice {
+			if v.IsNil() {
+				fmt.Fprintf(&p.b, "%s(nil)", t)
+				return
+			}
+			if t.Elem().Kind() == reflect.Uint8 {
+				fmt.Fprintf(&p.b, "%s(%q)", t, v.Bytes())
+				return
+			}
+			if v.Len() > 0 {
+				if p.enter(v.Pointer()) {
+					return
+				}
+				defer delete(p.visiting, v.Pointer())
+			}
+		}
+		p.b.WriteString(t.String())
+		if v.Len() == 0 {
+			p.b.WriteString("{}")
+			return
+		}
+		p.b.WriteString("{\n")
+		for i := 0; i < v.Len(); i++ {
+			p.indent(depth + 1)
+			p.value(v.Index(i), depth+1, false)
+			p.b.WriteString(",\n")
+		}
+		p.indent(depth)
+		p.b.WriteByte('}')
+	case reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		if v.IsNil() {
+			fmt.Fprintf(&p.b, "(%s)(nil)", t)
+		} else {
+			fmt.Fprintf(&p.b, "(%s)(non-nil)", t)
+		}
+	default:
+		s := basicString(v)
+		if typed && !isLiteralType(t) {
+			fmt.Fprintf(&p.b, "%s(%s)", t, s)
+			return
+		}
+		p.b.WriteString(s)
+	}
+}
+
+// inline renders v on a single line, for map keys.
+func (p *prettyPrinter) inline(v reflect.Value, depth int) string {
+	sub := &prettyPrinter{visiting: p.visiting}
+	sub.value(v, depth, false)
+	return strings.ReplaceAll(strings.ReplaceAll(sub.b.String(), "\n", " "), "\t", "")
+}
+
+// enter marks ptr as being visited and reports whether it already was, in
+// which case it writes a cycle marker.
+func (p *prettyPrinter) enter(ptr uintptr) bool {
+	if p.visiting[ptr] {
+		p.b.WriteString("<cycle>")
+		return true
+	}
+	p.visiting[ptr] = true
+	return false
+}
+
+func (p *prettyPrinter) indent(depth int) {
+	for i := 0; i < depth; i++ {
+		p.b.WriteByte('\t')
+	}
+}
+
+// opaqueString returns the String method's result for a struct value that
+// has a String method and no exported fields, such as time.Time.
+func opaqueString(v reflect.Value) (string, bool) {
+	t := v.Type()
+	if !v.CanInterface() || !t.Implements(stringerType) {
+		return "", false
+	}
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).IsExported() {
+			return "", false
+		}
+	}
+	return v.Interface().(fmt.Stringer).String(), true
+}
+
+// basicString formats a boolean, numeric or string value. It uses the
+// reflect accessors rather than Interface so that unexported fields work.
+func basicString(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(v.Uint(), 10)
+	case reflect.Float32:
+		return strconv.FormatFloat(v.Float(), 'g', -1, 32)
+	case reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, 64)
+	case reflect.Complex64, reflect.Complex128:
+		return fmt.Sprint(v.Complex())
+	case reflect.String:
+		return strconv.Quote(v.String())
+	}
+	return fmt.Sprintf("<%s>", v.Type())
+}
+
+// isLiteralType reports whether t is the default type of an untyped
+// constant, so that its values need no conversion to show their type.
+func isLiteralType(t reflect.Type) bool {
+	switch t {
+	case reflect.TypeOf(0), reflect.TypeOf(0.0), reflect.TypeOf(""), reflect.TypeOf(false):
+		return true
+	}
+	return false
+}
@@ -0,0 +1,126 @@
+package difflib_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	difflib "github.com/njchilds90/go-difflib"
+)
+
+type valuesUser struct {
+	Name  string
+	Age   int
+	Tags  []string
+	Meta  map[string]any
+	Boss  *valuesUser
+	Seen  time.Time
+	notes []byte
+}
+
+type valuesNode struct {
+	Val  int
+	Next *valuesNode
+}
+
+func TestDiffValues(t *testing.T) {
+	when := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	u := valuesUser{Name: "Ada", Age: 36, Tags: []string{"x", "y"}, Meta: map[string]any{"b": int64(2), "a": 1}, Seen: when}
+	tests := []struct {
+		name string
+		a, b any
+		want string
+	}{
+		{"equal structs", u, u, ""},
+		{"both nil", nil, nil, ""},
+		{
+			"changed field", u, func() valuesUser { v := u; v.Age = 37; return v }(),
+			" \tName:  \"Ada\",\n-\tAge:   36,\n+\tAge:   37,\n",
+		},
+		{
+			"map value typed through interface", map[string]any{"n": int64(1)}, map[string]any{"n": int32(1)},
+			"-\t\"n\": int64(1),\n+\t\"n\": int32(1),\n",
+		},
+		{
+			"time", valuesUser{Seen: when}, valuesUser{Seen: when.Add(time.Hour)},
+			"-\tSeen:  time.Time(\"2024-05-01 12:00:00 +0000 UTC\"),\n+\tSeen:  time.Time(\"2024-05-01 13:00:00 +0000 UTC\"),\n",
+		},
+		{
+			"unexported bytes", valuesUser{notes: []byte("hi")}, valuesUser{notes: []byte("ho")},
+			"-\tnotes: []uint8(\"hi\"),\n+\tnotes: []uint8(\"ho\"),\n",
+		},
+		{
+			"nil versus empty slice", []int(nil), []int{},
+			"-[]int(nil)\n+[]int{}\n",
+		},
+		{
+			"pointer", &valuesNode{Val: 1}, &valuesNode{Val: 2},
+			"-\tVal:  1,\n+\tVal:  2,\n",
+		},
+		{
+			"top-level typed", int64(3), 3,
+			"-int64(3)\n+3\n",
+		},
+	}
+	for _, tt := range tests {
+		got := difflib.DiffValues(tt.a, tt.b)
+		if tt.want == "" {
+			if got != "" {
+				t.Errorf("%s: got diff\n%s", tt.name, got)
+			}
+			continue
+		}
+		if !strings.HasPrefix(got, "--- a\n+++ b\n@@ ") || !strings.Contains(got, tt.want) {
+			t.Errorf("%s: diff\n%s\ndoes not contain\n%s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDiffValuesStableMapOrder(t *testing.T) {
+	a := map[int]string{}
+	b := map[int]string{}
+	for i := 0; i < 50; i++ {
+		a[i] = "v"
+		b[49-i] = "v"
+	}
+	if d := difflib.DiffValues(a, b); d != "" {
+		t.Errorf("equal maps built in different orders differ:\n%s", d)
+	}
+	b[7] = "w"
+	d := difflib.DiffValues(a, b)
+	if strings.Count(d, "\n-") != 1 || !strings.Contains(d, "-\t7: \"v\",\n+\t7: \"w\",\n") {
+		t.Errorf("diff =\n%s", d)
+	}
+}
+
+func TestDiffValuesCycle(t *testing.T) {
+	a := &valuesNode{Val: 1}
+	a.Next = a
+	b := &valuesNode{Val: 1, Next: &valuesNode{Val: 1}}
+	d := difflib.DiffValues(a, b)
+	if !strings.Contains(d, "-\tNext: <cycle>,\n") {
+		t.Errorf("cycle not rendered:\n%s", d)
+	}
+}
+
+func ExampleDiffValues() {
+	type Config struct {
+		Host  string
+		Ports []int
+	}
+	want := Config{Host: "localhost", Ports: []int{80, 443}}
+	got := Config{Host: "localhost", Ports: []int{80, 8443}}
+	fmt.Print(difflib.DiffValues(want, got))
+	// Output:
+	// --- a
+	// +++ b
+	// @@ -2,6 +2,6 @@
+	//  	Host:  "localhost",
+	//  	Ports: []int{
+	//  		80,
+	// -		443,
+	// +		8443,
+	//  	},
+	//  }
+}
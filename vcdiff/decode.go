@@ -0,0 +1,205 @@
+package vcdiff
+
+import (
+	"bytes"
+	"fmt"
+	"hash/adler32"
+)
+
+// maxPrealloc caps the target window buffer Decode allocates up front from
+// the size the delta declares.
+const maxPrealloc = 1 << 20
+
+// Decode applies a VCDIFF delta to source and returns the target. An error
+// wrapping ErrCorrupt is returned for a malformed delta; deltas using a
+// custom code table or secondary compression are reported as unsupported.
+//
+// Example:
+//
+//	target, err := vcdiff.Decode(source, delta)
+func Decode(source, delta []byte) ([]byte, error) {
+	r := &reader{buf: delta}
+	head, err := r.bytes(len(magic))
+	if err != nil || !bytes.Equal(head[:3], magic[:3]) {
+		return nil, fmt.Errorf("vcdiff: not a VCDIFF delta")
+	}
+	if head[3] != magic[3] {
+		return nil, fmt.Errorf("vcdiff: unsupported version %d", head[3])
+	}
+	ind, err := r.byte()
+	if err != nil {
+		return nil, err
+	}
+	if ind&hdrDecompress != 0 {
+		return nil, fmt.Errorf("vcdiff: secondary compression is not supported")
+	}
+	if ind&hdrCodeTable != 0 {
+		return nil, fmt.Errorf("vcdiff: custom code tables are not supported")
+	}
+	if ind&hdrAppHeader != 0 {
+		n, err := r.int()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := r.bytes(n); err != nil {
+			return nil, err
+		}
+	}
+	var target []byte
+	for !r.done() {
+		if target, err = decodeWindow(r, source, target); err != nil {
+			return nil, err
+		}
+	}
+	return target, nil
+}
+
+// decodeWindow decodes the window at r and appends its target data to target.
+func decodeWindow(r *reader, source, target []byte) ([]byte, error) {
+	ind, err := r.byte()
+	if err != nil {
+		return nil, err
+	}
+	var segment []byte
+	if ind&(winSource|winTarget) != 0 {
+		size, err := r.int()
+		if err != nil {
+			return nil, err
+		}
+		pos, err := r.int()
+		if err != nil {
+			return nil, err
+		}
+		from := source
+		if ind&winTarget != 0 {
+			from = target
+		}
+		if pos > len(from) || size > len(from)-pos {
+			return nil, fmt.Errorf("%w: source segment %d+%d exceeds %d bytes", ErrCorrupt, pos, size, len(from))
+		}
+		segment = from[pos : pos+size]
+	}
+	encLen, err := r.int()
+	if err != nil {
+		return nil, err
+	}
+	enc, err := r.bytes(encLen)
+	if err != nil {
+		return nil, err
+	}
+	w := &reader{buf: enc}
+	// The delta indicator sits between the target window size and the
+	// lengths of the data, instructions and addresses sections.
+	var sizes [4]int
+	for i := range sizes {
+		if i == 1 {
+			deltaInd, err := w.byte()
+			if err != nil {
+				return nil, err
+			}
+			if deltaInd != 0 {
+				return nil, fmt.Errorf("vcdiff: secondary compression is not supported")
+			}
+		}
+		if sizes[i], err = w.int(); err != nil {
+			return nil, err
+		}
+	}
+	var sum []byte
+	if ind&winAdler32 != 0 {
+		if sum, err = w.bytes(4); err != nil {
+			return nil, err
+		}
+	}
+	data, err := w.bytes(sizes[1])
+	if err != nil {
+		return nil, err
+	}
+	insts, err := w.bytes(sizes[2])
+	if err != nil {
+		return nil, err
+	}
+	addrs, err := w.bytes(sizes[3])
+	if err != nil {
+		return nil, err
+	}
+	if !w.done() {
+		return nil, fmt.Errorf("%w: trailing bytes in window", ErrCorrupt)
+	}
+
+	win, err := runWindow(segment, sizes[0], &reader{buf: data}, &reader{buf: insts}, &reader{buf: addrs})
+	if err != nil {
+		return nil, err
+	}
+	if sum != nil {
+		want := uint32(sum[0])<<24 | uint32(sum[1])<<16 | uint32(sum[2])<<8 | uint32(sum[3])
+		if adler32.Checksum(win) != want {
+			return nil, fmt.Errorf("%w: window checksum mismatch", ErrCorrupt)
+		}
+	}
+	return append(target, win...), nil
+}
+
+// runWindow executes the instructions of one window against its source
+// segment and returns the window's target data.
+func runWindow(segment []byte, size int, data, insts, addrs *reader) ([]byte, error) {
+	// The declared size is not trusted for allocation: out grows as the
+	// instructions produce data.
+	out := make([]byte, 0, min(size, maxPrealloc))
+	var cache addrCache
+	for !insts.done() {
+		code, _ := insts.byte()
+		for _, in := range defaultTable[code] {
+			if in.typ == instNoop {
+				continue
+			}
+			n := int(in.size)
+			if n == 0 {
+				var err error
+				if n, err = insts.int(); err != nil {
+					return nil, err
+				}
+			}
+			if n > size-len(out) {
+				return nil, fmt.Errorf("%w: instructions exceed the target window", ErrCorrupt)
+			}
+			switch in.typ {
+			case instAdd:
+				b, err := data.bytes(n)
+				if err != nil {
+					return nil, err
+				}
+				out = append(out, b...)
+			case instRun:
+				b, err := data.byte()
+				if err != nil {
+					return nil, err
+				}
+				for i := 0; i < n; i++ {
+					out = append(out, b)
+				}
+			case instCopy:
+				here := len(segment) + len(out)
+				addr, err := cache.decode(addrs, in.mode, here)
+				if err != nil {
+					return nil, err
+				}
+				for i := 0; i < n; i++ {
+					// Copies may overlap the data they produce.
+					if a := addr + i; a < len(segment) {
+						out = append(out, segment[a])
+					} else {
+						out = append(out, out[a-len(segment)])
+					}
+				}
+			}
+		}
+	}
+	if len(out) != size {
+		return nil, fmt.Errorf("%w: window produced %d bytes, want %d", ErrCorrupt, len(out), size)
+	}
+	if !data.done() || !addrs.done() {
+		return nil, fmt.Errorf("%w: unused window data", ErrCorrupt)
+	}
+	return out, nil
+}
@@ -0,0 +1,216 @@
+package vcdiff
+
+// Encoder tuning.
+const (
+	// maxWindow bounds the target data of one window, keeping windows
+	// within the limits of common decoders such as xdelta3.
+	maxWindow = 1 << 22
+	// blockSize is the length of the blocks indexed for COPY matching and
+	// the shortest COPY the encoder emits.
+	blockSize = 16
+	// minRun is the shortest run of one byte encoded as a RUN.
+	minRun = 8
+)
+
+// Encode returns a VCDIFF delta that turns source into target. Every window
+// may copy from all of source and from the target data already produced in
+// that window. The delta uses the default code table without secondary
+// compression or checksums.
+//
+// Example:
+//
+//	delta := vcdiff.Encode(oldBinary, newBinary)
+//	os.WriteFile("update.vcdiff", delta, 0o644)
+func Encode(source, target []byte) []byte {
+	out := append(magic[:len(magic):len(magic)], 0) // no header extensions
+	src := newBlockIndex(source)
+	for start := 0; start < len(target); start += maxWindow {
+		end := min(start+maxWindow, len(target))
+		out = encodeWindow(out, source, src, target[start:end])
+	}
+	return out
+}
+
+// blockIndex maps the hashes of blockSize-aligned blocks to their offsets.
+type blockIndex map[uint64]int
+
+func newBlockIndex(b []byte) blockIndex {
+	idx := blockIndex{}
+	for off := 0; off+blockSize <= len(b); off += blockSize {
+		idx.add(b, off)
+	}
+	return idx
+}
+
+// add indexes the block of b at off, keeping the earliest block for a hash.
+func (idx blockIndex) add(b []byte, off int) {
+	h := blockHash(b[off : off+blockSize])
+	if _, ok := idx[h]; !ok {
+		idx[h] = off
+	}
+}
+
+// hashBase is the multiplier of the polynomial rolling hash.
+const hashBase = 1099511628211
+
+// hashPow is hashBase raised to blockSize-1, the weight of the first byte
+// of a block, used to roll that byte out.
+var hashPow = func() uint64 {
+	p := uint64(1)
+	for i := 1; i < blockSize; i++ {
+		p *= hashBase
+	}
+	return p
+}()
+
+func blockHash(b []byte) uint64 {
+	var h uint64
+	for _, c := range b {
+		h = h*hashBase + uint64(c)
+	}
+	return h
+}
+
+// windowEncoder accumulates the three sections of one window.
+type windowEncoder struct {
+	data, insts, addrs []byte
+	cache              addrCache
+}
+
+// encodeWindow appends a window encoding t against source to out.
+func encodeWindow(out, source []byte, src blockIndex, t []byte) []byte {
+	enc := &windowEncoder{}
+	tgt := blockIndex{}
+	indexed := 0 // next aligned offset of t to index
+	pending := 0 // start of bytes not yet covered by an instruction
+	var h uint64
+	hashed := -1 // position whose block hash h holds, or -1
+	for p := 0; p < len(t); {
+		for ; indexed+blockSize <= p; indexed += blockSize {
+			tgt.add(t, indexed)
+		}
+		if p+blockSize > len(t) {
+			break
+		}
+		if hashed >= 0 && hashed == p-1 {
+			h = (h-uint64(t[p-1])*hashPow)*hashBase + uint64(t[p+blockSize-1])
+		} else {
+			h = blockHash(t[p : p+blockSize])
+		}
+		hashed = p
+
+		addr, n := 0, 0
+		if off, ok := src[h]; ok {
+			addr, n = off, matchLen(source[off:], t[p:])
+		}
+		if off, ok := tgt[h]; ok {
+			if m := matchLen(t[off:], t[p:]); m > n {
+				addr, n = len(source)+off, m
+			}
+		}
+		if n >= blockSize {
+			// Extend the match backwards over pending literal bytes.
+			for p > pending && addr > 0 && byteAt(source, t, addr-1) == t[p-1] {
+				p, addr, n = p-1, addr-1, n+1
+			}
+			enc.add(t[pending:p])
+			enc.copy(addr, n, len(source)+p)
+			p += n
+			pending, hashed = p, -1
+			continue
+		}
+		if r := runLen(t[p:]); r >= minRun {
+			enc.add(t[pending:p])
+			enc.run(t[p], r)
+			p += r
+			pending, hashed = p, -1
+			continue
+		}
+		p++
+	}
+	enc.add(t[pending:])
+
+	var body []byte
+	body = appendInt(body, len(t))
+	body = append(body, 0) // no secondary compression
+	body = appendInt(body, len(enc.data))
+	body = appendInt(body, len(enc.insts))
+	body = appendInt(body, len(enc.addrs))
+	body = append(body, enc.data...)
+	body = append(body, enc.insts...)
+	body = append(body, enc.addrs...)
+
+	if len(source) > 0 {
+		out = append(out, winSource)
+		out = appendInt(out, len(source))
+		out = appendInt(out, 0)
+	} else {
+		out = append(out, 0)
+	}
+	out = appendInt(out, len(body))
+	return append(out, body...)
+}
+
+// byteAt returns the byte at addr in the address space formed by source
+// followed by the window's target data t.
+func byteAt(source, t []byte, addr int) byte {
+	if addr < len(source) {
+		return source[addr]
+	}
+	return t[addr-len(source)]
+}
+
+// matchLen returns the length of the common prefix of a and b.
+func matchLen(a, b []byte) int {
+	n := min(len(a), len(b))
+	for i := 0; i < n; i++ {
+		if a[i] != b[i] {
+			return i
+		}
+	}
+	return n
+}
+
+// runLen returns the number of leading bytes of b equal to b[0].
+func runLen(b []byte) int {
+	n := 1
+	for n < len(b) && b[n] == b[0] {
+		n++
+	}
+	return n
+}
+
+// add emits an ADD of the literal bytes b.
+func (e *windowEncoder) add(b []byte) {
+	if len(b) == 0 {
+		return
+	}
+	e.data = append(e.data, b...)
+	if len(b) <= 17 {
+		e.insts = append(e.insts, byte(1+len(b)))
+		return
+	}
+	e.insts = append(e.insts, 1)
+	e.insts = appendInt(e.insts, len(b))
+}
+
+// run emits a RUN of n copies of c.
+func (e *windowEncoder) run(c byte, n int) {
+	e.data = append(e.data, c)
+	e.insts = append(e.insts, 0)
+	e.insts = appendInt(e.insts, n)
+}
+
+// copy emits a COPY of n bytes from addr, here being the current position
+// in the combined address space. Copies are at least blockSize long, so the
+// double ADD+COPY codes of the default table never apply.
+func (e *windowEncoder) copy(addr, n, here int) {
+	var mode byte
+	mode, e.addrs = e.cache.encode(e.addrs, addr, here)
+	if n <= 18 {
+		e.insts = append(e.insts, byte(19+int(mode)*16+n-3))
+		return
+	}
+	e.insts = append(e.insts, byte(19+int(mode)*16))
+	e.insts = appendInt(e.insts, n)
+}
@@ -0,0 +1,236 @@
+// Package vcdiff encodes and decodes binary deltas in the VCDIFF format of
+// RFC 3284, the format written by xdelta3 and used for HTTP delta encoding.
+//
+// Encode produces deltas that use the default code table and no secondary
+// compression, so any conforming decoder can apply them. Decode accepts
+// the full default code table, both source and target segments, xdelta3's
+// application header and its Adler-32 window checksums; deltas that use a
+// custom code table or secondary compression are rejected.
+//
+// Basic usage:
+//
+//	delta := vcdiff.Encode(oldBinary, newBinary)
+//	restored, err := vcdiff.Decode(oldBinary, delta)
+package vcdiff
+
+import (
+	"errors"
+	"fmt"
+)
+
+// magic is the four-byte header that starts every VCDIFF delta: "VCD" with
+// the high bits set, followed by version 0.
+var magic = [4]byte{0xD6, 0xC3, 0xC4, 0x00}
+
+// Header indicator bits.
+const (
+	hdrDecompress = 0x01 // secondary compressor id follows
+	hdrCodeTable  = 0x02 // custom code table follows
+	hdrAppHeader  = 0x04 // application header follows (xdelta3 extension)
+)
+
+// Window indicator bits.
+const (
+	winSource  = 0x01 // source segment comes from the source file
+	winTarget  = 0x02 // source segment comes from earlier target data
+	winAdler32 = 0x04 // Adler-32 checksum of the target window (xdelta3)
+)
+
+// Instruction types of the code table.
+const (
+	instNoop = iota
+	instAdd
+	instRun
+	instCopy
+)
+
+// Sizes of the address caches of the default code table.
+const (
+	nearSize = 4
+	sameSize = 3
+)
+
+// modeSelf and modeHere are the address modes that are not cache lookups.
+const (
+	modeSelf = 0
+	modeHere = 1
+)
+
+// ErrCorrupt is returned (wrapped) by Decode when the delta is truncated or
+// internally inconsistent.
+var ErrCorrupt = errors.New("vcdiff: corrupt delta")
+
+// inst is one half of a code table entry.
+type inst struct {
+	typ, size, mode byte
+}
+
+// codeEntry is an entry of the code table: up to two instructions.
+type codeEntry [2]inst
+
+// defaultTable is the default instruction code table of RFC 3284 section 5.6.
+var defaultTable = buildDefaultTable()
+
+func buildDefaultTable() [256]codeEntry {
+	var t [256]codeEntry
+	t[0][0] = inst{typ: instRun}
+	i := 1
+	for size := 0; size <= 17; size++ {
+		t[i][0] = inst{typ: instAdd, size: byte(size)}
+		i++
+	}
+	for mode := 0; mode < 2+nearSize+sameSize; mode++ {
+		t[i][0] = inst{typ: instCopy, mode: byte(mode)}
+		i++
+		for size := 4; size <= 18; size++ {
+			t[i][0] = inst{typ: instCopy, size: byte(size), mode: byte(mode)}
+			i++
+		}
+	}
+	for mode := 0; mode < 2+nearSize+sameSize; mode++ {
+		maxCopy := 6
+		if mode >= 2+nearSize {
+			maxCopy = 4
+		}
+		for add := 1; add <= 4; add++ {
+			for size := 4; size <= maxCopy; size++ {
+				t[i] = codeEntry{{typ: instAdd, size: byte(add)}, {typ: instCopy, size: byte(size), mode: byte(mode)}}
+				i++
+			}
+		}
+	}
+	for mode := 0; mode < 2+nearSize+sameSize; mode++ {
+		t[i] = codeEntry{{typ: instCopy, size: 4, mode: byte(mode)}, {typ: instAdd, size: 1}}
+		i++
+	}
+	return t
+}
+
+// addrCache is the near and same address cache of RFC 3284 section 5.1.
+type addrCache struct {
+	near     [nearSize]int
+	nextSlot int
+	same     [sameSize * 256]int
+}
+
+// update records addr as the most recent COPY address.
+func (c *addrCache) update(addr int) {
+	c.near[c.nextSlot] = addr
+	c.nextSlot = (c.nextSlot + 1) % nearSize
+	c.same[addr%(sameSize*256)] = addr
+}
+
+// decode reads an address in the given mode, here being the current
+// position in the combined source and target address space.
+func (c *addrCache) decode(r *reader, mode byte, here int) (int, error) {
+	var addr int
+	switch {
+	case mode == modeSelf:
+		v, err := r.int()
+		if err != nil {
+			return 0, err
+		}
+		addr = v
+	case mode == modeHere:
+		v, err := r.int()
+		if err != nil {
+			return 0, err
+		}
+		addr = here - v
+	case int(mode) < 2+nearSize:
+		v, err := r.int()
+		if err != nil {
+			return 0, err
+		}
+		addr = c.near[mode-2] + v
+	default:
+		b, err := r.byte()
+		if err != nil {
+			return 0, err
+		}
+		addr = c.same[(int(mode)-2-nearSize)*256+int(b)]
+	}
+	if addr < 0 || addr >= here {
+		return 0, fmt.Errorf("%w: address %d out of range", ErrCorrupt, addr)
+	}
+	c.update(addr)
+	return addr, nil
+}
+
+// encode chooses the cheapest mode for addr and appends its encoding to
+// buf, returning the mode and the extended buffer.
+func (c *addrCache) encode(buf []byte, addr, here int) (byte, []byte) {
+	if same := addr % (sameSize * 256); c.same[same] == addr {
+		c.update(addr)
+		return byte(2 + nearSize + same/256), append(buf, byte(same%256))
+	}
+	mode, best := byte(modeSelf), addr
+	if d := here - addr; d < best {
+		mode, best = modeHere, d
+	}
+	for i, n := range c.near {
+		if d := addr - n; d >= 0 && d < best {
+			mode, best = byte(2+i), d
+		}
+	}
+	c.update(addr)
+	return mode, appendInt(buf, best)
+}
+
+// appendInt appends v in the variable-length integer format of RFC 3284:
+// big-endian base-128 digits with the high bit set on all but the last.
+func appendInt(buf []byte, v int) []byte {
+	var tmp [10]byte
+	i := len(tmp) - 1
+	tmp[i] = byte(v & 0x7F)
+	for v >>= 7; v > 0; v >>= 7 {
+		i--
+		tmp[i] = byte(v&0x7F) | 0x80
+	}
+	return append(buf, tmp[i:]...)
+}
+
+// reader reads bytes and integers from one section of a delta.
+type reader struct {
+	buf []byte
+	pos int
+}
+
+func (r *reader) byte() (byte, error) {
+	if r.pos >= len(r.buf) {
+		return 0, fmt.Errorf("%w: unexpected end of data", ErrCorrupt)
+	}
+	b := r.buf[r.pos]
+	r.pos++
+	return b, nil
+}
+
+func (r *reader) int() (int, error) {
+	v := 0
+	for n := 0; ; n++ {
+		b, err := r.byte()
+		if err != nil {
+			return 0, err
+		}
+		if n == 9 || v > (1<<56) {
+			return 0, fmt.Errorf("%w: integer overflow", ErrCorrupt)
+		}
+		v = v<<7 | int(b&0x7F)
+		if b&0x80 == 0 {
+			return v, nil
+		}
+	}
+}
+
+func (r *reader) bytes(n int) ([]byte, error) {
+	if n < 0 || n > len(r.buf)-r.pos {
+		return nil, fmt.Errorf("%w: unexpected end of data", ErrCorrupt)
+	}
+	b := r.buf[r.pos : r.pos+n]
+	r.pos += n
+	return b, nil
+}
+
+func (r *reader) done() bool {
+	return r.pos >= len(r.buf)
+}
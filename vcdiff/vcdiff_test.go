@@ -0,0 +1,151 @@
+package vcdiff_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/njchilds90/go-difflib/vcdiff"
+)
+
+// rfcDelta encodes the example of RFC 3284 section 4.3 by hand: source
+// "abcdefghijklmnop", target "abcdwxyzefghefghefghefghzzzz".
+var rfcDelta = []byte{
+	0xD6, 0xC3, 0xC4, 0x00, 0x00, // header
+	0x01, 0x10, 0x00, // VCD_SOURCE, 16 bytes at 0
+	0x13,                         // delta encoding length
+	0x1C, 0x00, 0x05, 0x06, 0x03, // target 28, no compression, section lengths
+	'w', 'x', 'y', 'z', 'z', // data
+	0x14, 0x05, 0x14, 0x2C, 0x00, 0x04, // COPY 4 SELF, ADD 4, COPY 4 SELF, COPY 12 HERE, RUN 4
+	0x00, 0x04, 0x04, // addresses
+}
+
+func TestDecodeRFCExample(t *testing.T) {
+	got, err := vcdiff.Decode([]byte("abcdefghijklmnop"), rfcDelta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "abcdwxyzefghefghefghefghzzzz"; string(got) != want {
+		t.Errorf("Decode() = %q, want %q", got, want)
+	}
+}
+
+func TestDecodeAppHeaderAndChecksum(t *testing.T) {
+	// xdelta3 writes an application header and a window Adler-32 checksum.
+	delta := []byte{0xD6, 0xC3, 0xC4, 0x00, 0x04, 0x02, 'h', 'i',
+		0x04, 0x0D, 0x03, 0x00, 0x03, 0x01, 0x00,
+		0x02, 0x4D, 0x01, 0x27, // adler32("abc")
+		'a', 'b', 'c', 0x04}
+	got, err := vcdiff.Decode(nil, delta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "abc" {
+		t.Errorf("Decode() = %q, want %q", got, "abc")
+	}
+	delta[len(delta)-5]++ // corrupt the checksum
+	if _, err := vcdiff.Decode(nil, delta); !errors.Is(err, vcdiff.ErrCorrupt) {
+		t.Errorf("Decode() with bad checksum error = %v, want ErrCorrupt", err)
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		delta []byte
+		want  string
+	}{
+		{"not vcdiff", []byte("hello"), "not a VCDIFF delta"},
+		{"version", []byte{0xD6, 0xC3, 0xC4, 0x01, 0x00}, "unsupported version 1"},
+		{"secondary", []byte{0xD6, 0xC3, 0xC4, 0x00, 0x01, 0x02}, "secondary compression is not supported"},
+		{"code table", []byte{0xD6, 0xC3, 0xC4, 0x00, 0x02}, "custom code tables are not supported"},
+		{"truncated", rfcDelta[:20], "corrupt delta"},
+		{"segment", []byte{0xD6, 0xC3, 0xC4, 0x00, 0x00, 0x01, 0x20, 0x00}, "source segment 0+32 exceeds 16 bytes"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := vcdiff.Decode([]byte("abcdefghijklmnop"), tt.delta)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Decode() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeHugeWindow(t *testing.T) {
+	// A window declaring 2^50 target bytes but holding no instructions.
+	size := []byte{0x82, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x00}
+	enc := append(append([]byte{}, size...), 0x00, 0x00, 0x00, 0x00)
+	delta := append([]byte{0xD6, 0xC3, 0xC4, 0x00, 0x00, 0x00, byte(len(enc))}, enc...)
+	if _, err := vcdiff.Decode(nil, delta); !errors.Is(err, vcdiff.ErrCorrupt) {
+		t.Errorf("Decode() error = %v, want ErrCorrupt", err)
+	}
+}
+
+func TestEncodeRoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	random := func(n int) []byte {
+		b := make([]byte, n)
+		rng.Read(b)
+		return b
+	}
+	base := random(50000)
+	edited := append(append(append([]byte{}, base[:20000]...), random(300)...), base[20100:]...)
+	tests := []struct {
+		name           string
+		source, target []byte
+		maxSize        int
+	}{
+		{"empty", nil, nil, 5},
+		{"no source", nil, []byte("hello, world"), 40},
+		{"equal", base, base, 100},
+		{"edited", base, edited, 1000},
+		{"moved", base, append(append([]byte{}, base[30000:]...), base[:30000]...), 100},
+		{"repeats", nil, bytes.Repeat([]byte("0123456789abcdefXYZ"), 1000), 200},
+		{"runs", []byte("x"), bytes.Repeat([]byte{0}, 10000), 20},
+		{"unrelated", random(1000), random(1000), 1100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			delta := vcdiff.Encode(tt.source, tt.target)
+			if len(delta) > tt.maxSize {
+				t.Errorf("len(delta) = %d, want at most %d", len(delta), tt.maxSize)
+			}
+			got, err := vcdiff.Decode(tt.source, delta)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, tt.target) {
+				t.Errorf("Decode(Encode()) differs from target")
+			}
+		})
+	}
+}
+
+func TestEncodeLargeTarget(t *testing.T) {
+	// Targets larger than one window are split across several windows.
+	rng := rand.New(rand.NewSource(2))
+	target := make([]byte, 9<<20)
+	rng.Read(target)
+	delta := vcdiff.Encode(target, target)
+	got, err := vcdiff.Decode(target, delta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, target) {
+		t.Error("Decode(Encode()) differs from target")
+	}
+}
+
+func ExampleEncode() {
+	source := []byte("The quick brown fox jumps over the lazy dog.")
+	target := []byte("The quick brown fox jumps over the lazy cat.")
+	delta := vcdiff.Encode(source, target)
+	restored, err := vcdiff.Decode(source, delta)
+	fmt.Println(string(restored), err, len(delta) < len(target))
+	// Output:
+	// The quick brown fox jumps over the lazy cat. <nil> true
+}
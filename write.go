@@ -0,0 +1,135 @@
+package difflib
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// WriteTo writes the diff to w in unified format, exactly as String renders
+// it, without building the whole text in memory. It implements io.WriterTo.
+//
+// Example:
+//
+//	f, _ := os.Create("change.patch")
+//	defer f.Close()
+//	_, err := difflib.UnifiedDiff(input).WriteTo(f)
+func (d DiffResult) WriteTo(w io.Writer) (int64, error) {
+	if len(d.Hunks) == 0 {
+		return 0, nil
+	}
+	cw := &countWriter{w: w}
+	cw.write("--- " + fileLabel(d.FromFile, d.FromDate) + "\n")
+	cw.write("+++ " + fileLabel(d.ToFile, d.ToDate) + "\n")
+	for _, h := range d.Hunks {
+		cw.writeTo(h)
+	}
+	return cw.n, cw.err
+}
+
+// WriteTo writes the hunk's header and lines to w. It implements io.WriterTo.
+func (h Hunk) WriteTo(w io.Writer) (int64, error) {
+	cw := &countWriter{w: w}
+	cw.write(h.header() + "\n")
+	for _, l := range h.Lines {
+		cw.write(l)
+		if !strings.HasSuffix(l, "\n") {
+			cw.write("\n" + noNewlineMarker)
+		}
+	}
+	return cw.n, cw.err
+}
+
+// WriteContextDiff writes the context diff of input, as produced by
+// ContextDiff, to w and returns the number of bytes written.
+//
+// Example:
+//
+//	_, err := difflib.WriteContextDiff(os.Stdout, input)
+func WriteContextDiff(w io.Writer, input DiffInput) (int64, error) {
+	bw := bufio.NewWriter(w)
+	cw := &countWriter{w: bw}
+	emitContextDiff(input, cw.write)
+	return cw.flush(bw)
+}
+
+// WriteNDiff writes the ndiff of a and b, as produced by NDiff, to w and
+// returns the number of bytes written.
+//
+// Example:
+//
+//	_, err := difflib.WriteNDiff(conn, a, b)
+func WriteNDiff(w io.Writer, a, b []string) (int64, error) {
+	bw := bufio.NewWriter(w)
+	cw := &countWriter{w: bw}
+	emitNDiff(a, b, cw.write)
+	return cw.flush(bw)
+}
+
+// countWriter counts the bytes written to w and remembers the first error,
+// after which further writes are skipped.
+type countWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func (c *countWriter) write(s string) {
+	if c.err != nil {
+		return
+	}
+	n, err := io.WriteString(c.w, s)
+	c.n += int64(n)
+	c.err = err
+}
+
+func (c *countWriter) printf(format string, args ...any) {
+	c.write(fmt.Sprintf(format, args...))
+}
+
+func (c *countWriter) writeTo(wt io.WriterTo) {
+	if c.err != nil {
+		return
+	}
+	n, err := wt.WriteTo(c.w)
+	c.n += n
+	c.err = err
+}
+
+// flush flushes bw, which must wrap the destination of c, and returns the
+// byte count and first error.
+func (c *countWriter) flush(bw *bufio.Writer) (int64, error) {
+	if c.err == nil {
+		c.err = bw.Flush()
+	}
+	return c.n, c.err
+}
+
+// fileLabel returns the text of a --- or +++ header line after the marker,
+// quoting the name if necessary. A name with a space and no date gets a
+// trailing tab, as git writes it, so that the space is not taken for the
+// start of a timestamp.
+func fileLabel(name, date string) string {
+	quoted := quoteName(name)
+	switch {
+	case date != "":
+		return quoted + "\t" + date
+	case quoted == name && strings.Contains(name, " "):
+		return name + "\t"
+	}
+	return quoted
+}
+
+// FormatFileDate formats a modification time for DiffInput.FromDate and
+// ToDate as GNU diff does in its file headers, e.g.
+// "2024-05-01 10:00:00.000000000 +0000".
+//
+// Example:
+//
+//	info, _ := os.Stat("old.txt")
+//	input.FromDate = difflib.FormatFileDate(info.ModTime())
+func FormatFileDate(t time.Time) string {
+	return t.Format("2006-01-02 15:04:05.000000000 -0700")
+}
@@ -0,0 +1,160 @@
+package difflib_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	difflib "github.com/njchilds90/go-difflib"
+)
+
+// failWriter accepts n bytes and then fails.
+type failWriter struct{ n int }
+
+var errWriteFailed = errors.New("write failed")
+
+func (w *failWriter) Write(p []byte) (int, error) {
+	if len(p) > w.n {
+		n := w.n
+		w.n = 0
+		return n, errWriteFailed
+	}
+	w.n -= len(p)
+	return len(p), nil
+}
+
+func TestWriteTo(t *testing.T) {
+	input := difflib.DiffInput{
+		A:        difflib.SplitLines("one\ntwo\nthree\nfour\n"),
+		B:        difflib.SplitLines("one\n2\nthree\nfour\nfive\n"),
+		FromFile: "a.txt",
+		ToFile:   "b.txt",
+	}
+	d := difflib.UnifiedDiff(input)
+	ps, err := difflib.ParsePatchSet(gitPatch)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		wt   io.WriterTo
+		want string
+	}{
+		{"DiffResult", d, d.String()},
+		{"empty DiffResult", difflib.DiffResult{FromFile: "a", ToFile: "b"}, ""},
+		{"Hunk", d.Hunks[0], strings.TrimPrefix(d.String(), "--- a.txt\n+++ b.txt\n")},
+		{"FileDiff", ps.Files[1], ps.Files[1].String()},
+		{"PatchSet", ps, ps.String()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b bytes.Buffer
+			n, err := tt.wt.WriteTo(&b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if b.String() != tt.want || n != int64(len(tt.want)) {
+				t.Errorf("WriteTo() = %d, %q; want %d, %q", n, b.String(), len(tt.want), tt.want)
+			}
+
+			if len(tt.want) > 0 {
+				fw := &failWriter{n: len(tt.want) / 2}
+				n, err = tt.wt.WriteTo(fw)
+				if !errors.Is(err, errWriteFailed) || n != int64(len(tt.want)/2) {
+					t.Errorf("WriteTo(failing writer) = %d, %v; want %d, %v", n, err, len(tt.want)/2, errWriteFailed)
+				}
+			}
+		})
+	}
+}
+
+func TestWriteContextDiffAndNDiff(t *testing.T) {
+	input := difflib.DiffInput{
+		A:        difflib.SplitLines("one\ntwo\nthree\n"),
+		B:        difflib.SplitLines("one\nTWO\nthree\nfour\n"),
+		FromFile: "a",
+		ToFile:   "b",
+	}
+	var b bytes.Buffer
+	n, err := difflib.WriteContextDiff(&b, input)
+	if want := strings.Join(difflib.ContextDiff(input), ""); err != nil || b.String() != want || n != int64(len(want)) {
+		t.Errorf("WriteContextDiff() = %d, %v, %q; want %q", n, err, b.String(), want)
+	}
+
+	b.Reset()
+	n, err = difflib.WriteNDiff(&b, input.A, input.B)
+	if want := strings.Join(difflib.NDiff(input.A, input.B), ""); err != nil || b.String() != want || n != int64(len(want)) {
+		t.Errorf("WriteNDiff() = %d, %v, %q; want %q", n, err, b.String(), want)
+	}
+
+	if _, err := difflib.WriteNDiff(&failWriter{}, input.A, input.B); !errors.Is(err, errWriteFailed) {
+		t.Errorf("WriteNDiff(failing writer) error = %v", err)
+	}
+}
+
+func TestFileDates(t *testing.T) {
+	from := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 5, 2, 9, 30, 15, 123456789, time.FixedZone("", -7*3600))
+	input := difflib.DiffInput{
+		A:        difflib.SplitLines("one\ntwo\n"),
+		B:        difflib.SplitLines("one\n2\n"),
+		FromFile: "a.txt",
+		ToFile:   "b.txt",
+		FromDate: difflib.FormatFileDate(from),
+		ToDate:   difflib.FormatFileDate(to),
+	}
+	fromLine := "a.txt\t2024-05-01 10:00:00.000000000 +0000\n"
+	toLine := "b.txt\t2024-05-02 09:30:15.123456789 -0700\n"
+
+	d := difflib.UnifiedDiff(input)
+	unified := d.String()
+	if want := "--- " + fromLine + "+++ " + toLine; !strings.HasPrefix(unified, want) {
+		t.Errorf("UnifiedDiff headers =\n%s\nwant prefix\n%s", unified, want)
+	}
+	if got := strings.Join(difflib.ContextDiff(input), ""); !strings.HasPrefix(got, "*** "+fromLine+"--- "+toLine) {
+		t.Errorf("ContextDiff headers =\n%s", got)
+	}
+	var buf bytes.Buffer
+	opts := difflib.StreamDiffOptions{FromFile: "a.txt", ToFile: "b.txt", FromDate: input.FromDate, ToDate: input.ToDate}
+	if _, err := difflib.UnifiedDiffReaders(&buf, strings.NewReader("one\ntwo\n"), strings.NewReader("one\n2\n"), opts); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != unified {
+		t.Errorf("UnifiedDiffReaders =\n%s\nwant\n%s", buf.String(), unified)
+	}
+
+	// Parsing keeps the dates apart from the names, and inverting swaps them.
+	ps, err := difflib.ParsePatchSet(unified)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := ps.Files[0]
+	if f.FromFile != "a.txt" || f.FromDate != input.FromDate || f.ToFile != "b.txt" || f.ToDate != input.ToDate {
+		t.Errorf("parsed labels = %q %q %q %q", f.FromFile, f.FromDate, f.ToFile, f.ToDate)
+	}
+	if inv := d.Invert(); inv.FromDate != input.ToDate || inv.ToDate != input.FromDate {
+		t.Errorf("Invert dates = %q, %q", inv.FromDate, inv.ToDate)
+	}
+}
+
+func ExampleDiffResult_WriteTo() {
+	d := difflib.UnifiedDiff(difflib.DiffInput{
+		A:        difflib.SplitLines("one\ntwo\n"),
+		B:        difflib.SplitLines("one\n2\n"),
+		FromFile: "a.txt",
+		ToFile:   "b.txt",
+	})
+	d.WriteTo(os.Stdout)
+	// Output:
+	// --- a.txt
+	// +++ b.txt
+	// @@ -1,2 +1,2 @@
+	//  one
+	// -two
+	// +2
+}